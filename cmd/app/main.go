@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
@@ -11,8 +12,10 @@ import (
 	"github.com/hablof/merchant-experience/internal/config"
 	"github.com/hablof/merchant-experience/internal/database"
 	"github.com/hablof/merchant-experience/internal/gateway"
+	"github.com/hablof/merchant-experience/internal/importer"
 	"github.com/hablof/merchant-experience/internal/repository"
 	"github.com/hablof/merchant-experience/internal/router"
+	"github.com/hablof/merchant-experience/internal/scheduler"
 	"github.com/hablof/merchant-experience/internal/service"
 	"github.com/hablof/merchant-experience/internal/xlsxparser"
 
@@ -26,6 +29,11 @@ func main() {
 		return
 	}
 
+	// ключ шифрования лучше не держать в config.yml
+	if key := os.Getenv("CREDENTIALS_KEY"); key != "" {
+		cfg.Security.CredentialsKey = key
+	}
+
 	inDocker := false
 	if os.Getenv("CONTAINER") != "" {
 		inDocker = true
@@ -58,10 +66,30 @@ func main() {
 	}
 
 	r := repository.NewRepository(db, cfg)
-	s := service.NewService(r)
+	s := service.NewService(cfg, r)
 	g := gateway.NewGateway(cfg)
-	p := xlsxparser.NewParser()
-	handler := router.NewRouter(s, g, p)
+	p := xlsxparser.NewParser(cfg)
+	cp := xlsxparser.NewCSVParser(cfg)
+	im := importer.NewImporter(cfg, r, s, g, p, cp)
+	sch := scheduler.NewScheduler(cfg, r, im, g, r.AdvisoryLock(scheduler.LockKey(cfg)))
+	handler := router.NewRouter(cfg, s, im, sch)
+
+	importerCtx, stopImporter := context.WithCancel(context.Background())
+	importerDone := make(chan struct{})
+	go func() {
+		defer close(importerDone)
+		log.Println("starting import workers...")
+		im.Run(importerCtx)
+	}()
+
+	// фиды запускает только одна реплика, остальные ждут блокировку
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		log.Println("starting feed scheduler...")
+		sch.Run(schedulerCtx)
+	}()
 
 	server := &http.Server{
 		Addr:        ":" + cfg.Server.Port,
@@ -82,4 +110,12 @@ func main() {
 	<-terminationChannel
 	log.Println("terminating server...")
 	server.Close()
+
+	log.Println("stopping feed scheduler...")
+	stopScheduler()
+	<-schedulerDone
+
+	log.Println("waiting for import workers...")
+	stopImporter()
+	<-importerDone
 }
@@ -12,11 +12,19 @@ type Config struct {
 	Database   Database   `yaml:"database"`
 	Repository Repository `yaml:"repository"`
 	Gateway    Gateway    `yaml:"gateway"`
+	Importer   Importer   `yaml:"importer"`
+	Parser     Parser     `yaml:"parser"`
+	Validation Validation `yaml:"validation"`
+	Guard      Guard      `yaml:"guard"`
+	Security   Security   `yaml:"security"`
+	Scheduler  Scheduler  `yaml:"scheduler"`
 }
 
 type Server struct {
 	Port    string `yaml:"port"`
 	Timeout int64  `yaml:"timeout"`
+	// максимальный размер загружаемого файла таблицы, МБ
+	MaxUploadSizeMB int64 `yaml:"max-upload-size-mb"`
 }
 
 type Database struct {
@@ -30,10 +38,104 @@ type Database struct {
 
 type Repository struct {
 	Timeout int64 `yaml:"timeout"`
+	// сколько секунд могут идти перенос загрузки в каталог (Apply) и откат загрузки; 0 - по умолчанию 300
+	ApplyTimeout int64 `yaml:"apply-timeout"`
+	// сколько строк пишется одним запросом во временную таблицу загрузки
+	ChunkSize int `yaml:"chunk-size"`
+	// вставка через COPY во временную таблицу вместо INSERT ... VALUES
+	UseCopy bool `yaml:"use-copy"`
 }
 
 type Gateway struct {
 	Timeout int64 `yaml:"timeout"`
+	// сколько раз повторить скачивание, если источник недоступен (5xx, таймаут, обрыв соединения)
+	Retries int `yaml:"retries"`
+	// пауза перед первым повтором, мс; каждая следующая вдвое длиннее
+	RetryBackoffMs int64 `yaml:"retry-backoff-ms"`
+	// максимальный размер скачиваемой таблицы, МБ
+	MaxTableSizeMB int64 `yaml:"max-table-size-mb"`
+	// схемы ссылок на таблицы; пусто - http и https
+	AllowedSchemes []string `yaml:"allowed-schemes"`
+	// хосты (вместе с поддоменами), с которых можно скачивать; пусто - любые
+	AllowedHosts []string `yaml:"allowed-hosts"`
+	// хосты (вместе с поддоменами), с которых скачивать нельзя
+	DeniedHosts []string `yaml:"denied-hosts"`
+	// разрешить внутренние адреса (loopback, частные сети, link-local) - только для локального запуска
+	AllowPrivateIPs bool `yaml:"allow-private-ips"`
+	// сколько редиректов можно пройти; 0 - по умолчанию 5
+	MaxRedirects int `yaml:"max-redirects"`
+}
+
+type Importer struct {
+	Workers      int   `yaml:"workers"`
+	PollInterval int64 `yaml:"poll-interval"`
+	// сколько секунд может выполняться задача: скачивание, разбор и запись
+	JobTimeout int64 `yaml:"job-timeout"`
+	// через сколько секунд без продления аренды выполняемая задача считается брошенной и возвращается в очередь
+	LeaseTimeout int64 `yaml:"lease-timeout"`
+	// сколько раз задача выполняется, прежде чем брошенная задача завершится ошибкой "worker crashed", а не вернётся в очередь
+	MaxAttempts uint32 `yaml:"max-attempts"`
+	// сколько строк таблицы передаётся в сервис за раз
+	BatchSize int `yaml:"batch-size"`
+}
+
+type Parser struct {
+	// синонимы названий колонок в заголовке таблицы: offer_id -> [артикул, sku]
+	Aliases map[string][]string `yaml:"aliases"`
+	// листы и общие строки книги xlsx больше этого размера, МБ, распаковываются во временные файлы, а не в память
+	UnzipXMLSizeLimitMB int64 `yaml:"unzip-xml-size-limit-mb"`
+	// сколько МБ может занимать распакованная книга xlsx целиком; защита от zip-бомб
+	UnzipSizeLimitMB int64 `yaml:"unzip-size-limit-mb"`
+}
+
+type Validation struct {
+	// правила для всех продавцов
+	Default Rules `yaml:"default"`
+	// правила отдельных продавцов по seller_id; целиком заменяют default
+	Sellers map[uint64]Rules `yaml:"sellers"`
+}
+
+// Rules - правила проверки товаров; нулевое значение правило отключает
+type Rules struct {
+	MinPrice    uint64 `yaml:"min-price"`
+	MaxPrice    uint64 `yaml:"max-price"`
+	MaxQuantity uint64 `yaml:"max-quantity"`
+	// слова, с которыми название не принимается; регистр не важен
+	ForbiddenWords []string `yaml:"forbidden-words"`
+	RequireName    bool     `yaml:"require-name"`
+	// на сколько процентов цена может измениться относительно сохранённой за одну загрузку
+	MaxPriceChangePercent float64 `yaml:"max-price-change-percent"`
+}
+
+// Guard - защита каталога от сломанных выгрузок: загрузка, которая удаляет или резко переоценивает
+// слишком большую долю каталога, не записывается; 0 - ограничение отключено
+type Guard struct {
+	// сколько процентов каталога продавца загрузка может удалить
+	MaxDeletePercent float64 `yaml:"max-delete-percent"`
+	// изменение цены в процентах, начиная с которого оно считается резким
+	PriceChangePercent float64 `yaml:"price-change-percent"`
+	// у скольких процентов каталога загрузка может резко изменить цену
+	MaxRepricedPercent float64 `yaml:"max-repriced-percent"`
+	// hold - задача ждёт подтверждения (по умолчанию), reject - завершается ошибкой
+	Action string `yaml:"action"`
+}
+
+type Security struct {
+	// ключ шифрования учётных данных источников таблиц: 32 байта в base64 (openssl rand -base64 32);
+	// пусто - загрузки с учётными данными не принимаются
+	CredentialsKey string `yaml:"credentials-key"`
+}
+
+// Scheduler - загрузка фидов продавцов по расписанию; работает в одной реплике, выбранной advisory-блокировкой
+type Scheduler struct {
+	// как часто искать фиды, которым пора загружаться, секунды
+	PollInterval int64 `yaml:"poll-interval"`
+	// минимальный интервал фида, минуты
+	MinIntervalMinutes uint64 `yaml:"min-interval-minutes"`
+	// сколько фидов запускается за один обход
+	BatchSize uint64 `yaml:"batch-size"`
+	// ключ advisory-блокировки лидера; одинаковый у всех реплик одной базы
+	LockKey int64 `yaml:"lock-key"`
 }
 
 func ReadConfigYml(filePath string) (Config, error) {
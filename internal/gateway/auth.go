@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/hablof/merchant-experience/internal/models"
+)
+
+// в ссылке не удалось разобрать даже схему и хост; сама ссылка в ошибку не попадает
+var errMalformedURL = errors.New("malformed table url")
+
+// setAuth добавляет в запрос учётные данные источника: заголовки, затем basic auth или bearer-токен
+func setAuth(req *http.Request, auth models.TableAuth) {
+	for name, value := range auth.Headers {
+		req.Header.Set(name, value)
+	}
+
+	switch {
+	case auth.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+auth.BearerToken)
+	case auth.Username != "" || auth.Password != "":
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+}
+
+// stripAuth убирает учётные данные из запроса редиректа на другой хост: они выданы только исходному.
+// Так же и при переходе с https на http того же хоста: по http они ушли бы открытым текстом.
+// Стандартный клиент так поступает лишь с Authorization и только при уходе с домена, а не с произвольными заголовками.
+func stripAuth(req *http.Request, via []*http.Request) {
+	if len(via) == 0 {
+		return
+	}
+
+	downgrade := via[0].URL.Scheme == "https" && req.URL.Scheme != "https"
+	if req.URL.Host == via[0].URL.Host && !downgrade {
+		return
+	}
+
+	for name := range via[0].Header {
+		req.Header.Del(name)
+	}
+}
+
+// redactError скрывает в ошибке клиента логин, пароль и значения параметров ссылки (подписанные ссылки, токены)
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = RedactURL(urlErr.URL)
+	}
+
+	return err
+}
+
+// RedactURL скрывает в ссылке логин, пароль и значения параметров, оставляя их имена; так ссылка показывается в задачах и фидах
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(malformed url)"
+	}
+
+	u.User = nil
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query.Set(key, "xxxxx")
+		}
+		u.RawQuery = query.Encode()
+	}
+	u.Fragment = ""
+
+	return u.String()
+}
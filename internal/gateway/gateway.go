@@ -3,40 +3,144 @@ package gateway
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"log"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+)
+
+const (
+	defaultRetryBackoff   = 500 * time.Millisecond
+	defaultMaxTableSizeMB = 50
+
+	xlsxMediaType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	// сколько байт смотрит http.DetectContentType
+	sniffLen = 512
+)
+
+// xlsx - это zip-архив
+var zipMagic = []byte("PK\x03\x04")
+
+var (
+	// по ссылке ничего нет (404, 410)
+	ErrNotFound = errors.New("table not found")
+	// таблица больше gateway.max-table-size-mb
+	ErrTooLarge = errors.New("table too large")
+	// источник не отвечает или отвечает 5xx - в том числе после всех повторов
+	ErrUpstreamDown = errors.New("table host unavailable")
+	// по ссылке не таблица: html-страница, картинка или "xlsx", который не zip-архив
+	ErrNotTable = errors.New("not a table file")
+	// источник ответил другим неуспешным статусом (401, 403, ...)
+	ErrFetchFailed = errors.New("failed to fetch resource")
 )
 
 type Gateway struct {
-	hc http.Client
+	hc     http.Client
+	policy urlPolicy
+
+	retries int
+	backoff time.Duration
+	maxSize int64
 }
 
 func NewGateway(cfg config.Config) *Gateway {
-	c := http.Client{Timeout: time.Duration(cfg.Gateway.Timeout) * time.Second}
+	policy := newURLPolicy(cfg.Gateway)
+
+	// прокси не используется: иначе адрес назначения не проверить при подключении
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: policy.dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	c := http.Client{
+		Timeout:       time.Duration(cfg.Gateway.Timeout) * time.Second,
+		Transport:     transport,
+		CheckRedirect: policy.checkRedirect,
+	}
 
-	return &Gateway{
-		hc: c,
+	g := Gateway{
+		hc:      c,
+		policy:  policy,
+		retries: cfg.Gateway.Retries,
+		backoff: time.Duration(cfg.Gateway.RetryBackoffMs) * time.Millisecond,
+		maxSize: cfg.Gateway.MaxTableSizeMB << 20,
 	}
+	if g.backoff <= 0 {
+		g.backoff = defaultRetryBackoff
+	}
+	if g.maxSize <= 0 {
+		g.maxSize = defaultMaxTableSizeMB << 20
+	}
+
+	return &g
 }
 
-func (g *Gateway) Table(url string) (io.Reader, error) {
+// Table скачивает таблицу во временный файл по ссылке, разрешённой политикой (CheckURL). Если источник недоступен,
+// запрос повторяется с экспоненциальной паузой; остальные ошибки (ErrNotFound, ErrTooLarge, ErrNotTable, ErrForbiddenURL) не повторяются.
+// По валидаторам prev прошлой загрузки запрос делается условным: если таблица не менялась, возвращается NotModified без тела.
+// Когда ctx истекает, скачивание и повторы прерываются с ошибкой контекста.
+func (g *Gateway) Table(ctx context.Context, tableURL string, auth models.TableAuth, prev models.TableFetch) (models.Table, error) {
+	for attempt := 0; ; attempt++ {
+		table, err := g.fetch(ctx, tableURL, auth, prev)
+		if err == nil {
+			return table, nil
+		}
+
+		if ctx.Err() != nil {
+			return models.Table{}, ctx.Err()
+		}
+
+		if !errors.Is(err, ErrUpstreamDown) || attempt >= g.retries {
+			return models.Table{}, err
+		}
+
+		delay := g.backoff << attempt
+		log.Printf("fetch attempt %d failed: %v; retrying in %s", attempt+1, err, delay)
 
-	ctx, cf := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cf()
+		select {
+		case <-ctx.Done():
+			return models.Table{}, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+func (g *Gateway) fetch(ctx context.Context, tableURL string, auth models.TableAuth, prev models.TableFetch) (models.Table, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tableURL, nil)
 	if err != nil {
-		return nil, err
+		return models.Table{}, errMalformedURL
+	}
+
+	if err := g.policy.checkURL(req.URL); err != nil {
+		return models.Table{}, err
 	}
 
+	setAuth(req, auth)
+	conditional := setConditional(req, prev)
+
 	resp, err := g.hc.Do(req)
 	if err != nil {
-		return nil, err
+		// в ошибке клиента - ссылка, а в ней могут быть подпись или токен
+		err = redactError(err)
+		if retryable(err) {
+			return models.Table{}, fmt.Errorf("%w: %v", ErrUpstreamDown, err)
+		}
+
+		return models.Table{}, err
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -44,20 +148,154 @@ func (g *Gateway) Table(url string) (io.Reader, error) {
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("failed to fetch resource: %s", resp.Status)
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotModified && conditional:
+		// источник может обновить валидаторы и в ответе 304
+		fetch := prev
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			fetch.ETag = etag
+		}
+		if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+			fetch.LastModified = lastModified
+		}
 
-		return nil, errors.New("failed to fetch resource")
+		return models.Table{NotModified: true, Fetch: fetch}, nil
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusGone:
+		return models.Table{}, fmt.Errorf("%w: %s", ErrNotFound, resp.Status)
+	// 408 и 429 - тоже временная недоступность
+	case resp.StatusCode >= http.StatusInternalServerError,
+		resp.StatusCode == http.StatusRequestTimeout,
+		resp.StatusCode == http.StatusTooManyRequests:
+		return models.Table{}, fmt.Errorf("%w: %s", ErrUpstreamDown, resp.Status)
+	default:
+		return models.Table{}, fmt.Errorf("%w: %s", ErrFetchFailed, resp.Status)
 	}
 
-	// buf := make([]byte, resp.ContentLength)
-	buf, err := io.ReadAll(resp.Body)
-	switch {
-	case err == io.EOF: // всё хорошо
+	if resp.ContentLength > g.maxSize {
+		return models.Table{}, fmt.Errorf("%w: content length %d", ErrTooLarge, resp.ContentLength)
+	}
+
+	// таблица может быть очень большой, поэтому не держим её в памяти, а сохраняем во временный файл
+	f, err := os.CreateTemp("", "table-*")
+	if err != nil {
+		return models.Table{}, err
+	}
+
+	spooled := &tempFile{File: f}
+	hash := sha256.New()
+	// размер проверяется по ходу скачивания: Content-Length может не быть
+	n, err := io.Copy(io.MultiWriter(f, hash), io.LimitReader(resp.Body, g.maxSize+1))
+	if err != nil {
+		spooled.Close()
+		err = redactError(err)
+		if retryable(err) {
+			return models.Table{}, fmt.Errorf("%w: %v", ErrUpstreamDown, err)
+		}
+
+		return models.Table{}, err
+	}
+
+	if n > g.maxSize {
+		spooled.Close()
+		return models.Table{}, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, g.maxSize)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if err := checkTable(f, contentType, tableURL); err != nil {
+		spooled.Close()
+		return models.Table{}, err
+	}
+
+	return models.Table{
+		Body:        spooled,
+		ContentType: contentType,
+		Fetch: models.TableFetch{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			ContentHash:  hex.EncodeToString(hash.Sum(nil)),
+		},
+	}, nil
+}
+
+// setConditional добавляет в запрос валидаторы прошлой загрузки; возвращает, сделан ли запрос условным
+func setConditional(req *http.Request, prev models.TableFetch) bool {
+	if prev.ETag != "" {
+		req.Header.Set("If-None-Match", prev.ETag)
+	}
+	if prev.LastModified != "" {
+		req.Header.Set("If-Modified-Since", prev.LastModified)
+	}
+
+	return prev.ETag != "" || prev.LastModified != ""
+}
+
+// retryable: таймауты и обрывы соединения - источник может ожить
+func retryable(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// checkTable проверяет по первым байтам, что скачана таблица: xlsx (zip-архив) или текст (csv/tsv).
+// Если источник или расширение ссылки обещают xlsx, текст не принимается. Файл возвращается в начало.
+func checkTable(f io.ReadSeeker, contentType string, tableURL string) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	head = head[:n]
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if bytes.HasPrefix(head, zipMagic) {
+		return nil
+	}
+
+	detected := http.DetectContentType(head)
+	if declaredXLSX(contentType, tableURL) {
+		return fmt.Errorf("%w: expected xlsx, got %s", ErrNotTable, detected)
+	}
+
+	if !strings.HasPrefix(detected, "text/plain") {
+		return fmt.Errorf("%w: got %s", ErrNotTable, detected)
+	}
+
+	return nil
+}
+
+func declaredXLSX(contentType string, tableURL string) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == xlsxMediaType {
+		return true
+	}
+
+	u, err := url.Parse(tableURL)
+
+	return err == nil && strings.EqualFold(path.Ext(u.Path), ".xlsx")
+}
+
+// tempFile удаляет себя при закрытии
+type tempFile struct {
+	*os.File
+}
 
-	case err != nil:
-		return nil, err
+func (t *tempFile) Close() error {
+	closeErr := t.File.Close()
+	if err := os.Remove(t.File.Name()); err != nil {
+		return err
 	}
 
-	return bytes.NewBuffer(buf), nil
+	return closeErr
 }
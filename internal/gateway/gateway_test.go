@@ -1,42 +1,51 @@
 package gateway
 
 import (
-	"errors"
+	"bytes"
+	"context"
 	"io"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGateway_Table(t *testing.T) {
 
 	tests := []struct {
-		name          string
-		fileToServe   string
-		respStatus200 bool
-		wantErr       error
+		name            string
+		fileToServe     string
+		respStatus200   bool
+		wantContentType string
+		wantErr         error
 	}{
 		{
-			name:          "txt file",
-			fileToServe:   "test.txt",
-			respStatus200: true,
-			wantErr:       nil,
+			name:            "txt file",
+			fileToServe:     "test.txt",
+			respStatus200:   true,
+			wantContentType: "text/plain; charset=utf-8",
+			wantErr:         nil,
 		},
 		{
-			name:          "xlsx file",
-			fileToServe:   "example_file.xlsx",
-			respStatus200: true,
-			wantErr:       nil,
+			name:            "xlsx file",
+			fileToServe:     "example_file.xlsx",
+			respStatus200:   true,
+			wantContentType: "", // зависит от системной таблицы mime-типов
+			wantErr:         nil,
 		},
 		{
-			name:          "failed to fetch resource",
+			name:          "upstream down",
 			fileToServe:   "test.txt",
 			respStatus200: false,
-			wantErr:       errors.New("failed to fetch resource"),
+			wantErr:       ErrUpstreamDown,
 		},
 	}
 	for _, tt := range tests {
@@ -44,21 +53,31 @@ func TestGateway_Table(t *testing.T) {
 
 			server := httptest.NewServer(newTestHandler(tt.fileToServe, tt.respStatus200))
 
-			cfg := config.Config{Gateway: config.Gateway{Timeout: 5}}
+			cfg := config.Config{Gateway: config.Gateway{Timeout: 5, AllowPrivateIPs: true}}
 			g := NewGateway(cfg)
 
-			r, err := g.Table(server.URL)
+			table, err := g.Table(context.Background(), server.URL, models.TableAuth{}, models.TableFetch{})
 
-			assert.Equal(t, tt.wantErr, err, "method error")
+			assert.ErrorIs(t, err, tt.wantErr, "method error")
 			if err != nil {
 				t.SkipNow()
 			}
 
-			bytesReadFromMethod, err := io.ReadAll(r)
+			if tt.wantContentType != "" {
+				assert.Equal(t, tt.wantContentType, table.ContentType, "content type")
+			}
+
+			bytesReadFromMethod, err := io.ReadAll(table.Body)
 			if err != nil {
 				assert.FailNow(t, err.Error())
 			}
 
+			// временный файл удаляется при закрытии
+			tmpName := table.Body.(*tempFile).Name()
+			assert.NoError(t, table.Body.Close())
+			_, err = os.Stat(tmpName)
+			assert.True(t, os.IsNotExist(err), "temp file removed")
+
 			f, err := os.Open(filepath.Join("test", tt.fileToServe))
 			if err != nil {
 				assert.FailNow(t, err.Error())
@@ -73,3 +92,280 @@ func TestGateway_Table(t *testing.T) {
 		})
 	}
 }
+
+func TestGateway_Table_Errors(t *testing.T) {
+	xlsx, err := os.ReadFile(filepath.Join("test", "example_file.xlsx"))
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		handler http.HandlerFunc
+		wantErr error
+	}{
+		{
+			name: "not found",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.NotFound(w, r)
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "forbidden",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			wantErr: ErrFetchFailed,
+		},
+		{
+			name: "content length too large",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Length", strconv.Itoa(2<<20))
+				w.Write(bytes.Repeat([]byte("a"), 2<<20))
+			},
+			wantErr: ErrTooLarge,
+		},
+		{
+			name: "stream too large",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				// без Content-Length: размер проверяется при чтении
+				for i := 0; i < 3; i++ {
+					w.Write(bytes.Repeat([]byte("a"), 1<<20))
+					w.(http.Flusher).Flush()
+				}
+			},
+			wantErr: ErrTooLarge,
+		},
+		{
+			name: "html page",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte("<!DOCTYPE html><html><body>Войдите в аккаунт</body></html>"))
+			},
+			wantErr: ErrNotTable,
+		},
+		{
+			name: "xlsx content type, text payload",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", xlsxMediaType)
+				w.Write([]byte("offer_id;name;price;quantity"))
+			},
+			wantErr: ErrNotTable,
+		},
+		{
+			name: "xlsx extension, text payload",
+			path: "/price.xlsx",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("offer_id;name;price;quantity"))
+			},
+			wantErr: ErrNotTable,
+		},
+		{
+			name: "binary payload",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3"))
+			},
+			wantErr: ErrNotTable,
+		},
+		{
+			name: "xlsx",
+			path: "/price.xlsx",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", xlsxMediaType)
+				w.Write(xlsx)
+			},
+		},
+		{
+			name: "csv",
+			path: "/price.csv",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/csv")
+				w.Write([]byte("offer_id;name;price;quantity\n1;name;100;1"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			g := NewGateway(config.Config{Gateway: config.Gateway{Timeout: 5, MaxTableSizeMB: 1, AllowPrivateIPs: true}})
+
+			table, err := g.Table(context.Background(), server.URL+tt.path, models.TableAuth{}, models.TableFetch{})
+			assert.ErrorIs(t, err, tt.wantErr)
+			if err == nil {
+				assert.NoError(t, table.Body.Close())
+			}
+		})
+	}
+}
+
+func TestGateway_Table_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		retries   int
+		failures  int
+		slow      bool
+		backoffMs int64
+		// срок задачи; 0 - без срока
+		deadline  time.Duration
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "recovers after 5xx",
+			retries:   3,
+			failures:  2,
+			wantCalls: 3,
+		},
+		{
+			name:      "retries exhausted",
+			retries:   2,
+			failures:  10,
+			wantCalls: 3,
+			wantErr:   ErrUpstreamDown,
+		},
+		{
+			name:      "no retries",
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   ErrUpstreamDown,
+		},
+		{
+			name:      "timeout",
+			retries:   1,
+			failures:  1,
+			slow:      true,
+			wantCalls: 2,
+		},
+		{
+			name:      "job deadline stops retries",
+			retries:   3,
+			failures:  10,
+			backoffMs: 1000,
+			deadline:  200 * time.Millisecond,
+			wantCalls: 1,
+			wantErr:   context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+				if int(call) <= tt.failures {
+					if tt.slow {
+						time.Sleep(1500 * time.Millisecond)
+					}
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				w.Write([]byte("offer_id;name;price;quantity"))
+			}))
+			defer server.Close()
+
+			backoffMs := tt.backoffMs
+			if backoffMs == 0 {
+				backoffMs = 1
+			}
+			g := NewGateway(config.Config{Gateway: config.Gateway{Timeout: 1, Retries: tt.retries, RetryBackoffMs: backoffMs, AllowPrivateIPs: true}})
+
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			table, err := g.Table(ctx, server.URL, models.TableAuth{}, models.TableFetch{})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantCalls, int(atomic.LoadInt32(&calls)), "calls")
+			if err == nil {
+				b, _ := io.ReadAll(table.Body)
+				assert.Equal(t, "offer_id;name;price;quantity", string(b))
+				assert.NoError(t, table.Body.Close())
+			}
+		})
+	}
+}
+
+func TestGateway_Table_Conditional(t *testing.T) {
+	const (
+		body         = "offer_id;name;price;quantity"
+		bodyHash     = "6107ac4ca1e6abfcb1d4ba76300b10c668c6b6ef203e19434ca2e02d2117fa0d"
+		etag         = `"v1"`
+		lastModified = "Tue, 01 Aug 2023 12:00:00 GMT"
+	)
+
+	// sha256 тела; источник отвечает 304 на совпавший If-None-Match
+	handler := func(gotHeader http.Header) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			for _, name := range []string{"If-None-Match", "If-Modified-Since"} {
+				if v := r.Header.Get(name); v != "" {
+					gotHeader.Set(name, v)
+				}
+			}
+			w.Header().Set("Last-Modified", lastModified)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(body))
+		}
+	}
+
+	tests := []struct {
+		name            string
+		prev            models.TableFetch
+		wantHeader      http.Header
+		wantNotModified bool
+		wantFetch       models.TableFetch
+	}{
+		{
+			name:       "first fetch",
+			prev:       models.TableFetch{},
+			wantHeader: http.Header{},
+			wantFetch:  models.TableFetch{ETag: etag, LastModified: lastModified, ContentHash: bodyHash},
+		},
+		{
+			name:            "not modified",
+			prev:            models.TableFetch{ETag: etag, ContentHash: "abc"},
+			wantHeader:      http.Header{"If-None-Match": {etag}},
+			wantNotModified: true,
+			wantFetch:       models.TableFetch{ETag: etag, LastModified: lastModified, ContentHash: "abc"},
+		},
+		{
+			name:       "etag changed",
+			prev:       models.TableFetch{ETag: `"v0"`, LastModified: lastModified, ContentHash: "abc"},
+			wantHeader: http.Header{"If-None-Match": {`"v0"`}, "If-Modified-Since": {lastModified}},
+			wantFetch:  models.TableFetch{ETag: etag, LastModified: lastModified, ContentHash: bodyHash},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotHeader := http.Header{}
+			server := httptest.NewServer(handler(gotHeader))
+			defer server.Close()
+
+			g := NewGateway(config.Config{Gateway: config.Gateway{Timeout: 5, AllowPrivateIPs: true}})
+			table, err := g.Table(context.Background(), server.URL, models.TableAuth{}, tt.prev)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tt.wantHeader, gotHeader)
+			assert.Equal(t, tt.wantNotModified, table.NotModified)
+			assert.Equal(t, tt.wantFetch, table.Fetch)
+			if tt.wantNotModified {
+				assert.Nil(t, table.Body)
+			} else {
+				assert.NoError(t, table.Body.Close())
+			}
+		})
+	}
+}
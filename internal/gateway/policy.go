@@ -0,0 +1,206 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/config"
+)
+
+const (
+	defaultMaxRedirects = 5
+	resolveTimeout      = 5 * time.Second
+)
+
+var defaultAllowedSchemes = []string{"http", "https"}
+
+var (
+	// ссылка запрещена политикой: схема, хост или его адрес
+	ErrForbiddenURL = errors.New("table url not allowed")
+	// редиректов больше gateway.max-redirects
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// CGNAT (100.64.0.0/10) - по нему в некоторых облаках доступны метаданные
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// urlPolicy - какие ссылки можно скачивать: защита от запросов сервиса к самому себе,
+// внутренней сети и метаданным облака по ссылке продавца
+type urlPolicy struct {
+	schemes      map[string]bool
+	allowedHosts []string
+	deniedHosts  []string
+	allowPrivate bool
+	maxRedirects int
+}
+
+func newURLPolicy(cfg config.Gateway) urlPolicy {
+	p := urlPolicy{
+		schemes:      make(map[string]bool),
+		allowedHosts: normalizeHosts(cfg.AllowedHosts),
+		deniedHosts:  normalizeHosts(cfg.DeniedHosts),
+		allowPrivate: cfg.AllowPrivateIPs,
+		maxRedirects: cfg.MaxRedirects,
+	}
+
+	schemes := cfg.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = defaultAllowedSchemes
+	}
+	for _, scheme := range schemes {
+		p.schemes[strings.ToLower(scheme)] = true
+	}
+
+	if p.maxRedirects <= 0 {
+		p.maxRedirects = defaultMaxRedirects
+	}
+
+	return p
+}
+
+func normalizeHosts(hosts []string) []string {
+	normalized := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if host = strings.Trim(strings.ToLower(strings.TrimSpace(host)), "."); host != "" {
+			normalized = append(normalized, host)
+		}
+	}
+
+	return normalized
+}
+
+// checkURL проверяет схему и хост по спискам, без разрешения имени
+func (p urlPolicy) checkURL(u *url.URL) error {
+	if !p.schemes[strings.ToLower(u.Scheme)] {
+		return fmt.Errorf("%w: scheme %q is not allowed", ErrForbiddenURL, u.Scheme)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return fmt.Errorf("%w: no host", ErrForbiddenURL)
+	}
+
+	if matchHost(host, p.deniedHosts) {
+		return fmt.Errorf("%w: host %s is denied", ErrForbiddenURL, host)
+	}
+
+	if len(p.allowedHosts) > 0 && !matchHost(host, p.allowedHosts) {
+		return fmt.Errorf("%w: host %s is not in the allowed list", ErrForbiddenURL, host)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return p.checkIP(ip)
+	}
+
+	return nil
+}
+
+// matchHost: запись списка совпадает с самим хостом и его поддоменами
+func matchHost(host string, hosts []string) bool {
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkIP запрещает внутренние адреса, если не разрешены gateway.allow-private-ips
+func (p urlPolicy) checkIP(ip net.IP) error {
+	if problem := p.ipProblem(ip); problem != "" {
+		return fmt.Errorf("%w: %s is %s", ErrForbiddenURL, ip, problem)
+	}
+
+	return nil
+}
+
+func (p urlPolicy) ipProblem(ip net.IP) string {
+	if p.allowPrivate {
+		return ""
+	}
+
+	switch {
+	case ip.IsLoopback():
+		return "a loopback address"
+	case ip.IsPrivate(), sharedAddressSpace.Contains(ip):
+		return "a private address"
+	case ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast():
+		return "a link-local address"
+	case ip.IsUnspecified(), ip.IsMulticast(), ip.IsInterfaceLocalMulticast():
+		return "not a unicast address"
+	}
+
+	return ""
+}
+
+// dialControl проверяет адрес, к которому действительно подключается клиент:
+// имя уже разрешено, так что ни редирект, ни подмена DNS политику не обойдут
+func (p urlPolicy) dialControl(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: bad address %s", ErrForbiddenURL, address)
+	}
+
+	return p.checkIP(ip)
+}
+
+// checkRedirect проверяет каждую ссылку редиректа и их количество; на другой хост учётные данные не уходят
+func (p urlPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > p.maxRedirects {
+		return fmt.Errorf("%w: more than %d", ErrTooManyRedirects, p.maxRedirects)
+	}
+
+	if err := p.checkURL(req.URL); err != nil {
+		return err
+	}
+
+	stripAuth(req, via)
+
+	return nil
+}
+
+// CheckURL проверяет ссылку на таблицу до постановки задачи в очередь: схему, хост и все адреса, в которые он разрешается.
+// Ошибка всегда оборачивает ErrForbiddenURL и объясняет причину.
+func (g *Gateway) CheckURL(tableURL string) error {
+	u, err := url.Parse(tableURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrForbiddenURL, errMalformedURL)
+	}
+
+	if err := g.policy.checkURL(u); err != nil {
+		return err
+	}
+
+	if net.ParseIP(u.Hostname()) != nil {
+		return nil
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cf()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: host %s is not resolved", ErrForbiddenURL, u.Hostname())
+	}
+
+	for _, addr := range addrs {
+		if problem := g.policy.ipProblem(addr.IP); problem != "" {
+			return fmt.Errorf("%w: host %s resolves to %s, %s", ErrForbiddenURL, u.Hostname(), addr.IP, problem)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGateway_CheckURL(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		cfg        config.Gateway
+		url        string
+		wantErr    error
+		wantReason string
+	}{
+		{
+			name: "public ip",
+			url:  "http://93.184.216.34/table.xlsx",
+		},
+		{
+			name:       "scheme not allowed",
+			url:        "file:///etc/passwd",
+			wantErr:    ErrForbiddenURL,
+			wantReason: `scheme "file" is not allowed`,
+		},
+		{
+			name:       "scheme not in custom list",
+			cfg:        config.Gateway{AllowedSchemes: []string{"https"}},
+			url:        "http://93.184.216.34/table.xlsx",
+			wantErr:    ErrForbiddenURL,
+			wantReason: `scheme "http" is not allowed`,
+		},
+		{
+			name:       "no host",
+			url:        "http:///table.xlsx",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "no host",
+		},
+		{
+			name:       "metadata",
+			url:        "http://169.254.169.254/latest/meta-data/",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "169.254.169.254 is a link-local address",
+		},
+		{
+			name:       "loopback",
+			url:        "http://127.0.0.1:8000/",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "127.0.0.1 is a loopback address",
+		},
+		{
+			name:       "ipv6 loopback",
+			url:        "http://[::1]/",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "::1 is a loopback address",
+		},
+		{
+			name:       "private network",
+			url:        "http://10.1.2.3/table.xlsx",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "10.1.2.3 is a private address",
+		},
+		{
+			name:       "shared address space",
+			url:        "http://100.100.100.200/",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "100.100.100.200 is a private address",
+		},
+		{
+			name:       "name resolves to loopback",
+			url:        "http://localhost:8000/",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "host localhost resolves to",
+		},
+		{
+			name: "private allowed",
+			cfg:  config.Gateway{AllowPrivateIPs: true},
+			url:  "http://10.1.2.3/table.xlsx",
+		},
+		{
+			name:       "denied host and subdomains",
+			cfg:        config.Gateway{DeniedHosts: []string{"Internal.Example.com"}},
+			url:        "http://files.internal.example.com/t.xlsx",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "host files.internal.example.com is denied",
+		},
+		{
+			name:       "host not in allowed list",
+			cfg:        config.Gateway{AllowedHosts: []string{"docs.google.com"}},
+			url:        "http://93.184.216.34/table.xlsx",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "host 93.184.216.34 is not in the allowed list",
+		},
+		{
+			name:       "allowed list does not match by suffix",
+			cfg:        config.Gateway{AllowedHosts: []string{"google.com"}},
+			url:        "http://evilgoogle.com/t.xlsx",
+			wantErr:    ErrForbiddenURL,
+			wantReason: "host evilgoogle.com is not in the allowed list",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGateway(config.Config{Gateway: tt.cfg})
+
+			err := g.CheckURL(tt.url)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantReason != "" && assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestGateway_Table_Policy(t *testing.T) {
+	table := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("offer_id;name;price;quantity"))
+	})
+
+	t.Run("private address on connect", func(t *testing.T) {
+		server := httptest.NewServer(table)
+		defer server.Close()
+
+		// имя проходит проверку по спискам, адрес проверяется при подключении
+		u, _ := url.Parse(server.URL)
+		u.Host = strings.Replace(u.Host, "127.0.0.1", "localhost", 1)
+
+		_, err := NewGateway(config.Config{Gateway: config.Gateway{Timeout: 5}}).Table(context.Background(), u.String(), models.TableAuth{}, models.TableFetch{})
+		assert.ErrorIs(t, err, ErrForbiddenURL)
+	})
+
+	t.Run("redirect to denied host", func(t *testing.T) {
+		target := httptest.NewServer(table)
+		defer target.Close()
+
+		u, _ := url.Parse(target.URL)
+		u.Host = strings.Replace(u.Host, "127.0.0.1", "localhost", 1)
+		redirect := httptest.NewServer(http.RedirectHandler(u.String(), http.StatusFound))
+		defer redirect.Close()
+
+		cfg := config.Gateway{Timeout: 5, AllowPrivateIPs: true, DeniedHosts: []string{"localhost"}}
+		_, err := NewGateway(config.Config{Gateway: cfg}).Table(context.Background(), redirect.URL, models.TableAuth{}, models.TableFetch{})
+		assert.ErrorIs(t, err, ErrForbiddenURL)
+	})
+
+	t.Run("too many redirects", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, server.URL+r.URL.Path+"x", http.StatusFound)
+		}))
+		defer server.Close()
+
+		cfg := config.Gateway{Timeout: 5, AllowPrivateIPs: true, MaxRedirects: 2}
+		_, err := NewGateway(config.Config{Gateway: cfg}).Table(context.Background(), server.URL+"/", models.TableAuth{}, models.TableFetch{})
+		assert.ErrorIs(t, err, ErrTooManyRedirects)
+	})
+
+	t.Run("redirects within limit", func(t *testing.T) {
+		target := httptest.NewServer(table)
+		defer target.Close()
+		redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+		defer redirect.Close()
+
+		cfg := config.Gateway{Timeout: 5, AllowPrivateIPs: true, MaxRedirects: 1}
+		tbl, err := NewGateway(config.Config{Gateway: cfg}).Table(context.Background(), redirect.URL, models.TableAuth{}, models.TableFetch{})
+		if assert.NoError(t, err) {
+			assert.NoError(t, tbl.Body.Close())
+		}
+	})
+}
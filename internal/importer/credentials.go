@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hablof/merchant-experience/internal/gateway"
+	"github.com/hablof/merchant-experience/internal/models"
+)
+
+var (
+	ErrCredentialsDisabled = errors.New("credentials are not accepted: no encryption key configured")
+
+	// учётные данные задачи не расшифровываются: ключ сменили или данные повреждены
+	errCredentialsUnavailable = errors.New("credentials unavailable")
+)
+
+// moveUserinfo переносит логин и пароль из ссылки в basic auth, чтобы они не хранились в table_url открытым текстом
+func moveUserinfo(tableURL string, auth models.TableAuth) (string, models.TableAuth) {
+	u, err := url.Parse(tableURL)
+	if err != nil || u.User == nil {
+		return tableURL, auth
+	}
+
+	if auth.Username == "" && auth.Password == "" && auth.BearerToken == "" {
+		auth.Username = u.User.Username()
+		auth.Password, _ = u.User.Password()
+	}
+	u.User = nil
+
+	return u.String(), auth
+}
+
+// moveQuery переносит параметры ссылки в учётные данные: в подписанных ссылках это подпись или токен.
+// В table_url остаются только имена параметров, перед скачиванием ссылку восстанавливает withQuery.
+func moveQuery(tableURL string, auth models.TableAuth) (string, models.TableAuth) {
+	u, err := url.Parse(tableURL)
+	if err != nil || u.RawQuery == "" {
+		return tableURL, auth
+	}
+
+	auth.Query = u.RawQuery
+
+	return gateway.RedactURL(tableURL), auth
+}
+
+// параметры, по имени которых ссылка считается подписанной: значения таких параметров - подпись или токен
+var (
+	signedParams      = map[string]bool{"sig": true, "key": true, "auth": true, "policy": true, "key-pair-id": true, "code": true}
+	signedParamsParts = []string{"signature", "token", "secret", "password", "credential", "apikey", "api_key", "api-key"}
+)
+
+// signedQuery проверяет, есть ли в ссылке параметр, похожий на подпись или токен
+func signedQuery(tableURL string) bool {
+	u, err := url.Parse(tableURL)
+	if err != nil || u.RawQuery == "" {
+		return false
+	}
+
+	for key := range u.Query() {
+		key = strings.ToLower(key)
+		if signedParams[key] {
+			return true
+		}
+		for _, part := range signedParamsParts {
+			if strings.Contains(key, part) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func withQuery(tableURL string, query string) string {
+	u, err := url.Parse(tableURL)
+	if err != nil || query == "" {
+		return tableURL
+	}
+	u.RawQuery = query
+
+	return u.String()
+}
+
+// SealSource готовит источник таблицы к хранению: логин и пароль из ссылки переносит в basic auth, а её параметры -
+// в учётные данные, если есть ключ шифрования; без ключа остаются в ссылке только параметры без подписи и токенов.
+// Возвращает ссылку для table_url и зашифрованные учётные данные (nil, если их нет);
+// учётные данные или подписанная ссылка без ключа - ErrCredentialsDisabled.
+func (i *Importer) SealSource(tableURL string, auth models.TableAuth) (string, []byte, error) {
+	tableURL, auth = moveUserinfo(tableURL, auth)
+	if i.box != nil {
+		tableURL, auth = moveQuery(tableURL, auth)
+	} else if signedQuery(tableURL) {
+		return "", nil, ErrCredentialsDisabled
+	}
+
+	sealed, err := i.sealAuth(auth)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return tableURL, sealed, nil
+}
+
+// sealAuth шифрует учётные данные для хранения в задаче; пустые не сохраняются
+func (i *Importer) sealAuth(auth models.TableAuth) ([]byte, error) {
+	if auth.Empty() {
+		return nil, nil
+	}
+
+	if i.box == nil {
+		return nil, ErrCredentialsDisabled
+	}
+
+	b, err := json.Marshal(auth)
+	if err != nil {
+		log.Println(err)
+		return nil, ErrEnqueueFailed
+	}
+
+	sealed, err := i.box.Seal(b)
+	if err != nil {
+		log.Println(err)
+		return nil, ErrEnqueueFailed
+	}
+
+	return sealed, nil
+}
+
+func (i *Importer) openAuth(sealed []byte) (models.TableAuth, error) {
+	if len(sealed) == 0 {
+		return models.TableAuth{}, nil
+	}
+
+	if i.box == nil {
+		return models.TableAuth{}, errCredentialsUnavailable
+	}
+
+	b, err := i.box.Open(sealed)
+	if err != nil {
+		return models.TableAuth{}, errCredentialsUnavailable
+	}
+
+	auth := models.TableAuth{}
+	if err := json.Unmarshal(b, &auth); err != nil {
+		return models.TableAuth{}, errCredentialsUnavailable
+	}
+
+	return auth, nil
+}
@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"reflect"
+
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/service"
+)
+
+// lastFetch возвращает валидаторы последней загрузки той же таблицы, изменившей каталог продавца, если она была
+// с теми же настройками и после неё каталог не меняли: иначе таблица должна записаться заново и вернуть правки.
+// Предпросмотр, загруженные файлы и раскладка листов по продавцам всегда обрабатываются заново.
+func (i *Importer) lastFetch(job models.Job) models.TableFetch {
+	if job.DryRun || len(job.Sheets) > 0 {
+		return models.TableFetch{}
+	}
+	if isUpload(job.TableURL) {
+		return models.TableFetch{}
+	}
+
+	last, err := i.repo.LastImport(job.SellerId, job.TableURL)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.TableFetch{}
+	}
+	if err != nil {
+		// без валидаторов таблица просто обработается целиком
+		log.Printf("failed to get last import of seller %d: %v", job.SellerId, err)
+		return models.TableFetch{}
+	}
+
+	if last.SyncMode != job.SyncMode ||
+		last.Sheet != job.Sheet ||
+		!reflect.DeepEqual(last.Sheets, job.Sheets) {
+
+		return models.TableFetch{}
+	}
+
+	// правки через API, откаты и загрузки других таблиц, зафиксированные после её записи в каталог
+	changed, err := i.repo.CatalogChangedSince(job.SellerId, last.Id)
+	if err != nil {
+		log.Printf("failed to check catalog changes of seller %d: %v", job.SellerId, err)
+		return models.TableFetch{}
+	}
+	if changed {
+		return models.TableFetch{}
+	}
+
+	return last.TableFetch
+}
+
+// unchanged: источник ответил 304 или прислал ту же таблицу, что в прошлый раз
+func unchanged(table models.Table, prev models.TableFetch) bool {
+	if table.NotModified {
+		return true
+	}
+
+	return prev.ContentHash != "" && table.Fetch.ContentHash == prev.ContentHash
+}
+
+// finishUnchanged завершает задачу без разбора и записи. Валидаторы не сохраняются:
+// следующая загрузка сравнивается с той, что действительно изменила каталог, и после её отката таблица обработается заново.
+func (i *Importer) finishUnchanged(job models.Job) {
+	b, err := json.Marshal(service.UpdateResults{Issues: []models.ImportIssue{}, NoChanges: true})
+	if err != nil {
+		log.Println(err.Error())
+		i.fail(job, "service error")
+
+		return
+	}
+
+	if err := i.repo.FinishJob(job.Id, job.Attempts, models.JobDone, b, ""); err != nil {
+		log.Printf("failed to finish job #%d: %v", job.Id, err)
+	}
+}
+
+// saveFetch запоминает валидаторы скачанной таблицы; у загруженных файлов их нет
+func (i *Importer) saveFetch(jobId uint64, fetch models.TableFetch) {
+	if fetch.ContentHash == "" {
+		return
+	}
+
+	if err := i.repo.SaveJobFetch(jobId, fetch); err != nil {
+		log.Printf("failed to save fetch of job #%d: %v", jobId, err)
+	}
+}
@@ -0,0 +1,166 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/xlsxparser"
+)
+
+func TestImporter_process_unchanged(t *testing.T) {
+	lastFetch := models.TableFetch{ETag: `"v1"`, LastModified: "Tue, 01 Aug 2023 12:00:00 GMT", ContentHash: "abc"}
+	finishedAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	lastImport := models.Job{Id: 7, SellerId: 42, TableURL: "some.url/t", Status: models.JobDone, UpdatedAt: finishedAt, TableFetch: lastFetch}
+	noChanges := []byte(`{"added":0,"updated":0,"deleted":0,"issues":[],"noChanges":true}`)
+
+	tests := []struct {
+		name           string
+		job            models.Job
+		repoBehaviour  func(rm *RepositoryMock)
+		wantPrev       models.TableFetch
+		table          models.Table
+		wantProcessed  bool
+		wantSavedFetch bool
+	}{
+		{
+			name: "first import",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t"},
+			repoBehaviour: func(rm *RepositoryMock) {
+				rm.LastImportMock.Expect(42, "some.url/t").Return(models.Job{}, models.ErrNotFound)
+			},
+			wantPrev:       models.TableFetch{},
+			table:          models.Table{Body: newTableBody("table mock"), Fetch: models.TableFetch{ContentHash: "abc"}},
+			wantProcessed:  true,
+			wantSavedFetch: true,
+		},
+		{
+			name: "not modified",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t"},
+			repoBehaviour: func(rm *RepositoryMock) {
+				rm.LastImportMock.Expect(42, "some.url/t").Return(lastImport, nil)
+				rm.CatalogChangedSinceMock.Expect(42, 7).Return(false, nil)
+			},
+			wantPrev: lastFetch,
+			table:    models.Table{NotModified: true, Fetch: lastFetch},
+		},
+		{
+			name: "same content hash",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t"},
+			repoBehaviour: func(rm *RepositoryMock) {
+				rm.LastImportMock.Expect(42, "some.url/t").Return(lastImport, nil)
+				rm.CatalogChangedSinceMock.Expect(42, 7).Return(false, nil)
+			},
+			wantPrev: lastFetch,
+			table:    models.Table{Body: newTableBody("table mock"), Fetch: models.TableFetch{ETag: `"v2"`, ContentHash: "abc"}},
+		},
+		{
+			name: "content changed",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t"},
+			repoBehaviour: func(rm *RepositoryMock) {
+				rm.LastImportMock.Expect(42, "some.url/t").Return(lastImport, nil)
+				rm.CatalogChangedSinceMock.Expect(42, 7).Return(false, nil)
+			},
+			wantPrev:       lastFetch,
+			table:          models.Table{Body: newTableBody("table mock"), Fetch: models.TableFetch{ContentHash: "def"}},
+			wantProcessed:  true,
+			wantSavedFetch: true,
+		},
+		{
+			name: "another url of the seller",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/other"},
+			repoBehaviour: func(rm *RepositoryMock) {
+				rm.LastImportMock.Expect(42, "some.url/other").Return(models.Job{}, models.ErrNotFound)
+			},
+			wantPrev:       models.TableFetch{},
+			table:          models.Table{Body: newTableBody("table mock"), Fetch: models.TableFetch{ContentHash: "abc"}},
+			wantProcessed:  true,
+			wantSavedFetch: true,
+		},
+		{
+			name: "catalog changed after last import",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t"},
+			repoBehaviour: func(rm *RepositoryMock) {
+				rm.LastImportMock.Expect(42, "some.url/t").Return(lastImport, nil)
+				rm.CatalogChangedSinceMock.Expect(42, 7).Return(true, nil)
+			},
+			wantPrev:       models.TableFetch{},
+			table:          models.Table{Body: newTableBody("table mock"), Fetch: models.TableFetch{ContentHash: "abc"}},
+			wantProcessed:  true,
+			wantSavedFetch: true,
+		},
+		{
+			name: "catalog changes lookup failed",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t"},
+			repoBehaviour: func(rm *RepositoryMock) {
+				rm.LastImportMock.Expect(42, "some.url/t").Return(lastImport, nil)
+				rm.CatalogChangedSinceMock.Expect(42, 7).Return(false, errors.New("some err"))
+			},
+			wantPrev:       models.TableFetch{},
+			table:          models.Table{Body: newTableBody("table mock"), Fetch: models.TableFetch{ContentHash: "abc"}},
+			wantProcessed:  true,
+			wantSavedFetch: true,
+		},
+		{
+			name: "last import in another sync mode",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t", SyncMode: models.SyncModeReplace},
+			repoBehaviour: func(rm *RepositoryMock) {
+				rm.LastImportMock.Expect(42, "some.url/t").Return(lastImport, nil)
+			},
+			wantPrev:       models.TableFetch{},
+			table:          models.Table{Body: newTableBody("table mock"), Fetch: models.TableFetch{ContentHash: "abc"}},
+			wantProcessed:  true,
+			wantSavedFetch: true,
+		},
+		{
+			name: "last import lookup failed",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t"},
+			repoBehaviour: func(rm *RepositoryMock) {
+				rm.LastImportMock.Expect(42, "some.url/t").Return(models.Job{}, errors.New("some err"))
+			},
+			wantPrev:       models.TableFetch{},
+			table:          models.Table{Body: newTableBody("table mock"), Fetch: models.TableFetch{ContentHash: "abc"}},
+			wantProcessed:  true,
+			wantSavedFetch: true,
+		},
+		{
+			name:           "dry run is always processed",
+			job:            models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t", DryRun: true},
+			repoBehaviour:  func(rm *RepositoryMock) {},
+			wantPrev:       models.TableFetch{},
+			table:          models.Table{Body: newTableBody("table mock"), Fetch: models.TableFetch{ContentHash: "abc"}},
+			wantProcessed:  true,
+			wantSavedFetch: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rm := NewRepositoryMock(mc)
+			tdm := NewTableDownloaderMock(mc)
+			epm := NewExcelParserMock(mc)
+			i := NewImporter(config.Config{}, rm, NewServiceMock(mc), tdm, epm, NewExcelParserMock(mc))
+
+			tt.repoBehaviour(rm)
+			expectTable(tdm, tt.job.TableURL, models.TableAuth{}, tt.wantPrev, tt.table, nil)
+			if tt.wantSavedFetch {
+				rm.SaveJobFetchMock.Expect(1, tt.table.Fetch).Return(nil)
+			}
+			if tt.wantProcessed {
+				// разбор дошёл до парсера - дальше обработка как обычно
+				rm.SetJobStatusMock.Expect(1, 0, models.JobParsing).Return(nil)
+				epm.StreamProductsMock.Set(streamOnce(nil, nil, xlsxparser.ErrEmptyDoc))
+				rm.FinishJobMock.Expect(1, 0, models.JobFailed, nil, "bad table file").Return(nil)
+			} else {
+				rm.FinishJobMock.Expect(1, 0, models.JobDone, noChanges, "").Return(nil)
+			}
+
+			i.process(tt.job)
+		})
+	}
+}
@@ -0,0 +1,866 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/gateway"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/secrets"
+	"github.com/hablof/merchant-experience/internal/service"
+	"github.com/hablof/merchant-experience/internal/xlsxparser"
+)
+
+const (
+	defaultWorkers      = 1
+	defaultPollInterval = 5 * time.Second
+	defaultJobTimeout   = 10 * time.Minute
+	defaultLeaseTimeout = time.Minute
+	defaultMaxAttempts  = 3
+	defaultBatchSize    = 5000
+)
+
+var (
+	ErrJobNotFound   = errors.New("job not found")
+	ErrEnqueueFailed = errors.New("failed to enqueue job")
+	ErrRepoFailed    = errors.New("repo err")
+
+	ErrJobNotFinished  = errors.New("job is not finished")
+	ErrNothingToRevert = errors.New("dry run job changed nothing")
+	ErrAlreadyReverted = errors.New("job already reverted")
+	ErrRevertConflict  = errors.New("products changed after job")
+
+	ErrReportNotFound = errors.New("report not found")
+
+	ErrJobNotHeld = errors.New("job is not held")
+
+	// обработчик пачки прерывает разбор таблицы при ошибке сервиса
+	errServiceFailed = errors.New("service error")
+	// и при срабатывании защиты каталога
+	errGuardTripped = errors.New("guard tripped")
+	// и когда истекло время задачи
+	errJobTimeout = errors.New("job timeout")
+	// и когда оффер повторяется на листах одного продавца
+	errSheetsOverlap = errors.New("offer repeats on sheets of one seller")
+	// и когда аренду задачи потеряли: её вернули в очередь и выполняет другой воркер
+	errLeaseLost = errors.New("job lease lost")
+)
+
+// действие при срабатывании защиты каталога (config.Guard.Action)
+const guardActionReject = "reject"
+
+type TableDownloader interface {
+	// auth - учётные данные источника, пустые для открытых ссылок;
+	// prev - валидаторы прошлой загрузки, по ним источник может ответить NotModified;
+	// ctx ограничивает скачивание временем задачи
+	Table(ctx context.Context, url string, auth models.TableAuth, prev models.TableFetch) (models.Table, error)
+	// CheckURL проверяет, что ссылку можно скачивать; ошибка объясняет причину
+	CheckURL(url string) error
+}
+
+type ExcelParser interface {
+	// sheet - лист книги, пусто - первый лист
+	StreamProducts(r io.ReadSeeker, sheet string, batchSize int, handle xlsxparser.BatchHandler) error
+	// листы книги - для models.AllSheets; у CSV листов нет (xlsxparser.ErrSheetNotFound)
+	SheetNames(r io.ReadSeeker) ([]string, error)
+	WriteErrorReport(r io.ReadSeeker, issues []models.ImportIssue, w io.Writer) error
+}
+
+type Service interface {
+	BeginImport(sellerId uint64, opts service.UpdateOptions) (service.Import, error)
+}
+
+type Repository interface {
+	CreateJob(job models.Job) (models.Job, error)
+	CreateUploadJob(job models.Job, content []byte) (models.Job, error)
+	JobUpload(jobId uint64) ([]byte, error)
+	DeleteJobUpload(jobId uint64) error
+	ClaimJob() (models.Job, error)
+	SetJobStatus(jobId uint64, attempt uint32, status models.JobStatus) error
+	TouchJob(jobId uint64, attempt uint32) error
+	FinishJob(jobId uint64, attempt uint32, status models.JobStatus, results []byte, errMsg string) error
+	Job(jobId uint64) (models.Job, error)
+	RequeueStaleJobs(staleAfter time.Duration, maxAttempts uint32) (requeued uint64, failed uint64, err error)
+	RevertJob(jobId uint64) (models.RevertResults, error)
+	SaveJobReport(jobId uint64, report []byte) error
+	JobReport(jobId uint64) ([]byte, error)
+	ConfirmJob(jobId uint64) error
+	SaveJobFetch(jobId uint64, fetch models.TableFetch) error
+	LastImport(sellerId uint64, tableURL string) (models.Job, error)
+	CatalogChangedSince(sellerId uint64, jobId uint64) (bool, error)
+}
+
+// Importer хранит задачи в базе и выполняет их пулом воркеров:
+// TableDownloader -> ExcelParser (xlsx или csv) -> Service.BeginImport
+type Importer struct {
+	repo Repository
+	s    Service
+	td   TableDownloader
+	ep   ExcelParser
+	cp   ExcelParser
+
+	workers      int
+	pollInterval time.Duration
+	jobTimeout   time.Duration
+	// выполняемая задача без продления аренды дольше leaseTimeout возвращается в очередь
+	leaseTimeout time.Duration
+	// брошенная на этой попытке задача завершается ошибкой, а не возвращается в очередь
+	maxAttempts uint32
+	batchSize   int
+	// задача, остановленная защитой каталога, ждёт подтверждения; иначе завершается ошибкой
+	holdOnGuard bool
+	// шифрует учётные данные источников в задачах; nil - учётные данные не принимаются
+	box *secrets.Box
+
+	// будит воркеры сразу после постановки задачи, не дожидаясь pollInterval
+	wakeup chan struct{}
+}
+
+// ep разбирает xlsx, cp - csv/tsv
+func NewImporter(cfg config.Config, repo Repository, s Service, td TableDownloader, ep ExcelParser, cp ExcelParser) *Importer {
+	i := Importer{
+		repo:         repo,
+		s:            s,
+		td:           td,
+		ep:           ep,
+		cp:           cp,
+		workers:      cfg.Importer.Workers,
+		pollInterval: time.Duration(cfg.Importer.PollInterval) * time.Second,
+		jobTimeout:   time.Duration(cfg.Importer.JobTimeout) * time.Second,
+		leaseTimeout: time.Duration(cfg.Importer.LeaseTimeout) * time.Second,
+		maxAttempts:  cfg.Importer.MaxAttempts,
+		batchSize:    cfg.Importer.BatchSize,
+		holdOnGuard:  cfg.Guard.Action != guardActionReject,
+	}
+
+	if i.workers <= 0 {
+		i.workers = defaultWorkers
+	}
+	if i.pollInterval <= 0 {
+		i.pollInterval = defaultPollInterval
+	}
+	if i.jobTimeout <= 0 {
+		i.jobTimeout = defaultJobTimeout
+	}
+	if i.leaseTimeout <= 0 {
+		i.leaseTimeout = defaultLeaseTimeout
+	}
+	if i.maxAttempts == 0 {
+		i.maxAttempts = defaultMaxAttempts
+	}
+	if i.batchSize <= 0 {
+		i.batchSize = defaultBatchSize
+	}
+
+	box, err := secrets.NewBox(cfg)
+	switch {
+	case errors.Is(err, secrets.ErrNoKey):
+	case err != nil:
+		log.Printf("credentials for table sources are disabled: %v", err)
+	}
+	i.box = box
+
+	i.wakeup = make(chan struct{}, i.workers)
+
+	return &i
+}
+
+// Enqueue сохраняет задачу в базе и сразу возвращает её, не дожидаясь выполнения.
+// Ссылка, запрещённая политикой gateway, возвращается ошибкой gateway.ErrForbiddenURL с причиной.
+// Учётные данные источника (в том числе логин, пароль и параметры из ссылки) сохраняются в задаче зашифрованными (SealSource).
+func (i *Importer) Enqueue(job models.Job, auth models.TableAuth) (models.Job, error) {
+	if err := i.td.CheckURL(job.TableURL); err != nil {
+		log.Println(err)
+		return models.Job{}, err
+	}
+
+	tableURL, sealed, err := i.SealSource(job.TableURL, auth)
+	if err != nil {
+		return models.Job{}, err
+	}
+	job.TableURL, job.Auth = tableURL, sealed
+
+	return i.enqueue(job)
+}
+
+// EnqueueSealed ставит задачу, источник которой уже подготовлен SealSource: ссылка и job.Auth сохраняются как есть
+func (i *Importer) EnqueueSealed(job models.Job) (models.Job, error) {
+	if err := i.td.CheckURL(job.TableURL); err != nil {
+		log.Println(err)
+		return models.Job{}, err
+	}
+
+	return i.enqueue(job)
+}
+
+func (i *Importer) enqueue(job models.Job) (models.Job, error) {
+	createdJob, err := i.repo.CreateJob(job)
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrEnqueueFailed
+	}
+
+	i.wake()
+
+	return createdJob, nil
+}
+
+// wake будит воркер, не дожидаясь pollInterval
+func (i *Importer) wake() {
+	select {
+	case i.wakeup <- struct{}{}:
+	default: // все воркеры и так будут разбужены
+	}
+}
+
+func (i *Importer) Job(jobId uint64) (models.Job, error) {
+	job, err := i.repo.Job(jobId)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return models.Job{}, ErrJobNotFound
+
+	case err != nil:
+		log.Println(err)
+		return models.Job{}, ErrRepoFailed
+	}
+
+	return job, nil
+}
+
+// Revert возвращает офферы, затронутые завершённой загрузкой, к состоянию до неё
+func (i *Importer) Revert(jobId uint64) (models.RevertResults, error) {
+	job, err := i.Job(jobId)
+	if err != nil {
+		return models.RevertResults{}, err
+	}
+
+	switch {
+	// у упавшей или остановленной защитой задачи по нескольким листам могут быть записаны другие листы
+	case job.Status != models.JobDone && job.Status != models.JobFailed && job.Status != models.JobHeld:
+		return models.RevertResults{}, ErrJobNotFinished
+
+	case job.DryRun:
+		return models.RevertResults{}, ErrNothingToRevert
+	}
+
+	results, err := i.repo.RevertJob(jobId)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return models.RevertResults{}, ErrJobNotFound
+
+	case errors.Is(err, models.ErrAlreadyReverted):
+		return models.RevertResults{}, ErrAlreadyReverted
+
+	case errors.Is(err, models.ErrConflict):
+		return models.RevertResults{}, ErrRevertConflict
+
+	case err != nil:
+		log.Println(err)
+		return models.RevertResults{}, ErrRepoFailed
+	}
+
+	// откаченную задачу уже не подтвердить, загруженный файл больше не нужен
+	if job.Status == models.JobHeld {
+		i.removeUpload(job)
+	}
+
+	return results, nil
+}
+
+// Confirm возвращает в очередь задачу, остановленную защитой каталога; таблица загружается заново и без защиты.
+// В задаче по нескольким листам заново загружаются только листы, остановленные защитой (см. processSheets).
+func (i *Importer) Confirm(jobId uint64) (models.Job, error) {
+	job, err := i.Job(jobId)
+	if err != nil {
+		return models.Job{}, err
+	}
+
+	if job.Status != models.JobHeld || job.RevertedAt != nil {
+		return models.Job{}, ErrJobNotHeld
+	}
+
+	err = i.repo.ConfirmJob(jobId)
+	switch {
+	// задачу успели подтвердить или откатить параллельным запросом
+	case errors.Is(err, models.ErrNotFound):
+		return models.Job{}, ErrJobNotHeld
+
+	case err != nil:
+		log.Println(err)
+		return models.Job{}, ErrRepoFailed
+	}
+
+	i.wake()
+
+	job.Status = models.JobQueued
+	job.Confirmed = true
+
+	return job, nil
+}
+
+// Report возвращает xlsx-отчёт об ошибках строк задачи
+func (i *Importer) Report(jobId uint64) ([]byte, error) {
+	report, err := i.repo.JobReport(jobId)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		if _, err := i.Job(jobId); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrReportNotFound
+
+	case err != nil:
+		log.Println(err)
+		return nil, ErrRepoFailed
+	}
+
+	return report, nil
+}
+
+// Run запускает воркеры и блокируется до отмены контекста и завершения текущих задач
+func (i *Importer) Run(ctx context.Context) {
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		i.requeueStale(ctx)
+	}()
+
+	for n := 0; n < i.workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			i.work(ctx)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// requeueStale возвращает в очередь задачи, брошенные упавшими или перезапущенными воркерами (в том числе других реплик):
+// сразу при запуске и затем каждые pollInterval
+func (i *Importer) requeueStale(ctx context.Context) {
+	ticker := time.NewTicker(i.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		requeued, failed, err := i.repo.RequeueStaleJobs(i.leaseTimeout, i.maxAttempts)
+		if err != nil {
+			log.Printf("failed to requeue stale jobs: %v", err)
+		}
+		if failed > 0 {
+			log.Printf("failed %d stale job(s) after %d attempts", failed, i.maxAttempts)
+		}
+		if requeued > 0 {
+			log.Printf("requeued %d stale job(s)", requeued)
+			i.wake()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// heartbeat продлевает аренду задачи, пока она выполняется. Если аренду потеряли (задачу вернули в очередь
+// и забрал другой воркер), отменяет ctx задачи с причиной errLeaseLost: её результат уже не сохранится.
+func (i *Importer) heartbeat(ctx context.Context, lose context.CancelCauseFunc, job models.Job) {
+	ticker := time.NewTicker(i.leaseTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := i.repo.TouchJob(job.Id, job.Attempts)
+		if errors.Is(err, models.ErrNotFound) {
+			log.Printf("job #%d lease lost", job.Id)
+			lose(errLeaseLost)
+
+			return
+		}
+		if err != nil {
+			log.Printf("failed to extend job #%d lease: %v", job.Id, err)
+		}
+	}
+}
+
+func (i *Importer) work(ctx context.Context) {
+	ticker := time.NewTicker(i.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// разбираем очередь, пока она не опустеет
+		for ctx.Err() == nil {
+			job, err := i.repo.ClaimJob()
+			if errors.Is(err, models.ErrNotFound) {
+				break
+			}
+			if err != nil {
+				log.Printf("failed to claim job: %v", err)
+				break
+			}
+
+			i.process(job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-i.wakeup:
+		}
+	}
+}
+
+// process выполняет задачу, уже переведённую в статус downloading. На всю задачу отводится jobTimeout;
+// пока она выполняется, аренда продлевается, чтобы задачу не вернули в очередь. Потерянная аренда прерывает задачу.
+func (i *Importer) process(job models.Job) {
+	timeoutCtx, cf := context.WithTimeout(context.Background(), i.jobTimeout)
+	defer cf()
+	ctx, lose := context.WithCancelCause(timeoutCtx)
+	defer lose(nil)
+
+	go i.heartbeat(ctx, lose, job)
+
+	// загруженный файл нужен только до конца обработки; при падении сервиса задача вернётся в очередь вместе с ним.
+	// Остановленная защитой задача после подтверждения читает файл заново, а потерявшая аренду - другой воркер.
+	held := false
+	defer func() {
+		if !held && !errors.Is(context.Cause(ctx), errLeaseLost) {
+			i.removeUpload(job)
+		}
+	}()
+
+	prev := i.lastFetch(job)
+	table, err := i.table(ctx, job, prev)
+	if err != nil {
+		log.Println("failed to get table: " + err.Error())
+		i.fail(job, downloadErrMsg(err))
+
+		return
+	}
+
+	defer func() {
+		// на 304 тела нет
+		if table.Body == nil {
+			return
+		}
+		if err := table.Body.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	if unchanged(table, prev) {
+		i.finishUnchanged(job)
+		return
+	}
+	i.saveFetch(job.Id, table.Fetch)
+
+	i.setStatus(job, models.JobParsing)
+
+	writing := false
+	onWrite := func() {
+		if !writing {
+			writing = true
+			i.setStatus(job, models.JobWriting)
+		}
+	}
+
+	parser := i.parserFor(table.ContentType, job.TableURL)
+	if len(job.Sheets) > 0 {
+		held = i.processSheets(ctx, job, table.Body, parser, onWrite)
+		return
+	}
+
+	run := i.processSheet(ctx, job, table.Body, parser, job.Sheet, job.SellerId, onWrite)
+	held = run.status == models.JobHeld
+
+	var b []byte
+	if run.hasResults {
+		b, err = json.Marshal(run.results)
+		if err != nil {
+			log.Println(err.Error())
+			i.fail(job, "service error")
+
+			return
+		}
+
+		i.saveReport(job.Id, parser, table.Body, run.results.Issues)
+	}
+
+	if err := i.repo.FinishJob(job.Id, job.Attempts, run.status, b, run.errMsg); err != nil {
+		log.Printf("failed to finish job #%d: %v", job.Id, err)
+	}
+}
+
+// sheetRun - итог разбора и записи одного листа таблицы
+type sheetRun struct {
+	results service.UpdateResults
+	// результаты сохраняются, если лист записан или остановлен защитой каталога
+	hasResults bool
+	// done, failed или held
+	status models.JobStatus
+	errMsg string
+}
+
+// processSheet разбирает лист sheet (пусто - первый лист) и пишет его в каталог продавца sellerId
+func (i *Importer) processSheet(ctx context.Context, job models.Job, table io.ReadSeeker, parser ExcelParser, sheet string, sellerId uint64, onWrite func()) sheetRun {
+	return i.processSeller(ctx, job, table, parser, []string{sheet}, sellerId, onWrite)[0]
+}
+
+// processSeller разбирает листы sheets по очереди и пишет их в каталог продавца sellerId.
+// Пачки всех листов откладываются в одной загрузке (service.Import) и попадают в каталог одной транзакцией
+// после разбора последнего листа, поэтому режим replace удаляет только офферы, которых нет ни на одном листе продавца.
+// Листы записываются вместе или не записываются вовсе, статус и ошибка у них общие; удаления, найденные при записи,
+// и отчёт защиты каталога достаются последнему листу. Проблемам строк проставляется лист, если он задан.
+// Когда истекает ctx, разбор прерывается перед следующей пачкой.
+func (i *Importer) processSeller(ctx context.Context, job models.Job, table io.ReadSeeker, parser ExcelParser, sheets []string, sellerId uint64, onWrite func()) []sheetRun {
+	// загрузка начинается с первой пачки: пока парсер проверяет таблицу, транзакция не открыта
+	var imp service.Import
+	defer func() {
+		// после Commit ничего не делает
+		if imp != nil {
+			imp.Rollback()
+		}
+	}()
+
+	// результаты пачек суммируются по листам
+	totals := make([]service.UpdateResults, len(sheets))
+	for idx := range totals {
+		totals[idx] = service.UpdateResults{Issues: []models.ImportIssue{}, DryRun: job.DryRun}
+	}
+	// лист, который сейчас разбирается
+	cur := 0
+	lastSheet := len(sheets) - 1
+
+	// importErr переводит ошибку загрузки в ошибку обработчика; отчёт сработавшей защиты попадает в results
+	importErr := func(ur service.UpdateResults, err error) error {
+		if errors.Is(err, service.ErrGuardTripped) {
+			// в каталог ничего не записано: счётчики отложенных пачек не показываются
+			for idx := range totals {
+				totals[idx] = service.UpdateResults{Issues: totals[idx].Issues, DryRun: job.DryRun}
+			}
+			totals[lastSheet].Issues = append(totals[lastSheet].Issues, ur.Issues...)
+			totals[lastSheet].Guard = ur.Guard
+
+			return errGuardTripped
+		}
+
+		log.Println(err.Error())
+		return errServiceFailed
+	}
+
+	// офферы листов продавца: оффер с двух листов записался бы дважды
+	var seen map[uint64]struct{}
+	if len(sheets) > 1 {
+		seen = make(map[uint64]struct{})
+	}
+
+	handle := func(productUpdates []models.ProductUpdate, issues []models.ImportIssue, last bool) error {
+		if err := ctxErr(ctx); err != nil {
+			return err
+		}
+
+		if seen != nil {
+			for _, upd := range productUpdates {
+				if _, ok := seen[upd.Product.OfferId]; ok {
+					return errSheetsOverlap
+				}
+				seen[upd.Product.OfferId] = struct{}{}
+			}
+		}
+
+		if imp == nil {
+			onWrite()
+
+			var err error
+			imp, err = i.s.BeginImport(sellerId, service.UpdateOptions{
+				DryRun:    job.DryRun,
+				Source:    models.ChangeSource{Kind: models.ChangeKindImport, JobId: job.Id},
+				Confirmed: job.Confirmed,
+				Mode:      job.SyncMode,
+			})
+			if err != nil {
+				log.Println(err.Error())
+				return errServiceFailed
+			}
+		}
+
+		// офферы строк с ошибками: режим replace их не удаляет
+		var present []uint64
+		if job.SyncMode == models.SyncModeReplace {
+			present = presentOfferIDs(issues)
+		}
+
+		// в пачке только строки с ошибками
+		if len(productUpdates) == 0 && len(present) == 0 {
+			totals[cur].Issues = append(totals[cur].Issues, issues...)
+			return nil
+		}
+
+		ur, err := imp.Stage(productUpdates, present)
+		if err != nil {
+			totals[cur].Issues = append(totals[cur].Issues, issues...)
+			return importErr(ur, err)
+		}
+
+		addResults(&totals[cur], ur)
+		totals[cur].Issues = append(totals[cur].Issues, issues...)
+
+		return nil
+	}
+
+	// лист, на котором прервался разбор
+	var (
+		methodErr   error
+		failedSheet string
+	)
+	for cur = range sheets {
+		if methodErr = parser.StreamProducts(table, sheets[cur], i.batchSize, handle); methodErr != nil {
+			failedSheet = sheets[cur]
+			break
+		}
+	}
+	// отложенные пачки пишутся в каталог, только если разобраны все листы и задача ещё за этим воркером
+	if methodErr == nil && imp != nil {
+		methodErr = ctxErr(ctx)
+	}
+	if methodErr == nil && imp != nil {
+		ur, err := imp.Commit()
+		if err != nil {
+			methodErr = importErr(ur, err)
+		} else {
+			addResults(&totals[lastSheet], ur)
+		}
+	}
+	for idx, sheet := range sheets {
+		if sheet == "" {
+			continue
+		}
+		for issueIdx := range totals[idx].Issues {
+			totals[idx].Issues[issueIdx].Sheet = sheet
+		}
+	}
+
+	status, errMsg, hasResults := i.runStatus(job.Id, methodErr, failedSheet)
+
+	runs := make([]sheetRun, 0, len(sheets))
+	for idx, sheet := range sheets {
+		run := sheetRun{status: status, errMsg: errMsg, hasResults: hasResults}
+		if hasResults {
+			run.results = totals[idx]
+		}
+		// остальные листы продавца не записаны из-за упавшего листа
+		if failedSheet != "" && sheet != failedSheet {
+			run.errMsg = fmt.Sprintf("sheet %s of the same seller: %s", failedSheet, errMsg)
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs
+}
+
+// runStatus - статус листа по ошибке разбора и записи; failedSheet - лист, на котором прервался разбор
+func (i *Importer) runStatus(jobId uint64, methodErr error, failedSheet string) (status models.JobStatus, errMsg string, hasResults bool) {
+	var missingColumnsErr xlsxparser.ErrMissingColumns
+	switch {
+	case errors.Is(methodErr, errServiceFailed):
+		// в каталог ничего не записано
+		return models.JobFailed, "service error", false
+
+	case errors.Is(methodErr, errJobTimeout):
+		log.Printf("job #%d timed out", jobId)
+		return models.JobFailed, "job timeout", false
+
+	case errors.Is(methodErr, errGuardTripped):
+		// офферы, из-за которых сработала защита, отдаются в results
+		if i.holdOnGuard {
+			return models.JobHeld, "guard tripped: confirmation required", true
+		}
+
+		return models.JobFailed, "guard tripped", true
+
+	case errors.Is(methodErr, errLeaseLost):
+		// задачу выполняет другой воркер; статус этой попытки не сохранится
+		return models.JobFailed, "job lease lost", false
+
+	case errors.Is(methodErr, errSheetsOverlap):
+		log.Printf("job #%d: offer repeats on sheets of one seller", jobId)
+		return models.JobFailed, "offer_id repeats on sheets of one seller", false
+
+	case errors.Is(methodErr, xlsxparser.ErrEmptyDoc),
+		errors.Is(methodErr, xlsxparser.ErrEmptySheet),
+		errors.Is(methodErr, xlsxparser.ErrFailedToRead):
+
+		log.Println("bad table file")
+		return models.JobFailed, "bad table file", false
+
+	case errors.Is(methodErr, xlsxparser.ErrSheetNotFound):
+		log.Printf("sheet %q not found", failedSheet)
+		return models.JobFailed, "sheet not found", false
+
+	case errors.Is(methodErr, xlsxparser.ErrInvalidIDs):
+		log.Println("offer_id column has invalid value(s)")
+		return models.JobFailed, "offer_id column has invalid value(s)", false
+
+	case errors.As(methodErr, &missingColumnsErr):
+		log.Println(missingColumnsErr.Error())
+		return models.JobFailed, missingColumnsErr.Error(), false
+
+	case errors.Is(methodErr, xlsxparser.ErrHasDuplicates):
+		log.Println("table has duplicates")
+		return models.JobFailed, "table has duplicates", false
+
+	case methodErr != nil:
+		log.Println(methodErr.Error())
+		return models.JobFailed, "parsing error", false
+	}
+
+	return models.JobDone, "", true
+}
+
+func addResults(total *service.UpdateResults, ur service.UpdateResults) {
+	total.Added += ur.Added
+	total.Updated += ur.Updated
+	total.Deleted += ur.Deleted
+	total.Purged += ur.Purged
+	total.Issues = append(total.Issues, ur.Issues...)
+	total.Diff = append(total.Diff, ur.Diff...)
+
+	// в предпросмотре защита срабатывает без ошибки; отчёт один на лист - его возвращает Commit
+	if ur.Guard != nil {
+		total.Guard = ur.Guard
+	}
+}
+
+// parserFor выбирает парсер по Content-Type, а если он ничего не говорит о формате - по расширению файла в tableURL.
+// По умолчанию таблица считается xlsx.
+func (i *Importer) parserFor(contentType string, tableURL string) ExcelParser {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		switch mediaType {
+		case "text/csv", "application/csv", "text/tab-separated-values":
+			return i.cp
+
+		case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+			return i.ep
+		}
+	}
+
+	u, err := url.Parse(tableURL)
+	if err != nil {
+		return i.ep
+	}
+
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".csv", ".tsv":
+		return i.cp
+	}
+
+	return i.ep
+}
+
+// presentOfferIDs собирает офферы из строк, не прошедших разбор:
+// они есть в таблице, и режим replace не должен их удалять
+func presentOfferIDs(issues []models.ImportIssue) []uint64 {
+	var offerIDs []uint64
+	for _, issue := range issues {
+		if issue.Severity == models.SeverityError {
+			offerIDs = append(offerIDs, issue.OfferId)
+		}
+	}
+
+	return offerIDs
+}
+
+// saveReport сохраняет отчёт об ошибках строк до завершения задачи, чтобы отчёт был доступен вместе с результатом.
+// Без отчёта задача всё равно завершается: ошибки остаются в results.
+func (i *Importer) saveReport(jobId uint64, parser ExcelParser, table io.ReadSeeker, issues []models.ImportIssue) {
+	if len(issues) == 0 {
+		return
+	}
+
+	buf := bytes.Buffer{}
+	if err := parser.WriteErrorReport(table, issues, &buf); err != nil {
+		log.Printf("failed to write job #%d report: %v", jobId, err)
+		return
+	}
+
+	if err := i.repo.SaveJobReport(jobId, buf.Bytes()); err != nil {
+		log.Printf("failed to save job #%d report: %v", jobId, err)
+	}
+}
+
+func (i *Importer) setStatus(job models.Job, status models.JobStatus) {
+	if err := i.repo.SetJobStatus(job.Id, job.Attempts, status); err != nil {
+		log.Printf("failed to set job #%d status %s: %v", job.Id, status, err)
+	}
+}
+
+// ctxErr - ошибка обработчика пачки, если задачу пора прервать: истекло время или потеряна аренда
+func ctxErr(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	if errors.Is(context.Cause(ctx), errLeaseLost) {
+		return errLeaseLost
+	}
+
+	return errJobTimeout
+}
+
+// downloadErrMsg - ошибка задачи, по которой видно, что не так с таблицей по ссылке
+func downloadErrMsg(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "job timeout"
+	case errors.Is(err, gateway.ErrNotFound):
+		return "table not found"
+	case errors.Is(err, gateway.ErrTooLarge):
+		return "table too large"
+	case errors.Is(err, gateway.ErrUpstreamDown):
+		return "table host unavailable"
+	case errors.Is(err, gateway.ErrNotTable):
+		return "not a table file"
+	case errors.Is(err, gateway.ErrFetchFailed):
+		return "failed to fetch table"
+	case errors.Is(err, gateway.ErrForbiddenURL):
+		return "table url not allowed"
+	case errors.Is(err, gateway.ErrTooManyRedirects):
+		return "too many redirects"
+	case errors.Is(err, errCredentialsUnavailable):
+		return "credentials unavailable"
+	case errors.Is(err, errUploadNotFound):
+		return "uploaded table not found"
+	case errors.Is(err, errUploadUnavailable):
+		return "service error"
+	}
+
+	return "bad table url"
+}
+
+func (i *Importer) fail(job models.Job, errMsg string) {
+	if err := i.repo.FinishJob(job.Id, job.Attempts, models.JobFailed, nil, errMsg); err != nil {
+		log.Printf("failed to finish job #%d: %v", job.Id, err)
+	}
+}
@@ -0,0 +1,894 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/gateway"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/service"
+	"github.com/hablof/merchant-experience/internal/xlsxparser"
+	"github.com/stretchr/testify/assert"
+)
+
+// у всех задач в тестах Id = 1
+var importSource = models.ChangeSource{Kind: models.ChangeKindImport, JobId: 1}
+
+// tableBody - тело скачанной таблицы для тестов
+type tableBody struct {
+	*bytes.Reader
+}
+
+func (tableBody) Close() error { return nil }
+
+func newTableBody(s string) tableBody {
+	return tableBody{Reader: bytes.NewReader([]byte(s))}
+}
+
+// streamOnce имитирует парсер, отдающий таблицу одной пачкой
+func streamOnce(pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) func(r io.ReadSeeker, sheet string, batchSize int, handle xlsxparser.BatchHandler) error {
+	return func(r io.ReadSeeker, sheet string, batchSize int, handle xlsxparser.BatchHandler) error {
+		if pRetErr != nil {
+			return pRetErr
+		}
+
+		return handle(pReturns, pRetIssues, true)
+	}
+}
+
+// expectTable ожидает одно скачивание таблицы; контекст задачи не сравнивается, но у него должен быть срок
+func expectTable(tdm *TableDownloaderMock, url string, auth models.TableAuth, prev models.TableFetch, table models.Table, err error) {
+	tdm.TableMock.Set(func(ctx context.Context, gotURL string, gotAuth models.TableAuth, gotPrev models.TableFetch) (models.Table, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			tdm.t.Errorf("TableDownloaderMock.Table called without job deadline")
+		}
+
+		want := []interface{}{url, auth, prev}
+		got := []interface{}{gotURL, gotAuth, gotPrev}
+		if !minimock.Equal(want, got) {
+			tdm.t.Errorf("TableDownloaderMock.Table got unexpected parameters, want: %#v, got: %#v%s\n", want, got, minimock.Diff(want, got))
+		}
+
+		return table, err
+	})
+}
+
+// writeReport имитирует парсер, пишущий отчёт об ошибках
+func writeReport(report string) func(r io.ReadSeeker, issues []models.ImportIssue, w io.Writer) error {
+	return func(r io.ReadSeeker, issues []models.ImportIssue, w io.Writer) error {
+		_, err := io.WriteString(w, report)
+		return err
+	}
+}
+
+// expectImport ожидает загрузку листа в каталог sellerId и возвращает её мок; Rollback вызывается и после Commit
+func expectImport(sm *ServiceMock, sellerId uint64, opts service.UpdateOptions) *ImportMock {
+	im := NewImportMock(sm.t)
+	sm.BeginImportMock.When(sellerId, opts).Then(im, nil)
+	im.RollbackMock.Return()
+
+	return im
+}
+
+func TestImporter_process(t *testing.T) {
+
+	job := models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t", Status: models.JobDownloading}
+	productUpdates := []models.ProductUpdate{
+		{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true},
+		{Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true},
+	}
+	issues := []models.ImportIssue{
+		{Row: 3, OfferId: 3, Field: "name", Code: models.CodeTooLongName, Severity: models.SeverityError, Message: models.MsgTooLongName},
+		{Row: 4, OfferId: 4, Field: "price", Code: models.CodeInvalidNumber, Severity: models.SeverityError, Message: `must be a non-negative integer, got "0-40"`},
+	}
+
+	tests := []struct {
+		name string
+
+		tdReturns    models.Table
+		tdReturnsErr error
+		tdBehaviour  func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error)
+
+		parserReturns    []models.ProductUpdate
+		parserRetIssues  []models.ImportIssue
+		parserReturnsErr error
+		parserBehaviour  func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error)
+
+		serviceReturns    service.UpdateResults
+		serviceReturnsErr error
+		serviceBehaviour  func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error)
+
+		statusBehaviour func(rm *RepositoryMock)
+
+		wantStatus  models.JobStatus
+		wantResults []byte
+		wantErrMsg  string
+	}{
+		{
+			name:         "bad table url",
+			tdReturns:    models.Table{},
+			tdReturnsErr: errors.New("some table downloader err"),
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+			},
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {},
+			statusBehaviour:  func(rm *RepositoryMock) {},
+
+			wantStatus: models.JobFailed,
+			wantErrMsg: "bad table url",
+		},
+		{
+			name:         "table not found",
+			tdReturns:    models.Table{},
+			tdReturnsErr: fmt.Errorf("%w: 404 Not Found", gateway.ErrNotFound),
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+			},
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {},
+			statusBehaviour:  func(rm *RepositoryMock) {},
+
+			wantStatus: models.JobFailed,
+			wantErrMsg: "table not found",
+		},
+		{
+			name:      "bad table file",
+			tdReturns: models.Table{Body: newTableBody("table mock")},
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserReturnsErr: xlsxparser.ErrEmptyDoc,
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+				epm.StreamProductsMock.Set(streamOnce(pReturns, pRetIssues, pRetErr))
+			},
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {},
+			statusBehaviour: func(rm *RepositoryMock) {
+				rm.SetJobStatusMock.Expect(job.Id, job.Attempts, models.JobParsing).Return(nil)
+			},
+
+			wantStatus: models.JobFailed,
+			wantErrMsg: "bad table file",
+		},
+		{
+			name:      "table has duplicates",
+			tdReturns: models.Table{Body: newTableBody("table mock")},
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserReturnsErr: xlsxparser.ErrHasDuplicates,
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+				epm.StreamProductsMock.Set(streamOnce(pReturns, pRetIssues, pRetErr))
+			},
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {},
+			statusBehaviour: func(rm *RepositoryMock) {
+				rm.SetJobStatusMock.Expect(job.Id, job.Attempts, models.JobParsing).Return(nil)
+			},
+
+			wantStatus: models.JobFailed,
+			wantErrMsg: "table has duplicates",
+		},
+		{
+			name:      "missing columns",
+			tdReturns: models.Table{Body: newTableBody("table mock")},
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserReturnsErr: xlsxparser.ErrMissingColumns{Columns: []string{"price", "quantity"}},
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+				epm.StreamProductsMock.Set(streamOnce(pReturns, pRetIssues, pRetErr))
+			},
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {},
+			statusBehaviour: func(rm *RepositoryMock) {
+				rm.SetJobStatusMock.Expect(job.Id, job.Attempts, models.JobParsing).Return(nil)
+			},
+
+			wantStatus: models.JobFailed,
+			wantErrMsg: "missing required column(s): price, quantity",
+		},
+		{
+			name:      "unexpected parser error",
+			tdReturns: models.Table{Body: newTableBody("table mock")},
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserReturnsErr: errors.New("unexpected parser error"),
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+				epm.StreamProductsMock.Set(streamOnce(pReturns, pRetIssues, pRetErr))
+			},
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {},
+			statusBehaviour: func(rm *RepositoryMock) {
+				rm.SetJobStatusMock.Expect(job.Id, job.Attempts, models.JobParsing).Return(nil)
+			},
+
+			wantStatus: models.JobFailed,
+			wantErrMsg: "parsing error",
+		},
+		{
+			name:      "service error",
+			tdReturns: models.Table{Body: newTableBody("table mock")},
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserReturns:   productUpdates,
+			parserRetIssues: issues,
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+				epm.StreamProductsMock.Set(streamOnce(pReturns, pRetIssues, pRetErr))
+			},
+			serviceReturnsErr: errors.New("repo err"),
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {
+				im := expectImport(sm, job.SellerId, service.UpdateOptions{Source: importSource})
+				im.StageMock.Expect(productUpdates, nil).Return(serviceReturns, serviceRetErr)
+			},
+			statusBehaviour: func(rm *RepositoryMock) {
+				rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobParsing).Then(nil)
+				rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobWriting).Then(nil)
+			},
+
+			wantStatus: models.JobFailed,
+			wantErrMsg: "service error",
+		},
+		{
+			name:      "correct job",
+			tdReturns: models.Table{Body: newTableBody("table mock")},
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserReturns:   productUpdates,
+			parserRetIssues: issues,
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+				epm.StreamProductsMock.Set(streamOnce(pReturns, pRetIssues, pRetErr))
+				epm.WriteErrorReportMock.Set(writeReport("report"))
+			},
+			serviceReturns: service.UpdateResults{Added: 1, Updated: 1, Deleted: 0, Issues: []models.ImportIssue{}},
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {
+				im := expectImport(sm, job.SellerId, service.UpdateOptions{Source: importSource})
+				im.StageMock.Expect(productUpdates, nil).Return(serviceReturns, serviceRetErr)
+				im.CommitMock.Return(service.UpdateResults{}, nil)
+			},
+			statusBehaviour: func(rm *RepositoryMock) {
+				rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobParsing).Then(nil)
+				rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobWriting).Then(nil)
+				rm.SaveJobReportMock.Expect(job.Id, []byte("report")).Return(nil)
+			},
+
+			wantStatus:  models.JobDone,
+			wantResults: []byte(`{"added":1,"updated":1,"deleted":0,"issues":[{"row":3,"offerId":3,"field":"name","code":"too_long_name","severity":"error","message":"too long name"},{"row":4,"offerId":4,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"0-40\""}]}`),
+		},
+		{
+			name:      "job without errors has no report",
+			tdReturns: models.Table{Body: newTableBody("table mock")},
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserReturns: productUpdates,
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+				epm.StreamProductsMock.Set(streamOnce(pReturns, pRetIssues, pRetErr))
+			},
+			serviceReturns: service.UpdateResults{Added: 2, Issues: []models.ImportIssue{}},
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {
+				im := expectImport(sm, job.SellerId, service.UpdateOptions{Source: importSource})
+				im.StageMock.Expect(productUpdates, nil).Return(serviceReturns, serviceRetErr)
+				im.CommitMock.Return(service.UpdateResults{}, nil)
+			},
+			statusBehaviour: func(rm *RepositoryMock) {
+				rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobParsing).Then(nil)
+				rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobWriting).Then(nil)
+			},
+
+			wantStatus:  models.JobDone,
+			wantResults: []byte(`{"added":2,"updated":0,"deleted":0,"issues":[]}`),
+		},
+		{
+			name:      "report failure does not fail job",
+			tdReturns: models.Table{Body: newTableBody("table mock")},
+			tdBehaviour: func(tdm *TableDownloaderMock, tdRet models.Table, tdRetErr error) {
+				expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, tdRet, tdRetErr)
+			},
+			parserReturns:   productUpdates,
+			parserRetIssues: issues,
+			parserBehaviour: func(epm *ExcelParserMock, pReturns []models.ProductUpdate, pRetIssues []models.ImportIssue, pRetErr error) {
+				epm.StreamProductsMock.Set(streamOnce(pReturns, pRetIssues, pRetErr))
+				epm.WriteErrorReportMock.Return(xlsxparser.ErrFailedToRead)
+			},
+			serviceReturns: service.UpdateResults{Added: 1, Updated: 1, Deleted: 0, Issues: []models.ImportIssue{}},
+			serviceBehaviour: func(sm *ServiceMock, serviceReturns service.UpdateResults, serviceRetErr error) {
+				im := expectImport(sm, job.SellerId, service.UpdateOptions{Source: importSource})
+				im.StageMock.Expect(productUpdates, nil).Return(serviceReturns, serviceRetErr)
+				im.CommitMock.Return(service.UpdateResults{}, nil)
+			},
+			statusBehaviour: func(rm *RepositoryMock) {
+				rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobParsing).Then(nil)
+				rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobWriting).Then(nil)
+			},
+
+			wantStatus:  models.JobDone,
+			wantResults: []byte(`{"added":1,"updated":1,"deleted":0,"issues":[{"row":3,"offerId":3,"field":"name","code":"too_long_name","severity":"error","message":"too long name"},{"row":4,"offerId":4,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"0-40\""}]}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rm := NewRepositoryMock(mc)
+			sm := NewServiceMock(mc)
+			tdm := NewTableDownloaderMock(mc)
+			epm := NewExcelParserMock(mc)
+			i := NewImporter(config.Config{}, rm, sm, tdm, epm, NewExcelParserMock(mc))
+
+			tt.tdBehaviour(tdm, tt.tdReturns, tt.tdReturnsErr)
+			tt.parserBehaviour(epm, tt.parserReturns, tt.parserRetIssues, tt.parserReturnsErr)
+			tt.serviceBehaviour(sm, tt.serviceReturns, tt.serviceReturnsErr)
+			tt.statusBehaviour(rm)
+			rm.LastImportMock.Expect(job.SellerId, job.TableURL).Return(models.Job{}, models.ErrNotFound)
+			rm.FinishJobMock.Expect(job.Id, job.Attempts, tt.wantStatus, tt.wantResults, tt.wantErrMsg).Return(nil)
+
+			i.process(job)
+		})
+	}
+}
+
+func TestImporter_process_batches(t *testing.T) {
+
+	batch1 := []models.ProductUpdate{
+		{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true},
+		{Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true},
+	}
+	batch1Issues := []models.ImportIssue{
+		{Row: 3, OfferId: 3, Field: "name", Code: models.CodeTooLongName, Severity: models.SeverityError, Message: models.MsgTooLongName},
+	}
+	batch2 := []models.ProductUpdate{
+		{Product: models.Product{OfferId: 4, Name: "leg", Price: 40, Quantity: 4}, Available: false},
+	}
+
+	// парсер отдаёт таблицу тремя пачками; вторая - только строка с ошибкой
+	parser := func(r io.ReadSeeker, sheet string, batchSize int, handle xlsxparser.BatchHandler) error {
+		if err := handle(batch1, batch1Issues, false); err != nil {
+			return err
+		}
+		if err := handle(nil, []models.ImportIssue{{Row: 4, OfferId: 5, Field: "price", Code: models.CodeInvalidNumber, Severity: models.SeverityError, Message: "bad price"}}, false); err != nil {
+			return err
+		}
+
+		return handle(batch2, nil, true)
+	}
+
+	tests := []struct {
+		name             string
+		syncMode         models.SyncMode
+		serviceBehaviour func(sm *ServiceMock)
+
+		wantStatus  models.JobStatus
+		wantResults []byte
+		wantErrMsg  string
+		wantReport  bool
+	}{
+		{
+			name:     "merge: результаты пачек суммируются",
+			syncMode: models.SyncModeMerge,
+			serviceBehaviour: func(sm *ServiceMock) {
+				im := expectImport(sm, 42, service.UpdateOptions{Source: importSource, Mode: models.SyncModeMerge})
+				im.StageMock.When(batch1, nil).Then(service.UpdateResults{Added: 2, Issues: []models.ImportIssue{}}, nil)
+				im.StageMock.When(batch2, nil).Then(service.UpdateResults{Issues: []models.ImportIssue{}}, nil)
+				im.CommitMock.Return(service.UpdateResults{Deleted: 1}, nil)
+			},
+			wantStatus:  models.JobDone,
+			wantResults: []byte(`{"added":2,"updated":0,"deleted":1,"issues":[{"row":3,"offerId":3,"field":"name","code":"too_long_name","severity":"error","message":"too long name"},{"row":4,"offerId":5,"field":"price","code":"invalid_number","severity":"error","message":"bad price"}]}`),
+			wantReport:  true,
+		},
+		{
+			name:     "replace: офферы строк с ошибками откладываются вместе с пачками",
+			syncMode: models.SyncModeReplace,
+			serviceBehaviour: func(sm *ServiceMock) {
+				im := expectImport(sm, 42, service.UpdateOptions{Source: importSource, Mode: models.SyncModeReplace})
+				im.StageMock.When(batch1, []uint64{3}).Then(service.UpdateResults{Added: 2, Issues: []models.ImportIssue{}}, nil)
+				im.StageMock.When(nil, []uint64{5}).Then(service.UpdateResults{Issues: []models.ImportIssue{}}, nil)
+				im.StageMock.When(batch2, nil).Then(service.UpdateResults{Issues: []models.ImportIssue{}}, nil)
+				im.CommitMock.Return(service.UpdateResults{Deleted: 1, Purged: 7}, nil)
+			},
+			wantStatus:  models.JobDone,
+			wantResults: []byte(`{"added":2,"updated":0,"deleted":1,"purged":7,"issues":[{"row":3,"offerId":3,"field":"name","code":"too_long_name","severity":"error","message":"too long name"},{"row":4,"offerId":5,"field":"price","code":"invalid_number","severity":"error","message":"bad price"}]}`),
+			wantReport:  true,
+		},
+		{
+			name:     "ошибка сервиса после отложенной пачки: в каталог ничего не записано",
+			syncMode: models.SyncModeMerge,
+			serviceBehaviour: func(sm *ServiceMock) {
+				im := expectImport(sm, 42, service.UpdateOptions{Source: importSource, Mode: models.SyncModeMerge})
+				im.StageMock.When(batch1, nil).Then(service.UpdateResults{Added: 2, Issues: []models.ImportIssue{}}, nil)
+				im.StageMock.When(batch2, nil).Then(service.UpdateResults{}, errors.New("repo err"))
+			},
+			wantStatus: models.JobFailed,
+			wantErrMsg: "service error",
+		},
+		{
+			name:     "ошибка записи в каталог",
+			syncMode: models.SyncModeMerge,
+			serviceBehaviour: func(sm *ServiceMock) {
+				im := expectImport(sm, 42, service.UpdateOptions{Source: importSource, Mode: models.SyncModeMerge})
+				im.StageMock.When(batch1, nil).Then(service.UpdateResults{Added: 2, Issues: []models.ImportIssue{}}, nil)
+				im.StageMock.When(batch2, nil).Then(service.UpdateResults{Issues: []models.ImportIssue{}}, nil)
+				im.CommitMock.Return(service.UpdateResults{}, errors.New("repo err"))
+			},
+			wantStatus: models.JobFailed,
+			wantErrMsg: "service error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			job := models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t", Status: models.JobDownloading, SyncMode: tt.syncMode}
+
+			rm := NewRepositoryMock(mc)
+			sm := NewServiceMock(mc)
+			tdm := NewTableDownloaderMock(mc)
+			epm := NewExcelParserMock(mc)
+			i := NewImporter(config.Config{Importer: config.Importer{BatchSize: 2}}, rm, sm, tdm, epm, NewExcelParserMock(mc))
+
+			expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, models.Table{Body: newTableBody("table mock")}, nil)
+			epm.StreamProductsMock.Set(parser)
+			tt.serviceBehaviour(sm)
+			rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobParsing).Then(nil)
+			rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobWriting).Then(nil)
+			// отчёт сохраняется, только если лист записан
+			if tt.wantReport {
+				epm.WriteErrorReportMock.Set(writeReport("report"))
+				rm.SaveJobReportMock.Expect(job.Id, []byte("report")).Return(nil)
+			}
+			rm.LastImportMock.Expect(job.SellerId, job.TableURL).Return(models.Job{}, models.ErrNotFound)
+			rm.FinishJobMock.Expect(job.Id, job.Attempts, tt.wantStatus, tt.wantResults, tt.wantErrMsg).Return(nil)
+
+			i.process(job)
+		})
+	}
+}
+
+func TestImporter_process_timeout(t *testing.T) {
+	job := models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t", Status: models.JobDownloading}
+
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	rm := NewRepositoryMock(mc)
+	tdm := NewTableDownloaderMock(mc)
+	epm := NewExcelParserMock(mc)
+	i := NewImporter(config.Config{}, rm, NewServiceMock(mc), tdm, epm, NewExcelParserMock(mc))
+	// время задачи истекает, пока таблица скачивается
+	i.jobTimeout = time.Nanosecond
+
+	rm.LastImportMock.Expect(job.SellerId, job.TableURL).Return(models.Job{}, models.ErrNotFound)
+	expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, models.Table{Body: newTableBody("table mock")}, nil)
+	rm.SetJobStatusMock.Expect(job.Id, job.Attempts, models.JobParsing).Return(nil)
+	// до сервиса пачка не доходит
+	epm.StreamProductsMock.Set(streamOnce([]models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 10}, Available: true}}, nil, nil))
+	rm.FinishJobMock.Expect(job.Id, job.Attempts, models.JobFailed, nil, "job timeout").Return(nil)
+
+	i.process(job)
+}
+
+func TestImporter_heartbeat_leaseLost(t *testing.T) {
+	job := models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t", Status: models.JobDownloading, Attempts: 2}
+
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	rm := NewRepositoryMock(mc)
+	i := NewImporter(config.Config{}, rm, NewServiceMock(mc), NewTableDownloaderMock(mc), NewExcelParserMock(mc), NewExcelParserMock(mc))
+	i.leaseTimeout = 4 * time.Millisecond
+
+	// задачу вернули в очередь и забрал другой воркер: попытка 2 уже не текущая
+	rm.TouchJobMock.Expect(job.Id, job.Attempts).Return(models.ErrNotFound)
+
+	ctx, lose := context.WithCancelCause(context.Background())
+	defer lose(nil)
+
+	i.heartbeat(ctx, lose, job)
+	assert.ErrorIs(t, context.Cause(ctx), errLeaseLost)
+	assert.Equal(t, errLeaseLost, ctxErr(ctx))
+}
+
+func TestImporter_requeueStale(t *testing.T) {
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	rm := NewRepositoryMock(mc)
+	i := NewImporter(config.Config{Importer: config.Importer{LeaseTimeout: 30}}, rm, NewServiceMock(mc), NewTableDownloaderMock(mc), NewExcelParserMock(mc), NewExcelParserMock(mc))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// один обход: контекст отменяется на первом возврате задач в очередь
+	rm.RequeueStaleJobsMock.Set(func(staleAfter time.Duration, maxAttempts uint32) (uint64, uint64, error) {
+		assert.Equal(t, 30*time.Second, staleAfter)
+		assert.Equal(t, uint32(defaultMaxAttempts), maxAttempts)
+		cancel()
+
+		return 2, 1, nil
+	})
+
+	i.requeueStale(ctx)
+	// возвращённые задачи будят воркер
+	assert.Len(t, i.wakeup, 1)
+}
+
+func TestImporter_parserFor(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		contentType string
+		tableURL    string
+		wantCSV     bool
+	}{
+		{name: "xlsx content type", contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", tableURL: "http://some.url/t.csv", wantCSV: false},
+		{name: "csv content type", contentType: "text/csv; charset=windows-1251", tableURL: "http://some.url/t", wantCSV: true},
+		{name: "tsv content type", contentType: "text/tab-separated-values", tableURL: "http://some.url/t", wantCSV: true},
+		{name: "csv extension", contentType: "application/octet-stream", tableURL: "http://some.url/t.CSV?token=1", wantCSV: true},
+		{name: "tsv extension without content type", contentType: "", tableURL: "http://some.url/t.tsv", wantCSV: true},
+		{name: "xlsx extension", contentType: "application/octet-stream", tableURL: "http://some.url/t.xlsx", wantCSV: false},
+		{name: "unknown format", contentType: "text/plain", tableURL: "http://some.url/t.txt", wantCSV: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			epm := NewExcelParserMock(mc)
+			cpm := NewExcelParserMock(mc)
+			i := NewImporter(config.Config{}, NewRepositoryMock(mc), NewServiceMock(mc), NewTableDownloaderMock(mc), epm, cpm)
+
+			if tt.wantCSV {
+				assert.Same(t, cpm, i.parserFor(tt.contentType, tt.tableURL))
+			} else {
+				assert.Same(t, epm, i.parserFor(tt.contentType, tt.tableURL))
+			}
+		})
+	}
+}
+
+func TestDownloadErrMsg(t *testing.T) {
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "not found", err: fmt.Errorf("%w: 410 Gone", gateway.ErrNotFound), want: "table not found"},
+		{name: "too large", err: fmt.Errorf("%w: content length 104857600", gateway.ErrTooLarge), want: "table too large"},
+		{name: "upstream down", err: fmt.Errorf("%w: 503 Service Unavailable", gateway.ErrUpstreamDown), want: "table host unavailable"},
+		{name: "not a table", err: fmt.Errorf("%w: got text/html; charset=utf-8", gateway.ErrNotTable), want: "not a table file"},
+		{name: "fetch failed", err: fmt.Errorf("%w: 403 Forbidden", gateway.ErrFetchFailed), want: "failed to fetch table"},
+		{name: "forbidden url", err: fmt.Errorf("%w: 10.0.0.1 is a private address", gateway.ErrForbiddenURL), want: "table url not allowed"},
+		{name: "too many redirects", err: fmt.Errorf("%w: more than 5", gateway.ErrTooManyRedirects), want: "too many redirects"},
+		{name: "job timeout", err: context.DeadlineExceeded, want: "job timeout"},
+		{name: "bad url", err: errors.New(`unsupported protocol scheme "ftp"`), want: "bad table url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, downloadErrMsg(tt.err))
+		})
+	}
+}
+
+func TestImporter_Enqueue(t *testing.T) {
+	forbidden := fmt.Errorf("%w: 169.254.169.254 is a link-local address", gateway.ErrForbiddenURL)
+
+	tests := []struct {
+		name          string
+		checkErr      error
+		repoBehaviour func(rm *RepositoryMock, repoReturns models.Job, repoReturnErr error)
+		repoReturns   models.Job
+		repoReturnErr error
+		want          models.Job
+		wantErr       error
+	}{
+		{
+			name: "repo error",
+			repoBehaviour: func(rm *RepositoryMock, repoReturns models.Job, repoReturnErr error) {
+				rm.CreateJobMock.Expect(models.Job{SellerId: 42, TableURL: "some.url/t"}).Return(repoReturns, repoReturnErr)
+			},
+			repoReturnErr: errors.New("failed to execute query"),
+			want:          models.Job{},
+			wantErr:       ErrEnqueueFailed,
+		},
+		{
+			name: "job created",
+			repoBehaviour: func(rm *RepositoryMock, repoReturns models.Job, repoReturnErr error) {
+				rm.CreateJobMock.Expect(models.Job{SellerId: 42, TableURL: "some.url/t"}).Return(repoReturns, repoReturnErr)
+			},
+			repoReturns: models.Job{Id: 3, SellerId: 42, TableURL: "some.url/t", Status: models.JobQueued},
+			want:        models.Job{Id: 3, SellerId: 42, TableURL: "some.url/t", Status: models.JobQueued},
+			wantErr:     nil,
+		},
+		{
+			name:          "url not allowed",
+			checkErr:      forbidden,
+			repoBehaviour: func(rm *RepositoryMock, repoReturns models.Job, repoReturnErr error) {},
+			want:          models.Job{},
+			wantErr:       forbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rm := NewRepositoryMock(mc)
+			tdm := NewTableDownloaderMock(mc)
+			i := NewImporter(config.Config{}, rm, NewServiceMock(mc), tdm, NewExcelParserMock(mc), NewExcelParserMock(mc))
+
+			tdm.CheckURLMock.Expect("some.url/t").Return(tt.checkErr)
+			tt.repoBehaviour(rm, tt.repoReturns, tt.repoReturnErr)
+
+			job, err := i.Enqueue(models.Job{SellerId: 42, TableURL: "some.url/t"}, models.TableAuth{})
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, job)
+		})
+	}
+}
+
+func TestImporter_Job(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		repoReturns   models.Job
+		repoReturnErr error
+		want          models.Job
+		wantErr       error
+	}{
+		{
+			name:          "not found",
+			repoReturnErr: models.ErrNotFound,
+			want:          models.Job{},
+			wantErr:       ErrJobNotFound,
+		},
+		{
+			name:          "repo error",
+			repoReturnErr: errors.New("failed to execute query"),
+			want:          models.Job{},
+			wantErr:       ErrRepoFailed,
+		},
+		{
+			name:        "found",
+			repoReturns: models.Job{Id: 3, SellerId: 42, Status: models.JobDone},
+			want:        models.Job{Id: 3, SellerId: 42, Status: models.JobDone},
+			wantErr:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rm := NewRepositoryMock(mc)
+			i := NewImporter(config.Config{}, rm, NewServiceMock(mc), NewTableDownloaderMock(mc), NewExcelParserMock(mc), NewExcelParserMock(mc))
+
+			rm.JobMock.Expect(3).Return(tt.repoReturns, tt.repoReturnErr)
+
+			job, err := i.Job(3)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, job)
+		})
+	}
+}
+
+func TestImporter_Revert(t *testing.T) {
+
+	doneJob := models.Job{Id: 3, SellerId: 42, Status: models.JobDone}
+
+	tests := []struct {
+		name          string
+		repoJob       models.Job
+		repoJobErr    error
+		wantRevert    bool
+		revertReturns models.RevertResults
+		revertErr     error
+		want          models.RevertResults
+		wantErr       error
+	}{
+		{
+			name:       "задача не найдена",
+			repoJobErr: models.ErrNotFound,
+			wantErr:    ErrJobNotFound,
+		},
+		{
+			name:    "задача ещё выполняется",
+			repoJob: models.Job{Id: 3, SellerId: 42, Status: models.JobWriting},
+			wantErr: ErrJobNotFinished,
+		},
+		{
+			name:    "предпросмотр ничего не менял",
+			repoJob: models.Job{Id: 3, SellerId: 42, Status: models.JobDone, DryRun: true},
+			wantErr: ErrNothingToRevert,
+		},
+		{
+			name:       "уже откатили",
+			repoJob:    doneJob,
+			wantRevert: true,
+			revertErr:  models.ErrAlreadyReverted,
+			wantErr:    ErrAlreadyReverted,
+		},
+		{
+			name:       "офферы менялись после загрузки",
+			repoJob:    doneJob,
+			wantRevert: true,
+			revertErr:  models.ErrConflict,
+			wantErr:    ErrRevertConflict,
+		},
+		{
+			name:       "repo error",
+			repoJob:    doneJob,
+			wantRevert: true,
+			revertErr:  errors.New("failed to execute query"),
+			wantErr:    ErrRepoFailed,
+		},
+		{
+			name:          "откат задачи, остановленной защитой каталога",
+			repoJob:       models.Job{Id: 3, SellerId: 42, Status: models.JobHeld},
+			wantRevert:    true,
+			revertReturns: models.RevertResults{},
+			want:          models.RevertResults{},
+		},
+		{
+			name:          "откат упавшей задачи",
+			repoJob:       models.Job{Id: 3, SellerId: 42, Status: models.JobFailed},
+			wantRevert:    true,
+			revertReturns: models.RevertResults{Restored: 2, Removed: 1},
+			want:          models.RevertResults{Restored: 2, Removed: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rm := NewRepositoryMock(mc)
+			i := NewImporter(config.Config{}, rm, NewServiceMock(mc), NewTableDownloaderMock(mc), NewExcelParserMock(mc), NewExcelParserMock(mc))
+
+			rm.JobMock.Expect(3).Return(tt.repoJob, tt.repoJobErr)
+			if tt.wantRevert {
+				rm.RevertJobMock.Expect(3).Return(tt.revertReturns, tt.revertErr)
+			}
+
+			results, err := i.Revert(3)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, results)
+		})
+	}
+}
+
+func TestImporter_Confirm(t *testing.T) {
+
+	heldJob := models.Job{Id: 3, SellerId: 42, Status: models.JobHeld}
+	revertedAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		repoJob     models.Job
+		repoJobErr  error
+		wantConfirm bool
+		confirmErr  error
+		want        models.Job
+		wantErr     error
+	}{
+		{
+			name:       "задача не найдена",
+			repoJobErr: models.ErrNotFound,
+			wantErr:    ErrJobNotFound,
+		},
+		{
+			name:    "задача не остановлена защитой",
+			repoJob: models.Job{Id: 3, SellerId: 42, Status: models.JobDone},
+			wantErr: ErrJobNotHeld,
+		},
+		{
+			name:    "задача откачена",
+			repoJob: models.Job{Id: 3, SellerId: 42, Status: models.JobHeld, RevertedAt: &revertedAt},
+			wantErr: ErrJobNotHeld,
+		},
+		{
+			name:        "задачу подтвердили параллельным запросом",
+			repoJob:     heldJob,
+			wantConfirm: true,
+			confirmErr:  models.ErrNotFound,
+			wantErr:     ErrJobNotHeld,
+		},
+		{
+			name:        "repo error",
+			repoJob:     heldJob,
+			wantConfirm: true,
+			confirmErr:  errors.New("failed to execute query"),
+			wantErr:     ErrRepoFailed,
+		},
+		{
+			name:        "задача возвращена в очередь",
+			repoJob:     heldJob,
+			wantConfirm: true,
+			want:        models.Job{Id: 3, SellerId: 42, Status: models.JobQueued, Confirmed: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rm := NewRepositoryMock(mc)
+			i := NewImporter(config.Config{}, rm, NewServiceMock(mc), NewTableDownloaderMock(mc), NewExcelParserMock(mc), NewExcelParserMock(mc))
+
+			rm.JobMock.Expect(3).Return(tt.repoJob, tt.repoJobErr)
+			if tt.wantConfirm {
+				rm.ConfirmJobMock.Expect(3).Return(tt.confirmErr)
+			}
+
+			job, err := i.Confirm(3)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, job)
+		})
+	}
+}
+
+func TestImporter_Report(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		reportReturn []byte
+		reportErr    error
+		checkJob     bool
+		jobErr       error
+		want         []byte
+		wantErr      error
+	}{
+		{
+			name:      "задача не найдена",
+			reportErr: models.ErrNotFound,
+			checkJob:  true,
+			jobErr:    models.ErrNotFound,
+			wantErr:   ErrJobNotFound,
+		},
+		{
+			name:      "у задачи нет отчёта",
+			reportErr: models.ErrNotFound,
+			checkJob:  true,
+			wantErr:   ErrReportNotFound,
+		},
+		{
+			name:      "repo error",
+			reportErr: errors.New("failed to execute query"),
+			wantErr:   ErrRepoFailed,
+		},
+		{
+			name:         "отчёт найден",
+			reportReturn: []byte("report"),
+			want:         []byte("report"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rm := NewRepositoryMock(mc)
+			i := NewImporter(config.Config{}, rm, NewServiceMock(mc), NewTableDownloaderMock(mc), NewExcelParserMock(mc), NewExcelParserMock(mc))
+
+			rm.JobReportMock.Expect(3).Return(tt.reportReturn, tt.reportErr)
+			if tt.checkJob {
+				rm.JobMock.Expect(3).Return(models.Job{Id: 3, Status: models.JobDone}, tt.jobErr)
+			}
+
+			report, err := i.Report(3)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, report)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/service"
+	"github.com/hablof/merchant-experience/internal/xlsxparser"
+)
+
+// SheetResults - итог одного листа задачи с models.Job.Sheets
+type SheetResults struct {
+	Sheet    string           `json:"sheet"`
+	SellerId uint64           `json:"sellerId"`
+	Status   models.JobStatus `json:"status"`
+	Error    string           `json:"error,omitempty"`
+	Added    uint64           `json:"added"`
+	Updated  uint64           `json:"updated"`
+	Deleted  uint64           `json:"deleted"`
+	Purged   uint64           `json:"purged,omitempty"`
+	// проблемы строк листа - в общем списке issues с полем sheet
+	IssueCount int                  `json:"issueCount"`
+	Guard      *service.GuardReport `json:"guard,omitempty"`
+}
+
+// JobResults - results задачи по нескольким листам: суммы по всем листам и итог каждого листа
+type JobResults struct {
+	service.UpdateResults
+	Sheets []SheetResults `json:"sheets"`
+}
+
+// processSheets обрабатывает листы задачи по продавцам: все листы продавца пишутся в его каталог одной загрузкой
+// (см. processSeller), поэтому режим replace не удаляет офферы, записанные другим листом того же продавца.
+// Ошибка продавца не останавливает остальных. Задача ждёт подтверждения, если у какого-то продавца сработала защита
+// каталога (даже если другой упал), иначе падает, если упал хоть один лист. Подтверждённая задача заново
+// загружает без защиты только остановленных продавцов, итоги остальных листов остаются прежними. Возвращает признак held.
+func (i *Importer) processSheets(ctx context.Context, job models.Job, table io.ReadSeeker, parser ExcelParser, onWrite func()) bool {
+	sheets, err := sheetSellers(job, table, parser)
+	if err != nil {
+		i.fail(job, sheetsErrMsg(err))
+		return false
+	}
+
+	// итоги листов до подтверждения: по ним видно, каких продавцов остановила защита
+	var prev map[string]sheetOutcome
+	if job.Confirmed {
+		prev, err = prevOutcomes(job.Results)
+		if err != nil {
+			log.Printf("job #%d: %v", job.Id, err)
+			i.fail(job, "service error")
+
+			return false
+		}
+	}
+
+	outcomes := make(map[string]sheetOutcome, len(sheets))
+	for _, group := range sellerSheets(sheets) {
+		// продавец уже записан или упал до подтверждения - заново не загружается;
+		// защита отключается только для продавца, которого она остановила
+		rerun, confirmed := false, false
+		for _, sheet := range group.sheets {
+			before, ok := prev[sheet]
+			switch {
+			case !ok:
+				rerun = true
+			case before.results.Status == models.JobHeld:
+				rerun, confirmed = true, true
+			}
+		}
+
+		if !rerun {
+			for _, sheet := range group.sheets {
+				outcomes[sheet] = prev[sheet]
+			}
+
+			continue
+		}
+
+		sellerJob := job
+		sellerJob.Confirmed = confirmed
+		runs := i.processSeller(ctx, sellerJob, table, parser, group.sheets, group.sellerId, onWrite)
+		for idx, sheet := range group.sheets {
+			run := runs[idx]
+			outcomes[sheet] = sheetOutcome{
+				results: SheetResults{
+					Sheet:      sheet,
+					SellerId:   group.sellerId,
+					Status:     run.status,
+					Error:      run.errMsg,
+					Added:      run.results.Added,
+					Updated:    run.results.Updated,
+					Deleted:    run.results.Deleted,
+					Purged:     run.results.Purged,
+					IssueCount: len(run.results.Issues),
+					Guard:      run.results.Guard,
+				},
+				issues: run.results.Issues,
+				diff:   run.results.Diff,
+			}
+		}
+	}
+
+	results := JobResults{
+		UpdateResults: service.UpdateResults{Issues: []models.ImportIssue{}, DryRun: job.DryRun},
+		Sheets:        make([]SheetResults, 0, len(sheets)),
+	}
+
+	var failed, held []string
+	for _, sheet := range sheets.Names() {
+		outcome := outcomes[sheet]
+
+		// отчёты защиты относятся к каталогам разных продавцов и остаются только у листов
+		addResults(&results.UpdateResults, service.UpdateResults{
+			Added:   outcome.results.Added,
+			Updated: outcome.results.Updated,
+			Deleted: outcome.results.Deleted,
+			Purged:  outcome.results.Purged,
+			Issues:  outcome.issues,
+			Diff:    outcome.diff,
+		})
+		results.Sheets = append(results.Sheets, outcome.results)
+
+		switch outcome.results.Status {
+		case models.JobFailed:
+			failed = append(failed, sheet)
+		case models.JobHeld:
+			held = append(held, sheet)
+		}
+	}
+
+	status, errMsg := models.JobDone, ""
+	switch {
+	// остановленные листы можно подтвердить, не дожидаясь исправления упавших
+	case len(held) > 0 && len(failed) > 0:
+		status, errMsg = models.JobHeld, "guard tripped: confirmation required; failed sheet(s): "+strings.Join(failed, ", ")
+	case len(held) > 0:
+		status, errMsg = models.JobHeld, "guard tripped: confirmation required"
+	case len(failed) > 0:
+		status, errMsg = models.JobFailed, "failed sheet(s): "+strings.Join(failed, ", ")
+	}
+
+	b, err := json.Marshal(results)
+	if err != nil {
+		log.Println(err.Error())
+		i.fail(job, "service error")
+
+		return false
+	}
+
+	i.saveReport(job.Id, parser, table, results.Issues)
+
+	if err := i.repo.FinishJob(job.Id, job.Attempts, status, b, errMsg); err != nil {
+		log.Printf("failed to finish job #%d: %v", job.Id, err)
+	}
+
+	return status == models.JobHeld
+}
+
+// sheetOutcome - итог листа вместе с его проблемами строк
+type sheetOutcome struct {
+	results SheetResults
+	issues  []models.ImportIssue
+	diff    []service.ProductDiff
+}
+
+// sellerGroup - листы одного продавца в порядке имён
+type sellerGroup struct {
+	sellerId uint64
+	sheets   []string
+}
+
+// sellerSheets группирует листы по продавцам; продавцы идут в порядке своего первого листа
+func sellerSheets(sheets models.SheetSellers) []sellerGroup {
+	groups := make([]sellerGroup, 0, len(sheets))
+	bySeller := make(map[uint64]int, len(sheets))
+	for _, sheet := range sheets.Names() {
+		sellerId := sheets[sheet]
+		idx, ok := bySeller[sellerId]
+		if !ok {
+			idx = len(groups)
+			bySeller[sellerId] = idx
+			groups = append(groups, sellerGroup{sellerId: sellerId})
+		}
+
+		groups[idx].sheets = append(groups[idx].sheets, sheet)
+	}
+
+	return groups
+}
+
+// sheetSellers раскрывает models.AllSheets в листы книги; листы, названные в карте, остаются у своих продавцов
+func sheetSellers(job models.Job, table io.ReadSeeker, parser ExcelParser) (models.SheetSellers, error) {
+	defaultSeller, all := job.Sheets[models.AllSheets]
+	if !all {
+		return job.Sheets, nil
+	}
+
+	names, err := parser.SheetNames(table)
+	if err != nil {
+		return nil, err
+	}
+
+	sheets := make(models.SheetSellers, len(names)+len(job.Sheets))
+	for _, name := range names {
+		sheets[name] = defaultSeller
+	}
+	for name, sellerId := range job.Sheets {
+		if name != models.AllSheets {
+			sheets[name] = sellerId
+		}
+	}
+
+	return sheets, nil
+}
+
+// sheetsErrMsg - ошибка задачи, когда не удалось прочитать список листов книги
+func sheetsErrMsg(err error) string {
+	if errors.Is(err, xlsxparser.ErrSheetNotFound) {
+		return "sheet not found"
+	}
+
+	return "bad table file"
+}
+
+// prevOutcomes разбирает results задачи, остановленной защитой: итог каждого листа и его проблемы строк
+func prevOutcomes(b []byte) (map[string]sheetOutcome, error) {
+	results := JobResults{}
+	if err := json.Unmarshal(b, &results); err != nil {
+		return nil, fmt.Errorf("failed to read sheets results: %w", err)
+	}
+
+	outcomes := make(map[string]sheetOutcome, len(results.Sheets))
+	for _, sheet := range results.Sheets {
+		outcomes[sheet.Sheet] = sheetOutcome{results: sheet}
+	}
+	for _, issue := range results.Issues {
+		outcome, ok := outcomes[issue.Sheet]
+		if !ok {
+			continue
+		}
+		outcome.issues = append(outcome.issues, issue)
+		outcomes[issue.Sheet] = outcome
+	}
+
+	return outcomes, nil
+}
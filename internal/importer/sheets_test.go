@@ -0,0 +1,314 @@
+package importer
+
+import (
+	"io"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/service"
+	"github.com/hablof/merchant-experience/internal/xlsxparser"
+	"github.com/stretchr/testify/assert"
+)
+
+// streamSheets имитирует парсер книги: у каждого листа своя пачка, листа нет в sheets - ErrSheetNotFound
+func streamSheets(sheets map[string][]models.ProductUpdate, issues map[string][]models.ImportIssue) func(r io.ReadSeeker, sheet string, batchSize int, handle xlsxparser.BatchHandler) error {
+	return func(r io.ReadSeeker, sheet string, batchSize int, handle xlsxparser.BatchHandler) error {
+		updates, ok := sheets[sheet]
+		if !ok {
+			return xlsxparser.ErrSheetNotFound
+		}
+
+		return handle(updates, issues[sheet], true)
+	}
+}
+
+func TestImporter_process_sheets(t *testing.T) {
+
+	shop1 := []models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true, Row: 2}}
+	shop2 := []models.ProductUpdate{{Product: models.Product{OfferId: 7, Name: "tail", Price: 70, Quantity: 7}, Available: true, Row: 2}}
+	shop2Issues := []models.ImportIssue{{Row: 3, OfferId: 8, Field: "price", Code: models.CodeInvalidNumber, Severity: models.SeverityError, Message: `must be a non-negative integer, got "сто"`}}
+	guardReport := &service.GuardReport{CatalogSize: 1, Deleted: []uint64{1}}
+
+	// итоги задачи, остановленной защитой на листе "Магазин 1"
+	heldResults := []byte(`{"added":0,"updated":1,"deleted":0,` +
+		`"issues":[{"sheet":"Магазин 2","row":3,"offerId":8,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"сто\""}],` +
+		`"sheets":[` +
+		`{"sheet":"Магазин 1","sellerId":101,"status":"held","error":"guard tripped: confirmation required","added":0,"updated":0,"deleted":0,"issueCount":0,"guard":{"catalogSize":1,"deleted":[1]}},` +
+		`{"sheet":"Магазин 2","sellerId":102,"status":"done","added":0,"updated":1,"deleted":0,"issueCount":1},` +
+		`{"sheet":"Магазин 3","sellerId":103,"status":"failed","error":"sheet not found","added":0,"updated":0,"deleted":0,"issueCount":0}]}`)
+
+	tests := []struct {
+		name string
+		job  models.Job
+		// листы книги для models.AllSheets
+		sheetNames      []string
+		sheetNamesErr   error
+		serviceBehavior func(sm *ServiceMock)
+		wantStatus      models.JobStatus
+		wantResults     []byte
+		wantErrMsg      string
+		wantReport      bool
+	}{
+		{
+			name: "каждый лист пишется в каталог своего продавца",
+			job: models.Job{Id: 1, TableURL: "some.url/t", Status: models.JobDownloading, Sheets: models.SheetSellers{
+				"Магазин 2": 102,
+				"Магазин 1": 101,
+			}},
+			serviceBehavior: func(sm *ServiceMock) {
+				im1 := expectImport(sm, 101, service.UpdateOptions{Source: importSource})
+				im1.StageMock.Expect(shop1, nil).Return(service.UpdateResults{Added: 1, Issues: []models.ImportIssue{}}, nil)
+				im1.CommitMock.Return(service.UpdateResults{}, nil)
+				im2 := expectImport(sm, 102, service.UpdateOptions{Source: importSource})
+				im2.StageMock.Expect(shop2, nil).Return(service.UpdateResults{Updated: 1, Issues: []models.ImportIssue{}}, nil)
+				im2.CommitMock.Return(service.UpdateResults{}, nil)
+			},
+			wantStatus: models.JobDone,
+			wantResults: []byte(`{"added":1,"updated":1,"deleted":0,` +
+				`"issues":[{"sheet":"Магазин 2","row":3,"offerId":8,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"сто\""}],` +
+				`"sheets":[` +
+				`{"sheet":"Магазин 1","sellerId":101,"status":"done","added":1,"updated":0,"deleted":0,"issueCount":0},` +
+				`{"sheet":"Магазин 2","sellerId":102,"status":"done","added":0,"updated":1,"deleted":0,"issueCount":1}]}`),
+			wantReport: true,
+		},
+		{
+			name: "ошибка листа не останавливает остальные",
+			job: models.Job{Id: 1, TableURL: "some.url/t", Status: models.JobDownloading, Sheets: models.SheetSellers{
+				"Магазин 1": 101,
+				"Магазин 3": 103,
+			}},
+			serviceBehavior: func(sm *ServiceMock) {
+				im := expectImport(sm, 101, service.UpdateOptions{Source: importSource})
+				im.StageMock.Expect(shop1, nil).Return(service.UpdateResults{Added: 1, Issues: []models.ImportIssue{}}, nil)
+				im.CommitMock.Return(service.UpdateResults{}, nil)
+			},
+			wantStatus: models.JobFailed,
+			wantResults: []byte(`{"added":1,"updated":0,"deleted":0,"issues":[],"sheets":[` +
+				`{"sheet":"Магазин 1","sellerId":101,"status":"done","added":1,"updated":0,"deleted":0,"issueCount":0},` +
+				`{"sheet":"Магазин 3","sellerId":103,"status":"failed","error":"sheet not found","added":0,"updated":0,"deleted":0,"issueCount":0}]}`),
+			wantErrMsg: "failed sheet(s): Магазин 3",
+		},
+		{
+			name: "остановленный защитой лист можно подтвердить, даже если другой лист упал",
+			job: models.Job{Id: 1, TableURL: "some.url/t", Status: models.JobDownloading, Sheets: models.SheetSellers{
+				"Магазин 1": 101,
+				"Магазин 2": 102,
+				"Магазин 3": 103,
+			}},
+			serviceBehavior: func(sm *ServiceMock) {
+				im1 := expectImport(sm, 101, service.UpdateOptions{Source: importSource})
+				im1.StageMock.Expect(shop1, nil).Return(service.UpdateResults{Added: 1, Issues: []models.ImportIssue{}}, nil)
+				im1.CommitMock.Return(service.UpdateResults{Guard: guardReport}, service.ErrGuardTripped)
+				im2 := expectImport(sm, 102, service.UpdateOptions{Source: importSource})
+				im2.StageMock.Expect(shop2, nil).Return(service.UpdateResults{Updated: 1, Issues: []models.ImportIssue{}}, nil)
+				im2.CommitMock.Return(service.UpdateResults{}, nil)
+			},
+			wantStatus:  models.JobHeld,
+			wantResults: heldResults,
+			wantErrMsg:  "guard tripped: confirmation required; failed sheet(s): Магазин 3",
+			wantReport:  true,
+		},
+		{
+			name: "подтверждение загружает без защиты только остановленный лист",
+			job: models.Job{Id: 1, TableURL: "some.url/t", Status: models.JobDownloading, Confirmed: true, Results: heldResults, Sheets: models.SheetSellers{
+				"Магазин 1": 101,
+				"Магазин 2": 102,
+				"Магазин 3": 103,
+			}},
+			serviceBehavior: func(sm *ServiceMock) {
+				im := expectImport(sm, 101, service.UpdateOptions{Source: importSource, Confirmed: true})
+				im.StageMock.Expect(shop1, nil).Return(service.UpdateResults{Added: 1, Issues: []models.ImportIssue{}}, nil)
+				im.CommitMock.Return(service.UpdateResults{}, nil)
+			},
+			wantStatus: models.JobFailed,
+			wantResults: []byte(`{"added":1,"updated":1,"deleted":0,` +
+				`"issues":[{"sheet":"Магазин 2","row":3,"offerId":8,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"сто\""}],` +
+				`"sheets":[` +
+				`{"sheet":"Магазин 1","sellerId":101,"status":"done","added":1,"updated":0,"deleted":0,"issueCount":0},` +
+				`{"sheet":"Магазин 2","sellerId":102,"status":"done","added":0,"updated":1,"deleted":0,"issueCount":1},` +
+				`{"sheet":"Магазин 3","sellerId":103,"status":"failed","error":"sheet not found","added":0,"updated":0,"deleted":0,"issueCount":0}]}`),
+			wantErrMsg: "failed sheet(s): Магазин 3",
+			wantReport: true,
+		},
+		{
+			name: "все листы книги - одному продавцу, кроме названных",
+			job: models.Job{Id: 1, TableURL: "some.url/t", Status: models.JobDownloading, Sheets: models.SheetSellers{
+				models.AllSheets: 101,
+				"Магазин 2":      102,
+			}},
+			sheetNames: []string{"Магазин 1", "Магазин 2"},
+			serviceBehavior: func(sm *ServiceMock) {
+				im1 := expectImport(sm, 101, service.UpdateOptions{Source: importSource})
+				im1.StageMock.Expect(shop1, nil).Return(service.UpdateResults{Added: 1, Issues: []models.ImportIssue{}}, nil)
+				im1.CommitMock.Return(service.UpdateResults{}, nil)
+				im2 := expectImport(sm, 102, service.UpdateOptions{Source: importSource})
+				im2.StageMock.Expect(shop2, nil).Return(service.UpdateResults{Updated: 1, Issues: []models.ImportIssue{}}, nil)
+				im2.CommitMock.Return(service.UpdateResults{}, nil)
+			},
+			wantStatus: models.JobDone,
+			wantResults: []byte(`{"added":1,"updated":1,"deleted":0,` +
+				`"issues":[{"sheet":"Магазин 2","row":3,"offerId":8,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"сто\""}],` +
+				`"sheets":[` +
+				`{"sheet":"Магазин 1","sellerId":101,"status":"done","added":1,"updated":0,"deleted":0,"issueCount":0},` +
+				`{"sheet":"Магазин 2","sellerId":102,"status":"done","added":0,"updated":1,"deleted":0,"issueCount":1}]}`),
+			wantReport: true,
+		},
+		{
+			name:            "у csv нет листов для всех листов книги",
+			job:             models.Job{Id: 1, TableURL: "some.url/t", Status: models.JobDownloading, Sheets: models.SheetSellers{models.AllSheets: 101}},
+			sheetNamesErr:   xlsxparser.ErrSheetNotFound,
+			serviceBehavior: func(sm *ServiceMock) {},
+			wantStatus:      models.JobFailed,
+			wantErrMsg:      "sheet not found",
+		},
+		{
+			name: "один лист по имени: проблемы помечены листом",
+			job:  models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t", Status: models.JobDownloading, Sheet: "Магазин 2"},
+			serviceBehavior: func(sm *ServiceMock) {
+				im := expectImport(sm, 42, service.UpdateOptions{Source: importSource})
+				im.StageMock.Expect(shop2, nil).Return(service.UpdateResults{Updated: 1, Issues: []models.ImportIssue{}}, nil)
+				im.CommitMock.Return(service.UpdateResults{}, nil)
+			},
+			wantStatus:  models.JobDone,
+			wantResults: []byte(`{"added":0,"updated":1,"deleted":0,"issues":[{"sheet":"Магазин 2","row":3,"offerId":8,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"сто\""}]}`),
+			wantReport:  true,
+		},
+		{
+			name:            "одного листа нет в книге",
+			job:             models.Job{Id: 1, SellerId: 42, TableURL: "some.url/t", Status: models.JobDownloading, Sheet: "Магазин 3"},
+			serviceBehavior: func(sm *ServiceMock) {},
+			wantStatus:      models.JobFailed,
+			wantErrMsg:      "sheet not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rm := NewRepositoryMock(mc)
+			sm := NewServiceMock(mc)
+			tdm := NewTableDownloaderMock(mc)
+			epm := NewExcelParserMock(mc)
+			i := NewImporter(config.Config{}, rm, sm, tdm, epm, NewExcelParserMock(mc))
+
+			expectTable(tdm, tt.job.TableURL, models.TableAuth{}, models.TableFetch{}, models.Table{Body: newTableBody("book mock")}, nil)
+			if tt.sheetNames != nil || tt.sheetNamesErr != nil {
+				epm.SheetNamesMock.Return(tt.sheetNames, tt.sheetNamesErr)
+			}
+			// без списка листов книга не читается
+			if tt.sheetNamesErr == nil {
+				epm.StreamProductsMock.Set(streamSheets(
+					map[string][]models.ProductUpdate{"Магазин 1": shop1, "Магазин 2": shop2},
+					map[string][]models.ImportIssue{"Магазин 2": shop2Issues},
+				))
+			}
+			if tt.wantReport {
+				epm.WriteErrorReportMock.Set(func(r io.ReadSeeker, issues []models.ImportIssue, w io.Writer) error {
+					for _, issue := range issues {
+						assert.NotEmpty(t, issue.Sheet, "report needs sheet of issue")
+					}
+					return writeReport("report")(r, issues, w)
+				})
+				rm.SaveJobReportMock.Expect(1, []byte("report")).Return(nil)
+			}
+			if len(tt.job.Sheets) == 0 {
+				// раскладка листов по продавцам всегда обрабатывается заново
+				rm.LastImportMock.Expect(tt.job.SellerId, tt.job.TableURL).Return(models.Job{}, models.ErrNotFound)
+			}
+			tt.serviceBehavior(sm)
+			rm.SetJobStatusMock.Set(func(jobId uint64, attempt uint32, status models.JobStatus) error { return nil })
+			rm.FinishJobMock.Expect(1, 0, tt.wantStatus, tt.wantResults, tt.wantErrMsg).Return(nil)
+
+			i.process(tt.job)
+		})
+	}
+}
+
+func TestImporter_process_sheets_sameSeller(t *testing.T) {
+
+	shop1 := []models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true, Row: 2}}
+	shop2 := []models.ProductUpdate{{Product: models.Product{OfferId: 7, Name: "tail", Price: 70, Quantity: 7}, Available: true, Row: 2}}
+	// оффер 1 повторяется на другом листе того же продавца
+	shop3 := []models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 11, Quantity: 1}, Available: true, Row: 2}}
+	shop2Issues := []models.ImportIssue{{Row: 3, OfferId: 8, Field: "price", Code: models.CodeInvalidNumber, Severity: models.SeverityError, Message: `must be a non-negative integer, got "сто"`}}
+
+	t.Run("replace: листы продавца пишутся одной загрузкой и не удаляют офферы друг друга", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		rm := NewRepositoryMock(mc)
+		sm := NewServiceMock(mc)
+		tdm := NewTableDownloaderMock(mc)
+		epm := NewExcelParserMock(mc)
+		i := NewImporter(config.Config{}, rm, sm, tdm, epm, NewExcelParserMock(mc))
+
+		job := models.Job{Id: 1, TableURL: "some.url/t", Status: models.JobDownloading, SyncMode: models.SyncModeReplace, Sheets: models.SheetSellers{
+			models.AllSheets: 101,
+		}}
+
+		expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, models.Table{Body: newTableBody("book mock")}, nil)
+		epm.SheetNamesMock.Return([]string{"Магазин 1", "Магазин 2"}, nil)
+		epm.StreamProductsMock.Set(streamSheets(
+			map[string][]models.ProductUpdate{"Магазин 1": shop1, "Магазин 2": shop2},
+			map[string][]models.ImportIssue{"Магазин 2": shop2Issues},
+		))
+		epm.WriteErrorReportMock.Set(writeReport("report"))
+		rm.SaveJobReportMock.Expect(1, []byte("report")).Return(nil)
+
+		// одна загрузка на продавца: офферы обоих листов отложены до единственного удаления отсутствующих
+		im := expectImport(sm, 101, service.UpdateOptions{Source: importSource, Mode: models.SyncModeReplace})
+		im.StageMock.When(shop1, nil).Then(service.UpdateResults{Added: 1, Issues: []models.ImportIssue{}}, nil)
+		im.StageMock.When(shop2, []uint64{8}).Then(service.UpdateResults{Updated: 1, Issues: []models.ImportIssue{}}, nil)
+		im.CommitMock.Return(service.UpdateResults{Purged: 3}, nil)
+
+		rm.SetJobStatusMock.Set(func(jobId uint64, attempt uint32, status models.JobStatus) error { return nil })
+		rm.FinishJobMock.Expect(1, 0, models.JobDone, []byte(`{"added":1,"updated":1,"deleted":0,"purged":3,`+
+			`"issues":[{"sheet":"Магазин 2","row":3,"offerId":8,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"сто\""}],`+
+			`"sheets":[`+
+			`{"sheet":"Магазин 1","sellerId":101,"status":"done","added":1,"updated":0,"deleted":0,"issueCount":0},`+
+			`{"sheet":"Магазин 2","sellerId":101,"status":"done","added":0,"updated":1,"deleted":0,"purged":3,"issueCount":1}]}`), "").Return(nil)
+
+		i.process(job)
+
+		assert.Equal(t, uint64(1), sm.BeginImportAfterCounter(), "one import per seller")
+		assert.Equal(t, uint64(2), im.StageAfterCounter(), "both sheets staged")
+		assert.Equal(t, uint64(1), im.CommitAfterCounter(), "one purge pass per seller")
+	})
+
+	t.Run("оффер на двух листах продавца не пишется", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		rm := NewRepositoryMock(mc)
+		sm := NewServiceMock(mc)
+		tdm := NewTableDownloaderMock(mc)
+		epm := NewExcelParserMock(mc)
+		i := NewImporter(config.Config{}, rm, sm, tdm, epm, NewExcelParserMock(mc))
+
+		job := models.Job{Id: 1, TableURL: "some.url/t", Status: models.JobDownloading, SyncMode: models.SyncModeReplace, Sheets: models.SheetSellers{
+			"Магазин 1": 101,
+			"Магазин 3": 101,
+		}}
+
+		expectTable(tdm, job.TableURL, models.TableAuth{}, models.TableFetch{}, models.Table{Body: newTableBody("book mock")}, nil)
+		epm.StreamProductsMock.Set(streamSheets(
+			map[string][]models.ProductUpdate{"Магазин 1": shop1, "Магазин 3": shop3},
+			nil,
+		))
+
+		// загрузка откатывается: Commit не вызывается
+		im := expectImport(sm, 101, service.UpdateOptions{Source: importSource, Mode: models.SyncModeReplace})
+		im.StageMock.Expect(shop1, nil).Return(service.UpdateResults{Added: 1, Issues: []models.ImportIssue{}}, nil)
+
+		rm.SetJobStatusMock.Set(func(jobId uint64, attempt uint32, status models.JobStatus) error { return nil })
+		rm.FinishJobMock.Expect(1, 0, models.JobFailed, []byte(`{"added":0,"updated":0,"deleted":0,"issues":[],"sheets":[`+
+			`{"sheet":"Магазин 1","sellerId":101,"status":"failed","error":"sheet Магазин 3 of the same seller: offer_id repeats on sheets of one seller","added":0,"updated":0,"deleted":0,"issueCount":0},`+
+			`{"sheet":"Магазин 3","sellerId":101,"status":"failed","error":"offer_id repeats on sheets of one seller","added":0,"updated":0,"deleted":0,"issueCount":0}]}`),
+			"failed sheet(s): Магазин 1, Магазин 3").Return(nil)
+
+		i.process(job)
+	})
+}
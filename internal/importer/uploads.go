@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/hablof/merchant-experience/internal/models"
+)
+
+// загруженный файл хранится в базе вместе с задачей (её может забрать любая реплика),
+// задача ссылается на него адресом upload:///table.<расширение исходного файла>
+const uploadScheme = "upload"
+
+var (
+	ErrUploadFailed = errors.New("failed to save uploaded table")
+
+	// загруженного файла задачи нет в базе: его удалили после обработки или откатили задачу
+	errUploadNotFound = errors.New("uploaded table not found")
+	// загруженный файл не прочитать из базы
+	errUploadUnavailable = errors.New("uploaded table unavailable")
+)
+
+// EnqueueUpload сохраняет загруженную таблицу в базе вместе с задачей на её обработку.
+// По расширению filename потом выбирается парсер, как и для таблиц по URL.
+func (i *Importer) EnqueueUpload(job models.Job, filename string, body io.Reader) (models.Job, error) {
+	ext := strings.ToLower(path.Ext(filename))
+	switch ext {
+	case ".xlsx", ".csv", ".tsv":
+	default:
+		ext = ""
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrUploadFailed
+	}
+
+	job.TableURL = (&url.URL{Scheme: uploadScheme, Path: "/table" + ext}).String()
+
+	createdJob, err := i.repo.CreateUploadJob(job, content)
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrEnqueueFailed
+	}
+
+	i.wake()
+
+	return createdJob, nil
+}
+
+// table скачивает таблицу (условно, по валидаторам prev) или читает загруженный файл из базы
+func (i *Importer) table(ctx context.Context, job models.Job, prev models.TableFetch) (models.Table, error) {
+	if !isUpload(job.TableURL) {
+		auth, err := i.openAuth(job.Auth)
+		if err != nil {
+			return models.Table{}, err
+		}
+
+		tableURL := withQuery(job.TableURL, auth.Query)
+		auth.Query = ""
+
+		return i.td.Table(ctx, tableURL, auth, prev)
+	}
+
+	content, err := i.repo.JobUpload(job.Id)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.Table{}, errUploadNotFound
+	}
+	if err != nil {
+		log.Println(err)
+		return models.Table{}, errUploadUnavailable
+	}
+
+	return models.Table{Body: uploadBody{bytes.NewReader(content)}}, nil
+}
+
+// removeUpload удаляет загруженный файл после обработки задачи; таблицы по URL не трогает
+func (i *Importer) removeUpload(job models.Job) {
+	if !isUpload(job.TableURL) {
+		return
+	}
+
+	if err := i.repo.DeleteJobUpload(job.Id); err != nil {
+		log.Printf("failed to delete job #%d upload: %v", job.Id, err)
+	}
+}
+
+func isUpload(tableURL string) bool {
+	u, err := url.Parse(tableURL)
+
+	return err == nil && u.Scheme == uploadScheme
+}
+
+// uploadBody - загруженный файл, прочитанный из базы; закрывать нечего
+type uploadBody struct {
+	*bytes.Reader
+}
+
+func (uploadBody) Close() error {
+	return nil
+}
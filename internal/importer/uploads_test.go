@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/service"
+	"github.com/hablof/merchant-experience/internal/xlsxparser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImporter_EnqueueUpload(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		filename      string
+		repoReturnErr error
+		wantURL       string
+		wantErr       error
+	}{
+		{
+			name:     "xlsx",
+			filename: "Прайс.XLSX",
+			wantURL:  "upload:///table.xlsx",
+		},
+		{
+			name:     "csv",
+			filename: "price.csv",
+			wantURL:  "upload:///table.csv",
+		},
+		{
+			name:     "неизвестное расширение читается как xlsx",
+			filename: "../price.exe",
+			wantURL:  "upload:///table",
+		},
+		{
+			name:          "ошибка постановки в очередь",
+			filename:      "price.csv",
+			repoReturnErr: errors.New("failed to execute query"),
+			wantURL:       "upload:///table.csv",
+			wantErr:       ErrEnqueueFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rm := NewRepositoryMock(mc)
+			i := NewImporter(config.Config{}, rm, NewServiceMock(mc), NewTableDownloaderMock(mc), NewExcelParserMock(mc), NewExcelParserMock(mc))
+
+			// файл сохраняется в базе вместе с задачей
+			rm.CreateUploadJobMock.Set(func(job models.Job, content []byte) (models.Job, error) {
+				assert.Equal(t, tt.wantURL, job.TableURL)
+				assert.Equal(t, "table", string(content))
+				if tt.repoReturnErr != nil {
+					return models.Job{}, tt.repoReturnErr
+				}
+
+				job.Id = 3
+				return job, nil
+			})
+
+			job, err := i.EnqueueUpload(models.Job{SellerId: 42}, tt.filename, strings.NewReader("table"))
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, uint64(3), job.Id)
+				// воркер разбудили
+				assert.Len(t, i.wakeup, 1)
+			}
+		})
+	}
+}
+
+func TestImporter_process_upload(t *testing.T) {
+	job := models.Job{Id: 1, SellerId: 42, TableURL: "upload:///table.csv", Status: models.JobDownloading}
+	updates := []models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true}}
+
+	t.Run("файл читается из базы и удаляется после обработки", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		rm := NewRepositoryMock(mc)
+		sm := NewServiceMock(mc)
+		cpm := NewExcelParserMock(mc)
+		// TableDownloader не вызывается: файл задачи доступен любой реплике
+		i := NewImporter(config.Config{}, rm, sm, NewTableDownloaderMock(mc), NewExcelParserMock(mc), cpm)
+
+		rm.JobUploadMock.Expect(job.Id).Return([]byte("uploaded table"), nil)
+		cpm.StreamProductsMock.Set(func(r io.ReadSeeker, sheet string, batchSize int, handle xlsxparser.BatchHandler) error {
+			b, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, "uploaded table", string(b))
+
+			return handle(updates, nil, true)
+		})
+		im := expectImport(sm, 42, service.UpdateOptions{Source: importSource})
+		im.StageMock.Expect(updates, nil).Return(service.UpdateResults{Added: 1, Issues: []models.ImportIssue{}}, nil)
+		im.CommitMock.Return(service.UpdateResults{}, nil)
+		rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobParsing).Then(nil)
+		rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobWriting).Then(nil)
+		rm.FinishJobMock.Expect(job.Id, job.Attempts, models.JobDone, []byte(`{"added":1,"updated":0,"deleted":0,"issues":[]}`), "").Return(nil)
+		rm.DeleteJobUploadMock.Expect(job.Id).Return(nil)
+
+		i.process(job)
+	})
+
+	t.Run("файла нет в базе", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		rm := NewRepositoryMock(mc)
+		i := NewImporter(config.Config{}, rm, NewServiceMock(mc), NewTableDownloaderMock(mc), NewExcelParserMock(mc), NewExcelParserMock(mc))
+
+		rm.JobUploadMock.Expect(job.Id).Return(nil, models.ErrNotFound)
+		rm.FinishJobMock.Expect(job.Id, job.Attempts, models.JobFailed, nil, "uploaded table not found").Return(nil)
+		rm.DeleteJobUploadMock.Expect(job.Id).Return(nil)
+
+		i.process(job)
+	})
+}
+
+func TestImporter_process_guard(t *testing.T) {
+
+	updates := []models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 0, Quantity: 1}, Available: true}}
+	guardReport := &service.GuardReport{CatalogSize: 1, Repriced: []service.PriceChange{{OfferId: 1, OldPrice: 100, NewPrice: 0}}}
+	guardResults := []byte(`{"added":0,"updated":0,"deleted":0,"issues":[],"guard":{"catalogSize":1,"repriced":[{"offerId":1,"oldPrice":100,"newPrice":0}]}}`)
+
+	tests := []struct {
+		name        string
+		action      string
+		confirmed   bool
+		stageRet    service.UpdateResults
+		serviceErr  error
+		serviceRet  service.UpdateResults
+		wantStatus  models.JobStatus
+		wantResults []byte
+		wantErrMsg  string
+		wantKept    bool
+	}{
+		{
+			name:        "задача ждёт подтверждения, файл сохраняется",
+			stageRet:    service.UpdateResults{Updated: 1, Issues: []models.ImportIssue{}},
+			serviceErr:  service.ErrGuardTripped,
+			serviceRet:  service.UpdateResults{Issues: []models.ImportIssue{}, Guard: guardReport},
+			wantStatus:  models.JobHeld,
+			wantResults: guardResults,
+			wantErrMsg:  "guard tripped: confirmation required",
+			wantKept:    true,
+		},
+		{
+			name:        "reject завершает задачу ошибкой",
+			action:      "reject",
+			stageRet:    service.UpdateResults{Updated: 1, Issues: []models.ImportIssue{}},
+			serviceErr:  service.ErrGuardTripped,
+			serviceRet:  service.UpdateResults{Issues: []models.ImportIssue{}, Guard: guardReport},
+			wantStatus:  models.JobFailed,
+			wantResults: guardResults,
+			wantErrMsg:  "guard tripped",
+		},
+		{
+			name:        "подтверждённая задача выполняется без защиты",
+			confirmed:   true,
+			stageRet:    service.UpdateResults{Updated: 1, Issues: []models.ImportIssue{}},
+			wantStatus:  models.JobDone,
+			wantResults: []byte(`{"added":0,"updated":1,"deleted":0,"issues":[]}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			job := models.Job{Id: 1, SellerId: 42, TableURL: "upload:///table.csv", Status: models.JobDownloading, Confirmed: tt.confirmed}
+
+			rm := NewRepositoryMock(mc)
+			sm := NewServiceMock(mc)
+			cpm := NewExcelParserMock(mc)
+			cfg := config.Config{Guard: config.Guard{Action: tt.action}}
+			i := NewImporter(cfg, rm, sm, NewTableDownloaderMock(mc), NewExcelParserMock(mc), cpm)
+
+			rm.JobUploadMock.Expect(job.Id).Return([]byte("uploaded table"), nil)
+			cpm.StreamProductsMock.Set(streamOnce(updates, nil, nil))
+			// защита каталога срабатывает, когда отложенные пачки пишутся в каталог
+			im := expectImport(sm, 42, service.UpdateOptions{Source: importSource, Confirmed: tt.confirmed})
+			im.StageMock.Expect(updates, nil).Return(tt.stageRet, nil)
+			im.CommitMock.Return(tt.serviceRet, tt.serviceErr)
+			rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobParsing).Then(nil)
+			rm.SetJobStatusMock.When(job.Id, job.Attempts, models.JobWriting).Then(nil)
+			rm.FinishJobMock.Expect(job.Id, job.Attempts, tt.wantStatus, tt.wantResults, tt.wantErrMsg).Return(nil)
+			// задача, ждущая подтверждения, прочитает файл заново
+			if !tt.wantKept {
+				rm.DeleteJobUploadMock.Expect(job.Id).Return(nil)
+			}
+
+			i.process(job)
+		})
+	}
+}
@@ -2,20 +2,24 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/hablof/merchant-experience/internal/config"
 	"github.com/hablof/merchant-experience/internal/database"
 	"github.com/hablof/merchant-experience/internal/gateway"
+	"github.com/hablof/merchant-experience/internal/importer"
 	"github.com/hablof/merchant-experience/internal/pkg/testfileserver"
 	"github.com/hablof/merchant-experience/internal/repository"
 	"github.com/hablof/merchant-experience/internal/router"
+	"github.com/hablof/merchant-experience/internal/scheduler"
 	"github.com/hablof/merchant-experience/internal/service"
 	"github.com/hablof/merchant-experience/internal/xlsxparser"
 
@@ -26,7 +30,7 @@ import (
 const (
 	serverHostPort     = ":8015"
 	tableReqHostPort   = "http://127.0.0.1:8015"
-	respBodyWithErrors = `{"added":14,"updated":0,"deleted":0,"errors":[{"row":3,"field":"name","errMsg":"too long name"},{"row":4,"field":"price","errMsg":"strconv.ParseUint: parsing \"0-40\": invalid syntax"},{"row":5,"field":"price","errMsg":"strconv.ParseUint: parsing \"-666\": invalid syntax"},{"row":6,"field":"quantity","errMsg":"strconv.ParseUint: parsing \"0-40\": invalid syntax"},{"row":7,"field":"quantity","errMsg":"strconv.ParseUint: parsing \"-666\": invalid syntax"},{"row":8,"field":"available","errMsg":"strconv.ParseBool: parsing \"абра-кадабра\": invalid syntax"}]}`
+	respBodyWithErrors = `{"added":14,"updated":0,"deleted":0,"issues":[{"row":3,"offerId":3,"field":"name","code":"too_long_name","severity":"error","message":"too long name"},{"row":4,"offerId":4,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"0-40\""},{"row":5,"offerId":5,"field":"price","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"-666\""},{"row":6,"offerId":6,"field":"quantity","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"0-40\""},{"row":7,"offerId":7,"field":"quantity","code":"invalid_number","severity":"error","message":"must be a non-negative integer, got \"-666\""},{"row":8,"offerId":8,"field":"available","code":"invalid_bool","severity":"error","message":"must be true or false, got \"абра-кадабра\""}]}`
 	sellerN2Updated    = `[{"sellerId":2,"offerId":1,"name":"head_updated","price":1000,"quantity":1000},{"sellerId":2,"offerId":2,"name":"body_updated","price":1000,"quantity":1000},{"sellerId":2,"offerId":3,"name":"name1_3_updated","price":1000,"quantity":1000},{"sellerId":2,"offerId":4,"name":"bigchangus_updated","price":1000,"quantity":1000},{"sellerId":2,"offerId":19,"name":"name15_10_updated","price":1000,"quantity":1000},{"sellerId":2,"offerId":20,"name":"subtitles_updated","price":1000,"quantity":1000}]`
 )
 
@@ -35,8 +39,15 @@ type postTableJson struct {
 	SellerId uint64 `json:"sellerId"`
 }
 
+type jobJson struct {
+	Id      uint64          `json:"id"`
+	Status  string          `json:"status"`
+	Results json.RawMessage `json:"results"`
+	Error   string          `json:"error"`
+}
+
 func databaseTeardown(t *testing.T, db *sqlx.DB) {
-	_, err := db.Exec(`DROP TABLE IF EXISTS products;`)
+	_, err := db.Exec(`DROP TABLE IF EXISTS feeds, import_job_reports, products, import_jobs;`)
 	if err != nil {
 		assert.FailNow(t, err.Error())
 	}
@@ -44,7 +55,7 @@ func databaseTeardown(t *testing.T, db *sqlx.DB) {
 
 func databaseSetup(t *testing.T, db *sqlx.DB) {
 
-	if _, err := db.Exec(`DROP TABLE IF EXISTS products;`); err != nil {
+	if _, err := db.Exec(`DROP TABLE IF EXISTS feeds, import_job_reports, products, import_jobs;`); err != nil {
 		assert.FailNow(t, err.Error())
 	}
 
@@ -61,6 +72,43 @@ func databaseSetup(t *testing.T, db *sqlx.DB) {
 	if err != nil {
 		assert.FailNow(t, err.Error())
 	}
+
+	_, err = db.Exec(`CREATE TABLE import_jobs (
+		id         BIGSERIAL    PRIMARY KEY,
+		seller_id  BIGINT       NOT NULL,
+		table_url  TEXT         NOT NULL,
+		status     VARCHAR(16)  NOT NULL DEFAULT 'queued',
+		dry_run    BOOLEAN      NOT NULL DEFAULT false,
+		sync_mode  VARCHAR(16)  NOT NULL DEFAULT 'merge',
+		results    JSONB        NOT NULL DEFAULT 'null',
+		error      TEXT         NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+		updated_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+		reverted_at TIMESTAMPTZ,
+		has_report BOOLEAN      NOT NULL DEFAULT false,
+		confirmed  BOOLEAN      NOT NULL DEFAULT false,
+		sheet      TEXT         NOT NULL DEFAULT '',
+		sheets     JSONB,
+		auth       BYTEA,
+		etag       TEXT         NOT NULL DEFAULT '',
+		last_modified TEXT      NOT NULL DEFAULT '',
+		content_hash TEXT       NOT NULL DEFAULT '',
+		heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);`)
+
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	_, err = db.Exec(`CREATE TABLE import_job_reports (
+		job_id     BIGINT       PRIMARY KEY REFERENCES import_jobs(id) ON DELETE CASCADE,
+		content    BYTEA        NOT NULL,
+		created_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+	);`)
+
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
 }
 
 func TestMicroservice(t *testing.T) {
@@ -85,7 +133,8 @@ func TestMicroservice(t *testing.T) {
 		Server:     config.Server{Timeout: 5},
 		Database:   config.Database{HostLocal: "localhost", Port: "5432", User: "postgres", Password: "1234", DBName: "integration_testing"},
 		Repository: config.Repository{Timeout: 5},
-		Gateway:    config.Gateway{Timeout: 5},
+		Gateway:    config.Gateway{Timeout: 5, AllowPrivateIPs: true},
+		Importer:   config.Importer{Workers: 1, PollInterval: 1, JobTimeout: 60},
 	}
 
 	db, err := database.NewPostgres(cfg, false)
@@ -94,85 +143,93 @@ func TestMicroservice(t *testing.T) {
 	}
 
 	r := repository.NewRepository(db, cfg)
-	s := service.NewService(r)
+	s := service.NewService(cfg, r)
 	g := gateway.NewGateway(cfg)
-	p := xlsxparser.NewParser()
-	handler := router.NewRouter(s, g, p)
+	p := xlsxparser.NewParser(cfg)
+	cp := xlsxparser.NewCSVParser(cfg)
+	im := importer.NewImporter(cfg, r, s, g, p, cp)
+	sch := scheduler.NewScheduler(cfg, r, im, g, r.AdvisoryLock(scheduler.LockKey(cfg)))
+	handler := router.NewRouter(cfg, s, im, sch)
 
 	databaseSetup(t, db)
 	defer databaseTeardown(t, db)
 
+	importerCtx, stopImporter := context.WithCancel(context.Background())
+	defer stopImporter()
+	go im.Run(importerCtx)
+
 	// ЗАПИСЬ
 	testsPostTable := []struct {
 		name        string
 		pathToTable string
 		sellerId    uint64
 
-		wantStatusCode int
-		wantBody       string
+		wantJobStatus string
+		wantResults   string
+		wantError     string
 	}{
 		{
-			name:           "post table with duplicates",
-			pathToTable:    "/xlsxparser/test/example_duplicates.xlsx",
-			sellerId:       42,
-			wantStatusCode: http.StatusBadRequest,
-			wantBody:       "xslx file has duplicates",
+			name:          "post table with duplicates",
+			pathToTable:   "/xlsxparser/test/example_duplicates.xlsx",
+			sellerId:      42,
+			wantJobStatus: "failed",
+			wantError:     "table has duplicates",
 		},
 		{
-			name:           "post empty table",
-			pathToTable:    "/xlsxparser/test/example_empty.xlsx",
-			sellerId:       42,
-			wantStatusCode: http.StatusBadRequest,
-			wantBody:       "bad xslx file",
+			name:          "post empty table",
+			pathToTable:   "/xlsxparser/test/example_empty.xlsx",
+			sellerId:      42,
+			wantJobStatus: "failed",
+			wantError:     "bad table file",
 		},
 		{
-			name:           "post table with invalid offer_id column",
-			pathToTable:    "/xlsxparser/test/example_with_invalid_offer_id_col.xlsx",
-			sellerId:       42,
-			wantStatusCode: http.StatusBadRequest,
-			wantBody:       "offer_id column has invalid value(s)",
+			name:          "post table with invalid offer_id column",
+			pathToTable:   "/xlsxparser/test/example_with_invalid_offer_id_col.xlsx",
+			sellerId:      42,
+			wantJobStatus: "failed",
+			wantError:     "offer_id column has invalid value(s)",
 		},
 		{
-			name:           "post txt file",
-			pathToTable:    "/testtables/non-xlsx-file.txt",
-			sellerId:       42,
-			wantStatusCode: http.StatusBadRequest,
-			wantBody:       "bad xslx file",
+			name:          "post txt file",
+			pathToTable:   "/testtables/non-xlsx-file.txt",
+			sellerId:      42,
+			wantJobStatus: "failed",
+			wantError:     "bad table file",
 		},
 		{
-			name:           "post completly correct table with sellerId 1",
-			pathToTable:    "/testtables/01_correct.xlsx",
-			sellerId:       1,
-			wantStatusCode: http.StatusOK,
-			wantBody:       `{"added":20,"updated":0,"deleted":0,"errors":[]}`,
+			name:          "post completly correct table with sellerId 1",
+			pathToTable:   "/testtables/01_correct.xlsx",
+			sellerId:      1,
+			wantJobStatus: "done",
+			wantResults:   `{"added":20,"updated":0,"deleted":0,"issues":[]}`,
 		},
 		{
-			name:           "post completly correct table with sellerId 2 also",
-			pathToTable:    "/testtables/02_correct.xlsx",
-			sellerId:       2,
-			wantStatusCode: http.StatusOK,
-			wantBody:       `{"added":20,"updated":0,"deleted":0,"errors":[]}`,
+			name:          "post completly correct table with sellerId 2 also",
+			pathToTable:   "/testtables/02_correct.xlsx",
+			sellerId:      2,
+			wantJobStatus: "done",
+			wantResults:   `{"added":20,"updated":0,"deleted":0,"issues":[]}`,
 		},
 		{
-			name:           "post table with errors with sellerId 3",
-			pathToTable:    "/xlsxparser/test/example_with_errors.xlsx",
-			sellerId:       3,
-			wantStatusCode: http.StatusOK,
-			wantBody:       respBodyWithErrors,
+			name:          "post table with errors with sellerId 3",
+			pathToTable:   "/xlsxparser/test/example_with_errors.xlsx",
+			sellerId:      3,
+			wantJobStatus: "done",
+			wantResults:   respBodyWithErrors,
 		},
 		{
-			name:           "post table with delete offerIDs 11..20 with sellerId 1",
-			pathToTable:    "/testtables/03_correct_delete.xlsx",
-			sellerId:       1,
-			wantStatusCode: http.StatusOK,
-			wantBody:       `{"added":0,"updated":0,"deleted":10,"errors":[]}`,
+			name:          "post table with delete offerIDs 11..20 with sellerId 1",
+			pathToTable:   "/testtables/03_correct_delete.xlsx",
+			sellerId:      1,
+			wantJobStatus: "done",
+			wantResults:   `{"added":0,"updated":0,"deleted":10,"issues":[]}`,
 		},
 		{
-			name:           "post table with update offerIDs 1..4,19,20 with sellerId 2",
-			pathToTable:    "/testtables/04_correct_update.xlsx",
-			sellerId:       2,
-			wantStatusCode: http.StatusOK,
-			wantBody:       `{"added":0,"updated":6,"deleted":0,"errors":[]}`,
+			name:          "post table with update offerIDs 1..4,19,20 with sellerId 2",
+			pathToTable:   "/testtables/04_correct_update.xlsx",
+			sellerId:      2,
+			wantJobStatus: "done",
+			wantResults:   `{"added":0,"updated":6,"deleted":0,"issues":[]}`,
 		},
 	}
 
@@ -183,8 +240,22 @@ func TestMicroservice(t *testing.T) {
 			w, r := preparePostWR(t, tt.pathToTable, tt.sellerId)
 			handler.ServeHTTP(w, r)
 
-			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode)
-			assert.Equal(t, tt.wantBody, w.Body.String())
+			if !assert.Equal(t, http.StatusAccepted, w.Result().StatusCode) {
+				assert.FailNow(t, w.Body.String())
+			}
+
+			job := jobJson{}
+			if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+
+			job = waitForJob(t, handler, job.Id)
+
+			assert.Equal(t, tt.wantJobStatus, job.Status)
+			assert.Equal(t, tt.wantError, job.Error)
+			if tt.wantResults != "" {
+				assert.JSONEq(t, tt.wantResults, string(job.Results))
+			}
 		})
 	}
 
@@ -229,6 +300,31 @@ func TestMicroservice(t *testing.T) {
 	}
 }
 
+// ждём, пока воркер доведёт задачу до конечного статуса
+func waitForJob(t *testing.T, handler http.Handler, jobId uint64) jobJson {
+	deadline := time.Now().Add(30 * time.Second)
+
+	for time.Now().Before(deadline) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/jobs/"+strconv.FormatUint(jobId, 10), nil)
+		handler.ServeHTTP(w, r)
+
+		job := jobJson{}
+		if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		if job.Status == "done" || job.Status == "failed" {
+			return job
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	assert.FailNow(t, "job has not finished in time")
+	return jobJson{}
+}
+
 func preparePostWR(t *testing.T, path string, sellerId uint64) (*httptest.ResponseRecorder, *http.Request) {
 	w := httptest.NewRecorder()
 
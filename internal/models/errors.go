@@ -0,0 +1,11 @@
+package models
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("not found")
+	// загрузку уже откатили
+	ErrAlreadyReverted = errors.New("already reverted")
+	// после загрузки офферы менялись другими загрузками или запросами
+	ErrConflict = errors.New("conflict")
+)
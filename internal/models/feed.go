@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Feed - таблица продавца, которую планировщик сам загружает по расписанию
+type Feed struct {
+	Id       uint64 `db:"id"        json:"id"`
+	SellerId uint64 `db:"seller_id" json:"sellerId"`
+	TableURL string `db:"table_url" json:"tableURL"`
+	// как часто загружать таблицу, минуты
+	IntervalMinutes uint64    `db:"interval_minutes" json:"intervalMinutes"`
+	SyncMode        SyncMode  `db:"sync_mode"        json:"syncMode"`
+	NextRunAt       time.Time `db:"next_run_at"      json:"nextRunAt"`
+	CreatedAt       time.Time `db:"created_at"       json:"createdAt"`
+	// последний запуск: пусто, пока фид не запускался
+	LastRunAt *time.Time `db:"last_run_at" json:"lastRunAt,omitempty"`
+	// задача загрузки последнего запуска; нет, если задачу не удалось поставить
+	LastJobId *uint64 `db:"last_job_id" json:"lastJobId,omitempty"`
+	// статус задачи последнего запуска (или failed, если задачу не удалось поставить) и её ошибка
+	LastStatus JobStatus `db:"last_status" json:"lastStatus,omitempty"`
+	LastError  string    `db:"last_error"  json:"lastError,omitempty"`
+	// зашифрованные учётные данные источника (TableAuth), как у задачи
+	Auth []byte `db:"auth" json:"-"`
+}
+
+// FeedRun - итог запуска фида планировщиком
+type FeedRun struct {
+	JobId  *uint64
+	Status JobStatus
+	Error  string
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// что вызвало изменение товаров; сохраняется в истории вместе с изменением
+const (
+	ChangeKindImport = "import"
+	ChangeKindRevert = "revert"
+	ChangeKindAPI    = "api"
+)
+
+type ChangeSource struct {
+	Kind string
+	// загрузка таблицы, 0 - изменение не связано с загрузкой
+	JobId uint64
+	// идентификатор HTTP запроса для изменений через API
+	RequestId string
+}
+
+// действие над товаром, записанное в истории
+const (
+	ChangeInsert = "insert"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// ProductChange - запись истории товара; Old пуст у вставки, New - у удаления
+type ProductChange struct {
+	Id        uint64    `json:"id"`
+	SellerId  uint64    `json:"sellerId"`
+	OfferId   uint64    `json:"offerId"`
+	Action    string    `json:"action"`
+	Old       *Product  `json:"old,omitempty"`
+	New       *Product  `json:"new,omitempty"`
+	Source    string    `json:"source"`
+	JobId     uint64    `json:"jobId,omitempty"`
+	RequestId string    `json:"requestId,omitempty"`
+	ChangedAt time.Time `json:"changedAt"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "fmt"
+
+// IssueSeverity - насколько серьёзна проблема строки:
+// строка с ошибкой не загружается, с предупреждением - загружается
+type IssueSeverity string
+
+const (
+	SeverityError   IssueSeverity = "error"
+	SeverityWarning IssueSeverity = "warning"
+)
+
+// IssueCode - машиночитаемый код проблемы, на него могут опираться клиенты
+type IssueCode string
+
+const (
+	CodeInvalidNumber IssueCode = "invalid_number"
+	CodeInvalidBool   IssueCode = "invalid_bool"
+	CodeTooLongName   IssueCode = "too_long_name"
+
+	// правила из config.yml (validation)
+	CodeEmptyName          IssueCode = "empty_name"
+	CodeForbiddenWord      IssueCode = "forbidden_word"
+	CodePriceTooLow        IssueCode = "price_too_low"
+	CodePriceTooHigh       IssueCode = "price_too_high"
+	CodeQuantityTooHigh    IssueCode = "quantity_too_high"
+	CodePriceChangeTooHigh IssueCode = "price_change_too_high"
+)
+
+const (
+	MsgInvalidNumber = "must be a non-negative integer"
+	MsgInvalidBool   = "must be true or false"
+	MsgTooLongName   = "too long name"
+)
+
+// ImportIssue - проблема строки таблицы, найденная при разборе или валидации.
+// Row - номер строки в таблице (0, если товар пришёл не из таблицы), OfferId - 0, если его не удалось разобрать.
+// Sheet - лист книги, если задача читает не первый лист или несколько листов.
+type ImportIssue struct {
+	Sheet    string        `json:"sheet,omitempty"`
+	Row      uint64        `json:"row,omitempty"`
+	OfferId  uint64        `json:"offerId,omitempty"`
+	Field    string        `json:"field,omitempty"`
+	Code     IssueCode     `json:"code"`
+	Severity IssueSeverity `json:"severity"`
+	Message  string        `json:"message"`
+}
+
+func (i ImportIssue) Error() string {
+	return fmt.Sprintf("product invalid: row=%d, id=%d, field=%s, code=%s, err=%s", i.Row, i.OfferId, i.Field, i.Code, i.Message)
+}
+
+// HasErrors сообщает, есть ли среди проблем ошибки, а не только предупреждения
+func HasErrors(issues []ImportIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
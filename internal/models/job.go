@@ -0,0 +1,134 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobQueued      JobStatus = "queued"
+	JobDownloading JobStatus = "downloading"
+	JobParsing     JobStatus = "parsing"
+	JobWriting     JobStatus = "writing"
+	JobDone        JobStatus = "done"
+	JobFailed      JobStatus = "failed"
+	// загрузка остановлена защитой каталога и ждёт подтверждения
+	JobHeld JobStatus = "held"
+)
+
+// SyncMode определяет, что делать с офферами продавца, которых нет в таблице
+type SyncMode string
+
+const (
+	// отсутствующие в таблице офферы не трогаем
+	SyncModeMerge SyncMode = "merge"
+	// отсутствующие в таблице офферы удаляем
+	SyncModeReplace SyncMode = "replace"
+)
+
+// задача на загрузку таблицы продавца
+type Job struct {
+	Id       uint64    `db:"id"        json:"id"`
+	SellerId uint64    `db:"seller_id" json:"sellerId"`
+	TableURL string    `db:"table_url" json:"tableURL"`
+	Status   JobStatus `db:"status"    json:"status"`
+	// предпросмотр: таблица разбирается и сравнивается с базой, но ничего не записывается
+	DryRun   bool     `db:"dry_run"   json:"dryRun"`
+	SyncMode SyncMode `db:"sync_mode" json:"syncMode"`
+	// лист книги xlsx; пусто - первый лист
+	Sheet string `db:"sheet" json:"sheet,omitempty"`
+	// листы книги и продавцы, чьи каталоги на них (агентства присылают одну книгу на несколько продавцов);
+	// если заполнено, SellerId и Sheet не используются
+	Sheets SheetSellers `db:"sheets" json:"sheets,omitempty"`
+	// сериализованный service.UpdateResults, заполняется по завершении задачи
+	Results   json.RawMessage `db:"results"    json:"results"`
+	Error     string          `db:"error"      json:"error,omitempty"`
+	CreatedAt time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updatedAt"`
+	// время отката загрузки (POST /imports/{id}/revert), nil - не откатывалась
+	RevertedAt *time.Time `db:"reverted_at" json:"revertedAt,omitempty"`
+	// по задаче сохранён отчёт об ошибках (таблица с колонкой error)
+	HasReport bool `db:"has_report" json:"-"`
+	// загрузка подтверждена после остановки защитой каталога и выполняется без неё
+	Confirmed bool `db:"confirmed" json:"confirmed,omitempty"`
+	// зашифрованный TableAuth источника; не отдаётся клиенту и стирается, когда задача завершена
+	Auth []byte `db:"auth" json:"-"`
+	// номер попытки выполнения; воркер меняет задачу, только пока он не сменился (аренду не забрал другой воркер)
+	Attempts uint32 `db:"attempts" json:"-"`
+	// валидаторы таблицы, скачанной задачей; пусто у загруженных файлов и у задач без изменений
+	TableFetch
+	// адрес отчёта об ошибках; заполняется при выдаче задачи клиенту
+	ReportURL string `db:"-" json:"reportURL,omitempty"`
+}
+
+// RevertResults - итог отката загрузки
+type RevertResults struct {
+	// офферы, которым вернули значения до загрузки (в том числе удалённые загрузкой)
+	Restored uint64 `json:"restored"`
+	// офферы, добавленные загрузкой
+	Removed uint64 `json:"removed"`
+}
+
+// SheetSellers - название листа -> seller_id; в базе хранится как JSONB, пустое значение - NULL
+type SheetSellers map[string]uint64
+
+// AllSheets - ключ SheetSellers для всех листов книги, не названных в карте
+const AllSheets = "*"
+
+// Names - листы в порядке имён, чтобы задача обрабатывала их всегда одинаково
+func (s SheetSellers) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (s SheetSellers) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(map[string]uint64(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func (s *SheetSellers) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*s = nil
+		return nil
+
+	case []byte:
+		b = v
+
+	case string:
+		b = []byte(v)
+
+	default:
+		return errors.New("unsupported sheets value")
+	}
+
+	m := map[string]uint64{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	if len(m) == 0 {
+		m = nil
+	}
+	*s = m
+
+	return nil
+}
@@ -1,28 +1,15 @@
 package models
 
 import (
-	"fmt"
 	"unicode/utf8"
 )
 
-const (
-	MsgTooLongName = "too long name"
-)
-
-type ErrProductValidation struct {
-	OfferId uint64 `json:"offerId"`
-	Field   string `json:"field"`
-	ErrMsg  string `json:"errMsg"`
-}
-
-func (e ErrProductValidation) Error() string {
-	return fmt.Sprintf("product invalid: id=%d, field=%s, err=%s", e.OfferId, e.Field, e.ErrMsg)
-}
-
 type ProductUpdate struct {
 	Product Product
 	// SellerId  uint64
 	Available bool
+	// номер строки таблицы, 0 - товар пришёл не из таблицы
+	Row uint64
 }
 
 type Product struct {
@@ -33,16 +20,18 @@ type Product struct {
 	Quantity uint64 `db:"quantity"  json:"quantity"`
 }
 
-// returns ErrProductValidation type
+// returns ImportIssue type
 func (p Product) Validate() error {
-	e := ErrProductValidation{
-		OfferId: p.OfferId,
+	e := ImportIssue{
+		OfferId:  p.OfferId,
+		Severity: SeverityError,
 	}
 
 	switch {
 	case utf8.RuneCountInString(p.Name) > 100:
 		e.Field = "name"
-		e.ErrMsg = MsgTooLongName
+		e.Code = CodeTooLongName
+		e.Message = MsgTooLongName
 		return e
 	}
 
@@ -0,0 +1,40 @@
+package models
+
+import "io"
+
+// Table - скачанная таблица продавца
+type Table struct {
+	// парсеры читают таблицу в два прохода, поэтому нужен Seek; Close освобождает ресурсы (временный файл)
+	Body io.ReadSeekCloser
+	// значение заголовка Content-Type, может быть пустым
+	ContentType string
+	// источник ответил 304 на условный запрос: таблица не менялась, Body нет
+	NotModified bool
+	Fetch       TableFetch
+}
+
+// TableFetch - валидаторы скачанной таблицы: по ним следующая загрузка той же ссылки
+// делает условный запрос и узнаёт, что таблица не изменилась
+type TableFetch struct {
+	ETag         string `db:"etag"          json:"-"`
+	LastModified string `db:"last_modified" json:"-"`
+	// sha256 тела таблицы, hex
+	ContentHash string `db:"content_hash" json:"-"`
+}
+
+// TableAuth - доступ к таблице за авторизацией источника; в базе хранится только зашифрованным (Job.Auth)
+type TableAuth struct {
+	// basic auth
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// Authorization: Bearer <token>
+	BearerToken string `json:"bearerToken,omitempty"`
+	// дополнительные заголовки запроса, например X-Api-Key
+	Headers map[string]string `json:"headers,omitempty"`
+	// параметры подписанной ссылки: в table_url от них остаются только имена
+	Query string `json:"query,omitempty"`
+}
+
+func (a TableAuth) Empty() bool {
+	return a.Username == "" && a.Password == "" && a.BearerToken == "" && len(a.Headers) == 0 && a.Query == ""
+}
@@ -0,0 +1,46 @@
+package repository
+
+import "log"
+
+const (
+	// postgres принимает не больше 65535 параметров в одном запросе
+	maxBindParams = 65535
+	// параметров на строку временной таблицы загрузки: offer_id, name, price, quantity, action
+	paramsPerStagedRow = 5
+	maxChunkSize       = maxBindParams / paramsPerStagedRow
+
+	defaultChunkSize = 1000
+)
+
+const upsertSuffix = `ON CONFLICT ON CONSTRAINT no_duplicates DO UPDATE SET
+	name = EXCLUDED.name,
+	price = EXCLUDED.price,
+	quantity = EXCLUDED.quantity`
+
+var productCols = []string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}
+
+func chunkSize(configured int) int {
+	switch {
+	case configured <= 0:
+		return defaultChunkSize
+	case configured > maxChunkSize:
+		log.Printf("repository chunk size %d exceeds bind parameters limit, using %d", configured, maxChunkSize)
+		return maxChunkSize
+	default:
+		return configured
+	}
+}
+
+// chunkBounds режет n элементов на отрезки [from, to) длиной не больше size
+func chunkBounds(n, size int) [][2]int {
+	bounds := make([][2]int, 0, (n+size-1)/size)
+	for from := 0; from < n; from += size {
+		to := from + size
+		if to > n {
+			to = n
+		}
+		bounds = append(bounds, [2]int{from, to})
+	}
+
+	return bounds
+}
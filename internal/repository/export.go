@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/hablof/merchant-experience/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// ExportProducts отдаёт в handle весь каталог продавца страницами по pageSize товаров в порядке offer_id.
+// Все страницы читаются в одной транзакции REPEATABLE READ, поэтому выгрузка - снимок каталога на её начало:
+// изменения во время выгрузки в неё не попадают. Транзакция открыта, пока handle обрабатывает страницы,
+// поэтому не ограничена dbTimeout (им ограничен каждый запрос). Ошибка handle возвращается как есть.
+func (r *Repository) ExportProducts(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		log.Println(err)
+		return ErrTxFailed
+	}
+	defer rollback(tx)
+
+	if pageSize == 0 {
+		pageSize = defaultLimit
+	}
+
+	// nil - первая страница (offer_id 0 - допустимый оффер)
+	var after *uint64
+	for {
+		products, err := r.exportPage(tx, sellerId, after, pageSize)
+		if err != nil {
+			return err
+		}
+
+		if len(products) > 0 {
+			if err := handle(products); err != nil {
+				return err
+			}
+		}
+
+		if uint64(len(products)) < pageSize {
+			return nil
+		}
+		after = &products[len(products)-1].OfferId
+	}
+}
+
+// exportPage - страница каталога продавца после оффера after
+func (r *Repository) exportPage(tx *sqlx.Tx, sellerId uint64, after *uint64, pageSize uint64) ([]models.Product, error) {
+	selectQuery := r.initQuery.
+		Select(productCols...).
+		From(tableName).
+		Where(sq.Eq{sellerIdCol: sellerId})
+
+	if after != nil {
+		selectQuery = selectQuery.Where(sq.Gt{offerIdCol: *after})
+	}
+
+	selectQueryString, args, err := selectQuery.OrderBy(offerIdCol).Limit(pageSize).ToSql()
+	if err != nil {
+		log.Println(err)
+		return nil, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	products := make([]models.Product, 0, pageSize)
+	if err := tx.SelectContext(ctx, &products, selectQueryString, args...); err != nil {
+		log.Println(err)
+		return nil, ErrQueryExecFailed
+	}
+
+	return products, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRepository_ExportProducts(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	const (
+		firstPageQuery = "SELECT seller_id, offer_id, name, price, quantity FROM products WHERE seller_id = $1 ORDER BY offer_id LIMIT 2"
+		nextPageQuery  = "SELECT seller_id, offer_id, name, price, quantity FROM products WHERE seller_id = $1 AND offer_id > $2 ORDER BY offer_id LIMIT 2"
+	)
+
+	handleErr := errors.New("write failed")
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		handleErr     error
+		wantPages     [][]models.Product
+		wantErr       error
+	}{
+		{
+			name: "transaction start failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin().WillReturnError(errors.New("cannot start tx"))
+			},
+			wantErr: ErrTxFailed,
+		},
+		{
+			name: "все страницы читаются в одной транзакции",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(firstPageQuery).
+					WithArgs(42).
+					WillReturnRows(sqlxmock.NewRows(productCols).AddRow(42, 0, "name0", 1, 1).AddRow(42, 2, "name2", 2, 2))
+				m.ExpectQuery(nextPageQuery).
+					WithArgs(42, 2).
+					WillReturnRows(sqlxmock.NewRows(productCols).AddRow(42, 3, "name3", 3, 3))
+				m.ExpectRollback()
+			},
+			wantPages: [][]models.Product{
+				{{SellerId: 42, OfferId: 0, Name: "name0", Price: 1, Quantity: 1}, {SellerId: 42, OfferId: 2, Name: "name2", Price: 2, Quantity: 2}},
+				{{SellerId: 42, OfferId: 3, Name: "name3", Price: 3, Quantity: 3}},
+			},
+		},
+		{
+			name: "пустой каталог",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(firstPageQuery).WithArgs(42).WillReturnRows(sqlxmock.NewRows(productCols))
+				m.ExpectRollback()
+			},
+		},
+		{
+			name: "ошибка handle прерывает выгрузку",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(firstPageQuery).
+					WithArgs(42).
+					WillReturnRows(sqlxmock.NewRows(productCols).AddRow(42, 1, "name1", 1, 1).AddRow(42, 2, "name2", 2, 2))
+				m.ExpectRollback()
+			},
+			handleErr: handleErr,
+			wantPages: [][]models.Product{
+				{{SellerId: 42, OfferId: 1, Name: "name1", Price: 1, Quantity: 1}, {SellerId: 42, OfferId: 2, Name: "name2", Price: 2, Quantity: 2}},
+			},
+			wantErr: handleErr,
+		},
+		{
+			name: "error query execution",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(firstPageQuery).WithArgs(42).WillReturnError(errors.New("some err"))
+				m.ExpectRollback()
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			var pages [][]models.Product
+			err := r.ExportProducts(42, 2, func(products []models.Product) error {
+				pages = append(pages, products)
+				return tt.handleErr
+			})
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantPages, pages)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
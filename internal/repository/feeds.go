@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	feedsTableName     = "feeds"
+	intervalMinutesCol = "interval_minutes"
+	nextRunAtCol       = "next_run_at"
+	lastRunAtCol       = "last_run_at"
+	lastJobIdCol       = "last_job_id"
+	lastStatusCol      = "last_status"
+	lastErrorCol       = "last_error"
+)
+
+var feedCols = []string{idCol, sellerIdCol, tableURLCol, intervalMinutesCol, syncModeCol, nextRunAtCol, createdAtCol, lastRunAtCol, lastJobIdCol, lastStatusCol, lastErrorCol, authCol}
+
+// feedSelectCols - колонки фида (алиас f), статус и ошибка последнего запуска берутся из его задачи (алиас j),
+// пока она существует: они меняются, пока задача выполняется
+var feedSelectCols = func() []string {
+	cols := make([]string, 0, len(feedCols))
+	for _, col := range feedCols {
+		switch col {
+		case lastStatusCol:
+			cols = append(cols, "COALESCE(j."+statusCol+", f."+lastStatusCol+") AS "+lastStatusCol)
+		case lastErrorCol:
+			cols = append(cols, "COALESCE(j."+errorCol+", f."+lastErrorCol+") AS "+lastErrorCol)
+		default:
+			cols = append(cols, "f."+col)
+		}
+	}
+
+	return cols
+}()
+
+const feedsJoinJobs = jobsTableName + " j ON j." + idCol + " = f." + lastJobIdCol
+
+// CreateFeed сохраняет фид; первый запуск - при ближайшем обходе планировщика
+func (r *Repository) CreateFeed(feed models.Feed) (models.Feed, error) {
+	insertQueryString, args, err := r.initQuery.
+		Insert(feedsTableName).
+		Columns(sellerIdCol, tableURLCol, intervalMinutesCol, syncModeCol, authCol).
+		Values(feed.SellerId, feed.TableURL, feed.IntervalMinutes, feed.SyncMode, feed.Auth).
+		Suffix("RETURNING " + strings.Join(feedCols, ", ")).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.Feed{}, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	createdFeed := models.Feed{}
+	if err := r.db.GetContext(ctx, &createdFeed, insertQueryString, args...); err != nil {
+		log.Println(err)
+		return models.Feed{}, ErrQueryExecFailed
+	}
+
+	return createdFeed, nil
+}
+
+// Feeds возвращает фиды продавца в порядке создания
+func (r *Repository) Feeds(sellerId uint64) ([]models.Feed, error) {
+	return r.selectFeeds(r.initQuery.
+		Select(feedSelectCols...).
+		From(feedsTableName + " f").
+		LeftJoin(feedsJoinJobs).
+		Where(sq.Eq{"f." + sellerIdCol: sellerId}).
+		OrderBy("f." + idCol))
+}
+
+// DueFeeds возвращает фиды, которым пора запускаться: самые просроченные первыми.
+// Фид, задача которого ещё выполняется или ждёт подтверждения, пропускается до её завершения.
+func (r *Repository) DueFeeds(limit uint64) ([]models.Feed, error) {
+	return r.selectFeeds(r.initQuery.
+		Select(feedSelectCols...).
+		From(feedsTableName + " f").
+		LeftJoin(feedsJoinJobs).
+		Where(sq.Expr("f." + nextRunAtCol + " <= now()")).
+		Where(sq.Or{
+			sq.Eq{"j." + idCol: nil},
+			sq.Eq{"j." + statusCol: []models.JobStatus{models.JobDone, models.JobFailed}},
+			sq.NotEq{"j." + revertedAtCol: nil},
+		}).
+		OrderBy("f." + nextRunAtCol).
+		Limit(limit))
+}
+
+func (r *Repository) selectFeeds(query sq.SelectBuilder) ([]models.Feed, error) {
+	selectQueryString, args, err := query.ToSql()
+	if err != nil {
+		log.Println(err)
+		return nil, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	feeds := []models.Feed{}
+	if err := r.db.SelectContext(ctx, &feeds, selectQueryString, args...); err != nil {
+		log.Println(err)
+		return nil, ErrQueryExecFailed
+	}
+
+	return feeds, nil
+}
+
+// SaveFeedRun запоминает итог запуска фида и назначает следующий запуск через его интервал
+func (r *Repository) SaveFeedRun(feedId uint64, run models.FeedRun) error {
+	updateQueryString, args, err := r.initQuery.
+		Update(feedsTableName).
+		Set(lastRunAtCol, sq.Expr("now()")).
+		Set(nextRunAtCol, sq.Expr("now() + "+intervalMinutesCol+" * interval '1 minute'")).
+		Set(lastJobIdCol, run.JobId).
+		Set(lastStatusCol, run.Status).
+		Set(lastErrorCol, run.Error).
+		Where(sq.Eq{idCol: feedId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	return r.execJobUpdate(updateQueryString, args)
+}
+
+// DeleteFeed удаляет фид продавца; чужой или несуществующий фид - models.ErrNotFound
+func (r *Repository) DeleteFeed(sellerId uint64, feedId uint64) error {
+	deleteQueryString, args, err := r.initQuery.
+		Delete(feedsTableName).
+		Where(sq.Eq{idCol: feedId, sellerIdCol: sellerId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	return r.execJobUpdate(deleteQueryString, args)
+}
+
+// AdvisoryLock - сессионная advisory-блокировка Postgres с ключом key. Она держится, пока открыто её соединение,
+// поэтому при падении реплики или обрыве связи с базой освобождается сама.
+type AdvisoryLock struct {
+	db        *sqlx.DB
+	key       int64
+	dbTimeout time.Duration
+
+	mu   sync.Mutex
+	conn *sqlx.Conn
+}
+
+func (r *Repository) AdvisoryLock(key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: r.db, key: key, dbTimeout: r.dbTimeout}
+}
+
+// Acquire берёт блокировку, если она свободна, или проверяет, что она всё ещё у нас; false - блокировка у другой сессии
+func (l *AdvisoryLock) Acquire() (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ctx, cf := context.WithTimeout(context.Background(), l.dbTimeout)
+	defer cf()
+
+	if l.conn != nil {
+		_, err := l.conn.ExecContext(ctx, "SELECT 1")
+		if err == nil {
+			return true, nil
+		}
+
+		// вместе с соединением пропала и блокировка
+		log.Printf("advisory lock %d lost: %v", l.key, err)
+		l.discard()
+	}
+
+	conn, err := l.db.Connx(ctx)
+	if err != nil {
+		log.Println(err)
+		return false, ErrQueryExecFailed
+	}
+
+	locked := false
+	if err := conn.GetContext(ctx, &locked, "SELECT pg_try_advisory_lock($1)", l.key); err != nil {
+		log.Println(err)
+		conn.Close()
+
+		return false, ErrQueryExecFailed
+	}
+
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+
+	return true, nil
+}
+
+// Release снимает блокировку, если она у нас
+func (l *AdvisoryLock) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), l.dbTimeout)
+	defer cf()
+
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	l.discard()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+
+	return nil
+}
+
+// discard закрывает соединение блокировки, не возвращая его в пул: если блокировку не удалось снять,
+// она уйдёт вместе с сессией, а не останется на соединении, которое возьмёт кто-то ещё
+func (l *AdvisoryLock) discard() {
+	l.conn.Raw(func(driverConn interface{}) error {
+		return driver.ErrBadConn
+	})
+	l.conn = nil
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRepository_DueFeeds(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	nextRunAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	jobId := uint64(9)
+	reg := `SELECT f.id, .+, COALESCE\(j.status, f.last_status\) AS last_status, COALESCE\(j.error, f.last_error\) AS last_error, f.auth ` +
+		`FROM feeds f LEFT JOIN import_jobs j ON j.id = f.last_job_id ` +
+		`WHERE f.next_run_at <= now\(\) AND \(j.id IS NULL OR j.status IN \(\$1,\$2\) OR j.reverted_at IS NOT NULL\) ` +
+		`ORDER BY f.next_run_at LIMIT 10`
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          []models.Feed
+		wantErr       error
+	}{
+		{
+			name: "nothing due",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(reg).WithArgs(models.JobDone, models.JobFailed).WillReturnRows(sqlxmock.NewRows(feedCols))
+			},
+			want:    []models.Feed{},
+			wantErr: nil,
+		},
+		{
+			name: "due feeds",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				rows := sqlxmock.NewRows(feedCols).
+					AddRow(1, 42, "http://some.url/t", 60, "replace", nextRunAt, nextRunAt, nil, nil, "", "", []byte("sealed")).
+					AddRow(2, 43, "http://some.url/p", 15, "merge", nextRunAt, nextRunAt, nextRunAt, 9, "failed", "table not found", nil)
+				m.ExpectQuery(reg).WithArgs(models.JobDone, models.JobFailed).WillReturnRows(rows)
+			},
+			want: []models.Feed{
+				{Id: 1, SellerId: 42, TableURL: "http://some.url/t", IntervalMinutes: 60, SyncMode: models.SyncModeReplace, NextRunAt: nextRunAt, CreatedAt: nextRunAt, Auth: []byte("sealed")},
+				{
+					Id: 2, SellerId: 43, TableURL: "http://some.url/p", IntervalMinutes: 15, SyncMode: models.SyncModeMerge, NextRunAt: nextRunAt, CreatedAt: nextRunAt,
+					LastRunAt: &nextRunAt, LastJobId: &jobId, LastStatus: models.JobFailed, LastError: "table not found",
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "query execution failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(reg).WillReturnError(errors.New("some err"))
+			},
+			want:    nil,
+			wantErr: ErrQueryExecFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			feeds, err := r.DueFeeds(10)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, feeds)
+		})
+	}
+}
+
+func TestRepository_SaveFeedRun(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	const query = "UPDATE feeds SET last_run_at = now(), next_run_at = now() + interval_minutes * interval '1 minute', " +
+		"last_job_id = $1, last_status = $2, last_error = $3 WHERE id = $4"
+	jobId := uint64(9)
+
+	tests := []struct {
+		name          string
+		run           models.FeedRun
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name: "job enqueued",
+			run:  models.FeedRun{JobId: &jobId, Status: models.JobQueued},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).
+					WithArgs(&jobId, models.JobQueued, "", 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "enqueue failed",
+			run:  models.FeedRun{Status: models.JobFailed, Error: "table url not allowed"},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).
+					WithArgs(nil, models.JobFailed, "table url not allowed", 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "feed deleted",
+			run:  models.FeedRun{JobId: &jobId, Status: models.JobQueued},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			err := r.SaveFeedRun(1, tt.run)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestRepository_DeleteFeed(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	const query = "DELETE FROM feeds WHERE id = $1 AND seller_id = $2"
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name: "deleted",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).WithArgs(5, 42).WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "feed of another seller",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).WithArgs(5, 42).WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			err := r.DeleteFeed(42, 5)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestAdvisoryLock(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	const (
+		tryLock = "SELECT pg_try_advisory_lock($1)"
+		unlock  = "SELECT pg_advisory_unlock($1)"
+	)
+	r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+	lock := r.AdvisoryLock(7)
+
+	// лидер - другая реплика
+	mockCtrl.ExpectQuery(tryLock).WithArgs(7).WillReturnRows(sqlxmock.NewRows([]string{"locked"}).AddRow(false))
+	locked, err := lock.Acquire()
+	assert.NoError(t, err)
+	assert.False(t, locked)
+
+	// блокировка освободилась
+	mockCtrl.ExpectQuery(tryLock).WithArgs(7).WillReturnRows(sqlxmock.NewRows([]string{"locked"}).AddRow(true))
+	locked, err = lock.Acquire()
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	// блокировка у нас: проверяется только соединение
+	mockCtrl.ExpectExec("SELECT 1").WillReturnResult(sqlxmock.NewResult(0, 1))
+	locked, err = lock.Acquire()
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	mockCtrl.ExpectExec(unlock).WithArgs(7).WillReturnResult(sqlxmock.NewResult(0, 1))
+	assert.NoError(t, lock.Release())
+	assert.NoError(t, lock.Release(), "second release is a no-op")
+
+	assert.NoError(t, mockCtrl.ExpectationsWereMet())
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	historyTableName = "product_history"
+	actionCol        = "action"
+	oldNameCol       = "old_name"
+	oldPriceCol      = "old_price"
+	oldQuantityCol   = "old_quantity"
+	newNameCol       = "new_name"
+	newPriceCol      = "new_price"
+	newQuantityCol   = "new_quantity"
+	sourceCol        = "source"
+	jobIdCol         = "job_id"
+	requestIdCol     = "request_id"
+	changedAtCol     = "changed_at"
+	xactIdCol        = "xact_id"
+
+	// снимок транзакции загрузки на начало записи в каталог (import_jobs)
+	catalogSnapshotCol = "catalog_snapshot"
+	catalogXactIdCol   = "catalog_xact_id"
+)
+
+// setChangeSource передаёт источник изменений триггеру record_product_history (см. миграцию product_history).
+// Настройки действуют до конца транзакции, поэтому не протекают в другие запросы через пул соединений.
+func setChangeSource(ctx context.Context, tx *sqlx.Tx, source models.ChangeSource) error {
+	jobId := ""
+	if source.JobId != 0 {
+		jobId = strconv.FormatUint(source.JobId, 10)
+	}
+
+	_, err := tx.ExecContext(ctx,
+		"SELECT set_config('app.change_source', $1, true), set_config('app.change_job_id', $2, true), set_config('app.change_request_id', $3, true)",
+		source.Kind, jobId, source.RequestId,
+	)
+	if err != nil {
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+
+	return nil
+}
+
+// historyRow - строка product_history; старые значения пусты у вставки, новые - у удаления
+type historyRow struct {
+	Id          uint64         `db:"id"`
+	SellerId    uint64         `db:"seller_id"`
+	OfferId     uint64         `db:"offer_id"`
+	Action      string         `db:"action"`
+	OldName     sql.NullString `db:"old_name"`
+	OldPrice    sql.NullInt64  `db:"old_price"`
+	OldQuantity sql.NullInt64  `db:"old_quantity"`
+	NewName     sql.NullString `db:"new_name"`
+	NewPrice    sql.NullInt64  `db:"new_price"`
+	NewQuantity sql.NullInt64  `db:"new_quantity"`
+	Source      string         `db:"source"`
+	JobId       sql.NullInt64  `db:"job_id"`
+	RequestId   string         `db:"request_id"`
+	ChangedAt   time.Time      `db:"changed_at"`
+}
+
+func (h historyRow) toModel() models.ProductChange {
+	change := models.ProductChange{
+		Id:        h.Id,
+		SellerId:  h.SellerId,
+		OfferId:   h.OfferId,
+		Action:    h.Action,
+		Source:    h.Source,
+		JobId:     uint64(h.JobId.Int64),
+		RequestId: h.RequestId,
+		ChangedAt: h.ChangedAt,
+	}
+
+	if h.OldName.Valid {
+		change.Old = &models.Product{
+			SellerId: h.SellerId,
+			OfferId:  h.OfferId,
+			Name:     h.OldName.String,
+			Price:    uint64(h.OldPrice.Int64),
+			Quantity: uint64(h.OldQuantity.Int64),
+		}
+	}
+
+	if h.NewName.Valid {
+		change.New = &models.Product{
+			SellerId: h.SellerId,
+			OfferId:  h.OfferId,
+			Name:     h.NewName.String,
+			Price:    uint64(h.NewPrice.Int64),
+			Quantity: uint64(h.NewQuantity.Int64),
+		}
+	}
+
+	return change
+}
+
+var historyCols = []string{
+	idCol, sellerIdCol, offerIdCol, actionCol,
+	oldNameCol, oldPriceCol, oldQuantityCol, newNameCol, newPriceCol, newQuantityCol,
+	sourceCol, jobIdCol, requestIdCol, changedAtCol,
+}
+
+// ProductHistory возвращает последние limit изменений оффера, новые первыми
+func (r *Repository) ProductHistory(sellerId uint64, offerId uint64, limit uint64) ([]models.ProductChange, error) {
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	selectQueryString, args, err := r.initQuery.
+		Select(historyCols...).
+		From(historyTableName).
+		Where(sq.Eq{sellerIdCol: sellerId, offerIdCol: offerId}).
+		OrderBy(idCol + " DESC").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return nil, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	rows := make([]historyRow, 0)
+	if err := r.db.SelectContext(ctx, &rows, selectQueryString, args...); err != nil {
+		log.Println(err)
+		return nil, ErrQueryExecFailed
+	}
+
+	changes := make([]models.ProductChange, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, row.toModel())
+	}
+
+	return changes, nil
+}
+
+// markCatalogSnapshot запоминает в задаче jobId снимок и номер транзакции загрузки до записи в каталог:
+// по ним CatalogChangedSince находит изменения, зафиксированные позже, даже если их транзакция началась раньше
+func (t *importTx) markCatalogSnapshot(ctx context.Context, jobId uint64) error {
+	updateQuery := t.r.initQuery.
+		Update(jobsTableName).
+		Set(catalogSnapshotCol, sq.Expr("pg_current_snapshot()")).
+		Set(catalogXactIdCol, sq.Expr("pg_current_xact_id()")).
+		Where(sq.Eq{idCol: jobId})
+
+	_, err := t.exec(ctx, updateQuery)
+
+	return err
+}
+
+// CatalogChangedSince: каталог продавца менялся транзакциями, которых не видно в снимке загрузки jobId, -
+// правками через API, откатами или другими загрузками, в том числе зафиксированными позже неё, но начатыми раньше.
+// Задача без снимка (до миграции product_history_xact_id) считается изменённой.
+func (r *Repository) CatalogChangedSince(sellerId uint64, jobId uint64) (bool, error) {
+	changesQueryString, changesArgs, err := r.initQuery.
+		Select("1").
+		From(historyTableName).
+		Where(sq.Eq{sellerIdCol: sellerId}).
+		Where(xactIdCol + " >= pg_snapshot_xmin(" + jobsTableName + "." + catalogSnapshotCol + ")").
+		Where(xactIdCol + " <> " + jobsTableName + "." + catalogXactIdCol).
+		Where("NOT pg_visible_in_snapshot(" + xactIdCol + ", " + jobsTableName + "." + catalogSnapshotCol + ")").
+		PlaceholderFormat(sq.Question).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return false, ErrQueryBuilderFailed
+	}
+
+	selectQueryString, args, err := r.initQuery.
+		Select("1").
+		From(jobsTableName).
+		Where(sq.Eq{idCol: jobId}).
+		Where(sq.Or{
+			sq.Eq{catalogSnapshotCol: nil},
+			sq.Expr("EXISTS ("+changesQueryString+")", changesArgs...),
+		}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return false, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	found := 0
+	err = r.db.GetContext(ctx, &found, selectQueryString, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+
+	case err != nil:
+		log.Println(err)
+		return false, ErrQueryExecFailed
+	}
+
+	return true, nil
+}
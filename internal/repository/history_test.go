@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRepository_ProductHistory(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	changedAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	query := "SELECT id, seller_id, offer_id, action, old_name, old_price, old_quantity, new_name, new_price, new_quantity, " +
+		"source, job_id, request_id, changed_at FROM product_history WHERE offer_id = $1 AND seller_id = $2 ORDER BY id DESC LIMIT 10"
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          []models.ProductChange
+		wantErr       error
+	}{
+		{
+			name: "insert, update, delete",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				rows := sqlxmock.NewRows(historyCols).
+					AddRow(3, 42, 1, "delete", "name1", 20, 1, nil, nil, nil, "api", nil, "req-1", changedAt).
+					AddRow(2, 42, 1, "update", "name1", 10, 1, "name1", 20, 1, "import", 5, "", changedAt).
+					AddRow(1, 42, 1, "insert", nil, nil, nil, "name1", 10, 1, "import", 4, "", changedAt)
+				m.ExpectQuery(query).WithArgs(1, 42).WillReturnRows(rows)
+			},
+			want: []models.ProductChange{
+				{
+					Id: 3, SellerId: 42, OfferId: 1, Action: models.ChangeDelete, Source: "api", RequestId: "req-1", ChangedAt: changedAt,
+					Old: &models.Product{SellerId: 42, OfferId: 1, Name: "name1", Price: 20, Quantity: 1},
+				},
+				{
+					Id: 2, SellerId: 42, OfferId: 1, Action: models.ChangeUpdate, Source: models.ChangeKindImport, JobId: 5, ChangedAt: changedAt,
+					Old: &models.Product{SellerId: 42, OfferId: 1, Name: "name1", Price: 10, Quantity: 1},
+					New: &models.Product{SellerId: 42, OfferId: 1, Name: "name1", Price: 20, Quantity: 1},
+				},
+				{
+					Id: 1, SellerId: 42, OfferId: 1, Action: models.ChangeInsert, Source: models.ChangeKindImport, JobId: 4, ChangedAt: changedAt,
+					New: &models.Product{SellerId: 42, OfferId: 1, Name: "name1", Price: 10, Quantity: 1},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "empty history",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(query).WithArgs(1, 42).WillReturnRows(sqlxmock.NewRows(historyCols))
+			},
+			want:    []models.ProductChange{},
+			wantErr: nil,
+		},
+		{
+			name: "query execution failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(query).WithArgs(1, 42).WillReturnError(errors.New("some err"))
+			},
+			want:    nil,
+			wantErr: ErrQueryExecFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			changes, err := r.ProductHistory(42, 1, 10)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, changes)
+		})
+	}
+}
+
+func TestRepository_CatalogChangedSince(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	const query = "SELECT 1 FROM import_jobs WHERE id = $1 AND (catalog_snapshot IS NULL OR EXISTS (" +
+		"SELECT 1 FROM product_history WHERE seller_id = $2 AND xact_id >= pg_snapshot_xmin(import_jobs.catalog_snapshot) " +
+		"AND xact_id <> import_jobs.catalog_xact_id AND NOT pg_visible_in_snapshot(xact_id, import_jobs.catalog_snapshot)))"
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          bool
+		wantErr       error
+	}{
+		{
+			name: "catalog changed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(query).WithArgs(7, 42).WillReturnRows(sqlxmock.NewRows([]string{"?column?"}).AddRow(1))
+			},
+			want: true,
+		},
+		{
+			name: "no changes",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(query).WithArgs(7, 42).WillReturnRows(sqlxmock.NewRows([]string{"?column?"}))
+			},
+			want: false,
+		},
+		{
+			name: "query execution failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(query).WillReturnError(errors.New("some err"))
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			changed, err := r.CatalogChangedSince(42, 7)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, changed)
+		})
+	}
+}
+
+func TestRepository_ImportTx_ChangeSource(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	source := models.ChangeSource{Kind: models.ChangeKindImport, JobId: 7}
+
+	t.Run("источник изменений передаётся в транзакцию", func(t *testing.T) {
+		r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+
+		expectBeginImport(mockCtrl)
+		expectChangeSource(mockCtrl, source)
+		expectCatalogSnapshot(mockCtrl, source.JobId)
+		mockCtrl.ExpectExec(applyUpsertQuery).WithArgs(42, stageUpsert).WillReturnResult(sqlxmock.NewResult(0, 0))
+		mockCtrl.ExpectExec(applyDeleteQuery).WithArgs(42, stageDelete).WillReturnResult(sqlxmock.NewResult(0, 1))
+		mockCtrl.ExpectCommit()
+
+		tx, err := r.BeginImport(42)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, _, err = tx.Apply(false, source)
+		assert.NoError(t, err)
+		assert.NoError(t, mockCtrl.ExpectationsWereMet())
+	})
+
+	t.Run("ошибка set_config откатывает транзакцию", func(t *testing.T) {
+		r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+
+		expectBeginImport(mockCtrl)
+		mockCtrl.ExpectExec("SELECT set_config('app.change_source', $1, true), set_config('app.change_job_id', $2, true), set_config('app.change_request_id', $3, true)").
+			WillReturnError(errors.New("some err"))
+		mockCtrl.ExpectRollback()
+
+		tx, err := r.BeginImport(42)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, _, err = tx.Apply(false, source)
+		assert.Equal(t, ErrQueryExecFailed, err)
+		assert.NoError(t, mockCtrl.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/service"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// временная таблица загрузки: строки таблицы продавца до записи в каталог, удаляется вместе с транзакцией
+const stagingTableName = "import_staging"
+
+// что загрузка делает с оффером (колонка action)
+const (
+	stageUpsert = "upsert"
+	stageDelete = "delete"
+	// оффер есть в таблице, но не пишется (строка с ошибкой); в режиме replace не удаляется
+	stageKeep = "keep"
+)
+
+var stagingCols = []string{offerIdCol, nameCol, priceCol, quantityCol, actionCol}
+
+// importTx - транзакция загрузки таблицы в каталог продавца (service.ImportTx)
+type importTx struct {
+	r        *Repository
+	tx       *sqlx.Tx
+	sellerId uint64
+}
+
+// BeginImport открывает транзакцию загрузки таблицы в каталог продавца sellerId.
+// Транзакция открыта, пока таблица разбирается, поэтому не ограничена dbTimeout (им ограничен каждый запрос);
+// её закрывает Apply или Rollback.
+func (r *Repository) BeginImport(sellerId uint64) (service.ImportTx, error) {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		log.Println(err)
+		return nil, ErrTxFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	createQueryString := "CREATE TEMP TABLE " + stagingTableName + ` (
+	offer_id BIGINT       NOT NULL,
+	name     VARCHAR(100) NOT NULL,
+	price    BIGINT       NOT NULL,
+	quantity BIGINT       NOT NULL,
+	action   TEXT         NOT NULL
+) ON COMMIT DROP`
+	indexQueryString := "CREATE INDEX ON " + stagingTableName + " (" + offerIdCol + ")"
+	for _, queryString := range []string{createQueryString, indexQueryString} {
+		if _, err := tx.ExecContext(ctx, queryString); err != nil {
+			log.Println(err)
+			rollback(tx)
+			return nil, ErrQueryExecFailed
+		}
+	}
+
+	return &importTx{r: r, tx: tx, sellerId: sellerId}, nil
+}
+
+// stagedRow - строка временной таблицы загрузки
+type stagedRow struct {
+	product models.Product
+	action  string
+}
+
+// Stage откладывает запись пачки: товары для вставки или обновления, офферы для удаления
+// и офферы, которые есть в таблице, но не пишутся
+func (t *importTx) Stage(toUpsert []models.Product, toDelete []uint64, toKeep []uint64) error {
+	rows := make([]stagedRow, 0, len(toUpsert)+len(toDelete)+len(toKeep))
+	for _, product := range toUpsert {
+		rows = append(rows, stagedRow{product: product, action: stageUpsert})
+	}
+	for _, offerId := range toDelete {
+		rows = append(rows, stagedRow{product: models.Product{OfferId: offerId}, action: stageDelete})
+	}
+	for _, offerId := range toKeep {
+		rows = append(rows, stagedRow{product: models.Product{OfferId: offerId}, action: stageKeep})
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), t.r.dbTimeout)
+	defer cf()
+
+	if t.r.useCopy {
+		return t.copyStage(ctx, rows)
+	}
+
+	for _, bounds := range chunkBounds(len(rows), t.r.chunkSize) {
+		insertQuery := t.r.initQuery.
+			Insert(stagingTableName).
+			Columns(stagingCols...)
+
+		for _, row := range rows[bounds[0]:bounds[1]] {
+			insertQuery = insertQuery.Values(row.product.OfferId, row.product.Name, row.product.Price, row.product.Quantity, row.action)
+		}
+
+		insertQueryString, insertQueryArgs, err := insertQuery.ToSql()
+		if err != nil {
+			log.Println(err)
+			return ErrQueryBuilderFailed
+		}
+
+		if _, err := t.tx.ExecContext(ctx, insertQueryString, insertQueryArgs...); err != nil {
+			log.Println(err)
+			return ErrQueryExecFailed
+		}
+	}
+
+	return nil
+}
+
+// copyStage заливает пачку во временную таблицу через COPY
+func (t *importTx) copyStage(ctx context.Context, rows []stagedRow) error {
+	stmt, err := t.tx.PrepareContext(ctx, pq.CopyIn(stagingTableName, stagingCols...))
+	if err != nil {
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, int64(row.product.OfferId), row.product.Name, int64(row.product.Price), int64(row.product.Quantity), row.action); err != nil {
+			log.Println(err)
+			return ErrQueryExecFailed
+		}
+	}
+
+	// пустой Exec завершает COPY
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+
+	return nil
+}
+
+// CatalogSize - число офферов продавца в каталоге до загрузки
+func (t *importTx) CatalogSize() (uint64, error) {
+	selectQueryString, args, err := t.r.initQuery.
+		Select("count(*)").
+		From(tableName).
+		Where(sq.Eq{sellerIdCol: t.sellerId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return 0, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), t.r.dbTimeout)
+	defer cf()
+
+	size := uint64(0)
+	if err := t.tx.GetContext(ctx, &size, selectQueryString, args...); err != nil {
+		log.Println(err)
+		return 0, ErrQueryExecFailed
+	}
+
+	return size, nil
+}
+
+// MissingProducts возвращает товары продавца, которых нет среди отложенных строк: их удалит режим replace
+func (t *importTx) MissingProducts() ([]models.Product, error) {
+	selectQueryString, args, err := t.r.initQuery.
+		Select(productCols...).
+		From(tableName).
+		Where(sq.Eq{sellerIdCol: t.sellerId}).
+		Where(notStaged()).
+		OrderBy(offerIdCol).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return nil, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), t.r.dbTimeout)
+	defer cf()
+
+	products := make([]models.Product, 0)
+	if err := t.tx.SelectContext(ctx, &products, selectQueryString, args...); err != nil {
+		log.Println(err)
+		return nil, ErrQueryExecFailed
+	}
+
+	return products, nil
+}
+
+// Apply переносит отложенные строки в каталог и фиксирует транзакцию; replace - удалить офферы, которых нет в таблице
+func (t *importTx) Apply(replace bool, source models.ChangeSource) (deleted uint64, purged uint64, err error) {
+	defer rollback(t.tx)
+
+	ctx, cf := context.WithTimeout(context.Background(), t.r.applyTimeout)
+	defer cf()
+
+	// история изменений пишется триггером в этой же транзакции
+	if err := setChangeSource(ctx, t.tx, source); err != nil {
+		return 0, 0, err
+	}
+
+	if source.JobId != 0 {
+		if err := t.markCatalogSnapshot(ctx, source.JobId); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	upsertQuery := t.r.initQuery.
+		Insert(tableName).
+		Columns(productCols...).
+		Select(t.r.initQuery.
+			Select().
+			Column("CAST(? AS BIGINT)", t.sellerId).
+			Columns(offerIdCol, nameCol, priceCol, quantityCol).
+			From(stagingTableName).
+			Where(sq.Eq{actionCol: stageUpsert})).
+		Suffix(upsertSuffix)
+
+	deleteQuery := t.r.initQuery.
+		Delete(tableName).
+		Where(sq.Eq{sellerIdCol: t.sellerId}).
+		Where(sq.Expr(offerIdCol+" IN (SELECT "+offerIdCol+" FROM "+stagingTableName+" WHERE "+actionCol+" = ?)", stageDelete))
+
+	if _, err := t.exec(ctx, upsertQuery); err != nil {
+		return 0, 0, err
+	}
+
+	rowsDeleted, err := t.exec(ctx, deleteQuery)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rowsPurged := int64(0)
+	if replace {
+		purgeQuery := t.r.initQuery.
+			Delete(tableName).
+			Where(sq.Eq{sellerIdCol: t.sellerId}).
+			Where(notStaged())
+
+		rowsPurged, err = t.exec(ctx, purgeQuery)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err := t.tx.Commit(); err != nil {
+		log.Println(err)
+		return 0, 0, ErrTxFailed
+	}
+
+	return uint64(rowsDeleted), uint64(rowsPurged), nil
+}
+
+// Rollback отменяет загрузку; после Apply ничего не делает
+func (t *importTx) Rollback() error {
+	if err := t.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		log.Println(err)
+		return ErrTxFailed
+	}
+
+	return nil
+}
+
+func (t *importTx) exec(ctx context.Context, query sq.Sqlizer) (int64, error) {
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		log.Println(err)
+		return 0, ErrQueryBuilderFailed
+	}
+
+	result, err := t.tx.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		log.Println(err)
+		return 0, ErrQueryExecFailed
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println(err)
+		return 0, ErrQueryExecFailed
+	}
+
+	return rowsAffected, nil
+}
+
+// notStaged - оффера нет среди отложенных строк загрузки
+func notStaged() sq.Sqlizer {
+	return sq.Expr("NOT EXISTS (SELECT 1 FROM " + stagingTableName + " WHERE " + stagingTableName + "." + offerIdCol + " = " + tableName + "." + offerIdCol + ")")
+}
+
+func rollback(tx *sqlx.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+const (
+	createStagingQuery = `CREATE TEMP TABLE import_staging (
+		offer_id BIGINT NOT NULL, name VARCHAR(100) NOT NULL, price BIGINT NOT NULL, quantity BIGINT NOT NULL, action TEXT NOT NULL
+	) ON COMMIT DROP`
+	indexStagingQuery = "CREATE INDEX ON import_staging (offer_id)"
+
+	applyUpsertQuery = `INSERT INTO products (seller_id,offer_id,name,price,quantity)
+		SELECT CAST($1 AS BIGINT), offer_id, name, price, quantity FROM import_staging WHERE action = $2
+		ON CONFLICT ON CONSTRAINT no_duplicates DO UPDATE SET
+		name = EXCLUDED.name, price = EXCLUDED.price, quantity = EXCLUDED.quantity`
+	applyDeleteQuery = "DELETE FROM products WHERE seller_id = $1 AND offer_id IN (SELECT offer_id FROM import_staging WHERE action = $2)"
+	applyPurgeQuery  = "DELETE FROM products WHERE seller_id = $1 AND NOT EXISTS (SELECT 1 FROM import_staging WHERE import_staging.offer_id = products.offer_id)"
+)
+
+// expectBeginImport - открытие транзакции загрузки с временной таблицей
+func expectBeginImport(m sqlxmock.Sqlmock) {
+	m.ExpectBegin()
+	m.ExpectExec(createStagingQuery).WillReturnResult(sqlxmock.NewResult(0, 0))
+	m.ExpectExec(indexStagingQuery).WillReturnResult(sqlxmock.NewResult(0, 0))
+}
+
+func TestRepository_BeginImport(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name: "transaction start failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin().WillReturnError(errors.New("cannot start tx"))
+			},
+			wantErr: ErrTxFailed,
+		},
+		{
+			name: "create staging table failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(createStagingQuery).WillReturnError(errors.New("some err"))
+				m.ExpectRollback()
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name:          "ok",
+			mockBehaviour: expectBeginImport,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			tx, err := r.BeginImport(42)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantErr == nil, tx != nil, "transaction")
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_ImportTx_Stage(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name          string
+		useCopy       bool
+		toUpsert      []models.Product
+		toDelete      []uint64
+		toKeep        []uint64
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name: "nothing to stage",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+			},
+		},
+		{
+			name:     "строки пишутся пачками по 2",
+			toUpsert: []models.Product{{OfferId: 1, Name: "name1", Price: 10, Quantity: 1}},
+			toDelete: []uint64{2},
+			toKeep:   []uint64{3},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec("INSERT INTO import_staging (offer_id,name,price,quantity,action) VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10)").
+					WithArgs(1, "name1", 10, 1, stageUpsert, 2, "", 0, 0, stageDelete).
+					WillReturnResult(sqlxmock.NewResult(0, 2))
+				m.ExpectExec("INSERT INTO import_staging (offer_id,name,price,quantity,action) VALUES ($1,$2,$3,$4,$5)").
+					WithArgs(3, "", 0, 0, stageKeep).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:     "строки пишутся через COPY одной пачкой",
+			useCopy:  true,
+			toUpsert: []models.Product{{OfferId: 1, Name: "name1", Price: 10, Quantity: 1}, {OfferId: 3, Name: "name3", Price: 30, Quantity: 3}},
+			toDelete: []uint64{2},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				copyStmt := m.ExpectPrepare(`COPY "import_staging" ("offer_id", "name", "price", "quantity", "action") FROM STDIN`)
+				copyStmt.ExpectExec().WithArgs(1, "name1", 10, 1, stageUpsert).WillReturnResult(sqlxmock.NewResult(0, 0))
+				copyStmt.ExpectExec().WithArgs(3, "name3", 30, 3, stageUpsert).WillReturnResult(sqlxmock.NewResult(0, 0))
+				copyStmt.ExpectExec().WithArgs(2, "", 0, 0, stageDelete).WillReturnResult(sqlxmock.NewResult(0, 0))
+				copyStmt.ExpectExec().WillReturnResult(sqlxmock.NewResult(0, 3))
+			},
+		},
+		{
+			name:     "insert failed",
+			toDelete: []uint64{2},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec("INSERT INTO import_staging (offer_id,name,price,quantity,action) VALUES ($1,$2,$3,$4,$5)").
+					WithArgs(2, "", 0, 0, stageDelete).
+					WillReturnError(errors.New("some err"))
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5, ChunkSize: 2, UseCopy: tt.useCopy}})
+			expectBeginImport(mockCtrl)
+			tt.mockBehaviour(mockCtrl)
+			mockCtrl.ExpectRollback()
+
+			tx, err := r.BeginImport(42)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			err = tx.Stage(tt.toUpsert, tt.toDelete, tt.toKeep)
+			assert.Equal(t, tt.wantErr, err)
+			assert.NoError(t, tx.Rollback())
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_ImportTx_Apply(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	source := models.ChangeSource{Kind: models.ChangeKindImport, JobId: 7}
+
+	tests := []struct {
+		name          string
+		replace       bool
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantDeleted   uint64
+		wantPurged    uint64
+		wantErr       error
+	}{
+		{
+			name: "merge: отложенные строки пишутся одной транзакцией",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				expectChangeSource(m, source)
+				expectCatalogSnapshot(m, source.JobId)
+				m.ExpectExec(applyUpsertQuery).WithArgs(42, stageUpsert).WillReturnResult(sqlxmock.NewResult(0, 3))
+				m.ExpectExec(applyDeleteQuery).WithArgs(42, stageDelete).WillReturnResult(sqlxmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			wantDeleted: 1,
+		},
+		{
+			name:    "replace: офферы, которых нет в таблице, удаляются в той же транзакции",
+			replace: true,
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				expectChangeSource(m, source)
+				expectCatalogSnapshot(m, source.JobId)
+				m.ExpectExec(applyUpsertQuery).WithArgs(42, stageUpsert).WillReturnResult(sqlxmock.NewResult(0, 3))
+				m.ExpectExec(applyDeleteQuery).WithArgs(42, stageDelete).WillReturnResult(sqlxmock.NewResult(0, 1))
+				m.ExpectExec(applyPurgeQuery).WithArgs(42).WillReturnResult(sqlxmock.NewResult(0, 2))
+				m.ExpectCommit()
+			},
+			wantDeleted: 1,
+			wantPurged:  2,
+		},
+		{
+			name:    "ошибка удаления откатывает транзакцию",
+			replace: true,
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				expectChangeSource(m, source)
+				expectCatalogSnapshot(m, source.JobId)
+				m.ExpectExec(applyUpsertQuery).WithArgs(42, stageUpsert).WillReturnResult(sqlxmock.NewResult(0, 3))
+				m.ExpectExec(applyDeleteQuery).WithArgs(42, stageDelete).WillReturnError(errors.New("some err"))
+				m.ExpectRollback()
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name: "commit failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				expectChangeSource(m, source)
+				expectCatalogSnapshot(m, source.JobId)
+				m.ExpectExec(applyUpsertQuery).WithArgs(42, stageUpsert).WillReturnResult(sqlxmock.NewResult(0, 3))
+				m.ExpectExec(applyDeleteQuery).WithArgs(42, stageDelete).WillReturnResult(sqlxmock.NewResult(0, 0))
+				m.ExpectCommit().WillReturnError(errors.New("some err"))
+			},
+			wantErr: ErrTxFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			expectBeginImport(mockCtrl)
+			tt.mockBehaviour(mockCtrl)
+
+			tx, err := r.BeginImport(42)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			deleted, purged, err := tx.Apply(tt.replace, source)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantDeleted, deleted)
+			assert.Equal(t, tt.wantPurged, purged)
+			// после Apply откатывать нечего
+			assert.NoError(t, tx.Rollback())
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_ImportTx_MissingProducts(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+	expectBeginImport(mockCtrl)
+	mockCtrl.ExpectQuery("SELECT count(*) FROM products WHERE seller_id = $1").
+		WithArgs(42).
+		WillReturnRows(sqlxmock.NewRows([]string{"count"}).AddRow(3))
+	mockCtrl.ExpectQuery("SELECT seller_id, offer_id, name, price, quantity FROM products WHERE seller_id = $1 " +
+		"AND NOT EXISTS (SELECT 1 FROM import_staging WHERE import_staging.offer_id = products.offer_id) ORDER BY offer_id").
+		WithArgs(42).
+		WillReturnRows(sqlxmock.NewRows(productCols).AddRow(42, 2, "name2", 20, 2))
+	mockCtrl.ExpectRollback()
+
+	tx, err := r.BeginImport(42)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	size, err := tx.CatalogSize()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), size)
+
+	missing, err := tx.MissingProducts()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Product{{SellerId: 42, OfferId: 2, Name: "name2", Price: 20, Quantity: 2}}, missing)
+
+	assert.NoError(t, tx.Rollback())
+	assert.NoError(t, mockCtrl.ExpectationsWereMet())
+}
@@ -0,0 +1,353 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const (
+	jobsTableName = "import_jobs"
+	idCol         = "id"
+	tableURLCol   = "table_url"
+	statusCol     = "status"
+	resultsCol    = "results"
+	errorCol      = "error"
+	createdAtCol  = "created_at"
+	updatedAtCol  = "updated_at"
+	dryRunCol     = "dry_run"
+	syncModeCol   = "sync_mode"
+	revertedAtCol = "reverted_at"
+	hasReportCol  = "has_report"
+	confirmedCol  = "confirmed"
+	sheetCol      = "sheet"
+	sheetsCol     = "sheets"
+	authCol       = "auth"
+	etagCol       = "etag"
+	lastModCol    = "last_modified"
+	hashCol       = "content_hash"
+	heartbeatCol  = "heartbeat_at"
+	attemptsCol   = "attempts"
+)
+
+// промежуточные статусы: задачу выполняет воркер
+var runningStatuses = []models.JobStatus{models.JobDownloading, models.JobParsing, models.JobWriting}
+
+var jobCols = []string{idCol, sellerIdCol, tableURLCol, statusCol, dryRunCol, syncModeCol, resultsCol, errorCol, createdAtCol, updatedAtCol, revertedAtCol, hasReportCol, confirmedCol, sheetCol, sheetsCol, authCol, etagCol, lastModCol, hashCol, attemptsCol}
+
+// CreateJob ставит в очередь задачу с параметрами из job; id, статус и временные метки назначает база
+func (r *Repository) CreateJob(job models.Job) (models.Job, error) {
+	insertQueryString, args, err := r.insertJobQuery(job).ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	createdJob := models.Job{}
+	if err := r.db.GetContext(ctx, &createdJob, insertQueryString, args...); err != nil {
+		log.Println(err)
+		return models.Job{}, ErrQueryExecFailed
+	}
+
+	return createdJob, nil
+}
+
+func (r *Repository) insertJobQuery(job models.Job) sq.InsertBuilder {
+	return r.initQuery.
+		Insert(jobsTableName).
+		Columns(sellerIdCol, tableURLCol, statusCol, dryRunCol, syncModeCol, sheetCol, sheetsCol, authCol).
+		Values(job.SellerId, job.TableURL, models.JobQueued, job.DryRun, job.SyncMode, job.Sheet, job.Sheets, job.Auth).
+		Suffix("RETURNING " + strings.Join(jobCols, ", "))
+}
+
+// ClaimJob атомарно забирает самую старую задачу из очереди, переводит её в статус downloading и начинает новую попытку:
+// SetJobStatus, TouchJob и FinishJob меняют задачу, только пока номер попытки не сменился.
+// FOR UPDATE SKIP LOCKED позволяет нескольким воркерам (и репликам) не мешать друг другу.
+// При пустой очереди возвращает models.ErrNotFound.
+func (r *Repository) ClaimJob() (models.Job, error) {
+	updateQueryString, args, err := r.initQuery.
+		Update(jobsTableName).
+		Set(statusCol, models.JobDownloading).
+		Set(updatedAtCol, sq.Expr("now()")).
+		Set(heartbeatCol, sq.Expr("now()")).
+		Set(attemptsCol, sq.Expr(attemptsCol+" + 1")).
+		Where(sq.Expr(
+			"id = (SELECT id FROM "+jobsTableName+" WHERE status = ? ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED)",
+			models.JobQueued,
+		)).
+		Suffix("RETURNING " + strings.Join(jobCols, ", ")).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	job := models.Job{}
+	err = r.db.GetContext(ctx, &job, updateQueryString, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Job{}, models.ErrNotFound
+
+	case err != nil:
+		log.Println(err)
+		return models.Job{}, ErrQueryExecFailed
+	}
+
+	return job, nil
+}
+
+// SetJobStatus меняет статус выполняемой задачи; если её попытка attempt уже не текущая, возвращает models.ErrNotFound
+func (r *Repository) SetJobStatus(jobId uint64, attempt uint32, status models.JobStatus) error {
+	updateQueryString, args, err := r.initQuery.
+		Update(jobsTableName).
+		Set(statusCol, status).
+		Set(updatedAtCol, sq.Expr("now()")).
+		Where(sq.Eq{idCol: jobId, attemptsCol: attempt}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	return r.execJobUpdate(updateQueryString, args)
+}
+
+// TouchJob продлевает аренду выполняемой задачи; если задача уже не выполняется или её попытка attempt
+// уже не текущая (задачу вернули в очередь и забрал другой воркер), возвращает models.ErrNotFound
+func (r *Repository) TouchJob(jobId uint64, attempt uint32) error {
+	updateQueryString, args, err := r.initQuery.
+		Update(jobsTableName).
+		Set(heartbeatCol, sq.Expr("now()")).
+		Where(sq.Eq{idCol: jobId, attemptsCol: attempt, statusCol: runningStatuses}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	return r.execJobUpdate(updateQueryString, args)
+}
+
+// FinishJob переводит задачу в конечный статус, сохраняя результат (JSON) или текст ошибки;
+// если попытка attempt уже не текущая, задача не меняется и возвращается models.ErrNotFound
+func (r *Repository) FinishJob(jobId uint64, attempt uint32, status models.JobStatus, results []byte, errMsg string) error {
+	if len(results) == 0 {
+		results = []byte("null")
+	}
+
+	query := r.initQuery.
+		Update(jobsTableName).
+		Set(statusCol, status).
+		Set(resultsCol, string(results)).
+		Set(errorCol, errMsg).
+		Set(updatedAtCol, sq.Expr("now()"))
+	// учётные данные источника нужны только до конца задачи; остановленная защитой задача скачает таблицу заново
+	if status != models.JobHeld {
+		query = query.Set(authCol, nil)
+	}
+
+	updateQueryString, args, err := query.
+		Where(sq.Eq{idCol: jobId, attemptsCol: attempt}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	return r.execJobUpdate(updateQueryString, args)
+}
+
+// ConfirmJob возвращает в очередь задачу, остановленную защитой каталога; повторно она выполняется без защиты.
+// Если задачи нет, она не в статусе held или уже откачена, возвращает models.ErrNotFound.
+func (r *Repository) ConfirmJob(jobId uint64) error {
+	updateQueryString, args, err := r.initQuery.
+		Update(jobsTableName).
+		Set(statusCol, models.JobQueued).
+		Set(confirmedCol, true).
+		Set(updatedAtCol, sq.Expr("now()")).
+		Where(sq.Eq{idCol: jobId, statusCol: models.JobHeld, revertedAtCol: nil}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	return r.execJobUpdate(updateQueryString, args)
+}
+
+func (r *Repository) execJobUpdate(queryString string, args []interface{}) error {
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	result, err := r.db.ExecContext(ctx, queryString, args...)
+	if err != nil {
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// SaveJobFetch сохраняет валидаторы таблицы, скачанной задачей
+func (r *Repository) SaveJobFetch(jobId uint64, fetch models.TableFetch) error {
+	updateQueryString, args, err := r.initQuery.
+		Update(jobsTableName).
+		Set(etagCol, fetch.ETag).
+		Set(lastModCol, fetch.LastModified).
+		Set(hashCol, fetch.ContentHash).
+		Where(sq.Eq{idCol: jobId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	return r.execJobUpdate(updateQueryString, args)
+}
+
+// LastImport возвращает последнюю успешную загрузку таблицы tableURL в каталог продавца, которая его изменила:
+// не предпросмотр, не откаченную и не завершённую без изменений. Если такой нет, возвращает models.ErrNotFound.
+func (r *Repository) LastImport(sellerId uint64, tableURL string) (models.Job, error) {
+	selectQueryString, args, err := r.initQuery.
+		Select(jobCols...).
+		From(jobsTableName).
+		Where(sq.Eq{sellerIdCol: sellerId, tableURLCol: tableURL, statusCol: models.JobDone, dryRunCol: false, revertedAtCol: nil}).
+		Where(sq.Expr(resultsCol + "->>'noChanges' IS NULL")).
+		OrderBy(idCol + " DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	job := models.Job{}
+	err = r.db.GetContext(ctx, &job, selectQueryString, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Job{}, models.ErrNotFound
+
+	case err != nil:
+		log.Println(err)
+		return models.Job{}, ErrQueryExecFailed
+	}
+
+	return job, nil
+}
+
+func (r *Repository) Job(jobId uint64) (models.Job, error) {
+	selectQueryString, args, err := r.initQuery.
+		Select(jobCols...).
+		From(jobsTableName).
+		Where(sq.Eq{idCol: jobId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	job := models.Job{}
+	err = r.db.GetContext(ctx, &job, selectQueryString, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Job{}, models.ErrNotFound
+
+	case err != nil:
+		log.Println(err)
+		return models.Job{}, ErrQueryExecFailed
+	}
+
+	return job, nil
+}
+
+// RequeueStaleJobs возвращает в очередь выполняемые задачи, аренду которых не продлевали дольше staleAfter
+// (воркер упал или сервис перезапустили посреди загрузки). Задача, брошенная на попытке maxAttempts,
+// в очередь не возвращается, а завершается ошибкой "worker crashed": таблица, роняющая воркер, иначе роняла бы их по очереди.
+func (r *Repository) RequeueStaleJobs(staleAfter time.Duration, maxAttempts uint32) (requeued uint64, failed uint64, err error) {
+	stale := sq.And{
+		sq.Eq{statusCol: runningStatuses},
+		sq.Expr(heartbeatCol+" < now() - make_interval(secs => ?)", staleAfter.Seconds()),
+	}
+
+	failQuery := r.initQuery.
+		Update(jobsTableName).
+		Set(statusCol, models.JobFailed).
+		Set(resultsCol, "null").
+		Set(errorCol, "worker crashed").
+		Set(updatedAtCol, sq.Expr("now()")).
+		Set(authCol, nil).
+		Where(stale).
+		Where(sq.GtOrEq{attemptsCol: maxAttempts})
+
+	requeueQuery := r.initQuery.
+		Update(jobsTableName).
+		Set(statusCol, models.JobQueued).
+		Set(updatedAtCol, sq.Expr("now()")).
+		Where(stale)
+
+	failed, err = r.execStaleUpdate(failQuery)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	requeued, err = r.execStaleUpdate(requeueQuery)
+	if err != nil {
+		return 0, failed, err
+	}
+
+	return requeued, failed, nil
+}
+
+func (r *Repository) execStaleUpdate(query sq.UpdateBuilder) (uint64, error) {
+	updateQueryString, args, err := query.ToSql()
+	if err != nil {
+		log.Println(err)
+		return 0, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	result, err := r.db.ExecContext(ctx, updateQueryString, args...)
+	if err != nil {
+		log.Println(err)
+		return 0, ErrQueryExecFailed
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println(err)
+		return 0, ErrQueryExecFailed
+	}
+
+	return uint64(rowsAffected), nil
+}
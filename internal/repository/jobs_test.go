@@ -0,0 +1,422 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRepository_ClaimJob(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	createdAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          models.Job
+		wantErr       error
+	}{
+		{
+			name: "empty queue",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `UPDATE import_jobs SET status = \$1, updated_at = now\(\), heartbeat_at = now\(\), attempts = attempts \+ 1 WHERE id = \(SELECT id FROM import_jobs WHERE status = \$2 .+ FOR UPDATE SKIP LOCKED\) RETURNING`
+				m.ExpectQuery(reg).WithArgs(models.JobDownloading, models.JobQueued).WillReturnRows(sqlxmock.NewRows(jobCols))
+			},
+			want:    models.Job{},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "query execution failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(`UPDATE import_jobs`).WillReturnError(errors.New("some err"))
+			},
+			want:    models.Job{},
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name: "job claimed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				rows := sqlxmock.NewRows(jobCols).
+					AddRow(5, 42, "http://some.url/t", "downloading", false, "replace", []byte("null"), "", createdAt, createdAt, nil, false, false, "", nil, nil, "", "", "", 2)
+				m.ExpectQuery(`UPDATE import_jobs`).WithArgs(models.JobDownloading, models.JobQueued).WillReturnRows(rows)
+			},
+			want: models.Job{
+				Id:        5,
+				SellerId:  42,
+				TableURL:  "http://some.url/t",
+				Status:    models.JobDownloading,
+				SyncMode:  models.SyncModeReplace,
+				Results:   json.RawMessage("null"),
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Attempts:  2,
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			job, err := r.ClaimJob()
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, job)
+		})
+	}
+}
+
+func TestRepository_FinishJob(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name          string
+		jobId         uint64
+		attempt       uint32
+		status        models.JobStatus
+		results       []byte
+		errMsg        string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name:    "failed job without results",
+			jobId:   5,
+			attempt: 1,
+			status:  models.JobFailed,
+			results: nil,
+			errMsg:  "bad table url",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec("UPDATE import_jobs SET status = $1, results = $2, error = $3, updated_at = now(), auth = $4 WHERE attempts = $5 AND id = $6").
+					WithArgs(models.JobFailed, "null", "bad table url", nil, 1, 5).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "done job",
+			jobId:   5,
+			attempt: 1,
+			status:  models.JobDone,
+			results: []byte(`{"added":1}`),
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec("UPDATE import_jobs SET status = $1, results = $2, error = $3, updated_at = now(), auth = $4 WHERE attempts = $5 AND id = $6").
+					WithArgs(models.JobDone, `{"added":1}`, "", nil, 1, 5).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "held job keeps credentials",
+			jobId:   5,
+			attempt: 1,
+			status:  models.JobHeld,
+			results: []byte(`{"guard":{}}`),
+			errMsg:  "guard tripped: confirmation required",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec("UPDATE import_jobs SET status = $1, results = $2, error = $3, updated_at = now() WHERE attempts = $4 AND id = $5").
+					WithArgs(models.JobHeld, `{"guard":{}}`, "guard tripped: confirmation required", 1, 5).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "no such job",
+			jobId:   6,
+			attempt: 1,
+			status:  models.JobDone,
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec("UPDATE import_jobs SET status = $1, results = $2, error = $3, updated_at = now(), auth = $4 WHERE attempts = $5 AND id = $6").
+					WithArgs(models.JobDone, "null", "", nil, 1, 6).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name:    "job claimed by another worker",
+			jobId:   5,
+			attempt: 1,
+			status:  models.JobDone,
+			results: []byte(`{"added":1}`),
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec("UPDATE import_jobs SET status = $1, results = $2, error = $3, updated_at = now(), auth = $4 WHERE attempts = $5 AND id = $6").
+					WithArgs(models.JobDone, `{"added":1}`, "", nil, 1, 5).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			err := r.FinishJob(tt.jobId, tt.attempt, tt.status, tt.results, tt.errMsg)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestRepository_ConfirmJob(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	const query = "UPDATE import_jobs SET status = $1, confirmed = $2, updated_at = now() WHERE id = $3 AND reverted_at IS NULL AND status = $4"
+
+	tests := []struct {
+		name          string
+		jobId         uint64
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name:  "held job requeued",
+			jobId: 5,
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).
+					WithArgs(models.JobQueued, true, 5, models.JobHeld).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "job is not held",
+			jobId: 6,
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).
+					WithArgs(models.JobQueued, true, 6, models.JobHeld).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name:  "query execution failed",
+			jobId: 7,
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).WillReturnError(errors.New("some err"))
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			err := r.ConfirmJob(tt.jobId)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestRepository_TouchJob(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	const query = "UPDATE import_jobs SET heartbeat_at = now() WHERE attempts = $1 AND id = $2 AND status IN ($3,$4,$5)"
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name: "lease extended",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).
+					WithArgs(1, 5, models.JobDownloading, models.JobParsing, models.JobWriting).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "job is not running",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "lease lost: job claimed by another worker",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(query).
+					WithArgs(1, 5, models.JobDownloading, models.JobParsing, models.JobWriting).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			err := r.TouchJob(5, 1)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestRepository_RequeueStaleJobs(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	const failQuery = "UPDATE import_jobs SET status = $1, results = $2, error = $3, updated_at = now(), auth = $4 " +
+		"WHERE (status IN ($5,$6,$7) AND heartbeat_at < now() - make_interval(secs => $8)) AND attempts >= $9"
+	const requeueQuery = "UPDATE import_jobs SET status = $1, updated_at = now() " +
+		"WHERE (status IN ($2,$3,$4) AND heartbeat_at < now() - make_interval(secs => $5))"
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantRequeued  uint64
+		wantFailed    uint64
+		wantErr       error
+	}{
+		{
+			name: "abandoned jobs requeued",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(failQuery).
+					WithArgs(models.JobFailed, "null", "worker crashed", nil, models.JobDownloading, models.JobParsing, models.JobWriting, float64(60), 3).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				m.ExpectExec(requeueQuery).
+					WithArgs(models.JobQueued, models.JobDownloading, models.JobParsing, models.JobWriting, float64(60)).
+					WillReturnResult(sqlxmock.NewResult(0, 2))
+			},
+			wantRequeued: 2,
+			wantErr:      nil,
+		},
+		{
+			name: "jobs abandoned on the last attempt failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(failQuery).
+					WithArgs(models.JobFailed, "null", "worker crashed", nil, models.JobDownloading, models.JobParsing, models.JobWriting, float64(60), 3).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				m.ExpectExec(requeueQuery).
+					WithArgs(models.JobQueued, models.JobDownloading, models.JobParsing, models.JobWriting, float64(60)).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			wantFailed: 1,
+			wantErr:    nil,
+		},
+		{
+			name: "query execution failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectExec(failQuery).WillReturnError(errors.New("some err"))
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			requeued, failed, err := r.RequeueStaleJobs(time.Minute, 3)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantRequeued, requeued)
+			assert.Equal(t, tt.wantFailed, failed)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_LastImport(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	createdAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	reg := `SELECT .+ FROM import_jobs WHERE dry_run = \$1 AND reverted_at IS NULL AND seller_id = \$2 AND status = \$3 AND table_url = \$4 AND results->>'noChanges' IS NULL ORDER BY id DESC LIMIT 1`
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          models.Job
+		wantErr       error
+	}{
+		{
+			name: "no imports",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(reg).WithArgs(false, 42, models.JobDone, "http://some.url/t").WillReturnRows(sqlxmock.NewRows(jobCols))
+			},
+			want:    models.Job{},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "query execution failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(reg).WillReturnError(errors.New("some err"))
+			},
+			want:    models.Job{},
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name: "last import found",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				rows := sqlxmock.NewRows(jobCols).
+					AddRow(5, 42, "http://some.url/t", "done", false, "replace", []byte("null"), "", createdAt, createdAt, nil, false, false, "", nil, nil, `"v1"`, "Tue, 01 Aug 2023 12:00:00 GMT", "abc", 1)
+				m.ExpectQuery(reg).WithArgs(false, 42, models.JobDone, "http://some.url/t").WillReturnRows(rows)
+			},
+			want: models.Job{
+				Id:        5,
+				SellerId:  42,
+				TableURL:  "http://some.url/t",
+				Status:    models.JobDone,
+				SyncMode:  models.SyncModeReplace,
+				Results:   json.RawMessage("null"),
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Attempts:  1,
+				TableFetch: models.TableFetch{
+					ETag:         `"v1"`,
+					LastModified: "Tue, 01 Aug 2023 12:00:00 GMT",
+					ContentHash:  "abc",
+				},
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
+			r := NewRepository(db, cfg)
+			tt.mockBehaviour(mockCtrl)
+
+			job, err := r.LastImport(42, "http://some.url/t")
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, job)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/service"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// Product возвращает оффер продавца или models.ErrNotFound
+func (r *Repository) Product(sellerId uint64, offerId uint64) (models.Product, error) {
+	queryString, args, err := r.initQuery.
+		Select(productCols...).
+		From(tableName).
+		Where(sq.Eq{sellerIdCol: sellerId, offerIdCol: offerId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.Product{}, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	product := models.Product{}
+	err = r.db.GetContext(ctx, &product, queryString, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.Product{}, models.ErrNotFound
+
+	case err != nil:
+		log.Println(err)
+		return models.Product{}, ErrQueryExecFailed
+	}
+
+	return product, nil
+}
+
+// PutProduct создаёт оффер или целиком заменяет его значения; created - оффера до этого не было
+func (r *Repository) PutProduct(product models.Product, source models.ChangeSource) (created bool, err error) {
+	// xmax = 0 только у строки, вставленной этим запросом, а не обновлённой через ON CONFLICT
+	queryString, args, err := r.initQuery.
+		Insert(tableName).
+		Columns(productCols...).
+		Values(product.SellerId, product.OfferId, product.Name, product.Price, product.Quantity).
+		Suffix(upsertSuffix + " RETURNING (xmax = 0)").
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return false, ErrQueryBuilderFailed
+	}
+
+	err = r.inChangeTx(source, func(ctx context.Context, tx *sqlx.Tx) error {
+		return tx.QueryRowContext(ctx, queryString, args...).Scan(&created)
+	})
+
+	return created, err
+}
+
+// PatchProduct меняет только переданные в patch поля и возвращает оффер после изменения.
+// Пустой patch не допускается; если оффера нет - models.ErrNotFound.
+func (r *Repository) PatchProduct(sellerId uint64, offerId uint64, patch service.ProductPatch, source models.ChangeSource) (models.Product, error) {
+	values := map[string]interface{}{}
+	if patch.Name != nil {
+		values[nameCol] = *patch.Name
+	}
+	if patch.Price != nil {
+		values[priceCol] = *patch.Price
+	}
+	if patch.Quantity != nil {
+		values[quantityCol] = *patch.Quantity
+	}
+	if len(values) == 0 {
+		return models.Product{}, ErrEmptyRequest
+	}
+
+	queryString, args, err := r.initQuery.
+		Update(tableName).
+		SetMap(values).
+		Where(sq.Eq{sellerIdCol: sellerId, offerIdCol: offerId}).
+		Suffix("RETURNING " + strings.Join(productCols, ", ")).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.Product{}, ErrQueryBuilderFailed
+	}
+
+	product := models.Product{}
+	err = r.inChangeTx(source, func(ctx context.Context, tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, &product, queryString, args...)
+	})
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	return product, nil
+}
+
+// DeleteProduct удаляет оффер; если его нет - models.ErrNotFound
+func (r *Repository) DeleteProduct(sellerId uint64, offerId uint64, source models.ChangeSource) error {
+	queryString, args, err := r.initQuery.
+		Delete(tableName).
+		Where(sq.Eq{sellerIdCol: sellerId, offerIdCol: offerId}).
+		Suffix("RETURNING " + offerIdCol).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	return r.inChangeTx(source, func(ctx context.Context, tx *sqlx.Tx) error {
+		deletedId := uint64(0)
+		return tx.QueryRowContext(ctx, queryString, args...).Scan(&deletedId)
+	})
+}
+
+// inChangeTx выполняет изменение одного оффера в транзакции с источником изменений для истории.
+// sql.ErrNoRows из exec означает, что оффера нет, и превращается в models.ErrNotFound.
+func (r *Repository) inChangeTx(source models.ChangeSource, exec func(ctx context.Context, tx *sqlx.Tx) error) error {
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		log.Println(err)
+		return ErrTxFailed
+	}
+	defer tx.Rollback()
+
+	if err := setChangeSource(ctx, tx, source); err != nil {
+		return err
+	}
+
+	err = exec(ctx, tx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.ErrNotFound
+
+	case err != nil:
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println(err)
+		return ErrTxFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/service"
+	"github.com/stretchr/testify/assert"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRepository_Product(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "SELECT seller_id, offer_id, name, price, quantity FROM products WHERE offer_id = $1 AND seller_id = $2"
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          models.Product
+		wantErr       error
+	}{
+		{
+			name: "оффер найден",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(query).WithArgs(2, 42).
+					WillReturnRows(sqlxmock.NewRows(productCols).AddRow(42, 2, "name2", 20, 2))
+			},
+			want: models.Product{SellerId: 42, OfferId: 2, Name: "name2", Price: 20, Quantity: 2},
+		},
+		{
+			name: "оффера нет",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(query).WithArgs(2, 42).WillReturnRows(sqlxmock.NewRows(productCols))
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "query execution failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(query).WithArgs(2, 42).WillReturnError(errors.New("some err"))
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			product, err := r.Product(42, 2)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, product)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_PutProduct(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	source := models.ChangeSource{Kind: models.ChangeKindAPI, RequestId: "req-1"}
+	product := models.Product{SellerId: 42, OfferId: 2, Name: "name2", Price: 20, Quantity: 2}
+	query := "INSERT INTO products (seller_id,offer_id,name,price,quantity) VALUES ($1,$2,$3,$4,$5) " + upsertSuffix + " RETURNING (xmax = 0)"
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          bool
+		wantErr       error
+	}{
+		{
+			name: "оффер создан",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				expectChangeSource(m, source)
+				m.ExpectQuery(query).WithArgs(42, 2, "name2", 20, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"?column?"}).AddRow(true))
+				m.ExpectCommit()
+			},
+			want: true,
+		},
+		{
+			name: "оффер заменён",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				expectChangeSource(m, source)
+				m.ExpectQuery(query).WithArgs(42, 2, "name2", 20, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"?column?"}).AddRow(false))
+				m.ExpectCommit()
+			},
+			want: false,
+		},
+		{
+			name: "query execution failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				expectChangeSource(m, source)
+				m.ExpectQuery(query).WithArgs(42, 2, "name2", 20, 2).WillReturnError(errors.New("some err"))
+				m.ExpectRollback()
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			created, err := r.PutProduct(product, source)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, created)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_PatchProduct(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	source := models.ChangeSource{Kind: models.ChangeKindAPI, RequestId: "req-1"}
+	name, price := "new name", uint64(25)
+
+	tests := []struct {
+		name          string
+		patch         service.ProductPatch
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          models.Product
+		wantErr       error
+	}{
+		{
+			name:  "меняем цену",
+			patch: service.ProductPatch{Price: &price},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				expectChangeSource(m, source)
+				m.ExpectQuery("UPDATE products SET price = $1 WHERE offer_id = $2 AND seller_id = $3 RETURNING seller_id, offer_id, name, price, quantity").
+					WithArgs(25, 2, 42).
+					WillReturnRows(sqlxmock.NewRows(productCols).AddRow(42, 2, "name2", 25, 2))
+				m.ExpectCommit()
+			},
+			want: models.Product{SellerId: 42, OfferId: 2, Name: "name2", Price: 25, Quantity: 2},
+		},
+		{
+			name:  "меняем название и цену",
+			patch: service.ProductPatch{Name: &name, Price: &price},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				expectChangeSource(m, source)
+				m.ExpectQuery("UPDATE products SET name = $1, price = $2 WHERE offer_id = $3 AND seller_id = $4 RETURNING seller_id, offer_id, name, price, quantity").
+					WithArgs("new name", 25, 2, 42).
+					WillReturnRows(sqlxmock.NewRows(productCols).AddRow(42, 2, "new name", 25, 2))
+				m.ExpectCommit()
+			},
+			want: models.Product{SellerId: 42, OfferId: 2, Name: "new name", Price: 25, Quantity: 2},
+		},
+		{
+			name:  "оффера нет",
+			patch: service.ProductPatch{Price: &price},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				expectChangeSource(m, source)
+				m.ExpectQuery("UPDATE products SET price = $1 WHERE offer_id = $2 AND seller_id = $3 RETURNING seller_id, offer_id, name, price, quantity").
+					WithArgs(25, 2, 42).
+					WillReturnRows(sqlxmock.NewRows(productCols))
+				m.ExpectRollback()
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name:          "пустой patch",
+			patch:         service.ProductPatch{},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {},
+			wantErr:       ErrEmptyRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			product, err := r.PatchProduct(42, 2, tt.patch, source)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, product)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_DeleteProduct(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	source := models.ChangeSource{Kind: models.ChangeKindAPI, RequestId: "req-1"}
+	query := "DELETE FROM products WHERE offer_id = $1 AND seller_id = $2 RETURNING offer_id"
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name: "оффер удалён",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				expectChangeSource(m, source)
+				m.ExpectQuery(query).WithArgs(2, 42).WillReturnRows(sqlxmock.NewRows([]string{offerIdCol}).AddRow(2))
+				m.ExpectCommit()
+			},
+		},
+		{
+			name: "оффера нет",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				expectChangeSource(m, source)
+				m.ExpectQuery(query).WithArgs(2, 42).WillReturnRows(sqlxmock.NewRows([]string{offerIdCol}))
+				m.ExpectRollback()
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "begin failed",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin().WillReturnError(errors.New("some err"))
+			},
+			wantErr: ErrTxFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			assert.Equal(t, tt.wantErr, r.DeleteProduct(42, 2, source))
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
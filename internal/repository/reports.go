@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/hablof/merchant-experience/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const (
+	reportsTableName = "import_job_reports"
+	reportJobIdCol   = "job_id"
+	contentCol       = "content"
+)
+
+// SaveJobReport сохраняет отчёт об ошибках задачи и отмечает его наличие у задачи.
+// Повторная обработка задачи (после перезапуска) перезаписывает отчёт.
+func (r *Repository) SaveJobReport(jobId uint64, report []byte) error {
+	insertQueryString, insertArgs, err := r.initQuery.
+		Insert(reportsTableName).
+		Columns(reportJobIdCol, contentCol).
+		Values(jobId, report).
+		Suffix("ON CONFLICT (" + reportJobIdCol + ") DO UPDATE SET " + contentCol + " = EXCLUDED." + contentCol + ", " + createdAtCol + " = now()").
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	updateQueryString, updateArgs, err := r.initQuery.
+		Update(jobsTableName).
+		Set(hasReportCol, true).
+		Where(sq.Eq{idCol: jobId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		log.Println(err)
+		return ErrTxFailed
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, insertQueryString, insertArgs...); err != nil {
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+
+	if _, err := tx.ExecContext(ctx, updateQueryString, updateArgs...); err != nil {
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println(err)
+		return ErrTxFailed
+	}
+
+	return nil
+}
+
+// JobReport возвращает отчёт об ошибках задачи или models.ErrNotFound
+func (r *Repository) JobReport(jobId uint64) ([]byte, error) {
+	selectQueryString, args, err := r.initQuery.
+		Select(contentCol).
+		From(reportsTableName).
+		Where(sq.Eq{reportJobIdCol: jobId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return nil, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	var report []byte
+	err = r.db.GetContext(ctx, &report, selectQueryString, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrNotFound
+
+	case err != nil:
+		log.Println(err)
+		return nil, ErrQueryExecFailed
+	}
+
+	return report, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRepository_SaveJobReport(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	report := []byte("xlsx")
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name: "ошибка вставки отчёта",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`INSERT INTO import_job_reports \(job_id,content\) VALUES \(\$1,\$2\) ON CONFLICT \(job_id\) DO UPDATE`).
+					WithArgs(7, report).
+					WillReturnError(errors.New("some err"))
+				m.ExpectRollback()
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name: "отчёт сохранён",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`INSERT INTO import_job_reports`).
+					WithArgs(7, report).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				m.ExpectExec(`UPDATE import_jobs SET has_report = \$1 WHERE id = \$2`).
+					WithArgs(true, 7).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			err := r.SaveJobReport(7, report)
+			assert.Equal(t, tt.wantErr, err)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_JobReport(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          []byte
+		wantErr       error
+	}{
+		{
+			name: "отчёта нет",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(`SELECT content FROM import_job_reports WHERE job_id = \$1`).
+					WithArgs(7).
+					WillReturnRows(sqlxmock.NewRows([]string{contentCol}))
+			},
+			want:    nil,
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "ошибка запроса",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(`SELECT content FROM import_job_reports`).
+					WithArgs(7).
+					WillReturnError(errors.New("some err"))
+			},
+			want:    nil,
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name: "отчёт найден",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(`SELECT content FROM import_job_reports`).
+					WithArgs(7).
+					WillReturnRows(sqlxmock.NewRows([]string{contentCol}).AddRow([]byte("xlsx")))
+			},
+			want:    []byte("xlsx"),
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			report, err := r.JobReport(7)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, report)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
@@ -2,9 +2,10 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hablof/merchant-experience/internal/config"
@@ -13,6 +14,7 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const (
@@ -28,6 +30,17 @@ const (
 	defaultLimit = 100
 )
 
+// выражения полнотекстового поиска; nameTsvector совпадает с индексом products_name_fts_idx
+const (
+	nameTsvector = "to_tsvector('russian', " + nameCol + ")"
+	nameTsquery  = "plainto_tsquery('russian', ?)"
+	nameRank     = "ts_rank(" + nameTsvector + ", " + nameTsquery + ")"
+	rankCol      = "rank"
+)
+
+// спецсимволы LIKE в поисковой строке ищутся буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 var (
 	ErrQueryBuilderFailed = errors.New("query builder failed")
 	ErrTxFailed           = errors.New("transaction failed")
@@ -35,147 +48,199 @@ var (
 	ErrEmptyRequest       = errors.New("empty request")
 )
 
+// defaultApplyTimeout - бюджет переноса загрузки в каталог и её отката, если repository.apply-timeout не задан
+const defaultApplyTimeout = 5 * time.Minute
+
 type Repository struct {
 	db        *sqlx.DB
 	initQuery sq.StatementBuilderType
 	dbTimeout time.Duration
+	// Apply и RevertJob переписывают весь каталог продавца одной транзакцией, dbTimeout для них мал
+	applyTimeout time.Duration
+	chunkSize    int
+	useCopy      bool
 }
 
 func NewRepository(db *sqlx.DB, cfg config.Config) *Repository {
 	return &Repository{
-		db:        db,
-		initQuery: sq.StatementBuilder.PlaceholderFormat(sq.Dollar), // Postgress
-		dbTimeout: time.Duration(cfg.Repository.Timeout) * time.Second,
+		db:           db,
+		initQuery:    sq.StatementBuilder.PlaceholderFormat(sq.Dollar), // Postgress
+		dbTimeout:    time.Duration(cfg.Repository.Timeout) * time.Second,
+		applyTimeout: applyTimeout(cfg.Repository.ApplyTimeout),
+		chunkSize:    chunkSize(cfg.Repository.ChunkSize),
+		useCopy:      cfg.Repository.UseCopy,
 	}
 }
 
-func (r *Repository) ManageProducts(
-	sellerId uint64,
-	productsToAdd []models.Product,
-	productsToDelete []models.Product,
-	productsToUpdate []models.Product,
-) (numderOfDeletedProducts uint64, err error) {
+func applyTimeout(configured int64) time.Duration {
+	if configured <= 0 {
+		return defaultApplyTimeout
+	}
+
+	return time.Duration(configured) * time.Second
+}
+
+// ProductsByFilter возвращает страницу товаров; на товар больше limit запрашивается, чтобы понять, есть ли следующая страница
+func (r *Repository) ProductsByFilter(filter service.RequestFilter) (service.ProductsPage, error) {
+	selectQuery := r.initQuery.
+		Select(sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol).
+		From(tableName)
 
-	if len(productsToAdd)+len(productsToUpdate)+len(productsToDelete) == 0 {
-		return 0, ErrEmptyRequest
+	if len(filter.SellerIDs) > 0 {
+		selectQuery = selectQuery.Where(sq.Eq{sellerIdCol: filter.SellerIDs}) // WHERE ... in (...) construction
 	}
 
-	// start transaction
-	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
-	defer cf()
-	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
-	if err != nil {
-		log.Println(err)
-		return 0, ErrTxFailed
+	if len(filter.OfferIDs) > 0 {
+		selectQuery = selectQuery.Where(sq.Eq{offerIdCol: filter.OfferIDs})
 	}
-	defer tx.Rollback()
 
-	// insert query
-	if len(productsToAdd)+len(productsToUpdate) > 0 {
+	// пробелы должны быть отрезаны на слоях выше
+	switch {
+	case filter.Substring != "" && filter.SearchMode == service.SearchModeFulltext:
+		selectQuery = selectQuery.Where(sq.Expr(nameTsvector+" @@ "+nameTsquery, filter.Substring))
 
-		// prepare insert query
-		insertQuery := r.initQuery.
-			Insert(tableName).
-			Columns(sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol)
+	case filter.Substring != "":
+		// ILIKE обслуживается триграммным индексом products_name_trgm_idx
+		selectQuery = selectQuery.Where(sq.ILike{
+			nameCol: "%" + escapeLike(filter.Substring) + "%",
+		})
+	}
 
-		for _, elem := range productsToAdd {
-			insertQuery = insertQuery.Values(sellerId, elem.OfferId, elem.Name, elem.Price, elem.Quantity)
-		}
-		for _, elem := range productsToUpdate {
-			insertQuery = insertQuery.Values(sellerId, elem.OfferId, elem.Name, elem.Price, elem.Quantity)
-		}
+	if filter.PriceMin != nil {
+		selectQuery = selectQuery.Where(sq.GtOrEq{priceCol: *filter.PriceMin})
+	}
 
-		insertQuery = insertQuery.Suffix(
-			`ON CONFLICT ON CONSTRAINT no_duplicates DO UPDATE SET
-			name = EXCLUDED.name,
-			price = EXCLUDED.price,
-			quantity = EXCLUDED.quantity`,
-		)
+	if filter.PriceMax != nil {
+		selectQuery = selectQuery.Where(sq.LtOrEq{priceCol: *filter.PriceMax})
+	}
 
-		insertQueryString, insertQueryArgs, err := insertQuery.ToSql()
-		if err != nil {
-			log.Println(err)
-			return 0, ErrQueryBuilderFailed
-		}
+	if filter.QuantityMin != nil {
+		selectQuery = selectQuery.Where(sq.GtOrEq{quantityCol: *filter.QuantityMin})
+	}
 
-		// execute insert
-		insertQueryResult, err := tx.ExecContext(ctx, insertQueryString, insertQueryArgs...)
-		if err != nil {
-			log.Println(err)
-			return 0, ErrQueryExecFailed
-		}
-		rowsAffected, err := insertQueryResult.RowsAffected()
-		if err != nil {
-			log.Println(err)
-			return 0, ErrQueryExecFailed
-		}
-		if rowsAffected != int64(len(productsToAdd)+len(productsToUpdate)) {
-			log.Println("missmatched sum of products to add/update and affected rows")
-		}
+	if filter.QuantityMax != nil {
+		selectQuery = selectQuery.Where(sq.LtOrEq{quantityCol: *filter.QuantityMax})
 	}
 
-	productsDeleted := uint64(0)
-	// delete query
-	if len(productsToDelete) > 0 {
-		// prepare delete query
-		deleteIDs := make([]uint64, 0, len(productsToDelete))
-		for _, elem := range productsToDelete {
-			deleteIDs = append(deleteIDs, elem.OfferId)
-		}
-		deleteQuery := r.initQuery.Delete(tableName).Where(sq.Eq{sellerIdCol: sellerId, offerIdCol: deleteIDs})
-		deleteQueryString, deleteQueryArgs, err := deleteQuery.ToSql()
-		if err != nil {
-			log.Println(err)
-			return 0, ErrQueryBuilderFailed
-		}
-		// execute delete query
-		deleteQueryResult, err := tx.ExecContext(ctx, deleteQueryString, deleteQueryArgs...)
-		if err != nil {
-			log.Println(err)
-			return 0, ErrQueryExecFailed
+	if filter.InStock != nil {
+		if *filter.InStock {
+			selectQuery = selectQuery.Where(sq.Gt{quantityCol: 0})
+		} else {
+			selectQuery = selectQuery.Where(sq.Eq{quantityCol: 0})
 		}
-		rowsAffected, err := deleteQueryResult.RowsAffected()
+	}
+
+	keyCols := sortKey(filter.SortBy)
+	// аргументы первой колонки ключа (ранг релевантности зависит от поискового запроса)
+	var keyArgs []interface{}
+	direction, comparison := "ASC", ">"
+	if filter.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.ByRelevance() {
+		keyCols = []string{nameRank, sellerIdCol, offerIdCol}
+		keyArgs = []interface{}{filter.Substring}
+		direction, comparison = "DESC", "<"
+
+		// релевантность нужна курсору следующей страницы
+		selectQuery = selectQuery.Column(sq.Alias(sq.Expr(nameRank, filter.Substring), rankCol))
+	}
+
+	if filter.After != nil {
+		afterValue, err := filter.AfterValue()
 		if err != nil {
 			log.Println(err)
-			return 0, ErrQueryExecFailed
+			return service.ProductsPage{}, ErrQueryBuilderFailed
 		}
-		if rowsAffected != int64(len(productsToDelete)) {
-			log.Println("missmatched sum of products to delete and affected rows")
+
+		// курсор хранит весь ключ сортировки, товар из курсора может быть уже удалён
+		tuple := "(" + strings.Join(keyCols, ", ") + ")"
+		switch keyCols[0] {
+		case sellerIdCol:
+			selectQuery = selectQuery.Where(sq.Expr(tuple+" "+comparison+" (?, ?)", filter.After.SellerId, filter.After.OfferId))
+		case offerIdCol:
+			selectQuery = selectQuery.Where(sq.Expr(tuple+" "+comparison+" (?, ?)", filter.After.OfferId, filter.After.SellerId))
+		default:
+			args := make([]interface{}, 0, len(keyArgs)+3)
+			args = append(args, keyArgs...)
+			args = append(args, afterValue, filter.After.SellerId, filter.After.OfferId)
+
+			selectQuery = selectQuery.Where(sq.Expr(tuple+" "+comparison+" (?, ?, ?)", args...))
 		}
+	}
+
+	selectQuery = selectQuery.OrderByClause(keyCols[0]+" "+direction, keyArgs...)
+	for _, col := range keyCols[1:] {
+		selectQuery = selectQuery.OrderBy(col + " " + direction)
+	}
 
-		productsDeleted = uint64(rowsAffected)
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultLimit
 	}
 
-	if err := tx.Commit(); err != nil {
+	selectQueryString, args, err := selectQuery.Limit(limit + 1).ToSql()
+	if err != nil {
 		log.Println(err)
-		return 0, ErrTxFailed
+		return service.ProductsPage{}, ErrQueryBuilderFailed
 	}
 
-	return productsDeleted, nil
-}
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
 
-func (r *Repository) ProductsByFilter(filter service.RequestFilter) ([]models.Product, error) {
-	selectQuery := r.initQuery.
-		Select(sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol).
-		From(tableName)
+	rows := make([]productRow, 0)
+	if err := r.db.SelectContext(ctx, &rows, selectQueryString, args...); err != nil {
+		log.Println(err)
+		return service.ProductsPage{}, ErrQueryExecFailed
+	}
 
-	if len(filter.SellerIDs) > 0 {
-		selectQuery = selectQuery.Where(sq.Eq{sellerIdCol: filter.SellerIDs}) // WHERE ... in (...) construction
+	page := service.ProductsPage{}
+	if uint64(len(rows)) > limit {
+		rows = rows[:limit]
+		page.NextCursor = cursorAfter(rows[limit-1], keyCols[0])
 	}
 
-	if len(filter.OfferIDs) > 0 {
-		selectQuery = selectQuery.Where(sq.Eq{offerIdCol: filter.OfferIDs})
+	page.Products = make([]models.Product, 0, len(rows))
+	for _, row := range rows {
+		page.Products = append(page.Products, row.Product)
 	}
 
-	if filter.Substring != "" {
-		selectQuery = selectQuery.Where(sq.Like{
-			// пробелы должны быть отрезаны на слоях выше
-			nameCol: "%" + filter.Substring + "%",
-			// nameCol: "%" + strings.TrimSpace(filter.Substring) + "%",
-		})
+	return page, nil
+}
+
+// productRow - товар вместе с релевантностью полнотекстового поиска, если по ней идёт сортировка
+type productRow struct {
+	models.Product
+	Rank float32 `db:"rank"`
+}
+
+// cursorAfter - курсор на товар row; sortCol - первая колонка ключа сортировки
+func cursorAfter(row productRow, sortCol string) *service.Cursor {
+	cursor := service.Cursor{SellerId: row.SellerId, OfferId: row.OfferId}
+	switch sortCol {
+	case nameCol:
+		cursor.Value = row.Name
+	case priceCol:
+		cursor.Value = strconv.FormatUint(row.Price, 10)
+	case quantityCol:
+		cursor.Value = strconv.FormatUint(row.Quantity, 10)
+	case nameRank:
+		// без потерь: ParseFloat(..., 32) вернёт то же значение
+		cursor.Value = strconv.FormatFloat(float64(row.Rank), 'g', -1, 32)
 	}
 
-	selectQueryString, args, err := selectQuery.Limit(defaultLimit).ToSql()
+	return &cursor
+}
+
+func (r *Repository) SellerProductsByIDs(sellerId uint64, offerIDs []uint64) ([]models.Product, error) {
+	// = ANY($2) вместо IN (...) - один параметр на любое количество айдишников
+	selectQueryString, args, err := r.initQuery.
+		Select(sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol).
+		From(tableName).
+		Where(sq.Eq{sellerIdCol: sellerId}).
+		Where(sq.Expr(offerIdCol+" = ANY(?)", pq.Array(toInt64s(offerIDs)))).
+		ToSql()
 	if err != nil {
 		log.Println(err)
 		return nil, ErrQueryBuilderFailed
@@ -184,7 +249,7 @@ func (r *Repository) ProductsByFilter(filter service.RequestFilter) ([]models.Pr
 	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
 	defer cf()
 
-	products := make([]models.Product, 0)
+	products := make([]models.Product, 0, len(offerIDs))
 	if err := r.db.SelectContext(ctx, &products, selectQueryString, args...); err != nil {
 		log.Println(err)
 		return nil, ErrQueryExecFailed
@@ -193,21 +258,32 @@ func (r *Repository) ProductsByFilter(filter service.RequestFilter) ([]models.Pr
 	return products, nil
 }
 
-func (r *Repository) SellerProductIDs(sellerId uint64) ([]uint64, error) {
-	selectQueryString, args, err := r.initQuery.Select(offerIdCol).From(tableName).Where(sq.Eq{sellerIdCol: sellerId}).ToSql()
-	if err != nil {
-		log.Println(err)
-		return nil, ErrQueryBuilderFailed
-	}
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
 
-	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
-	defer cf()
+// sortKey - колонки ORDER BY; айдишники в конце делают порядок однозначным для курсора
+func sortKey(sortBy service.SortField) []string {
+	switch sortBy {
+	case service.SortByOfferId:
+		return []string{offerIdCol, sellerIdCol}
+	case service.SortByName:
+		return []string{nameCol, sellerIdCol, offerIdCol}
+	case service.SortByPrice:
+		return []string{priceCol, sellerIdCol, offerIdCol}
+	case service.SortByQuantity:
+		return []string{quantityCol, sellerIdCol, offerIdCol}
+	default:
+		return []string{sellerIdCol, offerIdCol}
+	}
+}
 
-	productIDs := make([]uint64, 0)
-	if err := r.db.SelectContext(ctx, &productIDs, selectQueryString, args...); err != nil {
-		log.Println(err)
-		return nil, ErrQueryExecFailed
+// pq.Array не умеет []uint64
+func toInt64s(slice []uint64) []int64 {
+	result := make([]int64, 0, len(slice))
+	for _, elem := range slice {
+		result = append(result, int64(elem))
 	}
 
-	return productIDs, nil
+	return result
 }
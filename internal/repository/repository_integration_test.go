@@ -1,8 +1,10 @@
 package repository
 
 import (
-	"fmt"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/hablof/merchant-experience/internal/config"
@@ -52,7 +54,7 @@ func TestRepository(t *testing.T) {
 	setup(t, db)
 
 	t.Run("добавляем три записи", func(t *testing.T) {
-		if _, err := r.ManageProducts(0, productsToAdd, nil, nil); err != nil {
+		if _, err := importProducts(r, 0, productsToAdd, nil, models.ChangeSource{}); err != nil {
 			assert.FailNow(t, err.Error())
 		}
 
@@ -73,7 +75,7 @@ func TestRepository(t *testing.T) {
 	})
 
 	t.Run("меняем все три добавленные записи", func(t *testing.T) {
-		if _, err := r.ManageProducts(0, nil, nil, productsToUpd); err != nil {
+		if _, err := importProducts(r, 0, productsToUpd, nil, models.ChangeSource{}); err != nil {
 			assert.FailNow(t, err.Error())
 		}
 
@@ -95,7 +97,7 @@ func TestRepository(t *testing.T) {
 
 	t.Run("удаляем все три записи", func(t *testing.T) {
 		productsToDel := productsToUpd
-		deleted, err := r.ManageProducts(0, nil, productsToDel, nil)
+		deleted, err := importProducts(r, 0, nil, productsToDel, models.ChangeSource{})
 		if err != nil {
 			assert.FailNow(t, err.Error())
 		}
@@ -109,6 +111,507 @@ func TestRepository(t *testing.T) {
 		assert.Equal(t, 0, count)
 	})
 
+	t.Run("режим replace: удаляем отсутствующие в таблице записи", func(t *testing.T) {
+		if _, err := importProducts(r, 0, productsToAdd, nil, models.ChangeSource{}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		tx, err := r.BeginImport(0)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if err := tx.Stage(productsToUpd[:1], nil, nil); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		deleted, purged, err := tx.Apply(true, models.ChangeSource{})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, uint64(0), deleted)
+		assert.Equal(t, uint64(2), purged)
+
+		count := 0
+		if err := db.QueryRowx(`SELECT COUNT(*) FROM products;`).Scan(&count); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, 1, count)
+
+		if _, err := importProducts(r, 0, nil, productsToUpd[:1], models.ChangeSource{}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	})
+
+	t.Run("режим replace: два листа одного продавца в одной загрузке", func(t *testing.T) {
+		if _, err := importProducts(r, 300, productsToAdd, nil, models.ChangeSource{}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		tx, err := r.BeginImport(300)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		// лист 1 - оффер 1, лист 2 - оффер 3; удаляется только оффер 2, которого нет ни на одном листе
+		if err := tx.Stage(productsToUpd[:1], nil, nil); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if err := tx.Stage(productsToUpd[2:], nil, nil); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		_, purged, err := tx.Apply(true, models.ChangeSource{})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, uint64(1), purged)
+
+		offerIDs := make([]uint64, 0)
+		if err := db.Select(&offerIDs, `SELECT offer_id FROM products WHERE seller_id = 300 ORDER BY offer_id;`); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, []uint64{1, 3}, offerIDs)
+
+		if _, err := db.Exec(`DELETE FROM products WHERE seller_id = 300;`); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	})
+
+	t.Run("пишем пачками и через COPY", func(t *testing.T) {
+		for _, repoCfg := range []config.Repository{
+			{Timeout: 5, ChunkSize: 2},
+			{Timeout: 5, ChunkSize: 2, UseCopy: true},
+		} {
+			chunked := NewRepository(db, config.Config{Repository: repoCfg})
+
+			if _, err := importProducts(chunked, 0, productsToAdd, nil, models.ChangeSource{}); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+
+			count := 0
+			if err := db.QueryRowx(`SELECT COUNT(*) FROM products;`).Scan(&count); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			assert.Equal(t, len(productsToAdd), count)
+
+			deleted, err := importProducts(chunked, 0, nil, productsToAdd, models.ChangeSource{})
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			assert.Equal(t, uint64(len(productsToAdd)), deleted)
+		}
+	})
+
+	t.Run("загрузка таблицы пишется одной транзакцией", func(t *testing.T) {
+		if _, err := importProducts(r, 0, productsToAdd, nil, models.ChangeSource{}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		for _, repoCfg := range []config.Repository{
+			{Timeout: 5, ChunkSize: 2},
+			{Timeout: 5, ChunkSize: 2, UseCopy: true},
+		} {
+			staged := NewRepository(db, config.Config{Repository: repoCfg})
+
+			// отменённая загрузка каталог не меняет
+			tx, err := staged.BeginImport(0)
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			if err := tx.Stage(nil, []uint64{1, 2, 3}, nil); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			assert.NoError(t, tx.Rollback())
+
+			count := 0
+			if err := db.QueryRowx(`SELECT COUNT(*) FROM products;`).Scan(&count); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			assert.Equal(t, len(productsToAdd), count)
+
+			// две пачки и удаление отсутствующих офферов; оффер 3 из строки с ошибкой остаётся
+			tx, err = staged.BeginImport(0)
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			if err := tx.Stage(productsToUpd[:1], nil, nil); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			if err := tx.Stage(nil, nil, []uint64{3}); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+
+			missing, err := tx.MissingProducts()
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			assert.Equal(t, []models.Product{productsToAdd[1]}, missing)
+
+			deleted, purged, err := tx.Apply(true, models.ChangeSource{})
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			assert.Equal(t, uint64(0), deleted)
+			assert.Equal(t, uint64(1), purged)
+
+			products := make([]models.Product, 0)
+			if err := db.Select(&products, `SELECT seller_id, offer_id, name, price, quantity FROM products ORDER BY offer_id;`); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			assert.Equal(t, []models.Product{productsToUpd[0], productsToAdd[2]}, products)
+
+			// возвращаем каталог к трём товарам
+			if _, err := importProducts(r, 0, productsToAdd, nil, models.ChangeSource{}); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+		}
+
+		if _, err := importProducts(r, 0, nil, productsToAdd, models.ChangeSource{}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	})
+
+	t.Run("история изменений оффера", func(t *testing.T) {
+		source := models.ChangeSource{Kind: models.ChangeKindImport, JobId: 5}
+		product := models.Product{OfferId: 1, Name: "name1", Price: 10, Quantity: 1}
+		updated := models.Product{OfferId: 1, Name: "name1", Price: 20, Quantity: 1}
+
+		if _, err := importProducts(r, 100, []models.Product{product}, nil, source); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		// upsert без изменений в историю не попадает
+		if _, err := importProducts(r, 100, []models.Product{product}, nil, source); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if _, err := importProducts(r, 100, []models.Product{updated}, nil, source); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if _, err := importProducts(r, 100, nil, []models.Product{updated}, models.ChangeSource{Kind: "api", RequestId: "req-1"}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		changes, err := r.ProductHistory(100, 1, 10)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if !assert.Len(t, changes, 3) {
+			return
+		}
+
+		oldValues := models.Product{SellerId: 100, OfferId: 1, Name: "name1", Price: 10, Quantity: 1}
+		newValues := models.Product{SellerId: 100, OfferId: 1, Name: "name1", Price: 20, Quantity: 1}
+
+		assert.Equal(t, models.ChangeDelete, changes[0].Action)
+		assert.Equal(t, &newValues, changes[0].Old)
+		assert.Nil(t, changes[0].New)
+		assert.Equal(t, "api", changes[0].Source)
+		assert.Equal(t, "req-1", changes[0].RequestId)
+		assert.Equal(t, uint64(0), changes[0].JobId)
+
+		assert.Equal(t, models.ChangeUpdate, changes[1].Action)
+		assert.Equal(t, &oldValues, changes[1].Old)
+		assert.Equal(t, &newValues, changes[1].New)
+		assert.Equal(t, uint64(5), changes[1].JobId)
+
+		assert.Equal(t, models.ChangeInsert, changes[2].Action)
+		assert.Nil(t, changes[2].Old)
+		assert.Equal(t, &oldValues, changes[2].New)
+		assert.Equal(t, models.ChangeKindImport, changes[2].Source)
+	})
+
+	t.Run("откат загрузки", func(t *testing.T) {
+		apiSource := models.ChangeSource{Kind: "api", RequestId: "req-2"}
+		before := []models.Product{
+			{SellerId: 200, OfferId: 1, Name: "name1", Price: 10, Quantity: 1},
+			{SellerId: 200, OfferId: 2, Name: "name2", Price: 20, Quantity: 2},
+		}
+		if _, err := importProducts(r, 200, before, nil, apiSource); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		job, err := r.CreateJob(models.Job{SellerId: 200, TableURL: "http://some.url/t", SyncMode: models.SyncModeMerge})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		source := models.ChangeSource{Kind: models.ChangeKindImport, JobId: job.Id}
+		if _, err := importProducts(r, 200,
+			[]models.Product{{OfferId: 3, Name: "name3", Price: 30, Quantity: 3}, {OfferId: 1, Name: "name1", Price: 15, Quantity: 1}},
+			[]models.Product{{OfferId: 2}},
+			source); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		results, err := r.RevertJob(job.Id)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, models.RevertResults{Restored: 2, Removed: 1}, results)
+
+		page, err := r.ProductsByFilter(service.RequestFilter{SellerIDs: []uint64{200}})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, before, page.Products)
+
+		_, err = r.RevertJob(job.Id)
+		assert.Equal(t, models.ErrAlreadyReverted, err)
+
+		// после второй загрузки оффер поменяли вручную - откатывать нельзя
+		job, err = r.CreateJob(models.Job{SellerId: 200, TableURL: "http://some.url/t", SyncMode: models.SyncModeMerge})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		source.JobId = job.Id
+		if _, err := importProducts(r, 200, []models.Product{{OfferId: 1, Name: "name1", Price: 15, Quantity: 1}}, nil, source); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if _, err := importProducts(r, 200, []models.Product{{OfferId: 1, Name: "name1", Price: 16, Quantity: 1}}, nil, apiSource); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		_, err = r.RevertJob(job.Id)
+		assert.Equal(t, models.ErrConflict, err)
+
+		_, err = r.RevertJob(job.Id + 100)
+		assert.Equal(t, models.ErrNotFound, err)
+	})
+
+	t.Run("последняя загрузка ссылки и правки каталога после неё", func(t *testing.T) {
+		importURL := func(tableURL string, offerId uint64) models.Job {
+			job, err := r.CreateJob(models.Job{SellerId: 250, TableURL: tableURL, SyncMode: models.SyncModeMerge})
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			source := models.ChangeSource{Kind: models.ChangeKindImport, JobId: job.Id}
+			if _, err := importProducts(r, 250, []models.Product{{OfferId: offerId, Name: "name", Price: 10, Quantity: 1}}, nil, source); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			if err := r.SaveJobFetch(job.Id, models.TableFetch{ContentHash: tableURL}); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			if err := r.FinishJob(job.Id, job.Attempts, models.JobDone, []byte(`{"added":1}`), ""); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+
+			return job
+		}
+
+		jobA := importURL("http://some.url/a", 1)
+		jobB := importURL("http://some.url/b", 2)
+
+		// у продавца две ссылки: последняя загрузка ищется по каждой
+		lastA, err := r.LastImport(250, "http://some.url/a")
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, jobA.Id, lastA.Id)
+		assert.Equal(t, "http://some.url/a", lastA.ContentHash)
+
+		lastB, err := r.LastImport(250, "http://some.url/b")
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, jobB.Id, lastB.Id)
+
+		_, err = r.LastImport(250, "http://some.url/c")
+		assert.Equal(t, models.ErrNotFound, err)
+
+		// после загрузки a каталог изменила загрузка b
+		changed, err := r.CatalogChangedSince(250, lastA.Id)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+
+		changed, err = r.CatalogChangedSince(250, lastB.Id)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+
+		if _, err := importProducts(r, 250, []models.Product{{OfferId: 2, Name: "name", Price: 11, Quantity: 1}}, nil, models.ChangeSource{Kind: models.ChangeKindAPI}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		changed, err = r.CatalogChangedSince(250, lastB.Id)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+
+		// длинная загрузка началась раньше загрузки c, а зафиксирована после неё:
+		// changed_at её истории раньше завершения c, но изменение всё равно после c
+		longTx, err := r.BeginImport(250)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		defer longTx.Rollback()
+		if err := longTx.Stage([]models.Product{{OfferId: 4, Name: "name", Price: 10, Quantity: 1}}, nil, nil); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		jobC := importURL("http://some.url/c", 3)
+		changed, err = r.CatalogChangedSince(250, jobC.Id)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+
+		if _, _, err := longTx.Apply(false, models.ChangeSource{Kind: models.ChangeKindImport}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		changed, err = r.CatalogChangedSince(250, jobC.Id)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+
+		// история записана до загрузки d, а транзакция зафиксирована после неё
+		editTx, err := db.Beginx()
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		defer editTx.Rollback()
+		if _, err := editTx.Exec("UPDATE products SET price = price + 1 WHERE seller_id = 250 AND offer_id = 1"); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		jobD := importURL("http://some.url/d", 5)
+		if err := editTx.Commit(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		changed, err = r.CatalogChangedSince(250, jobD.Id)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+	})
+
+	t.Run("задача по листам книги", func(t *testing.T) {
+		job, err := r.CreateJob(models.Job{
+			TableURL: "http://some.url/t",
+			SyncMode: models.SyncModeMerge,
+			Sheets:   models.SheetSellers{"Магазин 1": 401, "Магазин 2": 402},
+		})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, models.SheetSellers{"Магазин 1": 401, "Магазин 2": 402}, job.Sheets)
+
+		job, err = r.CreateJob(models.Job{SellerId: 400, TableURL: "http://some.url/t", SyncMode: models.SyncModeMerge, Sheet: "Прайс"})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		job, err = r.Job(job.Id)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, "Прайс", job.Sheet)
+		assert.Nil(t, job.Sheets)
+	})
+
+	t.Run("фиды: расписание и статус последнего запуска", func(t *testing.T) {
+		feed, err := r.CreateFeed(models.Feed{SellerId: 500, TableURL: "http://some.url/feed", IntervalMinutes: 60, SyncMode: models.SyncModeReplace, Auth: []byte("sealed")})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		due, err := r.DueFeeds(100)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if assert.Len(t, due, 1) {
+			assert.Equal(t, feed.Id, due[0].Id)
+			assert.Equal(t, []byte("sealed"), due[0].Auth)
+		}
+
+		job, err := r.CreateJob(models.Job{SellerId: 500, TableURL: feed.TableURL, SyncMode: feed.SyncMode})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if err := r.SaveFeedRun(feed.Id, models.FeedRun{JobId: &job.Id, Status: models.JobQueued}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		// следующий запуск - через интервал
+		due, err = r.DueFeeds(100)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Empty(t, due)
+
+		// статус последнего запуска - статус его задачи
+		if err := r.FinishJob(job.Id, job.Attempts, models.JobFailed, nil, "table not found"); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		feeds, err := r.Feeds(500)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if assert.Len(t, feeds, 1) {
+			assert.Equal(t, &job.Id, feeds[0].LastJobId)
+			assert.Equal(t, models.JobFailed, feeds[0].LastStatus)
+			assert.Equal(t, "table not found", feeds[0].LastError)
+			assert.WithinDuration(t, feeds[0].LastRunAt.Add(time.Hour), feeds[0].NextRunAt, time.Second)
+		}
+
+		assert.Equal(t, models.ErrNotFound, r.DeleteFeed(501, feed.Id), "feed of another seller")
+		assert.NoError(t, r.DeleteFeed(500, feed.Id))
+	})
+
+	t.Run("advisory-блокировка лидера", func(t *testing.T) {
+		leader, replica := r.AdvisoryLock(25), r.AdvisoryLock(25)
+
+		locked, err := leader.Acquire()
+		assert.NoError(t, err)
+		assert.True(t, locked)
+
+		locked, err = replica.Acquire()
+		assert.NoError(t, err)
+		assert.False(t, locked, "lock is held by another session")
+
+		assert.NoError(t, leader.Release())
+
+		locked, err = replica.Acquire()
+		assert.NoError(t, err)
+		assert.True(t, locked, "lock is free after release")
+		assert.NoError(t, replica.Release())
+	})
+
+	t.Run("операции над одним оффером", func(t *testing.T) {
+		source := models.ChangeSource{Kind: models.ChangeKindAPI, RequestId: "req-3"}
+		product := models.Product{SellerId: 300, OfferId: 1, Name: "name1", Price: 10, Quantity: 1}
+
+		created, err := r.PutProduct(product, source)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.True(t, created)
+
+		product.Quantity = 5
+		created, err = r.PutProduct(product, source)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.False(t, created)
+
+		price := uint64(15)
+		patched, err := r.PatchProduct(300, 1, service.ProductPatch{Price: &price}, source)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, models.Product{SellerId: 300, OfferId: 1, Name: "name1", Price: 15, Quantity: 5}, patched)
+
+		got, err := r.Product(300, 1)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, patched, got)
+
+		assert.NoError(t, r.DeleteProduct(300, 1, source))
+		assert.Equal(t, models.ErrNotFound, r.DeleteProduct(300, 1, source))
+
+		_, err = r.Product(300, 1)
+		assert.Equal(t, models.ErrNotFound, err)
+
+		_, err = r.PatchProduct(300, 1, service.ProductPatch{Price: &price}, source)
+		assert.Equal(t, models.ErrNotFound, err)
+
+		changes, err := r.ProductHistory(300, 1, 10)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		if assert.Len(t, changes, 4) {
+			assert.Equal(t, models.ChangeKindAPI, changes[0].Source)
+			assert.Equal(t, "req-3", changes[0].RequestId)
+		}
+	})
+
 	// это не тестирует методы репозитория...
 	t.Run("добавляем записи для тестирования ProductsByFilter()", func(t *testing.T) {
 		querystring, args, err := sq.Insert(tableName).
@@ -151,32 +654,8 @@ func TestRepository(t *testing.T) {
 		assert.Equal(t, int64(20), rowsAffected)
 	})
 
-	t.Run("проверка метода SellerProductIDs", func(t *testing.T) {
-
-		testCases := map[uint64][]uint64{
-			1:  {1, 2, 3, 4, 5, 6, 7, 33},
-			2:  {1, 3, 6, 10},
-			3:  {1, 5, 6},
-			4:  {},
-			5:  {5},
-			6:  {6},
-			7:  {},
-			9:  {9},
-			15: {10},
-			20: {20},
-		}
-
-		for k, v := range testCases {
-			offerIDs, err := r.SellerProductIDs(k)
-			if err != nil {
-				assert.FailNow(t, err.Error())
-			}
-			assert.Equal(t, v, offerIDs, fmt.Sprintf("SellerProductIDs with key \"%d\"", k))
-		}
-	})
-
 	t.Run("выбираем по айди продавца", func(t *testing.T) {
-		products, err := r.ProductsByFilter(service.RequestFilter{
+		page, err := r.ProductsByFilter(service.RequestFilter{
 			SellerIDs: []uint64{3, 9, 15},
 			OfferIDs:  []uint64{},
 			Substring: "",
@@ -192,11 +671,11 @@ func TestRepository(t *testing.T) {
 				{SellerId: 9, OfferId: 9, Name: "subwoofer", Price: 2, Quantity: 2},
 				{SellerId: 15, OfferId: 10, Name: "name15_10", Price: 71, Quantity: 10},
 			},
-			products)
+			page.Products)
 	})
 
 	t.Run("выбираем по айди продукта", func(t *testing.T) {
-		products, err := r.ProductsByFilter(service.RequestFilter{
+		page, err := r.ProductsByFilter(service.RequestFilter{
 			SellerIDs: []uint64{},
 			OfferIDs:  []uint64{1, 3},
 			Substring: "",
@@ -212,11 +691,11 @@ func TestRepository(t *testing.T) {
 				{SellerId: 2, OfferId: 3, Name: "big boss", Price: 3, Quantity: 321},
 				{SellerId: 3, OfferId: 1, Name: "name3_1", Price: 1, Quantity: 1},
 			},
-			products)
+			page.Products)
 	})
 
 	t.Run("выбираем по подстроке", func(t *testing.T) {
-		products, err := r.ProductsByFilter(service.RequestFilter{
+		page, err := r.ProductsByFilter(service.RequestFilter{
 			SellerIDs: []uint64{},
 			OfferIDs:  []uint64{},
 			Substring: "big",
@@ -232,11 +711,11 @@ func TestRepository(t *testing.T) {
 				{SellerId: 2, OfferId: 10, Name: "big spoon", Price: 1, Quantity: 166},
 				{SellerId: 3, OfferId: 6, Name: "big TV", Price: 1, Quantity: 1},
 			},
-			products)
+			page.Products)
 	})
 
 	t.Run("выбираем по айди продовца и айди продукта", func(t *testing.T) {
-		products, err := r.ProductsByFilter(service.RequestFilter{
+		page, err := r.ProductsByFilter(service.RequestFilter{
 			SellerIDs: []uint64{1, 2},
 			OfferIDs:  []uint64{3, 4},
 			Substring: "",
@@ -250,11 +729,11 @@ func TestRepository(t *testing.T) {
 				{SellerId: 1, OfferId: 4, Name: "big changus", Price: 40, Quantity: 1},
 				{SellerId: 2, OfferId: 3, Name: "big boss", Price: 3, Quantity: 321},
 			},
-			products)
+			page.Products)
 	})
 
 	t.Run("выбираем по айди продовца и по подстроке", func(t *testing.T) {
-		products, err := r.ProductsByFilter(service.RequestFilter{
+		page, err := r.ProductsByFilter(service.RequestFilter{
 			SellerIDs: []uint64{2, 3, 4},
 			OfferIDs:  []uint64{},
 			Substring: "big",
@@ -268,11 +747,11 @@ func TestRepository(t *testing.T) {
 				{SellerId: 2, OfferId: 10, Name: "big spoon", Price: 1, Quantity: 166},
 				{SellerId: 3, OfferId: 6, Name: "big TV", Price: 1, Quantity: 1},
 			},
-			products)
+			page.Products)
 	})
 
 	t.Run("выбираем по айди продукта и по подстроке", func(t *testing.T) {
-		products, err := r.ProductsByFilter(service.RequestFilter{
+		page, err := r.ProductsByFilter(service.RequestFilter{
 			SellerIDs: []uint64{},
 			OfferIDs:  []uint64{4, 10},
 			Substring: "big",
@@ -285,11 +764,11 @@ func TestRepository(t *testing.T) {
 				{SellerId: 1, OfferId: 4, Name: "big changus", Price: 40, Quantity: 1},
 				{SellerId: 2, OfferId: 10, Name: "big spoon", Price: 1, Quantity: 166},
 			},
-			products)
+			page.Products)
 	})
 
 	t.Run("выбираем ипо айди продовца, и по айди продукта, и по подстроке", func(t *testing.T) {
-		products, err := r.ProductsByFilter(service.RequestFilter{
+		page, err := r.ProductsByFilter(service.RequestFilter{
 			SellerIDs: []uint64{},
 			OfferIDs:  []uint64{4, 10},
 			Substring: "big",
@@ -302,11 +781,11 @@ func TestRepository(t *testing.T) {
 				{SellerId: 1, OfferId: 4, Name: "big changus", Price: 40, Quantity: 1},
 				{SellerId: 2, OfferId: 10, Name: "big spoon", Price: 1, Quantity: 166},
 			},
-			products)
+			page.Products)
 	})
 
 	t.Run("выбираем без фильтра", func(t *testing.T) {
-		products, err := r.ProductsByFilter(service.RequestFilter{
+		page, err := r.ProductsByFilter(service.RequestFilter{
 			SellerIDs: []uint64{},
 			OfferIDs:  []uint64{},
 			Substring: "",
@@ -337,13 +816,128 @@ func TestRepository(t *testing.T) {
 				{SellerId: 15, OfferId: 10, Name: "name15_10", Price: 71, Quantity: 10},
 				{SellerId: 20, OfferId: 20, Name: "subtitles", Price: 1, Quantity: 1},
 			},
-			products)
+			page.Products)
+	})
+
+	t.Run("поиск по подстроке не зависит от регистра", func(t *testing.T) {
+		page, err := r.ProductsByFilter(service.RequestFilter{
+			SellerIDs: []uint64{2},
+			Substring: "BIG",
+		})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t,
+			[]models.Product{
+				{SellerId: 2, OfferId: 3, Name: "big boss", Price: 3, Quantity: 321},
+				{SellerId: 2, OfferId: 10, Name: "big spoon", Price: 1, Quantity: 166},
+			},
+			page.Products)
+	})
+
+	t.Run("полнотекстовый поиск учитывает словоформы", func(t *testing.T) {
+		page, err := r.ProductsByFilter(service.RequestFilter{
+			Substring:  "колеса",
+			SearchMode: service.SearchModeFulltext,
+		})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t,
+			[]models.Product{
+				{SellerId: 1, OfferId: 5, Name: "Колесо", Price: 1, Quantity: 1},
+			},
+			page.Products)
 	})
 
+	t.Run("выбираем по диапазону цены и наличию", func(t *testing.T) {
+		priceMin, priceMax, inStock := uint64(2), uint64(20), true
+		page, err := r.ProductsByFilter(service.RequestFilter{
+			SellerIDs: []uint64{1},
+			PriceMin:  &priceMin,
+			PriceMax:  &priceMax,
+			InStock:   &inStock,
+		})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t,
+			[]models.Product{
+				{SellerId: 1, OfferId: 1, Name: "head", Price: 10, Quantity: 1},
+				{SellerId: 1, OfferId: 7, Name: "big melon", Price: 2, Quantity: 2},
+			},
+			page.Products)
+	})
+
+	t.Run("листаем товары продавца по убыванию цены", func(t *testing.T) {
+		filter := service.RequestFilter{
+			SellerIDs: []uint64{1},
+			Limit:     3,
+			SortBy:    service.SortByPrice,
+			SortDesc:  true,
+		}
+		page, err := r.ProductsByFilter(filter)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t,
+			[]models.Product{
+				{SellerId: 1, OfferId: 4, Name: "big changus", Price: 40, Quantity: 1},
+				{SellerId: 1, OfferId: 3, Name: "name1_3", Price: 30, Quantity: 3},
+				{SellerId: 1, OfferId: 2, Name: "body", Price: 20, Quantity: 0},
+			},
+			page.Products)
+		assert.Equal(t, &service.Cursor{SellerId: 1, OfferId: 2, Value: "20"}, page.NextCursor)
+
+		// курсор хранит цену, поэтому удаление товара из курсора не обрывает листание
+		if _, err := importProducts(r, 1, nil, []models.Product{{OfferId: 2}}, models.ChangeSource{Kind: "api"}); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		filter.After = page.NextCursor
+		page, err = r.ProductsByFilter(filter)
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t,
+			[]models.Product{
+				{SellerId: 1, OfferId: 1, Name: "head", Price: 10, Quantity: 1},
+				{SellerId: 1, OfferId: 7, Name: "big melon", Price: 2, Quantity: 2},
+				{SellerId: 1, OfferId: 33, Name: "head", Price: 1, Quantity: 1},
+			},
+			page.Products)
+	})
+
+}
+
+// importProducts пишет товары в каталог продавца одной загрузкой в режиме merge; возвращает число удалённых офферов
+func importProducts(r *Repository, sellerId uint64, toUpsert []models.Product, toDelete []models.Product, source models.ChangeSource) (uint64, error) {
+	tx, err := r.BeginImport(sellerId)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	deleteIDs := make([]uint64, 0, len(toDelete))
+	for _, product := range toDelete {
+		deleteIDs = append(deleteIDs, product.OfferId)
+	}
+	if err := tx.Stage(toUpsert, deleteIDs, nil); err != nil {
+		return 0, err
+	}
+
+	deleted, _, err := tx.Apply(false, source)
+
+	return deleted, err
 }
 
 func teardown(t *testing.T, db *sqlx.DB) {
-	_, err := db.Exec(`DROP TABLE IF EXISTS products;`)
+	_, err := db.Exec(`DROP TABLE IF EXISTS feeds, import_job_reports, import_job_uploads, products, import_jobs;`)
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	_, err = db.Exec(`DROP TABLE IF EXISTS product_history; DROP FUNCTION IF EXISTS record_product_history();`)
 	if err != nil {
 		assert.FailNow(t, err.Error())
 	}
@@ -351,7 +945,7 @@ func teardown(t *testing.T, db *sqlx.DB) {
 
 func setup(t *testing.T, db *sqlx.DB) {
 
-	if _, err := db.Exec(`DROP TABLE IF EXISTS products;`); err != nil {
+	if _, err := db.Exec(`DROP TABLE IF EXISTS feeds, import_job_reports, import_job_uploads, products, import_jobs;`); err != nil {
 		assert.FailNow(t, err.Error())
 	}
 
@@ -368,4 +962,49 @@ func setup(t *testing.T, db *sqlx.DB) {
 	if err != nil {
 		assert.FailNow(t, err.Error())
 	}
+
+	// таблица истории и триггер - из миграции, чтобы не расходиться с ней
+	if _, err := db.Exec(`DROP TABLE IF EXISTS product_history; DROP FUNCTION IF EXISTS record_product_history();`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	if _, err := db.Exec(migrationUp(t, "../../migrations/00006_product_history.sql")); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	// задачи загрузки нужны для отката
+	for _, path := range []string{
+		"../../migrations/00002_import_jobs.sql",
+		"../../migrations/00003_import_jobs_dry_run.sql",
+		"../../migrations/00004_import_jobs_sync_mode.sql",
+		"../../migrations/00007_import_jobs_reverted_at.sql",
+		"../../migrations/00008_import_job_reports.sql",
+		"../../migrations/00009_import_jobs_confirmed.sql",
+		"../../migrations/00010_import_jobs_sheets.sql",
+		"../../migrations/00011_import_jobs_auth.sql",
+		"../../migrations/00012_import_jobs_fetch.sql",
+		"../../migrations/00013_feeds.sql",
+		"../../migrations/00014_import_jobs_heartbeat.sql",
+		"../../migrations/00015_feeds_auth.sql",
+		"../../migrations/00016_product_history_changed_at.sql",
+		"../../migrations/00017_product_history_xact_id.sql",
+		"../../migrations/00018_import_jobs_attempts.sql",
+		"../../migrations/00019_import_job_uploads.sql",
+	} {
+		if _, err := db.Exec(migrationUp(t, path)); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+}
+
+// migrationUp возвращает секцию "-- +goose Up" файла миграции
+func migrationUp(t *testing.T, path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	up, _, _ := strings.Cut(string(b), "-- +goose Down")
+
+	return up
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"errors"
 	"log"
+	"strconv"
 	"testing"
 
 	"github.com/hablof/merchant-experience/internal/config"
@@ -12,202 +13,6 @@ import (
 	sqlxmock "github.com/zhashkevych/go-sqlxmock"
 )
 
-func TestRepository_ManageProducts(t *testing.T) {
-	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
-
-	tests := []struct {
-		name             string
-		sellerId         uint64
-		productsToAdd    []models.Product
-		productsToDelete []models.Product
-		productsToUpdate []models.Product
-		mockBehaviour    func(m sqlxmock.Sqlmock)
-		wantErr          error
-	}{
-		{
-			name:             "empty request",
-			sellerId:         42,
-			productsToAdd:    []models.Product{},
-			productsToDelete: []models.Product{},
-			productsToUpdate: []models.Product{},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-			},
-			wantErr: ErrEmptyRequest,
-		},
-		{
-			name:             "transaction start failed",
-			sellerId:         42,
-			productsToAdd:    []models.Product{},
-			productsToDelete: []models.Product{{OfferId: 1, Name: "name1", Price: 1, Quantity: 1}},
-			productsToUpdate: []models.Product{},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				m.ExpectBegin().WillReturnError(errors.New("cannot start tx"))
-			},
-			wantErr: ErrTxFailed,
-		},
-		{
-			name:             "insert query exec failed",
-			sellerId:         42,
-			productsToAdd:    []models.Product{{OfferId: 1, Name: "name1", Price: 1, Quantity: 1}},
-			productsToDelete: []models.Product{},
-			productsToUpdate: []models.Product{},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				m.ExpectBegin()
-				m.ExpectExec(`INSERT INTO products (seller_id,offer_id,name,price,quantity) 
-				VALUES ($1,$2,$3,$4,$5) ON CONFLICT ON CONSTRAINT no_duplicates DO UPDATE SET
-				name = EXCLUDED.name, price = EXCLUDED.price, quantity = EXCLUDED.quantity`).
-					WillReturnError(errors.New("exec error"))
-			},
-			wantErr: ErrQueryExecFailed,
-		},
-		{
-			name:             "insert query exec result with error",
-			sellerId:         42,
-			productsToAdd:    []models.Product{{OfferId: 1, Name: "name1", Price: 1, Quantity: 1}},
-			productsToDelete: []models.Product{},
-			productsToUpdate: []models.Product{},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				m.ExpectBegin()
-				m.ExpectExec(`INSERT INTO products (seller_id,offer_id,name,price,quantity) 
-				VALUES ($1,$2,$3,$4,$5) ON CONFLICT ON CONSTRAINT no_duplicates DO UPDATE SET
-				name = EXCLUDED.name, price = EXCLUDED.price, quantity = EXCLUDED.quantity`).
-					WillReturnResult(sqlxmock.NewErrorResult(errors.New("result exec err")))
-			},
-			wantErr: ErrQueryExecFailed,
-		},
-		{
-			name:             "delete query exec failed",
-			sellerId:         42,
-			productsToAdd:    []models.Product{},
-			productsToDelete: []models.Product{{OfferId: 1, Name: "name1", Price: 1, Quantity: 1}},
-			productsToUpdate: []models.Product{},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				m.ExpectBegin()
-				m.ExpectExec("DELETE FROM products WHERE offer_id IN ($1) AND seller_id = $2").
-					WillReturnError(errors.New("exec error"))
-			},
-			wantErr: ErrQueryExecFailed,
-		},
-		{
-			name:             "delete query exec result with error",
-			sellerId:         42,
-			productsToAdd:    []models.Product{},
-			productsToDelete: []models.Product{{OfferId: 1, Name: "name1", Price: 1, Quantity: 1}},
-			productsToUpdate: []models.Product{},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				m.ExpectBegin()
-				m.ExpectExec("DELETE FROM products WHERE offer_id IN ($1) AND seller_id = $2").
-					WillReturnResult(sqlxmock.NewErrorResult(errors.New("result exec err")))
-			},
-			wantErr: ErrQueryExecFailed,
-		},
-		{
-			name:          "request to delete only",
-			sellerId:      42,
-			productsToAdd: []models.Product{},
-			productsToDelete: []models.Product{
-				{OfferId: 1, Name: "name1", Price: 1, Quantity: 1},
-				{OfferId: 2, Name: "name2", Price: 2, Quantity: 2},
-				{OfferId: 3, Name: "name3", Price: 3, Quantity: 3},
-			},
-			productsToUpdate: []models.Product{},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				m.ExpectBegin()
-				m.ExpectExec("DELETE FROM products WHERE offer_id IN ($1,$2,$3) AND seller_id = $4").
-					WithArgs(1, 2, 3, 42).
-					WillReturnResult(sqlxmock.NewResult(0, 3))
-				m.ExpectCommit()
-			},
-			wantErr: nil,
-		},
-		{
-			name:          "request to update only",
-			sellerId:      42,
-			productsToAdd: []models.Product{},
-			productsToUpdate: []models.Product{
-				{OfferId: 1, Name: "name1", Price: 1, Quantity: 1},
-				{OfferId: 2, Name: "name2", Price: 2, Quantity: 2},
-				{OfferId: 3, Name: "name3", Price: 3, Quantity: 3},
-			},
-			productsToDelete: []models.Product{},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				m.ExpectBegin()
-				m.ExpectExec(`INSERT INTO products (seller_id,offer_id,name,price,quantity) 
-					VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10),($11,$12,$13,$14,$15) 
-					ON CONFLICT ON CONSTRAINT no_duplicates DO UPDATE SET
-					name = EXCLUDED.name, price = EXCLUDED.price, quantity = EXCLUDED.quantity`).
-					WithArgs(42, 1, "name1", 1, 1, 42, 2, "name2", 2, 2, 42, 3, "name3", 3, 3).
-					WillReturnResult(sqlxmock.NewResult(0, 3))
-				m.ExpectCommit()
-			},
-			wantErr: nil,
-		},
-		{
-			name:     "request to insert only",
-			sellerId: 42,
-			productsToAdd: []models.Product{
-				{OfferId: 1, Name: "name1", Price: 1, Quantity: 1},
-				{OfferId: 2, Name: "name2", Price: 2, Quantity: 2},
-				{OfferId: 3, Name: "name3", Price: 3, Quantity: 3},
-			},
-			productsToDelete: []models.Product{},
-			productsToUpdate: []models.Product{},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				m.ExpectBegin()
-				m.ExpectExec(`INSERT INTO products (seller_id,offer_id,name,price,quantity) 
-					VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10),($11,$12,$13,$14,$15) 
-					ON CONFLICT ON CONSTRAINT no_duplicates DO UPDATE SET
-					name = EXCLUDED.name, price = EXCLUDED.price, quantity = EXCLUDED.quantity`).
-					WithArgs(42, 1, "name1", 1, 1, 42, 2, "name2", 2, 2, 42, 3, "name3", 3, 3).
-					WillReturnResult(sqlxmock.NewResult(0, 3))
-				m.ExpectCommit()
-			},
-			wantErr: nil,
-		},
-		{
-			name:     "request to 1 insert, 1 update, 1 delete",
-			sellerId: 42,
-			productsToAdd: []models.Product{
-				{OfferId: 1, Name: "name1", Price: 1, Quantity: 1},
-			},
-			productsToDelete: []models.Product{
-				{OfferId: 2, Name: "name2", Price: 2, Quantity: 2},
-			},
-			productsToUpdate: []models.Product{
-				{OfferId: 3, Name: "name3", Price: 3, Quantity: 3},
-			},
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				m.ExpectBegin()
-				m.ExpectExec(`INSERT INTO products (seller_id,offer_id,name,price,quantity) 
-					VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10) 
-					ON CONFLICT ON CONSTRAINT no_duplicates DO UPDATE SET
-					name = EXCLUDED.name, price = EXCLUDED.price, quantity = EXCLUDED.quantity`).
-					WithArgs(42, 1, "name1", 1, 1, 42, 3, "name3", 3, 3).
-					WillReturnResult(sqlxmock.NewResult(0, 2))
-				m.ExpectExec("DELETE FROM products WHERE offer_id IN ($1) AND seller_id = $2").
-					WithArgs(2, 42).
-					WillReturnResult(sqlxmock.NewResult(0, 1))
-				m.ExpectCommit()
-			},
-			wantErr: nil,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			cfg := config.Config{Repository: config.Repository{Timeout: 5}}
-			r := NewRepository(db, cfg)
-			tt.mockBehaviour(mockCtrl)
-
-			_, err := r.ManageProducts(tt.sellerId, tt.productsToAdd, tt.productsToDelete, tt.productsToUpdate)
-			assert.Equal(t, tt.wantErr, err)
-		})
-	}
-}
-
 func TestRepository_ProductsByFilter(t *testing.T) {
 	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
 	if err != nil {
@@ -219,7 +24,7 @@ func TestRepository_ProductsByFilter(t *testing.T) {
 		name          string
 		filter        service.RequestFilter
 		mockBehaviour func(m sqlxmock.Sqlmock)
-		want          []models.Product
+		want          service.ProductsPage
 		wantErr       error
 	}{
 		{
@@ -233,7 +38,6 @@ func TestRepository_ProductsByFilter(t *testing.T) {
 				reg := `SELECT`
 				m.ExpectQuery(reg).WithArgs(1, 2, 3, 1, `%err%`).WillReturnError(errors.New("error query execution"))
 			},
-			want:    nil,
 			wantErr: ErrQueryExecFailed,
 		},
 		{
@@ -253,13 +57,13 @@ func TestRepository_ProductsByFilter(t *testing.T) {
 					AddRow(3, 1, "name3_1", 1, 1)
 				m.ExpectQuery(reg).WithArgs(1, 2, 3).WillReturnRows(rows)
 			},
-			want: []models.Product{
+			want: service.ProductsPage{Products: []models.Product{
 				{SellerId: 1, OfferId: 1, Name: "head", Price: 1, Quantity: 1},
 				{SellerId: 1, OfferId: 2, Name: "body", Price: 2, Quantity: 2},
 				{SellerId: 1, OfferId: 3, Name: "name1_3", Price: 3, Quantity: 3},
 				{SellerId: 2, OfferId: 1, Name: "name2_1", Price: 1, Quantity: 1},
 				{SellerId: 3, OfferId: 1, Name: "name3_1", Price: 1, Quantity: 1},
-			},
+			}},
 			wantErr: nil,
 		},
 		{
@@ -277,11 +81,11 @@ func TestRepository_ProductsByFilter(t *testing.T) {
 					AddRow(15, 10, "name15_10", 1, 1)
 				m.ExpectQuery(reg).WithArgs(1, 5, 10).WillReturnRows(rows)
 			},
-			want: []models.Product{
+			want: service.ProductsPage{Products: []models.Product{
 				{SellerId: 1, OfferId: 1, Name: "head", Price: 1, Quantity: 1},
 				{SellerId: 5, OfferId: 5, Name: "body", Price: 2, Quantity: 2},
 				{SellerId: 15, OfferId: 10, Name: "name15_10", Price: 1, Quantity: 1},
-			},
+			}},
 			wantErr: nil,
 		},
 		{
@@ -292,18 +96,18 @@ func TestRepository_ProductsByFilter(t *testing.T) {
 				Substring: "sub",
 			},
 			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				reg := `SELECT.+name LIKE`
+				reg := `SELECT.+name ILIKE`
 				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
 					AddRow(6, 6, "submarine", 1, 1).
 					AddRow(9, 9, "subwoofer", 2, 2).
 					AddRow(20, 20, "subtitles", 1, 1)
 				m.ExpectQuery(reg).WithArgs(`%sub%`).WillReturnRows(rows)
 			},
-			want: []models.Product{
+			want: service.ProductsPage{Products: []models.Product{
 				{SellerId: 6, OfferId: 6, Name: "submarine", Price: 1, Quantity: 1},
 				{SellerId: 9, OfferId: 9, Name: "subwoofer", Price: 2, Quantity: 2},
 				{SellerId: 20, OfferId: 20, Name: "subtitles", Price: 1, Quantity: 1},
-			},
+			}},
 			wantErr: nil,
 		},
 		{
@@ -322,12 +126,12 @@ func TestRepository_ProductsByFilter(t *testing.T) {
 					AddRow(3, 5, "Биткоинт", 1, 1)
 				m.ExpectQuery(reg).WithArgs(1, 2, 3, 5, 6).WillReturnRows(rows)
 			},
-			want: []models.Product{
+			want: service.ProductsPage{Products: []models.Product{
 				{SellerId: 1, OfferId: 5, Name: "Колесо", Price: 1, Quantity: 1},
 				{SellerId: 1, OfferId: 6, Name: "Кросовок", Price: 1, Quantity: 1},
 				{SellerId: 2, OfferId: 6, Name: "Ветка", Price: 1, Quantity: 1},
 				{SellerId: 3, OfferId: 5, Name: "Биткоинт", Price: 1, Quantity: 1},
-			},
+			}},
 			wantErr: nil,
 		},
 		{
@@ -338,7 +142,7 @@ func TestRepository_ProductsByFilter(t *testing.T) {
 				Substring: "big",
 			},
 			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				reg := `SELECT.+(?:(?:seller_id IN|offer_id IN|name LIKE).+){3}` // ровно три раза встретим одно из ...
+				reg := `SELECT.+(?:(?:seller_id IN|offer_id IN|name ILIKE).+){3}` // ровно три раза встретим одно из ...
 				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
 					AddRow(1, 4, "big changus", 1, 1).
 					AddRow(1, 7, "big melon", 2, 2).
@@ -347,12 +151,210 @@ func TestRepository_ProductsByFilter(t *testing.T) {
 					AddRow(3, 6, "big TV", 1, 1)
 				m.ExpectQuery(reg).WithArgs(1, 2, 3, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, `%big%`).WillReturnRows(rows)
 			},
-			want: []models.Product{
+			want: service.ProductsPage{Products: []models.Product{
 				{SellerId: 1, OfferId: 4, Name: "big changus", Price: 1, Quantity: 1},
 				{SellerId: 1, OfferId: 7, Name: "big melon", Price: 2, Quantity: 2},
 				{SellerId: 2, OfferId: 3, Name: "big boss", Price: 3, Quantity: 3},
 				{SellerId: 2, OfferId: 10, Name: "big spoon", Price: 1, Quantity: 1},
 				{SellerId: 3, OfferId: 6, Name: "big TV", Price: 1, Quantity: 1},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "substring with like wildcards",
+			filter: service.RequestFilter{
+				Substring: `100%_\`,
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+ FROM products WHERE name ILIKE \$1 ORDER BY`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
+					AddRow(1, 1, `Хлопок 100%_\`, 1, 1)
+				m.ExpectQuery(reg).WithArgs(`%100\%\_\\%`).WillReturnRows(rows)
+			},
+			want: service.ProductsPage{Products: []models.Product{
+				{SellerId: 1, OfferId: 1, Name: `Хлопок 100%_\`, Price: 1, Quantity: 1},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "fulltext search ranked by relevance",
+			filter: service.RequestFilter{
+				SellerIDs:  []uint64{1},
+				Substring:  "красные яблоки",
+				SearchMode: service.SearchModeFulltext,
+				Limit:      2,
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+, \(ts_rank\(to_tsvector\('russian', name\), plainto_tsquery\('russian', \$1\)\)\) AS rank ` +
+					`FROM products WHERE seller_id IN \(\$2\) ` +
+					`AND to_tsvector\('russian', name\) @@ plainto_tsquery\('russian', \$3\) ` +
+					`ORDER BY ts_rank\(to_tsvector\('russian', name\), plainto_tsquery\('russian', \$4\)\) DESC, seller_id DESC, offer_id DESC LIMIT 3`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol, rankCol}).
+					AddRow(1, 7, "Яблоко красное", 1, 1, 0.25).
+					AddRow(1, 3, "Яблоки", 1, 1, 0.125).
+					AddRow(1, 9, "Красные", 1, 1, 0.125)
+				m.ExpectQuery(reg).WithArgs("красные яблоки", 1, "красные яблоки", "красные яблоки").WillReturnRows(rows)
+			},
+			want: service.ProductsPage{
+				Products: []models.Product{
+					{SellerId: 1, OfferId: 7, Name: "Яблоко красное", Price: 1, Quantity: 1},
+					{SellerId: 1, OfferId: 3, Name: "Яблоки", Price: 1, Quantity: 1},
+				},
+				NextCursor: &service.Cursor{SellerId: 1, OfferId: 3, Value: "0.125"},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "fulltext search next page",
+			filter: service.RequestFilter{
+				Substring:  "яблоки",
+				SearchMode: service.SearchModeFulltext,
+				After:      &service.Cursor{SellerId: 1, OfferId: 7, Value: "0.5"},
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+ AS rank FROM products WHERE to_tsvector\('russian', name\) @@ plainto_tsquery\('russian', \$2\) ` +
+					`AND \(ts_rank\(.+\$3\)\), seller_id, offer_id\) < \(\$4, \$5, \$6\) ` +
+					`ORDER BY ts_rank\(.+\$7\)\) DESC, seller_id DESC, offer_id DESC LIMIT 101`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol, rankCol}).
+					AddRow(2, 1, "Яблоки", 1, 1, 0.25)
+				m.ExpectQuery(reg).WithArgs("яблоки", "яблоки", "яблоки", float32(0.5), 1, 7, "яблоки").WillReturnRows(rows)
+			},
+			want: service.ProductsPage{Products: []models.Product{
+				{SellerId: 2, OfferId: 1, Name: "Яблоки", Price: 1, Quantity: 1},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "fulltext search with explicit sort",
+			filter: service.RequestFilter{
+				Substring:  "яблоки",
+				SearchMode: service.SearchModeFulltext,
+				SortBy:     service.SortByName,
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+ FROM products WHERE to_tsvector\('russian', name\) @@ plainto_tsquery\('russian', \$1\) ` +
+					`ORDER BY name ASC, seller_id ASC, offer_id ASC LIMIT 101`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
+					AddRow(2, 1, "Яблоки", 1, 1)
+				m.ExpectQuery(reg).WithArgs("яблоки").WillReturnRows(rows)
+			},
+			want: service.ProductsPage{Products: []models.Product{
+				{SellerId: 2, OfferId: 1, Name: "Яблоки", Price: 1, Quantity: 1},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "filter by price range and out of stock",
+			filter: service.RequestFilter{
+				SellerIDs: []uint64{42},
+				PriceMin:  uint64Ptr(1000),
+				PriceMax:  uint64Ptr(5000),
+				InStock:   boolPtr(false),
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+ FROM products WHERE seller_id IN \(\$1\) AND price >= \$2 AND price <= \$3 AND quantity = \$4 ORDER BY`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
+					AddRow(42, 1, "name42_1", 1500, 0)
+				m.ExpectQuery(reg).WithArgs(42, 1000, 5000, 0).WillReturnRows(rows)
+			},
+			want: service.ProductsPage{Products: []models.Product{
+				{SellerId: 42, OfferId: 1, Name: "name42_1", Price: 1500, Quantity: 0},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "filter by quantity range and in stock",
+			filter: service.RequestFilter{
+				QuantityMin: uint64Ptr(5),
+				QuantityMax: uint64Ptr(10),
+				InStock:     boolPtr(true),
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+ FROM products WHERE quantity >= \$1 AND quantity <= \$2 AND quantity > \$3 ORDER BY`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
+					AddRow(1, 1, "name1_1", 1, 7)
+				m.ExpectQuery(reg).WithArgs(5, 10, 0).WillReturnRows(rows)
+			},
+			want: service.ProductsPage{Products: []models.Product{
+				{SellerId: 1, OfferId: 1, Name: "name1_1", Price: 1, Quantity: 7},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "next page in default order",
+			filter: service.RequestFilter{
+				Limit: 2,
+				After: &service.Cursor{SellerId: 1, OfferId: 2},
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+ FROM products WHERE \(seller_id, offer_id\) > \(\$1, \$2\) ORDER BY seller_id ASC, offer_id ASC LIMIT 3`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
+					AddRow(1, 3, "name1_3", 3, 3).
+					AddRow(2, 1, "name2_1", 1, 1)
+				m.ExpectQuery(reg).WithArgs(1, 2).WillReturnRows(rows)
+			},
+			want: service.ProductsPage{Products: []models.Product{
+				{SellerId: 1, OfferId: 3, Name: "name1_3", Price: 3, Quantity: 3},
+				{SellerId: 2, OfferId: 1, Name: "name2_1", Price: 1, Quantity: 1},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "next page sorted by offer_id",
+			filter: service.RequestFilter{
+				Limit:  1,
+				After:  &service.Cursor{SellerId: 7, OfferId: 2},
+				SortBy: service.SortByOfferId,
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+ FROM products WHERE \(offer_id, seller_id\) > \(\$1, \$2\) ORDER BY offer_id ASC, seller_id ASC LIMIT 2`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
+					AddRow(8, 2, "name8_2", 1, 1)
+				m.ExpectQuery(reg).WithArgs(2, 7).WillReturnRows(rows)
+			},
+			want: service.ProductsPage{Products: []models.Product{
+				{SellerId: 8, OfferId: 2, Name: "name8_2", Price: 1, Quantity: 1},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "next page sorted by price desc",
+			filter: service.RequestFilter{
+				SellerIDs: []uint64{1},
+				Limit:     3,
+				After:     &service.Cursor{SellerId: 1, OfferId: 2, Value: "10"},
+				SortBy:    service.SortByPrice,
+				SortDesc:  true,
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+ FROM products WHERE seller_id IN \(\$1\) ` +
+					`AND \(price, seller_id, offer_id\) < \(\$2, \$3, \$4\) ` +
+					`ORDER BY price DESC, seller_id DESC, offer_id DESC LIMIT 4`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
+					AddRow(1, 5, "cheap", 1, 1)
+				m.ExpectQuery(reg).WithArgs(1, uint64(10), 1, 2).WillReturnRows(rows)
+			},
+			want: service.ProductsPage{Products: []models.Product{
+				{SellerId: 1, OfferId: 5, Name: "cheap", Price: 1, Quantity: 1},
+			}},
+			wantErr: nil,
+		},
+		{
+			name: "курсор следующей страницы хранит значение сортировки",
+			filter: service.RequestFilter{
+				Limit:  1,
+				SortBy: service.SortByName,
+			},
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				reg := `SELECT .+ FROM products ORDER BY name ASC, seller_id ASC, offer_id ASC LIMIT 2`
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
+					AddRow(1, 5, "apple", 1, 1).
+					AddRow(1, 6, "banana", 1, 1)
+				m.ExpectQuery(reg).WillReturnRows(rows)
+			},
+			want: service.ProductsPage{
+				Products:   []models.Product{{SellerId: 1, OfferId: 5, Name: "apple", Price: 1, Quantity: 1}},
+				NextCursor: &service.Cursor{SellerId: 1, OfferId: 5, Value: "apple"},
 			},
 			wantErr: nil,
 		},
@@ -365,15 +367,15 @@ func TestRepository_ProductsByFilter(t *testing.T) {
 
 			log.Println(tt.name)
 
-			products, err := r.ProductsByFilter(tt.filter)
+			page, err := r.ProductsByFilter(tt.filter)
 			assert.Equal(t, tt.wantErr, err)
-			assert.Equal(t, tt.want, products)
+			assert.Equal(t, tt.want, page)
 		})
 	}
 }
 
-func TestRepository_SellerProductIDs(t *testing.T) {
-	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
+func TestRepository_SellerProductsByIDs(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
@@ -382,38 +384,37 @@ func TestRepository_SellerProductIDs(t *testing.T) {
 	tests := []struct {
 		name          string
 		sellerId      uint64
+		offerIDs      []uint64
 		mockBehaviour func(m sqlxmock.Sqlmock)
-		want          []uint64
+		want          []models.Product
 		wantErr       error
 	}{
 		{
 			name:     "valid query",
 			sellerId: 1,
+			offerIDs: []uint64{1, 5, 10},
 			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				reg := `SELECT.+ WHERE seller_id =.+`
-				rows := sqlxmock.NewRows([]string{"offer_id"}).AddRow(1).AddRow(2).AddRow(3).AddRow(4).AddRow(5)
-				m.ExpectQuery(reg).WithArgs(1).WillReturnRows(rows)
+				rows := sqlxmock.NewRows([]string{sellerIdCol, offerIdCol, nameCol, priceCol, quantityCol}).
+					AddRow(1, 1, "head", 1, 1).
+					AddRow(1, 10, "name1_10", 2, 2)
+				m.ExpectQuery("SELECT seller_id, offer_id, name, price, quantity FROM products WHERE seller_id = $1 AND offer_id = ANY($2)").
+					WithArgs(1, "{1,5,10}").
+					WillReturnRows(rows)
 			},
-			want:    []uint64{1, 2, 3, 4, 5},
-			wantErr: nil,
-		},
-		{
-			name:     "valid query empty select",
-			sellerId: 2,
-			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				reg := `SELECT.+ WHERE seller_id =.+`
-				rows := sqlxmock.NewRows([]string{"offer_id"})
-				m.ExpectQuery(reg).WithArgs(2).WillReturnRows(rows)
+			want: []models.Product{
+				{SellerId: 1, OfferId: 1, Name: "head", Price: 1, Quantity: 1},
+				{SellerId: 1, OfferId: 10, Name: "name1_10", Price: 2, Quantity: 2},
 			},
-			want:    []uint64{},
 			wantErr: nil,
 		},
 		{
 			name:     "query execution failed",
-			sellerId: 3,
+			sellerId: 2,
+			offerIDs: []uint64{1},
 			mockBehaviour: func(m sqlxmock.Sqlmock) {
-				reg := `SELECT.+ WHERE seller_id =.+`
-				m.ExpectQuery(reg).WithArgs(3).WillReturnError(errors.New("some err"))
+				m.ExpectQuery("SELECT seller_id, offer_id, name, price, quantity FROM products WHERE seller_id = $1 AND offer_id = ANY($2)").
+					WithArgs(2, "{1}").
+					WillReturnError(errors.New("some err"))
 			},
 			want:    nil,
 			wantErr: ErrQueryExecFailed,
@@ -425,11 +426,34 @@ func TestRepository_SellerProductIDs(t *testing.T) {
 			r := NewRepository(db, cfg)
 			tt.mockBehaviour(mockCtrl)
 
-			log.Println(tt.name)
-
-			products, err := r.SellerProductIDs(tt.sellerId)
+			products, err := r.SellerProductsByIDs(tt.sellerId, tt.offerIDs)
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.want, products)
 		})
 	}
 }
+
+func uint64Ptr(u uint64) *uint64 {
+	return &u
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func expectChangeSource(m sqlxmock.Sqlmock, source models.ChangeSource) {
+	jobId := ""
+	if source.JobId != 0 {
+		jobId = strconv.FormatUint(source.JobId, 10)
+	}
+
+	m.ExpectExec("SELECT set_config('app.change_source', $1, true), set_config('app.change_job_id', $2, true), set_config('app.change_request_id', $3, true)").
+		WithArgs(source.Kind, jobId, source.RequestId).
+		WillReturnResult(sqlxmock.NewResult(0, 1))
+}
+
+func expectCatalogSnapshot(m sqlxmock.Sqlmock, jobId uint64) {
+	m.ExpectExec("UPDATE import_jobs SET catalog_snapshot = pg_current_snapshot(), catalog_xact_id = pg_current_xact_id() WHERE id = $1").
+		WithArgs(jobId).
+		WillReturnResult(sqlxmock.NewResult(0, 1))
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/hablof/merchant-experience/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// importSnapshotQuery - состояние офферов до загрузки: первая запись истории по каждому офферу, сделанная загрузкой.
+// У добавленных загрузкой офферов первая запись - insert, старых значений нет.
+const importSnapshotQuery = `SELECT DISTINCT ON (seller_id, offer_id) seller_id, offer_id, action, old_name, old_price, old_quantity
+	FROM ` + historyTableName + `
+	WHERE job_id = $1 AND source = '` + models.ChangeKindImport + `'
+	ORDER BY seller_id, offer_id, id`
+
+// importConflictsQuery считает изменения затронутых загрузкой офферов, сделанные после неё кем-то другим
+const importConflictsQuery = `SELECT count(*) FROM ` + historyTableName + ` h
+	JOIN (
+		SELECT seller_id, offer_id, max(id) AS last_id FROM ` + historyTableName + `
+		WHERE job_id = $1 AND source = '` + models.ChangeKindImport + `'
+		GROUP BY seller_id, offer_id
+	) touched USING (seller_id, offer_id)
+	WHERE h.id > touched.last_id AND h.job_id IS DISTINCT FROM $1`
+
+// RevertJob возвращает офферы, затронутые загрузкой, к значениям до неё: удалённые и изменённые восстанавливаются,
+// добавленные удаляются. Всё выполняется в одной транзакции, откат пишется в историю с source = revert.
+// Возвращает models.ErrNotFound, models.ErrAlreadyReverted или models.ErrConflict,
+// если после загрузки офферы успели изменить.
+func (r *Repository) RevertJob(jobId uint64) (models.RevertResults, error) {
+	ctx, cf := context.WithTimeout(context.Background(), r.applyTimeout)
+	defer cf()
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrTxFailed
+	}
+	defer tx.Rollback()
+
+	// блокировка задачи не даёт откатить загрузку дважды параллельными запросами
+	lockQueryString, args, err := r.initQuery.
+		Select(revertedAtCol).
+		From(jobsTableName).
+		Where(sq.Eq{idCol: jobId}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrQueryBuilderFailed
+	}
+
+	var revertedAt sql.NullTime
+	err = tx.GetContext(ctx, &revertedAt, lockQueryString, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.RevertResults{}, models.ErrNotFound
+
+	case err != nil:
+		log.Println(err)
+		return models.RevertResults{}, ErrQueryExecFailed
+
+	case revertedAt.Valid:
+		return models.RevertResults{}, models.ErrAlreadyReverted
+	}
+
+	conflicts := 0
+	if err := tx.GetContext(ctx, &conflicts, importConflictsQuery, jobId); err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrQueryExecFailed
+	}
+	if conflicts > 0 {
+		return models.RevertResults{}, models.ErrConflict
+	}
+
+	if err := setChangeSource(ctx, tx, models.ChangeSource{Kind: models.ChangeKindRevert, JobId: jobId}); err != nil {
+		return models.RevertResults{}, err
+	}
+
+	// добавленные загрузкой офферы удаляем
+	removeQueryString := `WITH snapshot AS (` + importSnapshotQuery + `)
+	DELETE FROM ` + tableName + ` p USING snapshot s
+	WHERE p.seller_id = s.seller_id AND p.offer_id = s.offer_id AND s.action = '` + models.ChangeInsert + `'`
+
+	removeResult, err := tx.ExecContext(ctx, removeQueryString, jobId)
+	if err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrQueryExecFailed
+	}
+	removed, err := removeResult.RowsAffected()
+	if err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrQueryExecFailed
+	}
+
+	// изменённым и удалённым возвращаем старые значения
+	restoreQueryString := `INSERT INTO ` + tableName + ` (seller_id, offer_id, name, price, quantity)
+	SELECT seller_id, offer_id, old_name, old_price, old_quantity FROM (` + importSnapshotQuery + `) s
+	WHERE s.action <> '` + models.ChangeInsert + `'
+	` + upsertSuffix
+
+	restoreResult, err := tx.ExecContext(ctx, restoreQueryString, jobId)
+	if err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrQueryExecFailed
+	}
+	restored, err := restoreResult.RowsAffected()
+	if err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrQueryExecFailed
+	}
+
+	markQueryString, args, err := r.initQuery.
+		Update(jobsTableName).
+		Set(revertedAtCol, sq.Expr("now()")).
+		Set(updatedAtCol, sq.Expr("now()")).
+		Where(sq.Eq{idCol: jobId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrQueryBuilderFailed
+	}
+
+	if _, err := tx.ExecContext(ctx, markQueryString, args...); err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrQueryExecFailed
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println(err)
+		return models.RevertResults{}, ErrTxFailed
+	}
+
+	return models.RevertResults{Restored: uint64(restored), Removed: uint64(removed)}, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRepository_RevertJob(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	revertSource := models.ChangeSource{Kind: models.ChangeKindRevert, JobId: 3}
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          models.RevertResults
+		wantErr       error
+	}{
+		{
+			name: "задача не найдена",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT reverted_at FROM import_jobs WHERE id = \$1 FOR UPDATE`).
+					WithArgs(3).
+					WillReturnRows(sqlxmock.NewRows([]string{revertedAtCol}))
+				m.ExpectRollback()
+			},
+			want:    models.RevertResults{},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "загрузку уже откатили",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT reverted_at FROM import_jobs WHERE id = \$1 FOR UPDATE`).
+					WithArgs(3).
+					WillReturnRows(sqlxmock.NewRows([]string{revertedAtCol}).AddRow(time.Now()))
+				m.ExpectRollback()
+			},
+			want:    models.RevertResults{},
+			wantErr: models.ErrAlreadyReverted,
+		},
+		{
+			name: "офферы менялись после загрузки",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT reverted_at FROM import_jobs`).
+					WithArgs(3).
+					WillReturnRows(sqlxmock.NewRows([]string{revertedAtCol}).AddRow(nil))
+				m.ExpectQuery(`SELECT count\(\*\) FROM product_history h`).
+					WithArgs(3).
+					WillReturnRows(sqlxmock.NewRows([]string{"count"}).AddRow(1))
+				m.ExpectRollback()
+			},
+			want:    models.RevertResults{},
+			wantErr: models.ErrConflict,
+		},
+		{
+			name: "ошибка восстановления откатывает транзакцию",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT reverted_at FROM import_jobs`).
+					WithArgs(3).
+					WillReturnRows(sqlxmock.NewRows([]string{revertedAtCol}).AddRow(nil))
+				m.ExpectQuery(`SELECT count\(\*\) FROM product_history h`).
+					WithArgs(3).
+					WillReturnRows(sqlxmock.NewRows([]string{"count"}).AddRow(0))
+				m.ExpectExec(`SELECT set_config`).
+					WithArgs(revertSource.Kind, "3", "").
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				m.ExpectExec(`DELETE FROM products p USING snapshot s`).
+					WithArgs(3).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				m.ExpectExec(`INSERT INTO products`).
+					WithArgs(3).
+					WillReturnError(errors.New("some err"))
+				m.ExpectRollback()
+			},
+			want:    models.RevertResults{},
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name: "успешный откат",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT reverted_at FROM import_jobs`).
+					WithArgs(3).
+					WillReturnRows(sqlxmock.NewRows([]string{revertedAtCol}).AddRow(nil))
+				m.ExpectQuery(`SELECT count\(\*\) FROM product_history h`).
+					WithArgs(3).
+					WillReturnRows(sqlxmock.NewRows([]string{"count"}).AddRow(0))
+				m.ExpectExec(`SELECT set_config`).
+					WithArgs(revertSource.Kind, "3", "").
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				m.ExpectExec(`DELETE FROM products p USING snapshot s`).
+					WithArgs(3).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				m.ExpectExec(`INSERT INTO products (.+) ON CONFLICT ON CONSTRAINT no_duplicates`).
+					WithArgs(3).
+					WillReturnResult(sqlxmock.NewResult(0, 2))
+				m.ExpectExec(`UPDATE import_jobs SET reverted_at = now\(\), updated_at = now\(\) WHERE id = \$1`).
+					WithArgs(3).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			want:    models.RevertResults{Restored: 2, Removed: 1},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			results, err := r.RevertJob(3)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, results)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/hablof/merchant-experience/internal/models"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const uploadsTableName = "import_job_uploads"
+
+// CreateUploadJob ставит в очередь задачу на загруженный файл content; файл сохраняется в той же транзакции,
+// поэтому задачу, забранную любой репликой, не обработать без файла
+func (r *Repository) CreateUploadJob(job models.Job, content []byte) (models.Job, error) {
+	insertJobQueryString, jobArgs, err := r.insertJobQuery(job).ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrTxFailed
+	}
+	defer tx.Rollback()
+
+	createdJob := models.Job{}
+	if err := tx.GetContext(ctx, &createdJob, insertJobQueryString, jobArgs...); err != nil {
+		log.Println(err)
+		return models.Job{}, ErrQueryExecFailed
+	}
+
+	insertUploadQueryString, uploadArgs, err := r.initQuery.
+		Insert(uploadsTableName).
+		Columns(reportJobIdCol, contentCol).
+		Values(createdJob.Id, content).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return models.Job{}, ErrQueryBuilderFailed
+	}
+
+	if _, err := tx.ExecContext(ctx, insertUploadQueryString, uploadArgs...); err != nil {
+		log.Println(err)
+		return models.Job{}, ErrQueryExecFailed
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println(err)
+		return models.Job{}, ErrTxFailed
+	}
+
+	return createdJob, nil
+}
+
+// JobUpload возвращает загруженный файл задачи или models.ErrNotFound
+func (r *Repository) JobUpload(jobId uint64) ([]byte, error) {
+	selectQueryString, args, err := r.initQuery.
+		Select(contentCol).
+		From(uploadsTableName).
+		Where(sq.Eq{reportJobIdCol: jobId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return nil, ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	var content []byte
+	err = r.db.GetContext(ctx, &content, selectQueryString, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrNotFound
+
+	case err != nil:
+		log.Println(err)
+		return nil, ErrQueryExecFailed
+	}
+
+	return content, nil
+}
+
+// DeleteJobUpload удаляет загруженный файл задачи; если файла нет, ничего не делает
+func (r *Repository) DeleteJobUpload(jobId uint64) error {
+	deleteQueryString, args, err := r.initQuery.
+		Delete(uploadsTableName).
+		Where(sq.Eq{reportJobIdCol: jobId}).
+		ToSql()
+	if err != nil {
+		log.Println(err)
+		return ErrQueryBuilderFailed
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), r.dbTimeout)
+	defer cf()
+
+	if _, err := r.db.ExecContext(ctx, deleteQueryString, args...); err != nil {
+		log.Println(err)
+		return ErrQueryExecFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRepository_CreateUploadJob(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	content := []byte("csv")
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		wantErr       error
+	}{
+		{
+			name: "ошибка вставки задачи",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`INSERT INTO import_jobs`).
+					WillReturnError(errors.New("some err"))
+				m.ExpectRollback()
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name: "ошибка вставки файла откатывает задачу",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`INSERT INTO import_jobs`).
+					WillReturnRows(sqlxmock.NewRows([]string{idCol}).AddRow(7))
+				m.ExpectExec(`INSERT INTO import_job_uploads \(job_id,content\) VALUES \(\$1,\$2\)`).
+					WithArgs(7, content).
+					WillReturnError(errors.New("some err"))
+				m.ExpectRollback()
+			},
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name: "задача и файл сохранены",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`INSERT INTO import_jobs`).
+					WillReturnRows(sqlxmock.NewRows([]string{idCol}).AddRow(7))
+				m.ExpectExec(`INSERT INTO import_job_uploads`).
+					WithArgs(7, content).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			_, err := r.CreateUploadJob(models.Job{SellerId: 1, TableURL: "upload:///table.csv"}, content)
+			assert.Equal(t, tt.wantErr, err)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_JobUpload(t *testing.T) {
+	db, mockCtrl, err := sqlxmock.Newx(sqlxmock.QueryMatcherOption(sqlxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(m sqlxmock.Sqlmock)
+		want          []byte
+		wantErr       error
+	}{
+		{
+			name: "файла нет",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(`SELECT content FROM import_job_uploads WHERE job_id = \$1`).
+					WithArgs(7).
+					WillReturnRows(sqlxmock.NewRows([]string{contentCol}))
+			},
+			want:    nil,
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "ошибка запроса",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(`SELECT content FROM import_job_uploads`).
+					WithArgs(7).
+					WillReturnError(errors.New("some err"))
+			},
+			want:    nil,
+			wantErr: ErrQueryExecFailed,
+		},
+		{
+			name: "файл найден",
+			mockBehaviour: func(m sqlxmock.Sqlmock) {
+				m.ExpectQuery(`SELECT content FROM import_job_uploads`).
+					WithArgs(7).
+					WillReturnRows(sqlxmock.NewRows([]string{contentCol}).AddRow([]byte("csv")))
+			},
+			want:    []byte("csv"),
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(db, config.Config{Repository: config.Repository{Timeout: 5}})
+			tt.mockBehaviour(mockCtrl)
+
+			content, err := r.JobUpload(7)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, content)
+			assert.NoError(t, mockCtrl.ExpectationsWereMet())
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 )
 
+// LogRequest пишет в лог только метод и адрес запроса: тело и заголовки (учётные данные источников таблиц) не логируются
 func LogRequest(f http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
@@ -1,19 +1,27 @@
 package router
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/gateway"
+	"github.com/hablof/merchant-experience/internal/importer"
 	"github.com/hablof/merchant-experience/internal/models"
 	"github.com/hablof/merchant-experience/internal/router/middleware"
+	"github.com/hablof/merchant-experience/internal/scheduler"
 	"github.com/hablof/merchant-experience/internal/service"
-	xlsxparser "github.com/hablof/merchant-experience/internal/xlsxparser"
+	"github.com/hablof/merchant-experience/internal/xlsxparser"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -22,62 +30,880 @@ const (
 	sellerIdParamField  = "seller_id"
 	offerIdParamField   = "offer_id"
 	substringParamField = "substring"
+	searchModeField     = "search_mode"
+	limitParamField     = "limit"
+	afterSellerIdField  = "after_seller_id"
+	afterOfferIdField   = "after_offer_id"
+	afterValueField     = "after_value"
+	sortParamField      = "sort"
+	priceMinField       = "price_min"
+	priceMaxField       = "price_max"
+	quantityMinField    = "quantity_min"
+	quantityMaxField    = "quantity_max"
+	inStockField        = "in_stock"
+	envelopeField       = "envelope"
+	jobIdPathParam      = "id"
+	sellerIdPathParam   = "seller_id"
+	offerIdPathParam    = "offer_id"
+	feedIdPathParam     = "feed_id"
+
+	requestIdHeader = "X-Request-ID"
+	// параметры запроса следующей страницы товаров
+	nextCursorHeader = "X-Next-Cursor"
+	xlsxContentType  = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	// тело запроса изменения одного оффера
+	maxProductBodySize = 64 << 10
+	// тело запроса создания фида
+	maxFeedBodySize = 64 << 10
+
+	// поля multipart/form-data при загрузке файла таблицы
+	fileFormField     = "file"
+	sellerIdFormField = "sellerId"
+	dryRunFormField   = "dryRun"
+	modeFormField     = "mode"
+	sheetFormField    = "sheet"
+	sheetsFormField   = "sheets"
+
+	defaultMaxUploadSizeMB = 50
+	// поля формы и заголовки частей сверх размера файла
+	multipartOverhead = 1 << 20
+	// остальное ParseMultipartForm сбрасывает во временные файлы
+	multipartMemory = 10 << 20
 )
 
-type TableDownloader interface {
-	Table(url string) (io.Reader, error)
+type Service interface {
+	ProductsByFilter(filter service.RequestFilter) (service.ProductsPage, error)
+	ProductHistory(sellerId uint64, offerId uint64, limit uint64) ([]models.ProductChange, error)
+
+	Product(sellerId uint64, offerId uint64) (models.Product, error)
+	PutProduct(product models.Product, source models.ChangeSource) (created bool, err error)
+	PatchProduct(sellerId uint64, offerId uint64, patch service.ProductPatch, source models.ChangeSource) (models.Product, error)
+	DeleteProduct(sellerId uint64, offerId uint64, source models.ChangeSource) error
+
+	ExportProducts(sellerId uint64, handle func(products []models.Product) error) error
+}
+
+type Importer interface {
+	Enqueue(job models.Job, auth models.TableAuth) (models.Job, error)
+	Job(jobId uint64) (models.Job, error)
+	Revert(jobId uint64) (models.RevertResults, error)
+	EnqueueUpload(job models.Job, filename string, body io.Reader) (models.Job, error)
+	Report(jobId uint64) ([]byte, error)
+	Confirm(jobId uint64) (models.Job, error)
+}
+
+type Scheduler interface {
+	CreateFeed(feed models.Feed, auth models.TableAuth) (models.Feed, error)
+	Feeds(sellerId uint64) ([]models.Feed, error)
+	DeleteFeed(sellerId uint64, feedId uint64) error
+}
+
+// productSchema - тело PUT и PATCH /sellers/{seller_id}/offers/{offer_id}; для PUT обязательны все поля
+type productSchema struct {
+	Name     *string `json:"name"`
+	Price    *uint64 `json:"price"`
+	Quantity *uint64 `json:"quantity"`
+}
+
+// feedSchema - тело POST /sellers/{seller_id}/feeds
+type feedSchema struct {
+	TableURL        string `json:"tableURL"`
+	IntervalMinutes uint64 `json:"intervalMinutes"`
+	Mode            string `json:"mode"`
+	// учётные данные источника, как у загрузки по ссылке
+	Auth models.TableAuth `json:"auth"`
+}
+
+type jsonSchema struct {
+	TableURL string `json:"tableURL"`
+	SellerId uint64 `json:"sellerId"`
+	DryRun   bool   `json:"dryRun"`
+	Mode     string `json:"mode"`
+	// лист книги; по умолчанию первый
+	Sheet string `json:"sheet"`
+	// листы книги и их продавцы - вместо sellerId и sheet
+	Sheets models.SheetSellers `json:"sheets"`
+	// учётные данные источника таблицы; хранятся зашифрованными и не логируются
+	Auth models.TableAuth `json:"auth"`
+}
+
+type Handler struct {
+	s  Service
+	im Importer
+	fs Scheduler
+
+	maxUploadSize int64
+	// выгрузка каталога в формате, который принимает загрузка
+	xw xlsxparser.Writer
+}
+
+func NewRouter(
+	cfg config.Config,
+	s Service,
+	im Importer,
+	fs Scheduler,
+) http.Handler {
+
+	h := Handler{
+		s:             s,
+		im:            im,
+		fs:            fs,
+		maxUploadSize: cfg.Server.MaxUploadSizeMB << 20,
+		xw:            xlsxparser.NewWriter(),
+	}
+	if h.maxUploadSize <= 0 {
+		h.maxUploadSize = defaultMaxUploadSizeMB << 20
+	}
+
+	r := httprouter.New()
+	r.GET("/", h.GetProducts)
+	r.POST("/", h.PostTableURL)
+	r.GET("/jobs/:"+jobIdPathParam, h.GetJob)
+	r.GET("/jobs/:"+jobIdPathParam+"/report.xlsx", h.GetJobReport)
+	r.POST("/imports/:"+jobIdPathParam+"/revert", h.RevertImport)
+	r.POST("/imports/:"+jobIdPathParam+"/confirm", h.ConfirmImport)
+	r.GET("/sellers/:"+sellerIdPathParam+"/offers/:"+offerIdPathParam+"/history", h.GetProductHistory)
+	r.GET("/sellers/:"+sellerIdPathParam+"/offers/:"+offerIdPathParam, h.GetProduct)
+	r.PUT("/sellers/:"+sellerIdPathParam+"/offers/:"+offerIdPathParam, h.PutProduct)
+	r.PATCH("/sellers/:"+sellerIdPathParam+"/offers/:"+offerIdPathParam, h.PatchProduct)
+	r.DELETE("/sellers/:"+sellerIdPathParam+"/offers/:"+offerIdPathParam, h.DeleteProduct)
+	r.GET("/sellers/:"+sellerIdPathParam+"/export.xlsx", h.ExportProducts)
+	r.POST("/sellers/:"+sellerIdPathParam+"/feeds", h.PostFeed)
+	r.GET("/sellers/:"+sellerIdPathParam+"/feeds", h.GetFeeds)
+	r.DELETE("/sellers/:"+sellerIdPathParam+"/feeds/:"+feedIdPathParam, h.DeleteFeed)
+	r.PanicHandler = h.PanicHanler
+
+	return middleware.LogRequest(r.ServeHTTP)
+}
+
+func (h *Handler) PanicHanler(w http.ResponseWriter, r *http.Request, _ interface{}) {
+	log.Println("panic recovered")
+	w.WriteHeader(http.StatusInternalServerError)
+	w.Write([]byte("fatal service error"))
+}
+
+// PostTableURL ставит загрузку таблицы в очередь и сразу отвечает задачей,
+// статус которой можно узнать через GET /jobs/{id}.
+// Вместо JSON со ссылкой можно передать сам файл в multipart/form-data.
+func (h *Handler) PostTableURL(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && mediaType == "multipart/form-data" {
+		h.postTableFile(w, r)
+
+		return
+	}
+
+	b := make([]byte, r.ContentLength)
+	if _, err := r.Body.Read(b); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("unable to read body: " + err.Error())
+		fmt.Fprint(w, "unable to read body")
+
+		return
+	}
+
+	postStruct := jsonSchema{}
+	if err := json.Unmarshal(b, &postStruct); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad json: " + err.Error())
+		fmt.Fprint(w, "bad json")
+
+		return
+	}
+
+	// в ссылке могут быть подпись или токен, поэтому в лог она не попадает
+	if _, err := url.ParseRequestURI(postStruct.TableURL); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad table url")
+		fmt.Fprint(w, "bad table url")
+
+		return
+	}
+
+	syncMode, ok := parseSyncMode(postStruct.Mode)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad sync mode: " + postStruct.Mode)
+		fmt.Fprint(w, "bad sync mode")
+
+		return
+	}
+
+	if !validSheets(postStruct.SellerId, postStruct.Sheet, postStruct.Sheets) {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Printf("bad sheets: sellerId %d, sheet %q, sheets %v", postStruct.SellerId, postStruct.Sheet, postStruct.Sheets)
+		fmt.Fprint(w, "bad sheets")
+
+		return
+	}
+
+	if !validAuth(postStruct.Auth) {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad auth")
+		fmt.Fprint(w, "bad auth")
+
+		return
+	}
+
+	job, err := h.im.Enqueue(models.Job{
+		SellerId: postStruct.SellerId,
+		TableURL: postStruct.TableURL,
+		DryRun:   postStruct.DryRun,
+		SyncMode: syncMode,
+		Sheet:    postStruct.Sheet,
+		Sheets:   postStruct.Sheets,
+	}, postStruct.Auth)
+	if errors.Is(err, gateway.ErrForbiddenURL) || errors.Is(err, importer.ErrCredentialsDisabled) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err.Error())
+
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println(err.Error())
+		fmt.Fprint(w, "service error")
+
+		return
+	}
+
+	writeJobAccepted(w, job)
+}
+
+// postTableFile принимает файл таблицы в поле file; sellerId, dryRun, mode, sheet и sheets - поля той же формы.
+// sheets - JSON-объект {"лист": sellerId}.
+func (h *Handler) postTableFile(w http.ResponseWriter, r *http.Request) {
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			fmt.Fprint(w, "file too large")
+
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad multipart form: " + err.Error())
+		fmt.Fprint(w, "bad multipart form")
+
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile(fileFormField)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("no file in form: " + err.Error())
+		fmt.Fprint(w, "file is required")
+
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.maxUploadSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		fmt.Fprint(w, "file too large")
+
+		return
+	}
+
+	var sheets models.SheetSellers
+	if sheetsParam := r.FormValue(sheetsFormField); sheetsParam != "" {
+		if err := json.Unmarshal([]byte(sheetsParam), &sheets); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			log.Println("bad sheets: " + err.Error())
+			fmt.Fprint(w, "bad sheets")
+
+			return
+		}
+	}
+
+	// с картой листов продавец не нужен
+	var sellerId uint64
+	if sellerIdParam := r.FormValue(sellerIdFormField); sellerIdParam != "" || sheets == nil {
+		sellerId, err = strconv.ParseUint(sellerIdParam, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			log.Println("bad seller id: " + err.Error())
+			fmt.Fprint(w, "bad seller id")
+
+			return
+		}
+	}
+
+	dryRun := false
+	if dryRunParam := r.FormValue(dryRunFormField); dryRunParam != "" {
+		dryRun, err = strconv.ParseBool(dryRunParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			log.Println("bad dry run: " + err.Error())
+			fmt.Fprint(w, "bad dryRun")
+
+			return
+		}
+	}
+
+	syncMode, ok := parseSyncMode(r.FormValue(modeFormField))
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad sync mode: " + r.FormValue(modeFormField))
+		fmt.Fprint(w, "bad sync mode")
+
+		return
+	}
+
+	sheet := r.FormValue(sheetFormField)
+	if !validSheets(sellerId, sheet, sheets) {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Printf("bad sheets: sellerId %d, sheet %q, sheets %v", sellerId, sheet, sheets)
+		fmt.Fprint(w, "bad sheets")
+
+		return
+	}
+
+	job, err := h.im.EnqueueUpload(models.Job{
+		SellerId: sellerId,
+		DryRun:   dryRun,
+		SyncMode: syncMode,
+		Sheet:    sheet,
+		Sheets:   sheets,
+	}, header.Filename, file)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println(err.Error())
+		fmt.Fprint(w, "service error")
+
+		return
+	}
+
+	writeJobAccepted(w, job)
+}
+
+// validSheets: карта листов заменяет sellerId и sheet, в ней непустые имена листов и ненулевые продавцы
+func validSheets(sellerId uint64, sheet string, sheets models.SheetSellers) bool {
+	if sheets == nil {
+		return true
+	}
+	if len(sheets) == 0 || sellerId != 0 || sheet != "" {
+		return false
+	}
+
+	for name, id := range sheets {
+		if strings.TrimSpace(name) == "" || id == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validAuth: basic auth и bearer-токен взаимоисключающие, заголовки - с корректными именами
+// и без переводов строк; Authorization задаётся только через username/password или bearerToken.
+// Параметры ссылки берутся только из самой ссылки.
+func validAuth(auth models.TableAuth) bool {
+	if auth.BearerToken != "" && (auth.Username != "" || auth.Password != "") {
+		return false
+	}
+
+	if auth.Query != "" {
+		return false
+	}
+
+	for name, value := range auth.Headers {
+		if !validHeaderName(name) || strings.ContainsAny(value, "\r\n") ||
+			strings.EqualFold(name, "Authorization") || strings.EqualFold(name, "Host") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validHeaderName: token из RFC 7230
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseSyncMode: пустой режим - merge
+func parseSyncMode(mode string) (models.SyncMode, bool) {
+	syncMode := models.SyncMode(mode)
+	switch syncMode {
+	case "":
+		return models.SyncModeMerge, true
+
+	case models.SyncModeMerge, models.SyncModeReplace:
+		return syncMode, true
+	}
+
+	return "", false
+}
+
+// writeJobAccepted отвечает поставленной в очередь задачей и её адресом в Location
+func writeJobAccepted(w http.ResponseWriter, job models.Job) {
+	job.TableURL = gateway.RedactURL(job.TableURL)
+
+	b, err := json.Marshal(job)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println(err.Error())
+		fmt.Fprint(w, "service error")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Content-Type", "charset=utf-8")
+	w.Header().Add("Location", "/jobs/"+strconv.FormatUint(job.Id, 10))
+
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(b)
+}
+
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	jobId, err := strconv.ParseUint(p.ByName(jobIdPathParam), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad job id: " + err.Error())
+		fmt.Fprint(w, "bad job id")
+
+		return
+	}
+
+	job, err := h.im.Job(jobId)
+	switch {
+	case errors.Is(err, importer.ErrJobNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "job not found")
+
+		return
+
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to fetch job: " + err.Error())
+		fmt.Fprint(w, "failed to fetch job")
+
+		return
+	}
+
+	if job.HasReport {
+		job.ReportURL = reportURL(job.Id)
+	}
+	// без ключа шифрования подпись и токены остаются в ссылке задачи - наружу она отдаётся без них
+	job.TableURL = gateway.RedactURL(job.TableURL)
+
+	b, err := json.Marshal(job)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to marshal job: " + err.Error())
+		fmt.Fprint(w, "service error")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Content-Type", "charset=utf-8")
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// RevertImport откатывает загрузку: затронутые ей офферы получают значения до загрузки
+func (h *Handler) RevertImport(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	jobId, err := strconv.ParseUint(p.ByName(jobIdPathParam), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad import id: " + err.Error())
+		fmt.Fprint(w, "bad import id")
+
+		return
+	}
+
+	results, err := h.im.Revert(jobId)
+	switch {
+	case errors.Is(err, importer.ErrJobNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "import not found")
+
+		return
+
+	case errors.Is(err, importer.ErrJobNotFinished),
+		errors.Is(err, importer.ErrNothingToRevert),
+		errors.Is(err, importer.ErrAlreadyReverted),
+		errors.Is(err, importer.ErrRevertConflict):
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, err.Error())
+
+		return
+
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to revert import: " + err.Error())
+		fmt.Fprint(w, "failed to revert import")
+
+		return
+	}
+
+	b, err := json.Marshal(results)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to marshal revert results: " + err.Error())
+		fmt.Fprint(w, "service error")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Content-Type", "charset=utf-8")
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// ConfirmImport возвращает в очередь загрузку, остановленную защитой каталога; повторно она выполняется без защиты
+func (h *Handler) ConfirmImport(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	jobId, err := strconv.ParseUint(p.ByName(jobIdPathParam), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad import id: " + err.Error())
+		fmt.Fprint(w, "bad import id")
+
+		return
+	}
+
+	job, err := h.im.Confirm(jobId)
+	switch {
+	case errors.Is(err, importer.ErrJobNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "import not found")
+
+		return
+
+	case errors.Is(err, importer.ErrJobNotHeld):
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, err.Error())
+
+		return
+
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to confirm import: " + err.Error())
+		fmt.Fprint(w, "failed to confirm import")
+
+		return
+	}
+
+	writeJobAccepted(w, job)
+}
+
+// parseOfferPath разбирает seller_id и offer_id из пути; при ошибке сам отвечает 400
+func parseOfferPath(w http.ResponseWriter, p httprouter.Params) (sellerId uint64, offerId uint64, ok bool) {
+	sellerId, err := strconv.ParseUint(p.ByName(sellerIdPathParam), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad seller id: " + err.Error())
+		fmt.Fprint(w, "bad seller id")
+
+		return 0, 0, false
+	}
+
+	offerId, err = strconv.ParseUint(p.ByName(offerIdPathParam), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad offer id: " + err.Error())
+		fmt.Fprint(w, "bad offer id")
+
+		return 0, 0, false
+	}
+
+	return sellerId, offerId, true
+}
+
+// apiSource - источник изменений для истории: идентификатор запроса берётся из X-Request-ID
+// или генерируется, и возвращается клиенту в том же заголовке
+func apiSource(w http.ResponseWriter, r *http.Request) models.ChangeSource {
+	requestId := r.Header.Get(requestIdHeader)
+	if requestId == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			log.Println("failed to generate request id: " + err.Error())
+		}
+		requestId = hex.EncodeToString(b)
+	}
+	w.Header().Set(requestIdHeader, requestId)
+
+	return models.ChangeSource{Kind: models.ChangeKindAPI, RequestId: requestId}
+}
+
+// readProduct читает тело запроса изменения оффера; при ошибке сам отвечает 400
+func readProduct(w http.ResponseWriter, r *http.Request) (productSchema, bool) {
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxProductBodySize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("unable to read body: " + err.Error())
+		fmt.Fprint(w, "unable to read body")
+
+		return productSchema{}, false
+	}
+
+	ps := productSchema{}
+	if err := json.Unmarshal(b, &ps); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad json: " + err.Error())
+		fmt.Fprint(w, "bad json")
+
+		return productSchema{}, false
+	}
+
+	return ps, true
+}
+
+// writeProductError отвечает на ошибку сервиса при работе с одним оффером
+func writeProductError(w http.ResponseWriter, err error) {
+	var issue models.ImportIssue
+	switch {
+	case errors.As(err, &issue):
+		b, _ := json.Marshal(issue)
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("Content-Type", "charset=utf-8")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(b)
+
+	case errors.Is(err, service.ErrProductNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "product not found")
+
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("product request failed: " + err.Error())
+		fmt.Fprint(w, "service error")
+	}
+}
+
+func writeProduct(w http.ResponseWriter, statusCode int, product models.Product) {
+	b, err := json.Marshal(product)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to marshal product: " + err.Error())
+		fmt.Fprint(w, "service error")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Content-Type", "charset=utf-8")
+
+	w.WriteHeader(statusCode)
+	w.Write(b)
+}
+
+func (h *Handler) GetProduct(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	sellerId, offerId, ok := parseOfferPath(w, p)
+	if !ok {
+		return
+	}
+
+	product, err := h.s.Product(sellerId, offerId)
+	if err != nil {
+		writeProductError(w, err)
+
+		return
+	}
+
+	writeProduct(w, http.StatusOK, product)
+}
+
+// PutProduct создаёт оффер (201) или целиком заменяет его (200); name, price и quantity обязательны
+func (h *Handler) PutProduct(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	sellerId, offerId, ok := parseOfferPath(w, p)
+	if !ok {
+		return
+	}
+
+	ps, ok := readProduct(w, r)
+	if !ok {
+		return
+	}
+
+	if ps.Name == nil || ps.Price == nil || ps.Quantity == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "name, price and quantity are required")
+
+		return
+	}
+
+	product := models.Product{
+		SellerId: sellerId,
+		OfferId:  offerId,
+		Name:     *ps.Name,
+		Price:    *ps.Price,
+		Quantity: *ps.Quantity,
+	}
+
+	created, err := h.s.PutProduct(product, apiSource(w, r))
+	if err != nil {
+		writeProductError(w, err)
+
+		return
+	}
+
+	statusCode := http.StatusOK
+	if created {
+		statusCode = http.StatusCreated
+	}
+
+	writeProduct(w, statusCode, product)
 }
 
-type Service interface {
-	ProductsByFilter(filter service.RequestFilter) ([]models.Product, error)
-	UpdateProducts(sellerId uint64, productUpdates []models.ProductUpdate) (service.UpdateResults, error)
+// PatchProduct меняет только переданные поля оффера и отвечает его новым состоянием
+func (h *Handler) PatchProduct(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	sellerId, offerId, ok := parseOfferPath(w, p)
+	if !ok {
+		return
+	}
+
+	ps, ok := readProduct(w, r)
+	if !ok {
+		return
+	}
+
+	patch := service.ProductPatch{Name: ps.Name, Price: ps.Price, Quantity: ps.Quantity}
+	product, err := h.s.PatchProduct(sellerId, offerId, patch, apiSource(w, r))
+	if err != nil {
+		writeProductError(w, err)
+
+		return
+	}
+
+	writeProduct(w, http.StatusOK, product)
 }
 
-type ExcelParser interface {
-	ParseProducts(r io.Reader) (productUpdates []models.ProductUpdate, productErrs []error, methodErr error)
+func (h *Handler) DeleteProduct(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	sellerId, offerId, ok := parseOfferPath(w, p)
+	if !ok {
+		return
+	}
+
+	if err := h.s.DeleteProduct(sellerId, offerId, apiSource(w, r)); err != nil {
+		writeProductError(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-type jsonSchema struct {
-	TableURL string `json:"tableURL"`
-	SellerId uint64 `json:"sellerId"`
+// GetJobReport отдаёт копию таблицы задачи с колонкой error и подсвеченными ошибочными ячейками
+func (h *Handler) GetJobReport(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	jobId, err := strconv.ParseUint(p.ByName(jobIdPathParam), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad job id: " + err.Error())
+		fmt.Fprint(w, "bad job id")
+
+		return
+	}
+
+	report, err := h.im.Report(jobId)
+	switch {
+	case errors.Is(err, importer.ErrJobNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "job not found")
+
+		return
+
+	case errors.Is(err, importer.ErrReportNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "report not found")
+
+		return
+
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to fetch report: " + err.Error())
+		fmt.Fprint(w, "failed to fetch report")
+
+		return
+	}
+
+	w.Header().Set("Content-Type", xlsxContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="job-%d-errors.xlsx"`, jobId))
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(report)
 }
 
-type Handler struct {
-	s  Service
-	td TableDownloader
-	ep ExcelParser
+// reportURL - адрес отчёта об ошибках задачи, который отдаётся в GET /jobs/{id}
+func reportURL(jobId uint64) string {
+	return "/jobs/" + strconv.FormatUint(jobId, 10) + "/report.xlsx"
 }
 
-func NewRouter(
-	s Service,
-	td TableDownloader,
-	ep ExcelParser,
-) http.Handler {
+// ExportProducts отдаёт каталог продавца xlsx-файлом, который можно поправить и загрузить обратно
+func (h *Handler) ExportProducts(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 
-	h := Handler{
-		s:  s,
-		td: td,
-		ep: ep,
+	sellerId, err := strconv.ParseUint(p.ByName(sellerIdPathParam), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad seller id: " + err.Error())
+		fmt.Fprint(w, "bad seller id")
+
+		return
 	}
 
-	r := httprouter.New()
-	r.GET("/", h.GetProducts)
-	r.POST("/", h.PostTableURL)
-	r.PanicHandler = h.PanicHanler
+	// ответить 500 при ошибке выгрузки можно только потому, что книга собирается целиком до отправки первого байта
+	w.Header().Set("Content-Type", xlsxContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="seller-%d.xlsx"`, sellerId))
 
-	return middleware.LogRequest(r.ServeHTTP)
-}
+	err = h.xw.WriteProducts(w, func(handle func(products []models.Product) error) error {
+		return h.s.ExportProducts(sellerId, handle)
+	})
+	if err != nil {
+		w.Header().Del("Content-Disposition")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to export products: " + err.Error())
+		fmt.Fprint(w, "failed to export products")
 
-func (h *Handler) PanicHanler(w http.ResponseWriter, r *http.Request, _ interface{}) {
-	log.Println("panic recovered")
-	w.WriteHeader(http.StatusInternalServerError)
-	w.Write([]byte("fatal service error"))
+		return
+	}
 }
 
-func (h *Handler) PostTableURL(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+// PostFeed регистрирует фид продавца: таблицу по ссылке сервис сам загружает раз в intervalMinutes
+func (h *Handler) PostFeed(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 
-	b := make([]byte, r.ContentLength)
-	if _, err := r.Body.Read(b); err != nil && err != io.EOF {
+	sellerId, err := strconv.ParseUint(p.ByName(sellerIdPathParam), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad seller id: " + err.Error())
+		fmt.Fprint(w, "bad seller id")
+
+		return
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxFeedBodySize))
+	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		log.Println("unable to read body: " + err.Error())
 		fmt.Fprint(w, "unable to read body")
@@ -85,8 +911,8 @@ func (h *Handler) PostTableURL(w http.ResponseWriter, r *http.Request, p httprou
 		return
 	}
 
-	postStruct := jsonSchema{}
-	if err := json.Unmarshal(b, &postStruct); err != nil {
+	feedStruct := feedSchema{}
+	if err := json.Unmarshal(b, &feedStruct); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		log.Println("bad json: " + err.Error())
 		fmt.Fprint(w, "bad json")
@@ -94,73 +920,193 @@ func (h *Handler) PostTableURL(w http.ResponseWriter, r *http.Request, p httprou
 		return
 	}
 
-	table, err := h.td.Table(postStruct.TableURL)
-	if err != nil {
+	// в ссылке могут быть подпись или токен, поэтому в лог она не попадает
+	if _, err := url.ParseRequestURI(feedStruct.TableURL); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		log.Println("bad table url: " + err.Error())
+		log.Println("bad table url")
 		fmt.Fprint(w, "bad table url")
 
 		return
 	}
 
-	productUpdates, productErrs, methodErr := h.ep.ParseProducts(table)
-	switch {
-	case errors.Is(methodErr, xlsxparser.ErrEmptyDoc),
-		errors.Is(methodErr, xlsxparser.ErrEmptySheet),
-		errors.Is(methodErr, xlsxparser.ErrFailedToRead):
+	syncMode, ok := parseSyncMode(feedStruct.Mode)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad sync mode: " + feedStruct.Mode)
+		fmt.Fprint(w, "bad sync mode")
+
+		return
+	}
 
+	if !validAuth(feedStruct.Auth) {
 		w.WriteHeader(http.StatusBadRequest)
-		log.Println("bad xslx file")
-		fmt.Fprint(w, "bad xslx file")
+		log.Println("bad auth")
+		fmt.Fprint(w, "bad auth")
 
 		return
+	}
 
-	case errors.Is(methodErr, xlsxparser.ErrInvalidIDs):
+	feed, err := h.fs.CreateFeed(models.Feed{
+		SellerId:        sellerId,
+		TableURL:        feedStruct.TableURL,
+		IntervalMinutes: feedStruct.IntervalMinutes,
+		SyncMode:        syncMode,
+	}, feedStruct.Auth)
+	switch {
+	case errors.Is(err, gateway.ErrForbiddenURL),
+		errors.Is(err, scheduler.ErrIntervalTooShort),
+		errors.Is(err, importer.ErrCredentialsDisabled):
 		w.WriteHeader(http.StatusBadRequest)
-		log.Println("offer_id column has invalid value(s)")
-		fmt.Fprint(w, "offer_id column has invalid value(s)")
+		fmt.Fprint(w, err.Error())
+
+		return
+
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to create feed: " + err.Error())
+		fmt.Fprint(w, "failed to create feed")
+
+		return
+	}
+
+	feed.TableURL = gateway.RedactURL(feed.TableURL)
+
+	b, err = json.Marshal(feed)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to marshal feed: " + err.Error())
+		fmt.Fprint(w, "service error")
 
 		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Content-Type", "charset=utf-8")
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write(b)
+}
+
+// GetFeeds отдаёт фиды продавца вместе с итогом последнего запуска
+func (h *Handler) GetFeeds(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 
-	case errors.Is(methodErr, xlsxparser.ErrHasDuplicates):
+	sellerId, err := strconv.ParseUint(p.ByName(sellerIdPathParam), 10, 64)
+	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		log.Println("xslx file has duplicates")
-		fmt.Fprint(w, "xslx file has duplicates")
+		log.Println("bad seller id: " + err.Error())
+		fmt.Fprint(w, "bad seller id")
 
 		return
+	}
 
-	case methodErr != nil:
+	feeds, err := h.fs.Feeds(sellerId)
+	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		log.Println(methodErr.Error())
-		fmt.Fprint(w, "parsing error")
+		log.Println("failed to fetch feeds: " + err.Error())
+		fmt.Fprint(w, "failed to fetch feeds")
 
 		return
 	}
 
-	ur, err := h.s.UpdateProducts(postStruct.SellerId, productUpdates)
+	for idx := range feeds {
+		feeds[idx].TableURL = gateway.RedactURL(feeds[idx].TableURL)
+	}
+
+	b, err := json.Marshal(feeds)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		log.Println(err.Error())
+		log.Println("failed to marshal feeds: " + err.Error())
 		fmt.Fprint(w, "service error")
 
 		return
 	}
-	ur.Errors = append(ur.Errors, productErrs...)
 
-	b2, err := json.Marshal(ur)
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Content-Type", "charset=utf-8")
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func (h *Handler) DeleteFeed(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	sellerId, err := strconv.ParseUint(p.ByName(sellerIdPathParam), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad seller id: " + err.Error())
+		fmt.Fprint(w, "bad seller id")
+
+		return
+	}
+
+	feedId, err := strconv.ParseUint(p.ByName(feedIdPathParam), 10, 64)
 	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad feed id: " + err.Error())
+		fmt.Fprint(w, "bad feed id")
+
+		return
+	}
+
+	err = h.fs.DeleteFeed(sellerId, feedId)
+	switch {
+	case errors.Is(err, scheduler.ErrFeedNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "feed not found")
+
+		return
+
+	case err != nil:
 		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to delete feed: " + err.Error())
+		fmt.Fprint(w, "failed to delete feed")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetProductHistory отдаёт изменения оффера, новые первыми; limit - как у GET /
+func (h *Handler) GetProductHistory(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	sellerId, offerId, ok := parseOfferPath(w, p)
+	if !ok {
+		return
+	}
+
+	rf := service.RequestFilter{}
+	if err := parsePagination(url.Values{limitParamField: r.URL.Query()[limitParamField]}, &rf); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
 		log.Println(err.Error())
+		fmt.Fprint(w, err.Error())
+
+		return
+	}
+
+	changes, err := h.s.ProductHistory(sellerId, offerId, rf.Limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to fetch product history: " + err.Error())
+		fmt.Fprint(w, "failed to fetch product history")
+
+		return
+	}
+
+	b, err := json.Marshal(changes)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("failed to marshal product history: " + err.Error())
 		fmt.Fprint(w, "service error")
 
 		return
 	}
 
-	w.Header().Add("Content-Type", "text/plain")
+	w.Header().Add("Content-Type", "application/json")
 	w.Header().Add("Content-Type", "charset=utf-8")
 
 	w.WriteHeader(http.StatusOK)
-	w.Write(b2)
+	w.Write(b)
 }
 
 func (h *Handler) GetProducts(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -200,8 +1146,60 @@ func (h *Handler) GetProducts(w http.ResponseWriter, r *http.Request, _ httprout
 		OfferIDs:  offerIDs,
 		Substring: paramSubstr,
 	}
-	products, err := h.s.ProductsByFilter(rf)
-	if err != nil {
+
+	searchMode := service.SearchMode(r.URL.Query().Get(searchModeField))
+	switch searchMode {
+	case "", service.SearchModeSubstring, service.SearchModeFulltext:
+		rf.SearchMode = searchMode
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("bad search mode: " + string(searchMode))
+		fmt.Fprint(w, "bad search_mode")
+
+		return
+	}
+
+	if err := parseRangeFilters(r.URL.Query(), &rf); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println(err.Error())
+		fmt.Fprint(w, err.Error())
+
+		return
+	}
+
+	if err := parsePagination(r.URL.Query(), &rf); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println(err.Error())
+		fmt.Fprint(w, err.Error())
+
+		return
+	}
+
+	envelope := false
+	if envelopeParam := r.URL.Query().Get(envelopeField); envelopeParam != "" {
+		parsed, err := strconv.ParseBool(envelopeParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			log.Println(err.Error())
+			fmt.Fprint(w, "bad envelope")
+
+			return
+		}
+
+		envelope = parsed
+	}
+
+	page, err := h.s.ProductsByFilter(rf)
+	switch {
+	case errors.Is(err, service.ErrBadCursor):
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println(err.Error())
+		fmt.Fprint(w, "bad cursor")
+
+		return
+
+	case err != nil:
 		w.WriteHeader(http.StatusInternalServerError)
 		log.Println("failed to fetch products: " + err.Error())
 		fmt.Fprint(w, "failed to fetch products")
@@ -209,7 +1207,18 @@ func (h *Handler) GetProducts(w http.ResponseWriter, r *http.Request, _ httprout
 		return
 	}
 
-	b, err := json.Marshal(products)
+	var body interface{} = page.Products
+	if envelope {
+		env := productsEnvelope{Products: page.Products}
+		if page.NextCursor != nil {
+			nextCursor := cursorQuery(*page.NextCursor)
+			env.NextCursor = &nextCursor
+		}
+
+		body = env
+	}
+
+	b, err := json.Marshal(body)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		log.Println("failed to marshal products: " + err.Error())
@@ -220,7 +1229,123 @@ func (h *Handler) GetProducts(w http.ResponseWriter, r *http.Request, _ httprout
 
 	w.Header().Add("Content-Type", "application/json")
 	w.Header().Add("Content-Type", "charset=utf-8")
+	if page.NextCursor != nil {
+		w.Header().Set(nextCursorHeader, cursorQuery(*page.NextCursor))
+	}
 
 	w.WriteHeader(http.StatusOK)
 	w.Write(b)
 }
+
+// productsEnvelope - ответ GET / с envelope=1: страница товаров вместе с курсором следующей страницы
+type productsEnvelope struct {
+	Products []models.Product `json:"products"`
+	// параметры курсора, как в заголовке X-Next-Cursor; null на последней странице
+	NextCursor *string `json:"next_cursor"`
+}
+
+// cursorQuery - параметры курсора для запроса следующей страницы
+func cursorQuery(cursor service.Cursor) string {
+	query := url.Values{}
+	query.Set(afterSellerIdField, strconv.FormatUint(cursor.SellerId, 10))
+	query.Set(afterOfferIdField, strconv.FormatUint(cursor.OfferId, 10))
+	if cursor.Value != "" {
+		query.Set(afterValueField, cursor.Value)
+	}
+
+	return query.Encode()
+}
+
+// parsePagination разбирает limit, курсор и сортировку; в отличие от фильтров, некорректные значения не игнорируются
+func parsePagination(query url.Values, rf *service.RequestFilter) error {
+	if limitParam := query.Get(limitParamField); limitParam != "" {
+		limit, err := strconv.ParseUint(limitParam, 10, 64)
+		if err != nil || limit == 0 || limit > service.MaxLimit {
+			return errors.New("bad limit")
+		}
+
+		rf.Limit = limit
+	}
+
+	afterSellerIdParam := query.Get(afterSellerIdField)
+	afterOfferIdParam := query.Get(afterOfferIdField)
+	if afterSellerIdParam != "" || afterOfferIdParam != "" {
+		afterSellerId, err := strconv.ParseUint(afterSellerIdParam, 10, 64)
+		if err != nil {
+			return errors.New("bad cursor")
+		}
+
+		afterOfferId, err := strconv.ParseUint(afterOfferIdParam, 10, 64)
+		if err != nil {
+			return errors.New("bad cursor")
+		}
+
+		// значение колонки сортировки разбирает сервис: его тип зависит от sort
+		rf.After = &service.Cursor{SellerId: afterSellerId, OfferId: afterOfferId, Value: query.Get(afterValueField)}
+	}
+
+	// sort=price - по возрастанию, sort=-price - по убыванию
+	if sortParam := query.Get(sortParamField); sortParam != "" {
+		sortBy := service.SortField(strings.TrimPrefix(sortParam, "-"))
+		switch sortBy {
+		case service.SortByOfferId, service.SortByName, service.SortByPrice, service.SortByQuantity:
+		default:
+			return errors.New("bad sort")
+		}
+
+		rf.SortBy = sortBy
+		rf.SortDesc = strings.HasPrefix(sortParam, "-")
+	}
+
+	return nil
+}
+
+// parseRangeFilters разбирает границы цены и количества и флаг in_stock
+func parseRangeFilters(query url.Values, rf *service.RequestFilter) error {
+	var err error
+	if rf.PriceMin, err = parseOptionalUint(query, priceMinField); err != nil {
+		return err
+	}
+	if rf.PriceMax, err = parseOptionalUint(query, priceMaxField); err != nil {
+		return err
+	}
+	if rf.QuantityMin, err = parseOptionalUint(query, quantityMinField); err != nil {
+		return err
+	}
+	if rf.QuantityMax, err = parseOptionalUint(query, quantityMaxField); err != nil {
+		return err
+	}
+
+	if rf.PriceMin != nil && rf.PriceMax != nil && *rf.PriceMin > *rf.PriceMax {
+		return errors.New("bad price range")
+	}
+
+	if rf.QuantityMin != nil && rf.QuantityMax != nil && *rf.QuantityMin > *rf.QuantityMax {
+		return errors.New("bad quantity range")
+	}
+
+	if inStockParam := query.Get(inStockField); inStockParam != "" {
+		inStock, err := strconv.ParseBool(inStockParam)
+		if err != nil {
+			return errors.New("bad " + inStockField)
+		}
+
+		rf.InStock = &inStock
+	}
+
+	return nil
+}
+
+func parseOptionalUint(query url.Values, field string) (*uint64, error) {
+	param := query.Get(field)
+	if param == "" {
+		return nil, nil
+	}
+
+	u, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return nil, errors.New("bad " + field)
+	}
+
+	return &u, nil
+}
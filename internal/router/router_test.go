@@ -4,17 +4,24 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
-	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/gateway"
+	"github.com/hablof/merchant-experience/internal/importer"
 	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/scheduler"
 	"github.com/hablof/merchant-experience/internal/service"
-	xlsxparser "github.com/hablof/merchant-experience/internal/xlsxparser"
+	"github.com/hablof/merchant-experience/internal/xlsxparser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,14 +32,18 @@ func TestHandler_GetProducts(t *testing.T) {
 		pSellerIDs string
 		pOfferIDs  string
 		pSubstring string
+		// limit, курсор, сортировка, диапазоны
+		pPagination url.Values
 
 		expectedReqFilter service.RequestFilter
-		serviceReturns    []models.Product
+		serviceReturns    service.ProductsPage
 		serviceReturnsErr error
-		serviceBehaviour  func(sm *ServiceMock, expRF service.RequestFilter, serviceRet []models.Product, serviceRetErr error)
+		serviceBehaviour  func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error)
 
 		wantStatusCode  int
 		wantContentBody string
+		// заголовок X-Next-Cursor
+		wantNextCursor string
 	}{
 		{
 			name:              "empty request",
@@ -40,13 +51,13 @@ func TestHandler_GetProducts(t *testing.T) {
 			pOfferIDs:         "",
 			pSubstring:        "",
 			expectedReqFilter: service.RequestFilter{SellerIDs: nil, OfferIDs: nil, Substring: ""},
-			serviceReturns: []models.Product{
+			serviceReturns: service.ProductsPage{Products: []models.Product{
 				{SellerId: 1, OfferId: 1, Name: "name1", Price: 1, Quantity: 1},
 				{SellerId: 2, OfferId: 2, Name: "name2", Price: 2, Quantity: 2},
 				{SellerId: 3, OfferId: 3, Name: "name3", Price: 3, Quantity: 3},
-			},
+			}},
 			serviceReturnsErr: nil,
-			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet []models.Product, serviceRetErr error) {
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
 				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
 			},
 			wantStatusCode:  200,
@@ -58,13 +69,13 @@ func TestHandler_GetProducts(t *testing.T) {
 			pOfferIDs:         "2,3,4",
 			pSubstring:        "name",
 			expectedReqFilter: service.RequestFilter{SellerIDs: []uint64{1, 2, 3}, OfferIDs: []uint64{2, 3, 4}, Substring: "name"},
-			serviceReturns: []models.Product{
+			serviceReturns: service.ProductsPage{Products: []models.Product{
 				{SellerId: 1, OfferId: 1, Name: "name1", Price: 1, Quantity: 1},
 				{SellerId: 2, OfferId: 2, Name: "name2", Price: 2, Quantity: 2},
 				{SellerId: 3, OfferId: 3, Name: "name3", Price: 3, Quantity: 3},
-			},
+			}},
 			serviceReturnsErr: nil,
-			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet []models.Product, serviceRetErr error) {
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
 				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
 			},
 			wantStatusCode:  200,
@@ -76,13 +87,13 @@ func TestHandler_GetProducts(t *testing.T) {
 			pOfferIDs:         "2,incorrect3,4",
 			pSubstring:        "name", // name cannot be incorrect
 			expectedReqFilter: service.RequestFilter{SellerIDs: nil, OfferIDs: nil, Substring: "name"},
-			serviceReturns: []models.Product{
+			serviceReturns: service.ProductsPage{Products: []models.Product{
 				{SellerId: 1, OfferId: 1, Name: "name1", Price: 1, Quantity: 1},
 				{SellerId: 2, OfferId: 2, Name: "name2", Price: 2, Quantity: 2},
 				{SellerId: 3, OfferId: 3, Name: "name3", Price: 3, Quantity: 3},
-			},
+			}},
 			serviceReturnsErr: nil,
-			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet []models.Product, serviceRetErr error) {
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
 				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
 			},
 			wantStatusCode:  200,
@@ -94,9 +105,9 @@ func TestHandler_GetProducts(t *testing.T) {
 			pOfferIDs:         "",
 			pSubstring:        "name", // name cannot be incorrect
 			expectedReqFilter: service.RequestFilter{SellerIDs: nil, OfferIDs: nil, Substring: "name"},
-			serviceReturns:    nil,
+			serviceReturns:    service.ProductsPage{},
 			serviceReturnsErr: errors.New("repo err"),
-			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet []models.Product, serviceRetErr error) {
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
 				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
 			},
 			wantStatusCode:  500,
@@ -108,22 +119,231 @@ func TestHandler_GetProducts(t *testing.T) {
 			pOfferIDs:         "",
 			pSubstring:        "", // name cannot be incorrect
 			expectedReqFilter: service.RequestFilter{SellerIDs: nil, OfferIDs: nil, Substring: ""},
-			serviceReturns:    nil,
+			serviceReturns:    service.ProductsPage{},
 			serviceReturnsErr: nil,
-			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet []models.Product, serviceRetErr error) {
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
 				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
 			},
 			wantStatusCode:  200,
 			wantContentBody: `null`,
 		},
+		{
+			name:       "pagination params",
+			pSellerIDs: "1",
+			pPagination: url.Values{
+				"limit":           {"2"},
+				"after_seller_id": {"1"},
+				"after_offer_id":  {"3"},
+				"after_value":     {"2"},
+				"sort":            {"-price"},
+			},
+			expectedReqFilter: service.RequestFilter{
+				SellerIDs: []uint64{1},
+				OfferIDs:  nil,
+				Limit:     2,
+				After:     &service.Cursor{SellerId: 1, OfferId: 3, Value: "2"},
+				SortBy:    service.SortByPrice,
+				SortDesc:  true,
+			},
+			serviceReturns: service.ProductsPage{
+				Products: []models.Product{
+					{SellerId: 1, OfferId: 4, Name: "name4", Price: 2, Quantity: 1},
+					{SellerId: 1, OfferId: 5, Name: "name5", Price: 1, Quantity: 1},
+				},
+				NextCursor: &service.Cursor{SellerId: 1, OfferId: 5, Value: "1"},
+			},
+			serviceReturnsErr: nil,
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `[{"sellerId":1,"offerId":4,"name":"name4","price":2,"quantity":1},{"sellerId":1,"offerId":5,"name":"name5","price":1,"quantity":1}]`,
+			wantNextCursor:  "after_offer_id=5&after_seller_id=1&after_value=1",
+		},
+		{
+			name:       "envelope with next cursor",
+			pSellerIDs: "1",
+			pPagination: url.Values{
+				"limit":    {"1"},
+				"envelope": {"1"},
+			},
+			expectedReqFilter: service.RequestFilter{SellerIDs: []uint64{1}, OfferIDs: nil, Limit: 1},
+			serviceReturns: service.ProductsPage{
+				Products:   []models.Product{{SellerId: 1, OfferId: 4, Name: "name4", Price: 2, Quantity: 1}},
+				NextCursor: &service.Cursor{SellerId: 1, OfferId: 4},
+			},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"products":[{"sellerId":1,"offerId":4,"name":"name4","price":2,"quantity":1}],"next_cursor":"after_offer_id=4\u0026after_seller_id=1"}`,
+			wantNextCursor:  "after_offer_id=4&after_seller_id=1",
+		},
+		{
+			name:              "envelope on last page",
+			pSellerIDs:        "1",
+			pPagination:       url.Values{"envelope": {"true"}},
+			expectedReqFilter: service.RequestFilter{SellerIDs: []uint64{1}, OfferIDs: nil},
+			serviceReturns:    service.ProductsPage{Products: []models.Product{}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"products":[],"next_cursor":null}`,
+		},
+		{
+			name:        "bad envelope",
+			pPagination: url.Values{"envelope": {"yes please"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad envelope`,
+		},
+		{
+			name: "cursor value does not match sort",
+			pPagination: url.Values{
+				"after_seller_id": {"1"},
+				"after_offer_id":  {"3"},
+				"after_value":     {"cheap"},
+				"sort":            {"price"},
+			},
+			expectedReqFilter: service.RequestFilter{
+				After:  &service.Cursor{SellerId: 1, OfferId: 3, Value: "cheap"},
+				SortBy: service.SortByPrice,
+			},
+			serviceReturnsErr: service.ErrBadCursor,
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad cursor`,
+		},
+		{
+			name:       "range filters",
+			pSellerIDs: "42",
+			pPagination: url.Values{
+				"price_min":    {"1000"},
+				"price_max":    {"5000"},
+				"quantity_max": {"0"},
+				"in_stock":     {"false"},
+			},
+			expectedReqFilter: service.RequestFilter{
+				SellerIDs:   []uint64{42},
+				OfferIDs:    nil,
+				PriceMin:    uint64Ptr(1000),
+				PriceMax:    uint64Ptr(5000),
+				QuantityMax: uint64Ptr(0),
+				InStock:     boolPtr(false),
+			},
+			serviceReturns: service.ProductsPage{Products: []models.Product{
+				{SellerId: 42, OfferId: 1, Name: "name1", Price: 1500, Quantity: 0},
+			}},
+			serviceReturnsErr: nil,
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `[{"sellerId":42,"offerId":1,"name":"name1","price":1500,"quantity":0}]`,
+		},
+		{
+			name:        "fulltext search",
+			pSubstring:  "яблоки",
+			pPagination: url.Values{"search_mode": {"fulltext"}},
+			expectedReqFilter: service.RequestFilter{
+				SellerIDs:  nil,
+				OfferIDs:   nil,
+				Substring:  "яблоки",
+				SearchMode: service.SearchModeFulltext,
+			},
+			serviceReturns: service.ProductsPage{Products: []models.Product{
+				{SellerId: 1, OfferId: 7, Name: "Яблоко", Price: 1, Quantity: 1},
+			}},
+			serviceReturnsErr: nil,
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+				sm.ProductsByFilterMock.Expect(expRF).Return(serviceRet, serviceRetErr)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `[{"sellerId":1,"offerId":7,"name":"Яблоко","price":1,"quantity":1}]`,
+		},
+		{
+			name:        "bad search_mode",
+			pPagination: url.Values{"search_mode": {"regexp"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad search_mode`,
+		},
+		{
+			name:        "bad price_min",
+			pPagination: url.Values{"price_min": {"-1"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad price_min`,
+		},
+		{
+			name:        "bad quantity_max",
+			pPagination: url.Values{"quantity_max": {"many"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad quantity_max`,
+		},
+		{
+			name:        "price_min greater than price_max",
+			pPagination: url.Values{"price_min": {"5000"}, "price_max": {"1000"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad price range`,
+		},
+		{
+			name:        "bad in_stock",
+			pPagination: url.Values{"in_stock": {"yes"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad in_stock`,
+		},
+		{
+			name:        "bad limit",
+			pPagination: url.Values{"limit": {"0"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad limit`,
+		},
+		{
+			name:        "limit over max",
+			pPagination: url.Values{"limit": {"1001"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad limit`,
+		},
+		{
+			name:        "cursor without offer_id",
+			pPagination: url.Values{"after_seller_id": {"1"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad cursor`,
+		},
+		{
+			name:        "bad sort",
+			pPagination: url.Values{"sort": {"color"}},
+			serviceBehaviour: func(sm *ServiceMock, expRF service.RequestFilter, serviceRet service.ProductsPage, serviceRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: `bad sort`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
 			sm := NewServiceMock(t)
-			tdm := NewTableDownloaderMock(t)
-			epm := NewExcelParserMock(t)
-			h := NewRouter(sm, tdm, epm)
+			imm := NewImporterMock(t)
+			h := NewRouter(config.Config{}, sm, imm, NewSchedulerMock(t))
 
 			tt.serviceBehaviour(sm, tt.expectedReqFilter, tt.serviceReturns, tt.serviceReturnsErr)
 
@@ -137,6 +357,9 @@ func TestHandler_GetProducts(t *testing.T) {
 			if tt.pSubstring != "" {
 				paramVals.Add("substring", tt.pSubstring)
 			}
+			for key, vals := range tt.pPagination {
+				paramVals[key] = vals
+			}
 			params := paramVals.Encode()
 
 			w := httptest.NewRecorder()
@@ -146,6 +369,7 @@ func TestHandler_GetProducts(t *testing.T) {
 
 			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode, "status code")
 			assert.Equal(t, tt.wantContentBody, w.Body.String(), "response body")
+			assert.Equal(t, tt.wantNextCursor, w.Result().Header.Get("X-Next-Cursor"), "next cursor")
 		})
 	}
 }
@@ -154,179 +378,213 @@ func TestHandler_PostTableURL(t *testing.T) {
 
 	tests := []struct {
 		name        string
+		reqBody     string
 		reqUrl      string
 		reqSellerID uint64
 
-		tdExpectURL  string
-		tdReturns    io.Reader
-		tdReturnsErr error
-		tdBehaviour  func(tdm *TableDownloaderMock, expURL string, tdRet io.Reader, tdetErr error)
-
-		parserExpectTable  io.Reader
-		parserReturns      []models.ProductUpdate
-		parserRetValidErrs []error
-		parserReturnsErr   error
-		parserBehaviour    func(epm *ExcelParserMock, parserExpectTable io.Reader, pReturns []models.ProductUpdate, pRetValidErrs []error, pRetErr error)
-
-		expectedSellerID  uint64
-		expectedUpdates   []models.ProductUpdate
-		serviceReturns    service.UpdateResults
-		serviceReturnsErr error
-		serviceBehaviour  func(sm *ServiceMock, expectedSellerID uint64, expectedUpdates []models.ProductUpdate, serviceReturns service.UpdateResults, serviceRetErr error)
+		imReturns    models.Job
+		imReturnsErr error
+		imBehaviour  func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error)
 
 		wantStatusCode  int
 		wantContentBody string
 	}{
 		{
-			name:         "bad table url",
-			reqUrl:       "example.com/table",
-			reqSellerID:  42,
-			tdExpectURL:  "example.com/table",
-			tdReturns:    nil,
-			tdReturnsErr: errors.New("some table downloader err"),
-			tdBehaviour: func(tdm *TableDownloaderMock, expURL string, tdRet io.Reader, tdRetErr error) {
-				tdm.TableMock.Expect(expURL).Return(tdRet, tdRetErr)
-			},
-
-			parserBehaviour: func(epm *ExcelParserMock, parserExpectTable io.Reader, pReturns []models.ProductUpdate, pRetValidErrs []error, pRetErr error) {
+			name:    "bad json",
+			reqBody: `{"tableURL": "http://some.url/t", "sellerId": "один"}`,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
 			},
-
-			serviceBehaviour: func(sm *ServiceMock, expectedSellerID uint64, expectedUpdates []models.ProductUpdate, serviceReturns service.UpdateResults, serviceRetErr error) {
+			wantStatusCode:  400,
+			wantContentBody: "bad json",
+		},
+		{
+			name:        "bad table url",
+			reqUrl:      "example.com/table",
+			reqSellerID: 42,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
 			},
 			wantStatusCode:  400,
 			wantContentBody: "bad table url",
 		},
 		{
-			name:         "bad xslx file",
-			reqUrl:       "some.url/t",
+			name:         "importer error",
+			reqUrl:       "http://some.url/t",
 			reqSellerID:  1,
-			tdExpectURL:  "some.url/t",
-			tdReturns:    bytes.NewBufferString("table mock"),
-			tdReturnsErr: nil,
-			tdBehaviour: func(tdm *TableDownloaderMock, expURL string, tdRet io.Reader, tdRetErr error) {
-				tdm.TableMock.Expect(expURL).Return(tdRet, tdRetErr)
+			imReturns:    models.Job{},
+			imReturnsErr: importer.ErrEnqueueFailed,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{SellerId: expSellerID, TableURL: expURL, SyncMode: models.SyncModeMerge}, models.TableAuth{}).Return(imRet, imRetErr)
 			},
-
-			parserExpectTable:  bytes.NewBufferString("table mock"),
-			parserReturns:      nil,
-			parserRetValidErrs: nil,
-			parserReturnsErr:   xlsxparser.ErrEmptyDoc,
-			parserBehaviour: func(epm *ExcelParserMock, parserExpectTable io.Reader, pReturns []models.ProductUpdate, pRetValidErrs []error, pRetErr error) {
-				epm.ParseProductsMock.Expect(parserExpectTable).Return(pReturns, pRetValidErrs, pRetErr)
-			},
-
-			serviceBehaviour: func(sm *ServiceMock, expectedSellerID uint64, expectedUpdates []models.ProductUpdate, serviceReturns service.UpdateResults, serviceRetErr error) {
+			wantStatusCode:  500,
+			wantContentBody: "service error",
+		},
+		{
+			name:         "url not allowed",
+			reqUrl:       "http://169.254.169.254/latest/meta-data/",
+			reqSellerID:  1,
+			imReturnsErr: fmt.Errorf("%w: 169.254.169.254 is a link-local address", gateway.ErrForbiddenURL),
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{SellerId: expSellerID, TableURL: expURL, SyncMode: models.SyncModeMerge}, models.TableAuth{}).Return(imRet, imRetErr)
 			},
 			wantStatusCode:  400,
-			wantContentBody: "bad xslx file",
+			wantContentBody: "table url not allowed: 169.254.169.254 is a link-local address",
 		},
 		{
-			name:         "xslx file has duplicates",
-			reqUrl:       "some.url/t",
+			name:         "correct request",
+			reqUrl:       "http://some.url/t",
 			reqSellerID:  1,
-			tdExpectURL:  "some.url/t",
-			tdReturns:    bytes.NewBufferString("table mock"),
-			tdReturnsErr: nil,
-			tdBehaviour: func(tdm *TableDownloaderMock, expURL string, tdRet io.Reader, tdRetErr error) {
-				tdm.TableMock.Expect(expURL).Return(tdRet, tdRetErr)
+			imReturns:    models.Job{Id: 7, SellerId: 1, TableURL: "http://some.url/t", Status: models.JobQueued, SyncMode: models.SyncModeMerge, Results: json.RawMessage("null")},
+			imReturnsErr: nil,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{SellerId: expSellerID, TableURL: expURL, SyncMode: models.SyncModeMerge}, models.TableAuth{}).Return(imRet, imRetErr)
 			},
-
-			parserExpectTable:  bytes.NewBufferString("table mock"),
-			parserReturns:      nil,
-			parserRetValidErrs: nil,
-			parserReturnsErr:   xlsxparser.ErrHasDuplicates,
-			parserBehaviour: func(epm *ExcelParserMock, parserExpectTable io.Reader, pReturns []models.ProductUpdate, pRetValidErrs []error, pRetErr error) {
-				epm.ParseProductsMock.Expect(parserExpectTable).Return(pReturns, pRetValidErrs, pRetErr)
+			wantStatusCode:  202,
+			wantContentBody: `{"id":7,"sellerId":1,"tableURL":"http://some.url/t","status":"queued","dryRun":false,"syncMode":"merge","results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:         "dry run request",
+			reqBody:      `{"tableURL": "http://some.url/t", "sellerId": 1, "dryRun": true}`,
+			reqUrl:       "http://some.url/t",
+			reqSellerID:  1,
+			imReturns:    models.Job{Id: 8, SellerId: 1, TableURL: "http://some.url/t", Status: models.JobQueued, DryRun: true, SyncMode: models.SyncModeMerge, Results: json.RawMessage("null")},
+			imReturnsErr: nil,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{SellerId: expSellerID, TableURL: expURL, DryRun: true, SyncMode: models.SyncModeMerge}, models.TableAuth{}).Return(imRet, imRetErr)
 			},
-
-			serviceBehaviour: func(sm *ServiceMock, expectedSellerID uint64, expectedUpdates []models.ProductUpdate, serviceReturns service.UpdateResults, serviceRetErr error) {
+			wantStatusCode:  202,
+			wantContentBody: `{"id":8,"sellerId":1,"tableURL":"http://some.url/t","status":"queued","dryRun":true,"syncMode":"merge","results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:         "replace mode request",
+			reqBody:      `{"tableURL": "http://some.url/t", "sellerId": 1, "mode": "replace"}`,
+			reqUrl:       "http://some.url/t",
+			reqSellerID:  1,
+			imReturns:    models.Job{Id: 9, SellerId: 1, TableURL: "http://some.url/t", Status: models.JobQueued, SyncMode: models.SyncModeReplace, Results: json.RawMessage("null")},
+			imReturnsErr: nil,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{SellerId: expSellerID, TableURL: expURL, SyncMode: models.SyncModeReplace}, models.TableAuth{}).Return(imRet, imRetErr)
+			},
+			wantStatusCode:  202,
+			wantContentBody: `{"id":9,"sellerId":1,"tableURL":"http://some.url/t","status":"queued","dryRun":false,"syncMode":"replace","results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:    "bad sync mode",
+			reqBody: `{"tableURL": "http://some.url/t", "sellerId": 1, "mode": "wipe"}`,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
 			},
 			wantStatusCode:  400,
-			wantContentBody: "xslx file has duplicates",
+			wantContentBody: "bad sync mode",
 		},
 		{
-			name:         "unexpected parser error",
-			reqUrl:       "some.url/t",
+			name:         "sheet request",
+			reqBody:      `{"tableURL": "http://some.url/t", "sellerId": 1, "sheet": "Цены"}`,
+			reqUrl:       "http://some.url/t",
 			reqSellerID:  1,
-			tdExpectURL:  "some.url/t",
-			tdReturns:    bytes.NewBufferString("table mock"),
-			tdReturnsErr: nil,
-			tdBehaviour: func(tdm *TableDownloaderMock, expURL string, tdRet io.Reader, tdRetErr error) {
-				tdm.TableMock.Expect(expURL).Return(tdRet, tdRetErr)
+			imReturns:    models.Job{Id: 10, SellerId: 1, TableURL: "http://some.url/t", Status: models.JobQueued, SyncMode: models.SyncModeMerge, Sheet: "Цены", Results: json.RawMessage("null")},
+			imReturnsErr: nil,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{SellerId: expSellerID, TableURL: expURL, SyncMode: models.SyncModeMerge, Sheet: "Цены"}, models.TableAuth{}).Return(imRet, imRetErr)
 			},
-
-			parserExpectTable:  bytes.NewBufferString("table mock"),
-			parserReturns:      nil,
-			parserRetValidErrs: nil,
-			parserReturnsErr:   errors.New("unexpected parser error"),
-			parserBehaviour: func(epm *ExcelParserMock, parserExpectTable io.Reader, pReturns []models.ProductUpdate, pRetValidErrs []error, pRetErr error) {
-				epm.ParseProductsMock.Expect(parserExpectTable).Return(pReturns, pRetValidErrs, pRetErr)
+			wantStatusCode:  202,
+			wantContentBody: `{"id":10,"sellerId":1,"tableURL":"http://some.url/t","status":"queued","dryRun":false,"syncMode":"merge","sheet":"Цены","results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:         "sheets request",
+			reqBody:      `{"tableURL": "http://some.url/t", "sheets": {"Магазин 1": 1, "Магазин 2": 2}}`,
+			reqUrl:       "http://some.url/t",
+			imReturns:    models.Job{Id: 11, TableURL: "http://some.url/t", Status: models.JobQueued, SyncMode: models.SyncModeMerge, Sheets: models.SheetSellers{"Магазин 1": 1, "Магазин 2": 2}, Results: json.RawMessage("null")},
+			imReturnsErr: nil,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{TableURL: expURL, SyncMode: models.SyncModeMerge, Sheets: models.SheetSellers{"Магазин 1": 1, "Магазин 2": 2}}, models.TableAuth{}).Return(imRet, imRetErr)
 			},
-
-			serviceBehaviour: func(sm *ServiceMock, expectedSellerID uint64, expectedUpdates []models.ProductUpdate, serviceReturns service.UpdateResults, serviceRetErr error) {
+			wantStatusCode:  202,
+			wantContentBody: `{"id":11,"sellerId":0,"tableURL":"http://some.url/t","status":"queued","dryRun":false,"syncMode":"merge","sheets":{"Магазин 1":1,"Магазин 2":2},"results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:         "all sheets request",
+			reqBody:      `{"tableURL": "http://some.url/t", "sheets": {"*": 1}}`,
+			reqUrl:       "http://some.url/t",
+			imReturns:    models.Job{Id: 11, TableURL: "http://some.url/t", Status: models.JobQueued, SyncMode: models.SyncModeMerge, Sheets: models.SheetSellers{models.AllSheets: 1}, Results: json.RawMessage("null")},
+			imReturnsErr: nil,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{TableURL: expURL, SyncMode: models.SyncModeMerge, Sheets: models.SheetSellers{models.AllSheets: 1}}, models.TableAuth{}).Return(imRet, imRetErr)
 			},
-			wantStatusCode:  500,
-			wantContentBody: "parsing error",
+			wantStatusCode:  202,
+			wantContentBody: `{"id":11,"sellerId":0,"tableURL":"http://some.url/t","status":"queued","dryRun":false,"syncMode":"merge","sheets":{"*":1},"results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
 		},
 		{
-			name:         "service error",
-			reqUrl:       "some.url/t",
+			name:         "auth request",
+			reqBody:      `{"tableURL": "http://some.url/t", "sellerId": 1, "auth": {"bearerToken": "t0ken", "headers": {"X-Api-Key": "k3y"}}}`,
+			reqUrl:       "http://some.url/t",
 			reqSellerID:  1,
-			tdExpectURL:  "some.url/t",
-			tdReturns:    bytes.NewBufferString("table mock"),
-			tdReturnsErr: nil,
-			tdBehaviour: func(tdm *TableDownloaderMock, expURL string, tdRet io.Reader, tdRetErr error) {
-				tdm.TableMock.Expect(expURL).Return(tdRet, tdRetErr)
+			imReturns:    models.Job{Id: 12, SellerId: 1, TableURL: "http://some.url/t", Status: models.JobQueued, SyncMode: models.SyncModeMerge, Auth: []byte("sealed"), Results: json.RawMessage("null")},
+			imReturnsErr: nil,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{SellerId: expSellerID, TableURL: expURL, SyncMode: models.SyncModeMerge},
+					models.TableAuth{BearerToken: "t0ken", Headers: map[string]string{"X-Api-Key": "k3y"}}).Return(imRet, imRetErr)
 			},
-
-			parserExpectTable:  bytes.NewBufferString("table mock"),
-			parserReturns:      []models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true}, {Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true}},
-			parserRetValidErrs: []error{xlsxparser.ErrProductParsing{Row: 3, Field: "name", ErrMsg: models.MsgTooLongName}, xlsxparser.ErrProductParsing{Row: 4, Field: "price", ErrMsg: (&strconv.NumError{Func: "ParseUint", Num: "0-40", Err: strconv.ErrSyntax}).Error()}},
-			parserReturnsErr:   nil,
-			parserBehaviour: func(epm *ExcelParserMock, parserExpectTable io.Reader, pReturns []models.ProductUpdate, pRetValidErrs []error, pRetErr error) {
-				epm.ParseProductsMock.Expect(parserExpectTable).Return(pReturns, pRetValidErrs, pRetErr)
+			wantStatusCode:  202,
+			wantContentBody: `{"id":12,"sellerId":1,"tableURL":"http://some.url/t","status":"queued","dryRun":false,"syncMode":"merge","results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:    "basic auth and token",
+			reqBody: `{"tableURL": "http://some.url/t", "sellerId": 1, "auth": {"username": "seller", "password": "p@ss", "bearerToken": "t0ken"}}`,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
 			},
-
-			expectedSellerID:  1,
-			expectedUpdates:   []models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true}, {Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true}},
-			serviceReturns:    service.UpdateResults{},
-			serviceReturnsErr: errors.New("repo err"),
-			serviceBehaviour: func(sm *ServiceMock, expectedSellerID uint64, expectedUpdates []models.ProductUpdate, serviceReturns service.UpdateResults, serviceRetErr error) {
-				sm.UpdateProductsMock.Expect(expectedSellerID, expectedUpdates).Return(serviceReturns, serviceRetErr)
+			wantStatusCode:  400,
+			wantContentBody: "bad auth",
+		},
+		{
+			name:    "header injection",
+			reqBody: `{"tableURL": "http://some.url/t", "sellerId": 1, "auth": {"headers": {"X-Api-Key": "k3y\r\nX-Evil: 1"}}}`,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
 			},
-
-			wantStatusCode:  500,
-			wantContentBody: "service error",
+			wantStatusCode:  400,
+			wantContentBody: "bad auth",
 		},
 		{
-			name:         "correct request",
-			reqUrl:       "some.url/t",
+			name:    "query in auth",
+			reqBody: `{"tableURL": "http://some.url/t", "sellerId": 1, "auth": {"query": "token=t0ken"}}`,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: "bad auth",
+		},
+		{
+			name:    "bad header name",
+			reqBody: `{"tableURL": "http://some.url/t", "sellerId": 1, "auth": {"headers": {"X Api Key": "k3y"}}}`,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: "bad auth",
+		},
+		{
+			name:         "credentials disabled",
+			reqBody:      `{"tableURL": "http://some.url/t", "sellerId": 1, "auth": {"bearerToken": "t0ken"}}`,
+			reqUrl:       "http://some.url/t",
 			reqSellerID:  1,
-			tdExpectURL:  "some.url/t",
-			tdReturns:    bytes.NewBufferString("table mock"),
-			tdReturnsErr: nil,
-			tdBehaviour: func(tdm *TableDownloaderMock, expURL string, tdRet io.Reader, tdRetErr error) {
-				tdm.TableMock.Expect(expURL).Return(tdRet, tdRetErr)
+			imReturnsErr: importer.ErrCredentialsDisabled,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
+				imm.EnqueueMock.Expect(models.Job{SellerId: expSellerID, TableURL: expURL, SyncMode: models.SyncModeMerge}, models.TableAuth{BearerToken: "t0ken"}).Return(imRet, imRetErr)
 			},
-
-			parserExpectTable:  bytes.NewBufferString("table mock"),
-			parserReturns:      []models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true}, {Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true}},
-			parserRetValidErrs: []error{xlsxparser.ErrProductParsing{Row: 3, Field: "name", ErrMsg: models.MsgTooLongName}, xlsxparser.ErrProductParsing{Row: 4, Field: "price", ErrMsg: (&strconv.NumError{Func: "ParseUint", Num: "0-40", Err: strconv.ErrSyntax}).Error()}},
-			parserReturnsErr:   nil,
-			parserBehaviour: func(epm *ExcelParserMock, parserExpectTable io.Reader, pReturns []models.ProductUpdate, pRetValidErrs []error, pRetErr error) {
-				epm.ParseProductsMock.Expect(parserExpectTable).Return(pReturns, pRetValidErrs, pRetErr)
+			wantStatusCode:  400,
+			wantContentBody: "credentials are not accepted: no encryption key configured",
+		},
+		{
+			name:    "sheets with seller id",
+			reqBody: `{"tableURL": "http://some.url/t", "sellerId": 1, "sheets": {"Магазин 1": 1}}`,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
 			},
-
-			expectedSellerID:  1,
-			expectedUpdates:   []models.ProductUpdate{{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true}, {Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true}},
-			serviceReturns:    service.UpdateResults{Added: 1, Updated: 1, Deleted: 0, Errors: []error{}},
-			serviceReturnsErr: nil,
-			serviceBehaviour: func(sm *ServiceMock, expectedSellerID uint64, expectedUpdates []models.ProductUpdate, serviceReturns service.UpdateResults, serviceRetErr error) {
-				sm.UpdateProductsMock.Expect(expectedSellerID, expectedUpdates).Return(serviceReturns, serviceRetErr)
+			wantStatusCode:  400,
+			wantContentBody: "bad sheets",
+		},
+		{
+			name:    "sheets with zero seller",
+			reqBody: `{"tableURL": "http://some.url/t", "sheets": {"Магазин 1": 0}}`,
+			imBehaviour: func(imm *ImporterMock, expSellerID uint64, expURL string, imRet models.Job, imRetErr error) {
 			},
-
-			wantStatusCode:  200,
-			wantContentBody: `{"added":1,"updated":1,"deleted":0,"errors":[{"row":3,"field":"name","errMsg":"too long name"},{"row":4,"field":"price","errMsg":"strconv.ParseUint: parsing \"0-40\": invalid syntax"}]}`,
+			wantStatusCode:  400,
+			wantContentBody: "bad sheets",
 		},
 	}
 	for _, tt := range tests {
@@ -334,20 +592,21 @@ func TestHandler_PostTableURL(t *testing.T) {
 			log.Println(tt.name)
 
 			sm := NewServiceMock(t)
-			tdm := NewTableDownloaderMock(t)
-			epm := NewExcelParserMock(t)
-			h := NewRouter(sm, tdm, epm)
-
-			tt.tdBehaviour(tdm, tt.tdExpectURL, tt.tdReturns, tt.tdReturnsErr)
-			tt.parserBehaviour(epm, tt.parserExpectTable, tt.expectedUpdates, tt.parserRetValidErrs, tt.parserReturnsErr)
-			tt.serviceBehaviour(sm, tt.expectedSellerID, tt.expectedUpdates, tt.serviceReturns, tt.serviceReturnsErr)
-
-			body, err := json.Marshal(jsonSchema{
-				TableURL: tt.reqUrl,
-				SellerId: tt.reqSellerID,
-			})
-			if err != nil {
-				assert.FailNow(t, err.Error())
+			imm := NewImporterMock(t)
+			h := NewRouter(config.Config{}, sm, imm, NewSchedulerMock(t))
+
+			tt.imBehaviour(imm, tt.reqSellerID, tt.reqUrl, tt.imReturns, tt.imReturnsErr)
+
+			body := []byte(tt.reqBody)
+			if tt.reqBody == "" {
+				b, err := json.Marshal(jsonSchema{
+					TableURL: tt.reqUrl,
+					SellerId: tt.reqSellerID,
+				})
+				if err != nil {
+					assert.FailNow(t, err.Error())
+				}
+				body = b
 			}
 
 			w := httptest.NewRecorder()
@@ -360,3 +619,1118 @@ func TestHandler_PostTableURL(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_PostTableFile(t *testing.T) {
+
+	// form собирает multipart тело: поля формы и, если filename не пуст, файл в поле file
+	form := func(fields map[string]string, filename string, content string) (*bytes.Buffer, string) {
+		body := &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		for k, v := range fields {
+			mw.WriteField(k, v)
+		}
+		if filename != "" {
+			fw, _ := mw.CreateFormFile("file", filename)
+			fw.Write([]byte(content))
+		}
+		mw.Close()
+
+		return body, mw.FormDataContentType()
+	}
+
+	tests := []struct {
+		name     string
+		fields   map[string]string
+		filename string
+		content  string
+		cfg      config.Config
+
+		imBehaviour func(imm *ImporterMock)
+
+		wantStatusCode  int
+		wantContentBody string
+	}{
+		{
+			name:     "file enqueued",
+			fields:   map[string]string{"sellerId": "42", "dryRun": "true", "mode": "replace"},
+			filename: "price.csv",
+			content:  "offer_id;name;price;quantity",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.EnqueueUploadMock.Set(func(job models.Job, filename string, body io.Reader) (models.Job, error) {
+					assert.Equal(t, models.Job{SellerId: 42, DryRun: true, SyncMode: models.SyncModeReplace}, job)
+					assert.Equal(t, "price.csv", filename)
+					b, _ := io.ReadAll(body)
+					assert.Equal(t, "offer_id;name;price;quantity", string(b))
+
+					job.Id, job.TableURL, job.Status = 9, "upload:///table.csv", models.JobQueued
+					return job, nil
+				})
+			},
+			wantStatusCode:  202,
+			wantContentBody: `{"id":9,"sellerId":42,"tableURL":"upload:///table.csv","status":"queued","dryRun":true,"syncMode":"replace","results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:            "no file",
+			fields:          map[string]string{"sellerId": "42"},
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "file is required",
+		},
+		{
+			name:            "bad seller id",
+			fields:          map[string]string{"sellerId": "сорок два"},
+			filename:        "price.xlsx",
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad seller id",
+		},
+		{
+			name:            "bad dry run",
+			fields:          map[string]string{"sellerId": "42", "dryRun": "maybe"},
+			filename:        "price.xlsx",
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad dryRun",
+		},
+		{
+			name:            "bad sync mode",
+			fields:          map[string]string{"sellerId": "42", "mode": "wipe"},
+			filename:        "price.xlsx",
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad sync mode",
+		},
+		{
+			name:     "sheets without seller id",
+			fields:   map[string]string{"sheets": `{"Магазин 1": 1, "Магазин 2": 2}`},
+			filename: "agency.xlsx",
+			content:  "book",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.EnqueueUploadMock.Set(func(job models.Job, filename string, body io.Reader) (models.Job, error) {
+					assert.Equal(t, models.Job{SyncMode: models.SyncModeMerge, Sheets: models.SheetSellers{"Магазин 1": 1, "Магазин 2": 2}}, job)
+
+					job.Id, job.TableURL, job.Status = 10, "upload:///table.xlsx", models.JobQueued
+					return job, nil
+				})
+			},
+			wantStatusCode:  202,
+			wantContentBody: `{"id":10,"sellerId":0,"tableURL":"upload:///table.xlsx","status":"queued","dryRun":false,"syncMode":"merge","sheets":{"Магазин 1":1,"Магазин 2":2},"results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:     "sheet",
+			fields:   map[string]string{"sellerId": "42", "sheet": "Цены"},
+			filename: "price.xlsx",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.EnqueueUploadMock.Set(func(job models.Job, filename string, body io.Reader) (models.Job, error) {
+					assert.Equal(t, models.Job{SellerId: 42, SyncMode: models.SyncModeMerge, Sheet: "Цены"}, job)
+
+					job.Id, job.Status = 11, models.JobQueued
+					return job, nil
+				})
+			},
+			wantStatusCode:  202,
+			wantContentBody: `{"id":11,"sellerId":42,"tableURL":"","status":"queued","dryRun":false,"syncMode":"merge","sheet":"Цены","results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:            "bad sheets json",
+			fields:          map[string]string{"sheets": "Магазин 1=1"},
+			filename:        "agency.xlsx",
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad sheets",
+		},
+		{
+			name:            "sheets with sheet",
+			fields:          map[string]string{"sheet": "Магазин 1", "sheets": `{"Магазин 1": 1}`},
+			filename:        "agency.xlsx",
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad sheets",
+		},
+		{
+			name:            "file too large",
+			fields:          map[string]string{"sellerId": "42"},
+			filename:        "price.csv",
+			content:         strings.Repeat("a", 2<<20),
+			cfg:             config.Config{Server: config.Server{MaxUploadSizeMB: 1}},
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  413,
+			wantContentBody: "file too large",
+		},
+		{
+			name:     "importer error",
+			fields:   map[string]string{"sellerId": "42"},
+			filename: "price.xlsx",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.EnqueueUploadMock.Return(models.Job{}, importer.ErrUploadFailed)
+			},
+			wantStatusCode:  500,
+			wantContentBody: "service error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			sm := NewServiceMock(t)
+			imm := NewImporterMock(t)
+			h := NewRouter(tt.cfg, sm, imm, NewSchedulerMock(t))
+
+			tt.imBehaviour(imm)
+
+			body, contentType := form(tt.fields, tt.filename, tt.content)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", body)
+			r.Header.Set("Content-Type", contentType)
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode, "status code")
+			assert.Equal(t, tt.wantContentBody, w.Body.String(), "response body")
+		})
+	}
+}
+
+func TestHandler_GetJob(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		pJobID string
+
+		imExpectID   uint64
+		imReturns    models.Job
+		imReturnsErr error
+		imBehaviour  func(imm *ImporterMock, expID uint64, imRet models.Job, imRetErr error)
+
+		wantStatusCode  int
+		wantContentBody string
+	}{
+		{
+			name:   "bad job id",
+			pJobID: "seven",
+			imBehaviour: func(imm *ImporterMock, expID uint64, imRet models.Job, imRetErr error) {
+			},
+			wantStatusCode:  400,
+			wantContentBody: "bad job id",
+		},
+		{
+			name:         "job not found",
+			pJobID:       "7",
+			imExpectID:   7,
+			imReturnsErr: importer.ErrJobNotFound,
+			imBehaviour: func(imm *ImporterMock, expID uint64, imRet models.Job, imRetErr error) {
+				imm.JobMock.Expect(expID).Return(imRet, imRetErr)
+			},
+			wantStatusCode:  404,
+			wantContentBody: "job not found",
+		},
+		{
+			name:         "importer error",
+			pJobID:       "7",
+			imExpectID:   7,
+			imReturnsErr: importer.ErrRepoFailed,
+			imBehaviour: func(imm *ImporterMock, expID uint64, imRet models.Job, imRetErr error) {
+				imm.JobMock.Expect(expID).Return(imRet, imRetErr)
+			},
+			wantStatusCode:  500,
+			wantContentBody: "failed to fetch job",
+		},
+		{
+			name:       "done job",
+			pJobID:     "7",
+			imExpectID: 7,
+			imReturns: models.Job{
+				Id:       7,
+				SellerId: 1,
+				TableURL: "http://some.url/t",
+				Status:   models.JobDone,
+				Results:  json.RawMessage(`{"added":1,"updated":1,"deleted":0,"issues":[]}`),
+			},
+			imBehaviour: func(imm *ImporterMock, expID uint64, imRet models.Job, imRetErr error) {
+				imm.JobMock.Expect(expID).Return(imRet, imRetErr)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"id":7,"sellerId":1,"tableURL":"http://some.url/t","status":"done","dryRun":false,"syncMode":"","results":{"added":1,"updated":1,"deleted":0,"issues":[]},"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:       "signed url is redacted",
+			pJobID:     "7",
+			imExpectID: 7,
+			imReturns: models.Job{
+				Id:       7,
+				SellerId: 1,
+				TableURL: "https://some.url/t.xlsx?X-Amz-Signature=s1gnature",
+				Status:   models.JobQueued,
+				Results:  json.RawMessage("null"),
+			},
+			imBehaviour: func(imm *ImporterMock, expID uint64, imRet models.Job, imRetErr error) {
+				imm.JobMock.Expect(expID).Return(imRet, imRetErr)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"id":7,"sellerId":1,"tableURL":"https://some.url/t.xlsx?X-Amz-Signature=xxxxx","status":"queued","dryRun":false,"syncMode":"","results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:       "failed job",
+			pJobID:     "8",
+			imExpectID: 8,
+			imReturns: models.Job{
+				Id:       8,
+				SellerId: 1,
+				TableURL: "http://some.url/t",
+				Status:   models.JobFailed,
+				Results:  json.RawMessage("null"),
+				Error:    "bad table url",
+			},
+			imBehaviour: func(imm *ImporterMock, expID uint64, imRet models.Job, imRetErr error) {
+				imm.JobMock.Expect(expID).Return(imRet, imRetErr)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"id":8,"sellerId":1,"tableURL":"http://some.url/t","status":"failed","dryRun":false,"syncMode":"","results":null,"error":"bad table url","createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:       "job with error report",
+			pJobID:     "9",
+			imExpectID: 9,
+			imReturns: models.Job{
+				Id:        9,
+				SellerId:  1,
+				TableURL:  "http://some.url/t",
+				Status:    models.JobDone,
+				Results:   json.RawMessage(`{"added":1,"updated":0,"deleted":0,"issues":[{"row":3,"offerId":3,"field":"name","code":"too_long_name","severity":"error","message":"too long name"}]}`),
+				HasReport: true,
+			},
+			imBehaviour: func(imm *ImporterMock, expID uint64, imRet models.Job, imRetErr error) {
+				imm.JobMock.Expect(expID).Return(imRet, imRetErr)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"id":9,"sellerId":1,"tableURL":"http://some.url/t","status":"done","dryRun":false,"syncMode":"","results":{"added":1,"updated":0,"deleted":0,"issues":[{"row":3,"offerId":3,"field":"name","code":"too_long_name","severity":"error","message":"too long name"}]},"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","reportURL":"/jobs/9/report.xlsx"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			sm := NewServiceMock(t)
+			imm := NewImporterMock(t)
+			h := NewRouter(config.Config{}, sm, imm, NewSchedulerMock(t))
+
+			tt.imBehaviour(imm, tt.imExpectID, tt.imReturns, tt.imReturnsErr)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.pJobID, nil)
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode, "status code")
+			assert.Equal(t, tt.wantContentBody, w.Body.String(), "response body")
+		})
+	}
+}
+
+func TestHandler_GetJobReport(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		pJobID      string
+		imBehaviour func(imm *ImporterMock)
+
+		wantStatusCode  int
+		wantContentType string
+		wantContentBody string
+	}{
+		{
+			name:            "bad job id",
+			pJobID:          "seven",
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad job id",
+		},
+		{
+			name:   "job not found",
+			pJobID: "7",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.ReportMock.Expect(7).Return(nil, importer.ErrJobNotFound)
+			},
+			wantStatusCode:  404,
+			wantContentBody: "job not found",
+		},
+		{
+			name:   "report not found",
+			pJobID: "7",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.ReportMock.Expect(7).Return(nil, importer.ErrReportNotFound)
+			},
+			wantStatusCode:  404,
+			wantContentBody: "report not found",
+		},
+		{
+			name:   "importer error",
+			pJobID: "7",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.ReportMock.Expect(7).Return(nil, importer.ErrRepoFailed)
+			},
+			wantStatusCode:  500,
+			wantContentBody: "failed to fetch report",
+		},
+		{
+			name:   "report",
+			pJobID: "7",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.ReportMock.Expect(7).Return([]byte("xlsx report"), nil)
+			},
+			wantStatusCode:  200,
+			wantContentType: xlsxContentType,
+			wantContentBody: "xlsx report",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			sm := NewServiceMock(t)
+			imm := NewImporterMock(t)
+			h := NewRouter(config.Config{}, sm, imm, NewSchedulerMock(t))
+
+			tt.imBehaviour(imm)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.pJobID+"/report.xlsx", nil)
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode, "status code")
+			assert.Equal(t, tt.wantContentType, w.Result().Header.Get("Content-Type"), "content type")
+			assert.Equal(t, tt.wantContentBody, w.Body.String(), "response body")
+		})
+	}
+}
+
+func TestHandler_Product(t *testing.T) {
+
+	product := models.Product{SellerId: 42, OfferId: 2, Name: "name2", Price: 20, Quantity: 2}
+	source := models.ChangeSource{Kind: models.ChangeKindAPI, RequestId: "req-1"}
+	price := uint64(25)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+
+		smBehaviour func(sm *ServiceMock)
+
+		wantStatusCode  int
+		wantContentBody string
+	}{
+		{
+			name:            "bad seller id",
+			method:          http.MethodGet,
+			path:            "/sellers/one/offers/2",
+			smBehaviour:     func(sm *ServiceMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad seller id",
+		},
+		{
+			name:            "bad offer id",
+			method:          http.MethodDelete,
+			path:            "/sellers/42/offers/two",
+			smBehaviour:     func(sm *ServiceMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad offer id",
+		},
+		{
+			name:   "get product",
+			method: http.MethodGet,
+			path:   "/sellers/42/offers/2",
+			smBehaviour: func(sm *ServiceMock) {
+				sm.ProductMock.Expect(42, 2).Return(product, nil)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"sellerId":42,"offerId":2,"name":"name2","price":20,"quantity":2}`,
+		},
+		{
+			name:   "get missing product",
+			method: http.MethodGet,
+			path:   "/sellers/42/offers/2",
+			smBehaviour: func(sm *ServiceMock) {
+				sm.ProductMock.Expect(42, 2).Return(models.Product{}, service.ErrProductNotFound)
+			},
+			wantStatusCode:  404,
+			wantContentBody: "product not found",
+		},
+		{
+			name:   "put creates product",
+			method: http.MethodPut,
+			path:   "/sellers/42/offers/2",
+			body:   `{"name":"name2","price":20,"quantity":2}`,
+			smBehaviour: func(sm *ServiceMock) {
+				sm.PutProductMock.Expect(product, source).Return(true, nil)
+			},
+			wantStatusCode:  201,
+			wantContentBody: `{"sellerId":42,"offerId":2,"name":"name2","price":20,"quantity":2}`,
+		},
+		{
+			name:   "put replaces product",
+			method: http.MethodPut,
+			path:   "/sellers/42/offers/2",
+			body:   `{"name":"name2","price":20,"quantity":2}`,
+			smBehaviour: func(sm *ServiceMock) {
+				sm.PutProductMock.Expect(product, source).Return(false, nil)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"sellerId":42,"offerId":2,"name":"name2","price":20,"quantity":2}`,
+		},
+		{
+			name:            "put without quantity",
+			method:          http.MethodPut,
+			path:            "/sellers/42/offers/2",
+			body:            `{"name":"name2","price":20}`,
+			smBehaviour:     func(sm *ServiceMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "name, price and quantity are required",
+		},
+		{
+			name:            "put bad json",
+			method:          http.MethodPut,
+			path:            "/sellers/42/offers/2",
+			body:            `{"name":"name2","price":-20,"quantity":2}`,
+			smBehaviour:     func(sm *ServiceMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad json",
+		},
+		{
+			name:   "put invalid product",
+			method: http.MethodPut,
+			path:   "/sellers/42/offers/2",
+			body:   `{"name":"name2","price":20,"quantity":2}`,
+			smBehaviour: func(sm *ServiceMock) {
+				sm.PutProductMock.Expect(product, source).
+					Return(false, models.ImportIssue{OfferId: 2, Field: "name", Code: models.CodeTooLongName, Severity: models.SeverityError, Message: models.MsgTooLongName})
+			},
+			wantStatusCode:  400,
+			wantContentBody: `{"offerId":2,"field":"name","code":"too_long_name","severity":"error","message":"too long name"}`,
+		},
+		{
+			name:   "patch price",
+			method: http.MethodPatch,
+			path:   "/sellers/42/offers/2",
+			body:   `{"price":25}`,
+			smBehaviour: func(sm *ServiceMock) {
+				sm.PatchProductMock.Expect(42, 2, service.ProductPatch{Price: &price}, source).
+					Return(models.Product{SellerId: 42, OfferId: 2, Name: "name2", Price: 25, Quantity: 2}, nil)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"sellerId":42,"offerId":2,"name":"name2","price":25,"quantity":2}`,
+		},
+		{
+			name:   "patch missing product",
+			method: http.MethodPatch,
+			path:   "/sellers/42/offers/2",
+			body:   `{"price":25}`,
+			smBehaviour: func(sm *ServiceMock) {
+				sm.PatchProductMock.Expect(42, 2, service.ProductPatch{Price: &price}, source).
+					Return(models.Product{}, service.ErrProductNotFound)
+			},
+			wantStatusCode:  404,
+			wantContentBody: "product not found",
+		},
+		{
+			name:   "delete product",
+			method: http.MethodDelete,
+			path:   "/sellers/42/offers/2",
+			smBehaviour: func(sm *ServiceMock) {
+				sm.DeleteProductMock.Expect(42, 2, source).Return(nil)
+			},
+			wantStatusCode:  204,
+			wantContentBody: "",
+		},
+		{
+			name:   "delete service error",
+			method: http.MethodDelete,
+			path:   "/sellers/42/offers/2",
+			smBehaviour: func(sm *ServiceMock) {
+				sm.DeleteProductMock.Expect(42, 2, source).Return(service.ErrRepoFailed)
+			},
+			wantStatusCode:  500,
+			wantContentBody: "service error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			sm := NewServiceMock(t)
+			imm := NewImporterMock(t)
+			h := NewRouter(config.Config{}, sm, imm, NewSchedulerMock(t))
+
+			tt.smBehaviour(sm)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			r.Header.Set("X-Request-ID", "req-1")
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode, "status code")
+			assert.Equal(t, tt.wantContentBody, w.Body.String(), "response body")
+		})
+	}
+}
+
+func TestHandler_ProductRequestId(t *testing.T) {
+
+	sm := NewServiceMock(t)
+	h := NewRouter(config.Config{}, sm, NewImporterMock(t), NewSchedulerMock(t))
+
+	var gotSource models.ChangeSource
+	sm.DeleteProductMock.Set(func(sellerId uint64, offerId uint64, source models.ChangeSource) error {
+		gotSource = source
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/sellers/42/offers/2", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+	assert.Equal(t, models.ChangeKindAPI, gotSource.Kind)
+	assert.NotEmpty(t, gotSource.RequestId, "request id is generated")
+	assert.Equal(t, gotSource.RequestId, w.Header().Get("X-Request-ID"), "request id is returned to client")
+}
+
+func TestHandler_ExportProducts(t *testing.T) {
+
+	t.Run("bad seller id", func(t *testing.T) {
+		h := NewRouter(config.Config{}, NewServiceMock(t), NewImporterMock(t), NewSchedulerMock(t))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sellers/one/export.xlsx", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		assert.Equal(t, "bad seller id", w.Body.String())
+	})
+
+	t.Run("выгрузку можно загрузить обратно", func(t *testing.T) {
+		sm := NewServiceMock(t)
+		h := NewRouter(config.Config{}, sm, NewImporterMock(t), NewSchedulerMock(t))
+
+		sm.ExportProductsMock.Set(func(sellerId uint64, handle func(products []models.Product) error) error {
+			assert.Equal(t, uint64(42), sellerId)
+			if err := handle([]models.Product{{SellerId: 42, OfferId: 1, Name: "name1", Price: 10, Quantity: 1}}); err != nil {
+				return err
+			}
+
+			return handle([]models.Product{{SellerId: 42, OfferId: 2, Name: "name2", Price: 20, Quantity: 0}})
+		})
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sellers/42/export.xlsx", nil))
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="seller-42.xlsx"`, w.Header().Get("Content-Disposition"))
+
+		var productUpdates []models.ProductUpdate
+		err := xlsxparser.NewParser(config.Config{}).StreamProducts(bytes.NewReader(w.Body.Bytes()), "", 10, func(batch []models.ProductUpdate, issues []models.ImportIssue, last bool) error {
+			assert.Empty(t, issues)
+			productUpdates = append(productUpdates, batch...)
+
+			return nil
+		})
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, []models.ProductUpdate{
+			{Product: models.Product{OfferId: 1, Name: "name1", Price: 10, Quantity: 1}, Available: true, Row: 2},
+			{Product: models.Product{OfferId: 2, Name: "name2", Price: 20, Quantity: 0}, Available: true, Row: 3},
+		}, productUpdates)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		sm := NewServiceMock(t)
+		h := NewRouter(config.Config{}, sm, NewImporterMock(t), NewSchedulerMock(t))
+
+		sm.ExportProductsMock.Return(service.ErrRepoFailed)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sellers/42/export.xlsx", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		assert.Equal(t, "failed to export products", w.Body.String())
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+	})
+}
+
+func TestHandler_RevertImport(t *testing.T) {
+
+	tests := []struct {
+		name string
+		path string
+
+		imBehaviour func(imm *ImporterMock)
+
+		wantStatusCode  int
+		wantContentBody string
+	}{
+		{
+			name:            "bad import id",
+			path:            "/imports/seven/revert",
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad import id",
+		},
+		{
+			name: "import not found",
+			path: "/imports/7/revert",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.RevertMock.Expect(7).Return(models.RevertResults{}, importer.ErrJobNotFound)
+			},
+			wantStatusCode:  404,
+			wantContentBody: "import not found",
+		},
+		{
+			name: "import not finished",
+			path: "/imports/7/revert",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.RevertMock.Expect(7).Return(models.RevertResults{}, importer.ErrJobNotFinished)
+			},
+			wantStatusCode:  409,
+			wantContentBody: "job is not finished",
+		},
+		{
+			name: "already reverted",
+			path: "/imports/7/revert",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.RevertMock.Expect(7).Return(models.RevertResults{}, importer.ErrAlreadyReverted)
+			},
+			wantStatusCode:  409,
+			wantContentBody: "job already reverted",
+		},
+		{
+			name: "products changed after import",
+			path: "/imports/7/revert",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.RevertMock.Expect(7).Return(models.RevertResults{}, importer.ErrRevertConflict)
+			},
+			wantStatusCode:  409,
+			wantContentBody: "products changed after job",
+		},
+		{
+			name: "importer error",
+			path: "/imports/7/revert",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.RevertMock.Expect(7).Return(models.RevertResults{}, importer.ErrRepoFailed)
+			},
+			wantStatusCode:  500,
+			wantContentBody: "failed to revert import",
+		},
+		{
+			name: "reverted",
+			path: "/imports/7/revert",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.RevertMock.Expect(7).Return(models.RevertResults{Restored: 2, Removed: 1}, nil)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `{"restored":2,"removed":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			sm := NewServiceMock(t)
+			imm := NewImporterMock(t)
+			h := NewRouter(config.Config{}, sm, imm, NewSchedulerMock(t))
+
+			tt.imBehaviour(imm)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tt.path, nil)
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode, "status code")
+			assert.Equal(t, tt.wantContentBody, w.Body.String(), "response body")
+		})
+	}
+}
+
+func TestHandler_ConfirmImport(t *testing.T) {
+
+	tests := []struct {
+		name string
+		path string
+
+		imBehaviour func(imm *ImporterMock)
+
+		wantStatusCode  int
+		wantLocation    string
+		wantContentBody string
+	}{
+		{
+			name:            "bad import id",
+			path:            "/imports/seven/confirm",
+			imBehaviour:     func(imm *ImporterMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad import id",
+		},
+		{
+			name: "import not found",
+			path: "/imports/7/confirm",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.ConfirmMock.Expect(7).Return(models.Job{}, importer.ErrJobNotFound)
+			},
+			wantStatusCode:  404,
+			wantContentBody: "import not found",
+		},
+		{
+			name: "import is not held",
+			path: "/imports/7/confirm",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.ConfirmMock.Expect(7).Return(models.Job{}, importer.ErrJobNotHeld)
+			},
+			wantStatusCode:  409,
+			wantContentBody: "job is not held",
+		},
+		{
+			name: "importer error",
+			path: "/imports/7/confirm",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.ConfirmMock.Expect(7).Return(models.Job{}, importer.ErrRepoFailed)
+			},
+			wantStatusCode:  500,
+			wantContentBody: "failed to confirm import",
+		},
+		{
+			name: "confirmed",
+			path: "/imports/7/confirm",
+			imBehaviour: func(imm *ImporterMock) {
+				imm.ConfirmMock.Expect(7).Return(models.Job{
+					Id:        7,
+					SellerId:  1,
+					TableURL:  "http://some.url/t",
+					Status:    models.JobQueued,
+					SyncMode:  models.SyncModeReplace,
+					Results:   []byte("null"),
+					Confirmed: true,
+				}, nil)
+			},
+			wantStatusCode:  202,
+			wantLocation:    "/jobs/7",
+			wantContentBody: `{"id":7,"sellerId":1,"tableURL":"http://some.url/t","status":"queued","dryRun":false,"syncMode":"replace","results":null,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","confirmed":true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			sm := NewServiceMock(t)
+			imm := NewImporterMock(t)
+			h := NewRouter(config.Config{}, sm, imm, NewSchedulerMock(t))
+
+			tt.imBehaviour(imm)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tt.path, nil)
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode, "status code")
+			assert.Equal(t, tt.wantLocation, w.Header().Get("Location"), "location")
+			assert.Equal(t, tt.wantContentBody, w.Body.String(), "response body")
+		})
+	}
+}
+
+func TestHandler_GetProductHistory(t *testing.T) {
+
+	changedAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		path string
+
+		smBehaviour func(sm *ServiceMock)
+
+		wantStatusCode  int
+		wantContentBody string
+	}{
+		{
+			name:            "bad seller id",
+			path:            "/sellers/one/offers/1/history",
+			smBehaviour:     func(sm *ServiceMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad seller id",
+		},
+		{
+			name:            "bad offer id",
+			path:            "/sellers/1/offers/-1/history",
+			smBehaviour:     func(sm *ServiceMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad offer id",
+		},
+		{
+			name:            "bad limit",
+			path:            "/sellers/1/offers/1/history?limit=0",
+			smBehaviour:     func(sm *ServiceMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad limit",
+		},
+		{
+			name: "service error",
+			path: "/sellers/1/offers/1/history",
+			smBehaviour: func(sm *ServiceMock) {
+				sm.ProductHistoryMock.Expect(1, 1, 0).Return(nil, errors.New("repo err"))
+			},
+			wantStatusCode:  500,
+			wantContentBody: "failed to fetch product history",
+		},
+		{
+			name: "history",
+			path: "/sellers/42/offers/1/history?limit=10",
+			smBehaviour: func(sm *ServiceMock) {
+				sm.ProductHistoryMock.Expect(42, 1, 10).Return([]models.ProductChange{
+					{
+						Id: 2, SellerId: 42, OfferId: 1, Action: models.ChangeUpdate, Source: models.ChangeKindImport, JobId: 5, ChangedAt: changedAt,
+						Old: &models.Product{SellerId: 42, OfferId: 1, Name: "name1", Price: 10, Quantity: 1},
+						New: &models.Product{SellerId: 42, OfferId: 1, Name: "name1", Price: 20, Quantity: 1},
+					},
+					{
+						Id: 1, SellerId: 42, OfferId: 1, Action: models.ChangeInsert, Source: models.ChangeKindImport, JobId: 4, ChangedAt: changedAt,
+						New: &models.Product{SellerId: 42, OfferId: 1, Name: "name1", Price: 10, Quantity: 1},
+					},
+				}, nil)
+			},
+			wantStatusCode: 200,
+			wantContentBody: `[{"id":2,"sellerId":42,"offerId":1,"action":"update",` +
+				`"old":{"sellerId":42,"offerId":1,"name":"name1","price":10,"quantity":1},` +
+				`"new":{"sellerId":42,"offerId":1,"name":"name1","price":20,"quantity":1},` +
+				`"source":"import","jobId":5,"changedAt":"2023-08-01T12:00:00Z"},` +
+				`{"id":1,"sellerId":42,"offerId":1,"action":"insert",` +
+				`"new":{"sellerId":42,"offerId":1,"name":"name1","price":10,"quantity":1},` +
+				`"source":"import","jobId":4,"changedAt":"2023-08-01T12:00:00Z"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			sm := NewServiceMock(t)
+			imm := NewImporterMock(t)
+			h := NewRouter(config.Config{}, sm, imm, NewSchedulerMock(t))
+
+			tt.smBehaviour(sm)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode, "status code")
+			assert.Equal(t, tt.wantContentBody, w.Body.String(), "response body")
+		})
+	}
+}
+
+func uint64Ptr(u uint64) *uint64 {
+	return &u
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestHandler_Feeds(t *testing.T) {
+
+	createdAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	jobId := uint64(9)
+	feed := models.Feed{
+		Id:              3,
+		SellerId:        42,
+		TableURL:        "https://some.url/t",
+		IntervalMinutes: 60,
+		SyncMode:        models.SyncModeReplace,
+		NextRunAt:       createdAt,
+		CreatedAt:       createdAt,
+	}
+	feedJSON := `{"id":3,"sellerId":42,"tableURL":"https://some.url/t","intervalMinutes":60,"syncMode":"replace","nextRunAt":"2023-08-01T12:00:00Z","createdAt":"2023-08-01T12:00:00Z"}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+
+		fsBehaviour func(fsm *SchedulerMock)
+
+		wantStatusCode  int
+		wantContentBody string
+	}{
+		{
+			name:   "feed created",
+			method: http.MethodPost,
+			path:   "/sellers/42/feeds",
+			body:   `{"tableURL":"https://some.url/t","intervalMinutes":60,"mode":"replace"}`,
+			fsBehaviour: func(fsm *SchedulerMock) {
+				fsm.CreateFeedMock.Expect(models.Feed{SellerId: 42, TableURL: "https://some.url/t", IntervalMinutes: 60, SyncMode: models.SyncModeReplace}, models.TableAuth{}).
+					Return(feed, nil)
+			},
+			wantStatusCode:  201,
+			wantContentBody: feedJSON,
+		},
+		{
+			name:   "feed with credentials",
+			method: http.MethodPost,
+			path:   "/sellers/42/feeds",
+			body:   `{"tableURL":"https://some.url/t?token=t0ken","intervalMinutes":60,"mode":"replace","auth":{"headers":{"X-Api-Key":"k3y"}}}`,
+			fsBehaviour: func(fsm *SchedulerMock) {
+				fsm.CreateFeedMock.Expect(
+					models.Feed{SellerId: 42, TableURL: "https://some.url/t?token=t0ken", IntervalMinutes: 60, SyncMode: models.SyncModeReplace},
+					models.TableAuth{Headers: map[string]string{"X-Api-Key": "k3y"}},
+				).Return(models.Feed{Id: 3, SellerId: 42, TableURL: "https://some.url/t?token=t0ken", IntervalMinutes: 60, SyncMode: models.SyncModeReplace, Auth: []byte("sealed")}, nil)
+			},
+			wantStatusCode: 201,
+			// без ключа шифрования параметры остаются в ссылке фида, но наружу не отдаются
+			wantContentBody: `{"id":3,"sellerId":42,"tableURL":"https://some.url/t?token=xxxxx","intervalMinutes":60,"syncMode":"replace","nextRunAt":"0001-01-01T00:00:00Z","createdAt":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:            "bad auth",
+			method:          http.MethodPost,
+			path:            "/sellers/42/feeds",
+			body:            `{"tableURL":"https://some.url/t","intervalMinutes":60,"auth":{"query":"token=t0ken"}}`,
+			fsBehaviour:     func(fsm *SchedulerMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad auth",
+		},
+		{
+			name:   "credentials disabled",
+			method: http.MethodPost,
+			path:   "/sellers/42/feeds",
+			body:   `{"tableURL":"https://some.url/t","intervalMinutes":60,"auth":{"bearerToken":"t0ken"}}`,
+			fsBehaviour: func(fsm *SchedulerMock) {
+				fsm.CreateFeedMock.Return(models.Feed{}, importer.ErrCredentialsDisabled)
+			},
+			wantStatusCode:  400,
+			wantContentBody: "credentials are not accepted: no encryption key configured",
+		},
+		{
+			name:            "bad seller id",
+			method:          http.MethodPost,
+			path:            "/sellers/x/feeds",
+			body:            `{"tableURL":"https://some.url/t","intervalMinutes":60}`,
+			fsBehaviour:     func(fsm *SchedulerMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad seller id",
+		},
+		{
+			name:            "bad table url",
+			method:          http.MethodPost,
+			path:            "/sellers/42/feeds",
+			body:            `{"tableURL":"some url","intervalMinutes":60}`,
+			fsBehaviour:     func(fsm *SchedulerMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad table url",
+		},
+		{
+			name:            "bad sync mode",
+			method:          http.MethodPost,
+			path:            "/sellers/42/feeds",
+			body:            `{"tableURL":"https://some.url/t","intervalMinutes":60,"mode":"sometimes"}`,
+			fsBehaviour:     func(fsm *SchedulerMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad sync mode",
+		},
+		{
+			name:   "interval too short",
+			method: http.MethodPost,
+			path:   "/sellers/42/feeds",
+			body:   `{"tableURL":"https://some.url/t","intervalMinutes":1}`,
+			fsBehaviour: func(fsm *SchedulerMock) {
+				fsm.CreateFeedMock.Return(models.Feed{}, fmt.Errorf("%w: minimum is 15 minutes", scheduler.ErrIntervalTooShort))
+			},
+			wantStatusCode:  400,
+			wantContentBody: "feed interval too short: minimum is 15 minutes",
+		},
+		{
+			name:   "url not allowed",
+			method: http.MethodPost,
+			path:   "/sellers/42/feeds",
+			body:   `{"tableURL":"http://localhost/t","intervalMinutes":60}`,
+			fsBehaviour: func(fsm *SchedulerMock) {
+				fsm.CreateFeedMock.Return(models.Feed{}, fmt.Errorf("%w: host localhost resolves to 127.0.0.1, a loopback address", gateway.ErrForbiddenURL))
+			},
+			wantStatusCode:  400,
+			wantContentBody: "table url not allowed: host localhost resolves to 127.0.0.1, a loopback address",
+		},
+		{
+			name:   "scheduler error",
+			method: http.MethodPost,
+			path:   "/sellers/42/feeds",
+			body:   `{"tableURL":"https://some.url/t","intervalMinutes":60}`,
+			fsBehaviour: func(fsm *SchedulerMock) {
+				fsm.CreateFeedMock.Return(models.Feed{}, scheduler.ErrRepoFailed)
+			},
+			wantStatusCode:  500,
+			wantContentBody: "failed to create feed",
+		},
+		{
+			name:   "feeds with last run",
+			method: http.MethodGet,
+			path:   "/sellers/42/feeds",
+			fsBehaviour: func(fsm *SchedulerMock) {
+				ran := feed
+				ran.LastRunAt, ran.LastJobId, ran.LastStatus, ran.LastError = &createdAt, &jobId, models.JobFailed, "table not found"
+				fsm.FeedsMock.Expect(42).Return([]models.Feed{ran}, nil)
+			},
+			wantStatusCode: 200,
+			wantContentBody: `[{"id":3,"sellerId":42,"tableURL":"https://some.url/t","intervalMinutes":60,"syncMode":"replace","nextRunAt":"2023-08-01T12:00:00Z","createdAt":"2023-08-01T12:00:00Z",` +
+				`"lastRunAt":"2023-08-01T12:00:00Z","lastJobId":9,"lastStatus":"failed","lastError":"table not found"}]`,
+		},
+		{
+			name:   "no feeds",
+			method: http.MethodGet,
+			path:   "/sellers/42/feeds",
+			fsBehaviour: func(fsm *SchedulerMock) {
+				fsm.FeedsMock.Expect(42).Return([]models.Feed{}, nil)
+			},
+			wantStatusCode:  200,
+			wantContentBody: `[]`,
+		},
+		{
+			name:   "feed deleted",
+			method: http.MethodDelete,
+			path:   "/sellers/42/feeds/3",
+			fsBehaviour: func(fsm *SchedulerMock) {
+				fsm.DeleteFeedMock.Expect(42, 3).Return(nil)
+			},
+			wantStatusCode: 204,
+		},
+		{
+			name:            "bad feed id",
+			method:          http.MethodDelete,
+			path:            "/sellers/42/feeds/x",
+			fsBehaviour:     func(fsm *SchedulerMock) {},
+			wantStatusCode:  400,
+			wantContentBody: "bad feed id",
+		},
+		{
+			name:   "feed not found",
+			method: http.MethodDelete,
+			path:   "/sellers/42/feeds/3",
+			fsBehaviour: func(fsm *SchedulerMock) {
+				fsm.DeleteFeedMock.Expect(42, 3).Return(scheduler.ErrFeedNotFound)
+			},
+			wantStatusCode:  404,
+			wantContentBody: "feed not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			fsm := NewSchedulerMock(t)
+			h := NewRouter(config.Config{}, NewServiceMock(t), NewImporterMock(t), fsm)
+
+			tt.fsBehaviour(fsm)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode, "status code")
+			assert.Equal(t, tt.wantContentBody, w.Body.String(), "response body")
+		})
+	}
+}
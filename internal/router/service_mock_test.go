@@ -18,17 +18,47 @@ import (
 type ServiceMock struct {
 	t minimock.Tester
 
-	funcProductsByFilter          func(filter service.RequestFilter) (pa1 []models.Product, err error)
+	funcDeleteProduct          func(sellerId uint64, offerId uint64, source models.ChangeSource) (err error)
+	inspectFuncDeleteProduct   func(sellerId uint64, offerId uint64, source models.ChangeSource)
+	afterDeleteProductCounter  uint64
+	beforeDeleteProductCounter uint64
+	DeleteProductMock          mServiceMockDeleteProduct
+
+	funcExportProducts          func(sellerId uint64, handle func(products []models.Product) error) (err error)
+	inspectFuncExportProducts   func(sellerId uint64, handle func(products []models.Product) error)
+	afterExportProductsCounter  uint64
+	beforeExportProductsCounter uint64
+	ExportProductsMock          mServiceMockExportProducts
+
+	funcPatchProduct          func(sellerId uint64, offerId uint64, patch service.ProductPatch, source models.ChangeSource) (p1 models.Product, err error)
+	inspectFuncPatchProduct   func(sellerId uint64, offerId uint64, patch service.ProductPatch, source models.ChangeSource)
+	afterPatchProductCounter  uint64
+	beforePatchProductCounter uint64
+	PatchProductMock          mServiceMockPatchProduct
+
+	funcProduct          func(sellerId uint64, offerId uint64) (p1 models.Product, err error)
+	inspectFuncProduct   func(sellerId uint64, offerId uint64)
+	afterProductCounter  uint64
+	beforeProductCounter uint64
+	ProductMock          mServiceMockProduct
+
+	funcProductHistory          func(sellerId uint64, offerId uint64, limit uint64) (pa1 []models.ProductChange, err error)
+	inspectFuncProductHistory   func(sellerId uint64, offerId uint64, limit uint64)
+	afterProductHistoryCounter  uint64
+	beforeProductHistoryCounter uint64
+	ProductHistoryMock          mServiceMockProductHistory
+
+	funcProductsByFilter          func(filter service.RequestFilter) (p1 service.ProductsPage, err error)
 	inspectFuncProductsByFilter   func(filter service.RequestFilter)
 	afterProductsByFilterCounter  uint64
 	beforeProductsByFilterCounter uint64
 	ProductsByFilterMock          mServiceMockProductsByFilter
 
-	funcUpdateProducts          func(sellerId uint64, productUpdates []models.ProductUpdate) (u1 service.UpdateResults, err error)
-	inspectFuncUpdateProducts   func(sellerId uint64, productUpdates []models.ProductUpdate)
-	afterUpdateProductsCounter  uint64
-	beforeUpdateProductsCounter uint64
-	UpdateProductsMock          mServiceMockUpdateProducts
+	funcPutProduct          func(product models.Product, source models.ChangeSource) (created bool, err error)
+	inspectFuncPutProduct   func(product models.Product, source models.ChangeSource)
+	afterPutProductCounter  uint64
+	beforePutProductCounter uint64
+	PutProductMock          mServiceMockPutProduct
 }
 
 // NewServiceMock returns a mock for Service
@@ -38,13 +68,1115 @@ func NewServiceMock(t minimock.Tester) *ServiceMock {
 		controller.RegisterMocker(m)
 	}
 
+	m.DeleteProductMock = mServiceMockDeleteProduct{mock: m}
+	m.DeleteProductMock.callArgs = []*ServiceMockDeleteProductParams{}
+
+	m.ExportProductsMock = mServiceMockExportProducts{mock: m}
+	m.ExportProductsMock.callArgs = []*ServiceMockExportProductsParams{}
+
+	m.PatchProductMock = mServiceMockPatchProduct{mock: m}
+	m.PatchProductMock.callArgs = []*ServiceMockPatchProductParams{}
+
+	m.ProductMock = mServiceMockProduct{mock: m}
+	m.ProductMock.callArgs = []*ServiceMockProductParams{}
+
+	m.ProductHistoryMock = mServiceMockProductHistory{mock: m}
+	m.ProductHistoryMock.callArgs = []*ServiceMockProductHistoryParams{}
+
 	m.ProductsByFilterMock = mServiceMockProductsByFilter{mock: m}
 	m.ProductsByFilterMock.callArgs = []*ServiceMockProductsByFilterParams{}
 
-	m.UpdateProductsMock = mServiceMockUpdateProducts{mock: m}
-	m.UpdateProductsMock.callArgs = []*ServiceMockUpdateProductsParams{}
+	m.PutProductMock = mServiceMockPutProduct{mock: m}
+	m.PutProductMock.callArgs = []*ServiceMockPutProductParams{}
+
+	return m
+}
+
+type mServiceMockDeleteProduct struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockDeleteProductExpectation
+	expectations       []*ServiceMockDeleteProductExpectation
+
+	callArgs []*ServiceMockDeleteProductParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockDeleteProductExpectation specifies expectation struct of the Service.DeleteProduct
+type ServiceMockDeleteProductExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockDeleteProductParams
+	results *ServiceMockDeleteProductResults
+	Counter uint64
+}
+
+// ServiceMockDeleteProductParams contains parameters of the Service.DeleteProduct
+type ServiceMockDeleteProductParams struct {
+	sellerId uint64
+	offerId  uint64
+	source   models.ChangeSource
+}
+
+// ServiceMockDeleteProductResults contains results of the Service.DeleteProduct
+type ServiceMockDeleteProductResults struct {
+	err error
+}
+
+// Expect sets up expected params for Service.DeleteProduct
+func (mmDeleteProduct *mServiceMockDeleteProduct) Expect(sellerId uint64, offerId uint64, source models.ChangeSource) *mServiceMockDeleteProduct {
+	if mmDeleteProduct.mock.funcDeleteProduct != nil {
+		mmDeleteProduct.mock.t.Fatalf("ServiceMock.DeleteProduct mock is already set by Set")
+	}
+
+	if mmDeleteProduct.defaultExpectation == nil {
+		mmDeleteProduct.defaultExpectation = &ServiceMockDeleteProductExpectation{}
+	}
+
+	mmDeleteProduct.defaultExpectation.params = &ServiceMockDeleteProductParams{sellerId, offerId, source}
+	for _, e := range mmDeleteProduct.expectations {
+		if minimock.Equal(e.params, mmDeleteProduct.defaultExpectation.params) {
+			mmDeleteProduct.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmDeleteProduct.defaultExpectation.params)
+		}
+	}
+
+	return mmDeleteProduct
+}
+
+// Inspect accepts an inspector function that has same arguments as the Service.DeleteProduct
+func (mmDeleteProduct *mServiceMockDeleteProduct) Inspect(f func(sellerId uint64, offerId uint64, source models.ChangeSource)) *mServiceMockDeleteProduct {
+	if mmDeleteProduct.mock.inspectFuncDeleteProduct != nil {
+		mmDeleteProduct.mock.t.Fatalf("Inspect function is already set for ServiceMock.DeleteProduct")
+	}
+
+	mmDeleteProduct.mock.inspectFuncDeleteProduct = f
+
+	return mmDeleteProduct
+}
+
+// Return sets up results that will be returned by Service.DeleteProduct
+func (mmDeleteProduct *mServiceMockDeleteProduct) Return(err error) *ServiceMock {
+	if mmDeleteProduct.mock.funcDeleteProduct != nil {
+		mmDeleteProduct.mock.t.Fatalf("ServiceMock.DeleteProduct mock is already set by Set")
+	}
+
+	if mmDeleteProduct.defaultExpectation == nil {
+		mmDeleteProduct.defaultExpectation = &ServiceMockDeleteProductExpectation{mock: mmDeleteProduct.mock}
+	}
+	mmDeleteProduct.defaultExpectation.results = &ServiceMockDeleteProductResults{err}
+	return mmDeleteProduct.mock
+}
+
+// Set uses given function f to mock the Service.DeleteProduct method
+func (mmDeleteProduct *mServiceMockDeleteProduct) Set(f func(sellerId uint64, offerId uint64, source models.ChangeSource) (err error)) *ServiceMock {
+	if mmDeleteProduct.defaultExpectation != nil {
+		mmDeleteProduct.mock.t.Fatalf("Default expectation is already set for the Service.DeleteProduct method")
+	}
+
+	if len(mmDeleteProduct.expectations) > 0 {
+		mmDeleteProduct.mock.t.Fatalf("Some expectations are already set for the Service.DeleteProduct method")
+	}
+
+	mmDeleteProduct.mock.funcDeleteProduct = f
+	return mmDeleteProduct.mock
+}
+
+// When sets expectation for the Service.DeleteProduct which will trigger the result defined by the following
+// Then helper
+func (mmDeleteProduct *mServiceMockDeleteProduct) When(sellerId uint64, offerId uint64, source models.ChangeSource) *ServiceMockDeleteProductExpectation {
+	if mmDeleteProduct.mock.funcDeleteProduct != nil {
+		mmDeleteProduct.mock.t.Fatalf("ServiceMock.DeleteProduct mock is already set by Set")
+	}
+
+	expectation := &ServiceMockDeleteProductExpectation{
+		mock:   mmDeleteProduct.mock,
+		params: &ServiceMockDeleteProductParams{sellerId, offerId, source},
+	}
+	mmDeleteProduct.expectations = append(mmDeleteProduct.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Service.DeleteProduct return parameters for the expectation previously defined by the When method
+func (e *ServiceMockDeleteProductExpectation) Then(err error) *ServiceMock {
+	e.results = &ServiceMockDeleteProductResults{err}
+	return e.mock
+}
+
+// DeleteProduct implements Service
+func (mmDeleteProduct *ServiceMock) DeleteProduct(sellerId uint64, offerId uint64, source models.ChangeSource) (err error) {
+	mm_atomic.AddUint64(&mmDeleteProduct.beforeDeleteProductCounter, 1)
+	defer mm_atomic.AddUint64(&mmDeleteProduct.afterDeleteProductCounter, 1)
+
+	if mmDeleteProduct.inspectFuncDeleteProduct != nil {
+		mmDeleteProduct.inspectFuncDeleteProduct(sellerId, offerId, source)
+	}
+
+	mm_params := &ServiceMockDeleteProductParams{sellerId, offerId, source}
+
+	// Record call args
+	mmDeleteProduct.DeleteProductMock.mutex.Lock()
+	mmDeleteProduct.DeleteProductMock.callArgs = append(mmDeleteProduct.DeleteProductMock.callArgs, mm_params)
+	mmDeleteProduct.DeleteProductMock.mutex.Unlock()
+
+	for _, e := range mmDeleteProduct.DeleteProductMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmDeleteProduct.DeleteProductMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmDeleteProduct.DeleteProductMock.defaultExpectation.Counter, 1)
+		mm_want := mmDeleteProduct.DeleteProductMock.defaultExpectation.params
+		mm_got := ServiceMockDeleteProductParams{sellerId, offerId, source}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmDeleteProduct.t.Errorf("ServiceMock.DeleteProduct got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmDeleteProduct.DeleteProductMock.defaultExpectation.results
+		if mm_results == nil {
+			mmDeleteProduct.t.Fatal("No results are set for the ServiceMock.DeleteProduct")
+		}
+		return (*mm_results).err
+	}
+	if mmDeleteProduct.funcDeleteProduct != nil {
+		return mmDeleteProduct.funcDeleteProduct(sellerId, offerId, source)
+	}
+	mmDeleteProduct.t.Fatalf("Unexpected call to ServiceMock.DeleteProduct. %v %v %v", sellerId, offerId, source)
+	return
+}
+
+// DeleteProductAfterCounter returns a count of finished ServiceMock.DeleteProduct invocations
+func (mmDeleteProduct *ServiceMock) DeleteProductAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDeleteProduct.afterDeleteProductCounter)
+}
+
+// DeleteProductBeforeCounter returns a count of ServiceMock.DeleteProduct invocations
+func (mmDeleteProduct *ServiceMock) DeleteProductBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDeleteProduct.beforeDeleteProductCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.DeleteProduct.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmDeleteProduct *mServiceMockDeleteProduct) Calls() []*ServiceMockDeleteProductParams {
+	mmDeleteProduct.mutex.RLock()
+
+	argCopy := make([]*ServiceMockDeleteProductParams, len(mmDeleteProduct.callArgs))
+	copy(argCopy, mmDeleteProduct.callArgs)
+
+	mmDeleteProduct.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockDeleteProductDone returns true if the count of the DeleteProduct invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockDeleteProductDone() bool {
+	for _, e := range m.DeleteProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.DeleteProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterDeleteProductCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcDeleteProduct != nil && mm_atomic.LoadUint64(&m.afterDeleteProductCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockDeleteProductInspect logs each unmet expectation
+func (m *ServiceMock) MinimockDeleteProductInspect() {
+	for _, e := range m.DeleteProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.DeleteProduct with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.DeleteProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterDeleteProductCounter) < 1 {
+		if m.DeleteProductMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.DeleteProduct")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.DeleteProduct with params: %#v", *m.DeleteProductMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcDeleteProduct != nil && mm_atomic.LoadUint64(&m.afterDeleteProductCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.DeleteProduct")
+	}
+}
+
+type mServiceMockExportProducts struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockExportProductsExpectation
+	expectations       []*ServiceMockExportProductsExpectation
+
+	callArgs []*ServiceMockExportProductsParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockExportProductsExpectation specifies expectation struct of the Service.ExportProducts
+type ServiceMockExportProductsExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockExportProductsParams
+	results *ServiceMockExportProductsResults
+	Counter uint64
+}
+
+// ServiceMockExportProductsParams contains parameters of the Service.ExportProducts
+type ServiceMockExportProductsParams struct {
+	sellerId uint64
+	handle   func(products []models.Product) error
+}
+
+// ServiceMockExportProductsResults contains results of the Service.ExportProducts
+type ServiceMockExportProductsResults struct {
+	err error
+}
+
+// Expect sets up expected params for Service.ExportProducts
+func (mmExportProducts *mServiceMockExportProducts) Expect(sellerId uint64, handle func(products []models.Product) error) *mServiceMockExportProducts {
+	if mmExportProducts.mock.funcExportProducts != nil {
+		mmExportProducts.mock.t.Fatalf("ServiceMock.ExportProducts mock is already set by Set")
+	}
+
+	if mmExportProducts.defaultExpectation == nil {
+		mmExportProducts.defaultExpectation = &ServiceMockExportProductsExpectation{}
+	}
+
+	mmExportProducts.defaultExpectation.params = &ServiceMockExportProductsParams{sellerId, handle}
+	for _, e := range mmExportProducts.expectations {
+		if minimock.Equal(e.params, mmExportProducts.defaultExpectation.params) {
+			mmExportProducts.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmExportProducts.defaultExpectation.params)
+		}
+	}
+
+	return mmExportProducts
+}
+
+// Inspect accepts an inspector function that has same arguments as the Service.ExportProducts
+func (mmExportProducts *mServiceMockExportProducts) Inspect(f func(sellerId uint64, handle func(products []models.Product) error)) *mServiceMockExportProducts {
+	if mmExportProducts.mock.inspectFuncExportProducts != nil {
+		mmExportProducts.mock.t.Fatalf("Inspect function is already set for ServiceMock.ExportProducts")
+	}
+
+	mmExportProducts.mock.inspectFuncExportProducts = f
+
+	return mmExportProducts
+}
+
+// Return sets up results that will be returned by Service.ExportProducts
+func (mmExportProducts *mServiceMockExportProducts) Return(err error) *ServiceMock {
+	if mmExportProducts.mock.funcExportProducts != nil {
+		mmExportProducts.mock.t.Fatalf("ServiceMock.ExportProducts mock is already set by Set")
+	}
+
+	if mmExportProducts.defaultExpectation == nil {
+		mmExportProducts.defaultExpectation = &ServiceMockExportProductsExpectation{mock: mmExportProducts.mock}
+	}
+	mmExportProducts.defaultExpectation.results = &ServiceMockExportProductsResults{err}
+	return mmExportProducts.mock
+}
+
+// Set uses given function f to mock the Service.ExportProducts method
+func (mmExportProducts *mServiceMockExportProducts) Set(f func(sellerId uint64, handle func(products []models.Product) error) (err error)) *ServiceMock {
+	if mmExportProducts.defaultExpectation != nil {
+		mmExportProducts.mock.t.Fatalf("Default expectation is already set for the Service.ExportProducts method")
+	}
+
+	if len(mmExportProducts.expectations) > 0 {
+		mmExportProducts.mock.t.Fatalf("Some expectations are already set for the Service.ExportProducts method")
+	}
+
+	mmExportProducts.mock.funcExportProducts = f
+	return mmExportProducts.mock
+}
+
+// When sets expectation for the Service.ExportProducts which will trigger the result defined by the following
+// Then helper
+func (mmExportProducts *mServiceMockExportProducts) When(sellerId uint64, handle func(products []models.Product) error) *ServiceMockExportProductsExpectation {
+	if mmExportProducts.mock.funcExportProducts != nil {
+		mmExportProducts.mock.t.Fatalf("ServiceMock.ExportProducts mock is already set by Set")
+	}
+
+	expectation := &ServiceMockExportProductsExpectation{
+		mock:   mmExportProducts.mock,
+		params: &ServiceMockExportProductsParams{sellerId, handle},
+	}
+	mmExportProducts.expectations = append(mmExportProducts.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Service.ExportProducts return parameters for the expectation previously defined by the When method
+func (e *ServiceMockExportProductsExpectation) Then(err error) *ServiceMock {
+	e.results = &ServiceMockExportProductsResults{err}
+	return e.mock
+}
+
+// ExportProducts implements Service
+func (mmExportProducts *ServiceMock) ExportProducts(sellerId uint64, handle func(products []models.Product) error) (err error) {
+	mm_atomic.AddUint64(&mmExportProducts.beforeExportProductsCounter, 1)
+	defer mm_atomic.AddUint64(&mmExportProducts.afterExportProductsCounter, 1)
+
+	if mmExportProducts.inspectFuncExportProducts != nil {
+		mmExportProducts.inspectFuncExportProducts(sellerId, handle)
+	}
+
+	mm_params := &ServiceMockExportProductsParams{sellerId, handle}
+
+	// Record call args
+	mmExportProducts.ExportProductsMock.mutex.Lock()
+	mmExportProducts.ExportProductsMock.callArgs = append(mmExportProducts.ExportProductsMock.callArgs, mm_params)
+	mmExportProducts.ExportProductsMock.mutex.Unlock()
+
+	for _, e := range mmExportProducts.ExportProductsMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmExportProducts.ExportProductsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmExportProducts.ExportProductsMock.defaultExpectation.Counter, 1)
+		mm_want := mmExportProducts.ExportProductsMock.defaultExpectation.params
+		mm_got := ServiceMockExportProductsParams{sellerId, handle}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmExportProducts.t.Errorf("ServiceMock.ExportProducts got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmExportProducts.ExportProductsMock.defaultExpectation.results
+		if mm_results == nil {
+			mmExportProducts.t.Fatal("No results are set for the ServiceMock.ExportProducts")
+		}
+		return (*mm_results).err
+	}
+	if mmExportProducts.funcExportProducts != nil {
+		return mmExportProducts.funcExportProducts(sellerId, handle)
+	}
+	mmExportProducts.t.Fatalf("Unexpected call to ServiceMock.ExportProducts. %v %v", sellerId, handle)
+	return
+}
+
+// ExportProductsAfterCounter returns a count of finished ServiceMock.ExportProducts invocations
+func (mmExportProducts *ServiceMock) ExportProductsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmExportProducts.afterExportProductsCounter)
+}
+
+// ExportProductsBeforeCounter returns a count of ServiceMock.ExportProducts invocations
+func (mmExportProducts *ServiceMock) ExportProductsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmExportProducts.beforeExportProductsCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.ExportProducts.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmExportProducts *mServiceMockExportProducts) Calls() []*ServiceMockExportProductsParams {
+	mmExportProducts.mutex.RLock()
+
+	argCopy := make([]*ServiceMockExportProductsParams, len(mmExportProducts.callArgs))
+	copy(argCopy, mmExportProducts.callArgs)
+
+	mmExportProducts.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockExportProductsDone returns true if the count of the ExportProducts invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockExportProductsDone() bool {
+	for _, e := range m.ExportProductsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ExportProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterExportProductsCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcExportProducts != nil && mm_atomic.LoadUint64(&m.afterExportProductsCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockExportProductsInspect logs each unmet expectation
+func (m *ServiceMock) MinimockExportProductsInspect() {
+	for _, e := range m.ExportProductsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.ExportProducts with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ExportProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterExportProductsCounter) < 1 {
+		if m.ExportProductsMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.ExportProducts")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.ExportProducts with params: %#v", *m.ExportProductsMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcExportProducts != nil && mm_atomic.LoadUint64(&m.afterExportProductsCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.ExportProducts")
+	}
+}
+
+type mServiceMockPatchProduct struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockPatchProductExpectation
+	expectations       []*ServiceMockPatchProductExpectation
+
+	callArgs []*ServiceMockPatchProductParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockPatchProductExpectation specifies expectation struct of the Service.PatchProduct
+type ServiceMockPatchProductExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockPatchProductParams
+	results *ServiceMockPatchProductResults
+	Counter uint64
+}
+
+// ServiceMockPatchProductParams contains parameters of the Service.PatchProduct
+type ServiceMockPatchProductParams struct {
+	sellerId uint64
+	offerId  uint64
+	patch    service.ProductPatch
+	source   models.ChangeSource
+}
+
+// ServiceMockPatchProductResults contains results of the Service.PatchProduct
+type ServiceMockPatchProductResults struct {
+	p1  models.Product
+	err error
+}
+
+// Expect sets up expected params for Service.PatchProduct
+func (mmPatchProduct *mServiceMockPatchProduct) Expect(sellerId uint64, offerId uint64, patch service.ProductPatch, source models.ChangeSource) *mServiceMockPatchProduct {
+	if mmPatchProduct.mock.funcPatchProduct != nil {
+		mmPatchProduct.mock.t.Fatalf("ServiceMock.PatchProduct mock is already set by Set")
+	}
+
+	if mmPatchProduct.defaultExpectation == nil {
+		mmPatchProduct.defaultExpectation = &ServiceMockPatchProductExpectation{}
+	}
+
+	mmPatchProduct.defaultExpectation.params = &ServiceMockPatchProductParams{sellerId, offerId, patch, source}
+	for _, e := range mmPatchProduct.expectations {
+		if minimock.Equal(e.params, mmPatchProduct.defaultExpectation.params) {
+			mmPatchProduct.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmPatchProduct.defaultExpectation.params)
+		}
+	}
+
+	return mmPatchProduct
+}
+
+// Inspect accepts an inspector function that has same arguments as the Service.PatchProduct
+func (mmPatchProduct *mServiceMockPatchProduct) Inspect(f func(sellerId uint64, offerId uint64, patch service.ProductPatch, source models.ChangeSource)) *mServiceMockPatchProduct {
+	if mmPatchProduct.mock.inspectFuncPatchProduct != nil {
+		mmPatchProduct.mock.t.Fatalf("Inspect function is already set for ServiceMock.PatchProduct")
+	}
+
+	mmPatchProduct.mock.inspectFuncPatchProduct = f
+
+	return mmPatchProduct
+}
+
+// Return sets up results that will be returned by Service.PatchProduct
+func (mmPatchProduct *mServiceMockPatchProduct) Return(p1 models.Product, err error) *ServiceMock {
+	if mmPatchProduct.mock.funcPatchProduct != nil {
+		mmPatchProduct.mock.t.Fatalf("ServiceMock.PatchProduct mock is already set by Set")
+	}
+
+	if mmPatchProduct.defaultExpectation == nil {
+		mmPatchProduct.defaultExpectation = &ServiceMockPatchProductExpectation{mock: mmPatchProduct.mock}
+	}
+	mmPatchProduct.defaultExpectation.results = &ServiceMockPatchProductResults{p1, err}
+	return mmPatchProduct.mock
+}
+
+// Set uses given function f to mock the Service.PatchProduct method
+func (mmPatchProduct *mServiceMockPatchProduct) Set(f func(sellerId uint64, offerId uint64, patch service.ProductPatch, source models.ChangeSource) (p1 models.Product, err error)) *ServiceMock {
+	if mmPatchProduct.defaultExpectation != nil {
+		mmPatchProduct.mock.t.Fatalf("Default expectation is already set for the Service.PatchProduct method")
+	}
+
+	if len(mmPatchProduct.expectations) > 0 {
+		mmPatchProduct.mock.t.Fatalf("Some expectations are already set for the Service.PatchProduct method")
+	}
+
+	mmPatchProduct.mock.funcPatchProduct = f
+	return mmPatchProduct.mock
+}
+
+// When sets expectation for the Service.PatchProduct which will trigger the result defined by the following
+// Then helper
+func (mmPatchProduct *mServiceMockPatchProduct) When(sellerId uint64, offerId uint64, patch service.ProductPatch, source models.ChangeSource) *ServiceMockPatchProductExpectation {
+	if mmPatchProduct.mock.funcPatchProduct != nil {
+		mmPatchProduct.mock.t.Fatalf("ServiceMock.PatchProduct mock is already set by Set")
+	}
+
+	expectation := &ServiceMockPatchProductExpectation{
+		mock:   mmPatchProduct.mock,
+		params: &ServiceMockPatchProductParams{sellerId, offerId, patch, source},
+	}
+	mmPatchProduct.expectations = append(mmPatchProduct.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Service.PatchProduct return parameters for the expectation previously defined by the When method
+func (e *ServiceMockPatchProductExpectation) Then(p1 models.Product, err error) *ServiceMock {
+	e.results = &ServiceMockPatchProductResults{p1, err}
+	return e.mock
+}
+
+// PatchProduct implements Service
+func (mmPatchProduct *ServiceMock) PatchProduct(sellerId uint64, offerId uint64, patch service.ProductPatch, source models.ChangeSource) (p1 models.Product, err error) {
+	mm_atomic.AddUint64(&mmPatchProduct.beforePatchProductCounter, 1)
+	defer mm_atomic.AddUint64(&mmPatchProduct.afterPatchProductCounter, 1)
+
+	if mmPatchProduct.inspectFuncPatchProduct != nil {
+		mmPatchProduct.inspectFuncPatchProduct(sellerId, offerId, patch, source)
+	}
+
+	mm_params := &ServiceMockPatchProductParams{sellerId, offerId, patch, source}
+
+	// Record call args
+	mmPatchProduct.PatchProductMock.mutex.Lock()
+	mmPatchProduct.PatchProductMock.callArgs = append(mmPatchProduct.PatchProductMock.callArgs, mm_params)
+	mmPatchProduct.PatchProductMock.mutex.Unlock()
+
+	for _, e := range mmPatchProduct.PatchProductMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.p1, e.results.err
+		}
+	}
+
+	if mmPatchProduct.PatchProductMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmPatchProduct.PatchProductMock.defaultExpectation.Counter, 1)
+		mm_want := mmPatchProduct.PatchProductMock.defaultExpectation.params
+		mm_got := ServiceMockPatchProductParams{sellerId, offerId, patch, source}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmPatchProduct.t.Errorf("ServiceMock.PatchProduct got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmPatchProduct.PatchProductMock.defaultExpectation.results
+		if mm_results == nil {
+			mmPatchProduct.t.Fatal("No results are set for the ServiceMock.PatchProduct")
+		}
+		return (*mm_results).p1, (*mm_results).err
+	}
+	if mmPatchProduct.funcPatchProduct != nil {
+		return mmPatchProduct.funcPatchProduct(sellerId, offerId, patch, source)
+	}
+	mmPatchProduct.t.Fatalf("Unexpected call to ServiceMock.PatchProduct. %v %v %v %v", sellerId, offerId, patch, source)
+	return
+}
+
+// PatchProductAfterCounter returns a count of finished ServiceMock.PatchProduct invocations
+func (mmPatchProduct *ServiceMock) PatchProductAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPatchProduct.afterPatchProductCounter)
+}
+
+// PatchProductBeforeCounter returns a count of ServiceMock.PatchProduct invocations
+func (mmPatchProduct *ServiceMock) PatchProductBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPatchProduct.beforePatchProductCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.PatchProduct.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmPatchProduct *mServiceMockPatchProduct) Calls() []*ServiceMockPatchProductParams {
+	mmPatchProduct.mutex.RLock()
+
+	argCopy := make([]*ServiceMockPatchProductParams, len(mmPatchProduct.callArgs))
+	copy(argCopy, mmPatchProduct.callArgs)
 
-	return m
+	mmPatchProduct.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockPatchProductDone returns true if the count of the PatchProduct invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockPatchProductDone() bool {
+	for _, e := range m.PatchProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.PatchProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPatchProductCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcPatchProduct != nil && mm_atomic.LoadUint64(&m.afterPatchProductCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockPatchProductInspect logs each unmet expectation
+func (m *ServiceMock) MinimockPatchProductInspect() {
+	for _, e := range m.PatchProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.PatchProduct with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.PatchProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPatchProductCounter) < 1 {
+		if m.PatchProductMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.PatchProduct")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.PatchProduct with params: %#v", *m.PatchProductMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcPatchProduct != nil && mm_atomic.LoadUint64(&m.afterPatchProductCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.PatchProduct")
+	}
+}
+
+type mServiceMockProduct struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockProductExpectation
+	expectations       []*ServiceMockProductExpectation
+
+	callArgs []*ServiceMockProductParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockProductExpectation specifies expectation struct of the Service.Product
+type ServiceMockProductExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockProductParams
+	results *ServiceMockProductResults
+	Counter uint64
+}
+
+// ServiceMockProductParams contains parameters of the Service.Product
+type ServiceMockProductParams struct {
+	sellerId uint64
+	offerId  uint64
+}
+
+// ServiceMockProductResults contains results of the Service.Product
+type ServiceMockProductResults struct {
+	p1  models.Product
+	err error
+}
+
+// Expect sets up expected params for Service.Product
+func (mmProduct *mServiceMockProduct) Expect(sellerId uint64, offerId uint64) *mServiceMockProduct {
+	if mmProduct.mock.funcProduct != nil {
+		mmProduct.mock.t.Fatalf("ServiceMock.Product mock is already set by Set")
+	}
+
+	if mmProduct.defaultExpectation == nil {
+		mmProduct.defaultExpectation = &ServiceMockProductExpectation{}
+	}
+
+	mmProduct.defaultExpectation.params = &ServiceMockProductParams{sellerId, offerId}
+	for _, e := range mmProduct.expectations {
+		if minimock.Equal(e.params, mmProduct.defaultExpectation.params) {
+			mmProduct.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmProduct.defaultExpectation.params)
+		}
+	}
+
+	return mmProduct
+}
+
+// Inspect accepts an inspector function that has same arguments as the Service.Product
+func (mmProduct *mServiceMockProduct) Inspect(f func(sellerId uint64, offerId uint64)) *mServiceMockProduct {
+	if mmProduct.mock.inspectFuncProduct != nil {
+		mmProduct.mock.t.Fatalf("Inspect function is already set for ServiceMock.Product")
+	}
+
+	mmProduct.mock.inspectFuncProduct = f
+
+	return mmProduct
+}
+
+// Return sets up results that will be returned by Service.Product
+func (mmProduct *mServiceMockProduct) Return(p1 models.Product, err error) *ServiceMock {
+	if mmProduct.mock.funcProduct != nil {
+		mmProduct.mock.t.Fatalf("ServiceMock.Product mock is already set by Set")
+	}
+
+	if mmProduct.defaultExpectation == nil {
+		mmProduct.defaultExpectation = &ServiceMockProductExpectation{mock: mmProduct.mock}
+	}
+	mmProduct.defaultExpectation.results = &ServiceMockProductResults{p1, err}
+	return mmProduct.mock
+}
+
+// Set uses given function f to mock the Service.Product method
+func (mmProduct *mServiceMockProduct) Set(f func(sellerId uint64, offerId uint64) (p1 models.Product, err error)) *ServiceMock {
+	if mmProduct.defaultExpectation != nil {
+		mmProduct.mock.t.Fatalf("Default expectation is already set for the Service.Product method")
+	}
+
+	if len(mmProduct.expectations) > 0 {
+		mmProduct.mock.t.Fatalf("Some expectations are already set for the Service.Product method")
+	}
+
+	mmProduct.mock.funcProduct = f
+	return mmProduct.mock
+}
+
+// When sets expectation for the Service.Product which will trigger the result defined by the following
+// Then helper
+func (mmProduct *mServiceMockProduct) When(sellerId uint64, offerId uint64) *ServiceMockProductExpectation {
+	if mmProduct.mock.funcProduct != nil {
+		mmProduct.mock.t.Fatalf("ServiceMock.Product mock is already set by Set")
+	}
+
+	expectation := &ServiceMockProductExpectation{
+		mock:   mmProduct.mock,
+		params: &ServiceMockProductParams{sellerId, offerId},
+	}
+	mmProduct.expectations = append(mmProduct.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Service.Product return parameters for the expectation previously defined by the When method
+func (e *ServiceMockProductExpectation) Then(p1 models.Product, err error) *ServiceMock {
+	e.results = &ServiceMockProductResults{p1, err}
+	return e.mock
+}
+
+// Product implements Service
+func (mmProduct *ServiceMock) Product(sellerId uint64, offerId uint64) (p1 models.Product, err error) {
+	mm_atomic.AddUint64(&mmProduct.beforeProductCounter, 1)
+	defer mm_atomic.AddUint64(&mmProduct.afterProductCounter, 1)
+
+	if mmProduct.inspectFuncProduct != nil {
+		mmProduct.inspectFuncProduct(sellerId, offerId)
+	}
+
+	mm_params := &ServiceMockProductParams{sellerId, offerId}
+
+	// Record call args
+	mmProduct.ProductMock.mutex.Lock()
+	mmProduct.ProductMock.callArgs = append(mmProduct.ProductMock.callArgs, mm_params)
+	mmProduct.ProductMock.mutex.Unlock()
+
+	for _, e := range mmProduct.ProductMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.p1, e.results.err
+		}
+	}
+
+	if mmProduct.ProductMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmProduct.ProductMock.defaultExpectation.Counter, 1)
+		mm_want := mmProduct.ProductMock.defaultExpectation.params
+		mm_got := ServiceMockProductParams{sellerId, offerId}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmProduct.t.Errorf("ServiceMock.Product got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmProduct.ProductMock.defaultExpectation.results
+		if mm_results == nil {
+			mmProduct.t.Fatal("No results are set for the ServiceMock.Product")
+		}
+		return (*mm_results).p1, (*mm_results).err
+	}
+	if mmProduct.funcProduct != nil {
+		return mmProduct.funcProduct(sellerId, offerId)
+	}
+	mmProduct.t.Fatalf("Unexpected call to ServiceMock.Product. %v %v", sellerId, offerId)
+	return
+}
+
+// ProductAfterCounter returns a count of finished ServiceMock.Product invocations
+func (mmProduct *ServiceMock) ProductAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProduct.afterProductCounter)
+}
+
+// ProductBeforeCounter returns a count of ServiceMock.Product invocations
+func (mmProduct *ServiceMock) ProductBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProduct.beforeProductCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.Product.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmProduct *mServiceMockProduct) Calls() []*ServiceMockProductParams {
+	mmProduct.mutex.RLock()
+
+	argCopy := make([]*ServiceMockProductParams, len(mmProduct.callArgs))
+	copy(argCopy, mmProduct.callArgs)
+
+	mmProduct.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockProductDone returns true if the count of the Product invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockProductDone() bool {
+	for _, e := range m.ProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcProduct != nil && mm_atomic.LoadUint64(&m.afterProductCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockProductInspect logs each unmet expectation
+func (m *ServiceMock) MinimockProductInspect() {
+	for _, e := range m.ProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.Product with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductCounter) < 1 {
+		if m.ProductMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.Product")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.Product with params: %#v", *m.ProductMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcProduct != nil && mm_atomic.LoadUint64(&m.afterProductCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.Product")
+	}
+}
+
+type mServiceMockProductHistory struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockProductHistoryExpectation
+	expectations       []*ServiceMockProductHistoryExpectation
+
+	callArgs []*ServiceMockProductHistoryParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockProductHistoryExpectation specifies expectation struct of the Service.ProductHistory
+type ServiceMockProductHistoryExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockProductHistoryParams
+	results *ServiceMockProductHistoryResults
+	Counter uint64
+}
+
+// ServiceMockProductHistoryParams contains parameters of the Service.ProductHistory
+type ServiceMockProductHistoryParams struct {
+	sellerId uint64
+	offerId  uint64
+	limit    uint64
+}
+
+// ServiceMockProductHistoryResults contains results of the Service.ProductHistory
+type ServiceMockProductHistoryResults struct {
+	pa1 []models.ProductChange
+	err error
+}
+
+// Expect sets up expected params for Service.ProductHistory
+func (mmProductHistory *mServiceMockProductHistory) Expect(sellerId uint64, offerId uint64, limit uint64) *mServiceMockProductHistory {
+	if mmProductHistory.mock.funcProductHistory != nil {
+		mmProductHistory.mock.t.Fatalf("ServiceMock.ProductHistory mock is already set by Set")
+	}
+
+	if mmProductHistory.defaultExpectation == nil {
+		mmProductHistory.defaultExpectation = &ServiceMockProductHistoryExpectation{}
+	}
+
+	mmProductHistory.defaultExpectation.params = &ServiceMockProductHistoryParams{sellerId, offerId, limit}
+	for _, e := range mmProductHistory.expectations {
+		if minimock.Equal(e.params, mmProductHistory.defaultExpectation.params) {
+			mmProductHistory.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmProductHistory.defaultExpectation.params)
+		}
+	}
+
+	return mmProductHistory
+}
+
+// Inspect accepts an inspector function that has same arguments as the Service.ProductHistory
+func (mmProductHistory *mServiceMockProductHistory) Inspect(f func(sellerId uint64, offerId uint64, limit uint64)) *mServiceMockProductHistory {
+	if mmProductHistory.mock.inspectFuncProductHistory != nil {
+		mmProductHistory.mock.t.Fatalf("Inspect function is already set for ServiceMock.ProductHistory")
+	}
+
+	mmProductHistory.mock.inspectFuncProductHistory = f
+
+	return mmProductHistory
+}
+
+// Return sets up results that will be returned by Service.ProductHistory
+func (mmProductHistory *mServiceMockProductHistory) Return(pa1 []models.ProductChange, err error) *ServiceMock {
+	if mmProductHistory.mock.funcProductHistory != nil {
+		mmProductHistory.mock.t.Fatalf("ServiceMock.ProductHistory mock is already set by Set")
+	}
+
+	if mmProductHistory.defaultExpectation == nil {
+		mmProductHistory.defaultExpectation = &ServiceMockProductHistoryExpectation{mock: mmProductHistory.mock}
+	}
+	mmProductHistory.defaultExpectation.results = &ServiceMockProductHistoryResults{pa1, err}
+	return mmProductHistory.mock
+}
+
+// Set uses given function f to mock the Service.ProductHistory method
+func (mmProductHistory *mServiceMockProductHistory) Set(f func(sellerId uint64, offerId uint64, limit uint64) (pa1 []models.ProductChange, err error)) *ServiceMock {
+	if mmProductHistory.defaultExpectation != nil {
+		mmProductHistory.mock.t.Fatalf("Default expectation is already set for the Service.ProductHistory method")
+	}
+
+	if len(mmProductHistory.expectations) > 0 {
+		mmProductHistory.mock.t.Fatalf("Some expectations are already set for the Service.ProductHistory method")
+	}
+
+	mmProductHistory.mock.funcProductHistory = f
+	return mmProductHistory.mock
+}
+
+// When sets expectation for the Service.ProductHistory which will trigger the result defined by the following
+// Then helper
+func (mmProductHistory *mServiceMockProductHistory) When(sellerId uint64, offerId uint64, limit uint64) *ServiceMockProductHistoryExpectation {
+	if mmProductHistory.mock.funcProductHistory != nil {
+		mmProductHistory.mock.t.Fatalf("ServiceMock.ProductHistory mock is already set by Set")
+	}
+
+	expectation := &ServiceMockProductHistoryExpectation{
+		mock:   mmProductHistory.mock,
+		params: &ServiceMockProductHistoryParams{sellerId, offerId, limit},
+	}
+	mmProductHistory.expectations = append(mmProductHistory.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Service.ProductHistory return parameters for the expectation previously defined by the When method
+func (e *ServiceMockProductHistoryExpectation) Then(pa1 []models.ProductChange, err error) *ServiceMock {
+	e.results = &ServiceMockProductHistoryResults{pa1, err}
+	return e.mock
+}
+
+// ProductHistory implements Service
+func (mmProductHistory *ServiceMock) ProductHistory(sellerId uint64, offerId uint64, limit uint64) (pa1 []models.ProductChange, err error) {
+	mm_atomic.AddUint64(&mmProductHistory.beforeProductHistoryCounter, 1)
+	defer mm_atomic.AddUint64(&mmProductHistory.afterProductHistoryCounter, 1)
+
+	if mmProductHistory.inspectFuncProductHistory != nil {
+		mmProductHistory.inspectFuncProductHistory(sellerId, offerId, limit)
+	}
+
+	mm_params := &ServiceMockProductHistoryParams{sellerId, offerId, limit}
+
+	// Record call args
+	mmProductHistory.ProductHistoryMock.mutex.Lock()
+	mmProductHistory.ProductHistoryMock.callArgs = append(mmProductHistory.ProductHistoryMock.callArgs, mm_params)
+	mmProductHistory.ProductHistoryMock.mutex.Unlock()
+
+	for _, e := range mmProductHistory.ProductHistoryMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.pa1, e.results.err
+		}
+	}
+
+	if mmProductHistory.ProductHistoryMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmProductHistory.ProductHistoryMock.defaultExpectation.Counter, 1)
+		mm_want := mmProductHistory.ProductHistoryMock.defaultExpectation.params
+		mm_got := ServiceMockProductHistoryParams{sellerId, offerId, limit}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmProductHistory.t.Errorf("ServiceMock.ProductHistory got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmProductHistory.ProductHistoryMock.defaultExpectation.results
+		if mm_results == nil {
+			mmProductHistory.t.Fatal("No results are set for the ServiceMock.ProductHistory")
+		}
+		return (*mm_results).pa1, (*mm_results).err
+	}
+	if mmProductHistory.funcProductHistory != nil {
+		return mmProductHistory.funcProductHistory(sellerId, offerId, limit)
+	}
+	mmProductHistory.t.Fatalf("Unexpected call to ServiceMock.ProductHistory. %v %v %v", sellerId, offerId, limit)
+	return
+}
+
+// ProductHistoryAfterCounter returns a count of finished ServiceMock.ProductHistory invocations
+func (mmProductHistory *ServiceMock) ProductHistoryAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProductHistory.afterProductHistoryCounter)
+}
+
+// ProductHistoryBeforeCounter returns a count of ServiceMock.ProductHistory invocations
+func (mmProductHistory *ServiceMock) ProductHistoryBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProductHistory.beforeProductHistoryCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.ProductHistory.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmProductHistory *mServiceMockProductHistory) Calls() []*ServiceMockProductHistoryParams {
+	mmProductHistory.mutex.RLock()
+
+	argCopy := make([]*ServiceMockProductHistoryParams, len(mmProductHistory.callArgs))
+	copy(argCopy, mmProductHistory.callArgs)
+
+	mmProductHistory.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockProductHistoryDone returns true if the count of the ProductHistory invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockProductHistoryDone() bool {
+	for _, e := range m.ProductHistoryMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ProductHistoryMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductHistoryCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcProductHistory != nil && mm_atomic.LoadUint64(&m.afterProductHistoryCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockProductHistoryInspect logs each unmet expectation
+func (m *ServiceMock) MinimockProductHistoryInspect() {
+	for _, e := range m.ProductHistoryMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.ProductHistory with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ProductHistoryMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductHistoryCounter) < 1 {
+		if m.ProductHistoryMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.ProductHistory")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.ProductHistory with params: %#v", *m.ProductHistoryMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcProductHistory != nil && mm_atomic.LoadUint64(&m.afterProductHistoryCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.ProductHistory")
+	}
 }
 
 type mServiceMockProductsByFilter struct {
@@ -71,7 +1203,7 @@ type ServiceMockProductsByFilterParams struct {
 
 // ServiceMockProductsByFilterResults contains results of the Service.ProductsByFilter
 type ServiceMockProductsByFilterResults struct {
-	pa1 []models.Product
+	p1  service.ProductsPage
 	err error
 }
 
@@ -107,7 +1239,7 @@ func (mmProductsByFilter *mServiceMockProductsByFilter) Inspect(f func(filter se
 }
 
 // Return sets up results that will be returned by Service.ProductsByFilter
-func (mmProductsByFilter *mServiceMockProductsByFilter) Return(pa1 []models.Product, err error) *ServiceMock {
+func (mmProductsByFilter *mServiceMockProductsByFilter) Return(p1 service.ProductsPage, err error) *ServiceMock {
 	if mmProductsByFilter.mock.funcProductsByFilter != nil {
 		mmProductsByFilter.mock.t.Fatalf("ServiceMock.ProductsByFilter mock is already set by Set")
 	}
@@ -115,12 +1247,12 @@ func (mmProductsByFilter *mServiceMockProductsByFilter) Return(pa1 []models.Prod
 	if mmProductsByFilter.defaultExpectation == nil {
 		mmProductsByFilter.defaultExpectation = &ServiceMockProductsByFilterExpectation{mock: mmProductsByFilter.mock}
 	}
-	mmProductsByFilter.defaultExpectation.results = &ServiceMockProductsByFilterResults{pa1, err}
+	mmProductsByFilter.defaultExpectation.results = &ServiceMockProductsByFilterResults{p1, err}
 	return mmProductsByFilter.mock
 }
 
 // Set uses given function f to mock the Service.ProductsByFilter method
-func (mmProductsByFilter *mServiceMockProductsByFilter) Set(f func(filter service.RequestFilter) (pa1 []models.Product, err error)) *ServiceMock {
+func (mmProductsByFilter *mServiceMockProductsByFilter) Set(f func(filter service.RequestFilter) (p1 service.ProductsPage, err error)) *ServiceMock {
 	if mmProductsByFilter.defaultExpectation != nil {
 		mmProductsByFilter.mock.t.Fatalf("Default expectation is already set for the Service.ProductsByFilter method")
 	}
@@ -149,13 +1281,13 @@ func (mmProductsByFilter *mServiceMockProductsByFilter) When(filter service.Requ
 }
 
 // Then sets up Service.ProductsByFilter return parameters for the expectation previously defined by the When method
-func (e *ServiceMockProductsByFilterExpectation) Then(pa1 []models.Product, err error) *ServiceMock {
-	e.results = &ServiceMockProductsByFilterResults{pa1, err}
+func (e *ServiceMockProductsByFilterExpectation) Then(p1 service.ProductsPage, err error) *ServiceMock {
+	e.results = &ServiceMockProductsByFilterResults{p1, err}
 	return e.mock
 }
 
 // ProductsByFilter implements Service
-func (mmProductsByFilter *ServiceMock) ProductsByFilter(filter service.RequestFilter) (pa1 []models.Product, err error) {
+func (mmProductsByFilter *ServiceMock) ProductsByFilter(filter service.RequestFilter) (p1 service.ProductsPage, err error) {
 	mm_atomic.AddUint64(&mmProductsByFilter.beforeProductsByFilterCounter, 1)
 	defer mm_atomic.AddUint64(&mmProductsByFilter.afterProductsByFilterCounter, 1)
 
@@ -173,7 +1305,7 @@ func (mmProductsByFilter *ServiceMock) ProductsByFilter(filter service.RequestFi
 	for _, e := range mmProductsByFilter.ProductsByFilterMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.pa1, e.results.err
+			return e.results.p1, e.results.err
 		}
 	}
 
@@ -189,7 +1321,7 @@ func (mmProductsByFilter *ServiceMock) ProductsByFilter(filter service.RequestFi
 		if mm_results == nil {
 			mmProductsByFilter.t.Fatal("No results are set for the ServiceMock.ProductsByFilter")
 		}
-		return (*mm_results).pa1, (*mm_results).err
+		return (*mm_results).p1, (*mm_results).err
 	}
 	if mmProductsByFilter.funcProductsByFilter != nil {
 		return mmProductsByFilter.funcProductsByFilter(filter)
@@ -263,229 +1395,239 @@ func (m *ServiceMock) MinimockProductsByFilterInspect() {
 	}
 }
 
-type mServiceMockUpdateProducts struct {
+type mServiceMockPutProduct struct {
 	mock               *ServiceMock
-	defaultExpectation *ServiceMockUpdateProductsExpectation
-	expectations       []*ServiceMockUpdateProductsExpectation
+	defaultExpectation *ServiceMockPutProductExpectation
+	expectations       []*ServiceMockPutProductExpectation
 
-	callArgs []*ServiceMockUpdateProductsParams
+	callArgs []*ServiceMockPutProductParams
 	mutex    sync.RWMutex
 }
 
-// ServiceMockUpdateProductsExpectation specifies expectation struct of the Service.UpdateProducts
-type ServiceMockUpdateProductsExpectation struct {
+// ServiceMockPutProductExpectation specifies expectation struct of the Service.PutProduct
+type ServiceMockPutProductExpectation struct {
 	mock    *ServiceMock
-	params  *ServiceMockUpdateProductsParams
-	results *ServiceMockUpdateProductsResults
+	params  *ServiceMockPutProductParams
+	results *ServiceMockPutProductResults
 	Counter uint64
 }
 
-// ServiceMockUpdateProductsParams contains parameters of the Service.UpdateProducts
-type ServiceMockUpdateProductsParams struct {
-	sellerId       uint64
-	productUpdates []models.ProductUpdate
+// ServiceMockPutProductParams contains parameters of the Service.PutProduct
+type ServiceMockPutProductParams struct {
+	product models.Product
+	source  models.ChangeSource
 }
 
-// ServiceMockUpdateProductsResults contains results of the Service.UpdateProducts
-type ServiceMockUpdateProductsResults struct {
-	u1  service.UpdateResults
-	err error
+// ServiceMockPutProductResults contains results of the Service.PutProduct
+type ServiceMockPutProductResults struct {
+	created bool
+	err     error
 }
 
-// Expect sets up expected params for Service.UpdateProducts
-func (mmUpdateProducts *mServiceMockUpdateProducts) Expect(sellerId uint64, productUpdates []models.ProductUpdate) *mServiceMockUpdateProducts {
-	if mmUpdateProducts.mock.funcUpdateProducts != nil {
-		mmUpdateProducts.mock.t.Fatalf("ServiceMock.UpdateProducts mock is already set by Set")
+// Expect sets up expected params for Service.PutProduct
+func (mmPutProduct *mServiceMockPutProduct) Expect(product models.Product, source models.ChangeSource) *mServiceMockPutProduct {
+	if mmPutProduct.mock.funcPutProduct != nil {
+		mmPutProduct.mock.t.Fatalf("ServiceMock.PutProduct mock is already set by Set")
 	}
 
-	if mmUpdateProducts.defaultExpectation == nil {
-		mmUpdateProducts.defaultExpectation = &ServiceMockUpdateProductsExpectation{}
+	if mmPutProduct.defaultExpectation == nil {
+		mmPutProduct.defaultExpectation = &ServiceMockPutProductExpectation{}
 	}
 
-	mmUpdateProducts.defaultExpectation.params = &ServiceMockUpdateProductsParams{sellerId, productUpdates}
-	for _, e := range mmUpdateProducts.expectations {
-		if minimock.Equal(e.params, mmUpdateProducts.defaultExpectation.params) {
-			mmUpdateProducts.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmUpdateProducts.defaultExpectation.params)
+	mmPutProduct.defaultExpectation.params = &ServiceMockPutProductParams{product, source}
+	for _, e := range mmPutProduct.expectations {
+		if minimock.Equal(e.params, mmPutProduct.defaultExpectation.params) {
+			mmPutProduct.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmPutProduct.defaultExpectation.params)
 		}
 	}
 
-	return mmUpdateProducts
+	return mmPutProduct
 }
 
-// Inspect accepts an inspector function that has same arguments as the Service.UpdateProducts
-func (mmUpdateProducts *mServiceMockUpdateProducts) Inspect(f func(sellerId uint64, productUpdates []models.ProductUpdate)) *mServiceMockUpdateProducts {
-	if mmUpdateProducts.mock.inspectFuncUpdateProducts != nil {
-		mmUpdateProducts.mock.t.Fatalf("Inspect function is already set for ServiceMock.UpdateProducts")
+// Inspect accepts an inspector function that has same arguments as the Service.PutProduct
+func (mmPutProduct *mServiceMockPutProduct) Inspect(f func(product models.Product, source models.ChangeSource)) *mServiceMockPutProduct {
+	if mmPutProduct.mock.inspectFuncPutProduct != nil {
+		mmPutProduct.mock.t.Fatalf("Inspect function is already set for ServiceMock.PutProduct")
 	}
 
-	mmUpdateProducts.mock.inspectFuncUpdateProducts = f
+	mmPutProduct.mock.inspectFuncPutProduct = f
 
-	return mmUpdateProducts
+	return mmPutProduct
 }
 
-// Return sets up results that will be returned by Service.UpdateProducts
-func (mmUpdateProducts *mServiceMockUpdateProducts) Return(u1 service.UpdateResults, err error) *ServiceMock {
-	if mmUpdateProducts.mock.funcUpdateProducts != nil {
-		mmUpdateProducts.mock.t.Fatalf("ServiceMock.UpdateProducts mock is already set by Set")
+// Return sets up results that will be returned by Service.PutProduct
+func (mmPutProduct *mServiceMockPutProduct) Return(created bool, err error) *ServiceMock {
+	if mmPutProduct.mock.funcPutProduct != nil {
+		mmPutProduct.mock.t.Fatalf("ServiceMock.PutProduct mock is already set by Set")
 	}
 
-	if mmUpdateProducts.defaultExpectation == nil {
-		mmUpdateProducts.defaultExpectation = &ServiceMockUpdateProductsExpectation{mock: mmUpdateProducts.mock}
+	if mmPutProduct.defaultExpectation == nil {
+		mmPutProduct.defaultExpectation = &ServiceMockPutProductExpectation{mock: mmPutProduct.mock}
 	}
-	mmUpdateProducts.defaultExpectation.results = &ServiceMockUpdateProductsResults{u1, err}
-	return mmUpdateProducts.mock
+	mmPutProduct.defaultExpectation.results = &ServiceMockPutProductResults{created, err}
+	return mmPutProduct.mock
 }
 
-// Set uses given function f to mock the Service.UpdateProducts method
-func (mmUpdateProducts *mServiceMockUpdateProducts) Set(f func(sellerId uint64, productUpdates []models.ProductUpdate) (u1 service.UpdateResults, err error)) *ServiceMock {
-	if mmUpdateProducts.defaultExpectation != nil {
-		mmUpdateProducts.mock.t.Fatalf("Default expectation is already set for the Service.UpdateProducts method")
+// Set uses given function f to mock the Service.PutProduct method
+func (mmPutProduct *mServiceMockPutProduct) Set(f func(product models.Product, source models.ChangeSource) (created bool, err error)) *ServiceMock {
+	if mmPutProduct.defaultExpectation != nil {
+		mmPutProduct.mock.t.Fatalf("Default expectation is already set for the Service.PutProduct method")
 	}
 
-	if len(mmUpdateProducts.expectations) > 0 {
-		mmUpdateProducts.mock.t.Fatalf("Some expectations are already set for the Service.UpdateProducts method")
+	if len(mmPutProduct.expectations) > 0 {
+		mmPutProduct.mock.t.Fatalf("Some expectations are already set for the Service.PutProduct method")
 	}
 
-	mmUpdateProducts.mock.funcUpdateProducts = f
-	return mmUpdateProducts.mock
+	mmPutProduct.mock.funcPutProduct = f
+	return mmPutProduct.mock
 }
 
-// When sets expectation for the Service.UpdateProducts which will trigger the result defined by the following
+// When sets expectation for the Service.PutProduct which will trigger the result defined by the following
 // Then helper
-func (mmUpdateProducts *mServiceMockUpdateProducts) When(sellerId uint64, productUpdates []models.ProductUpdate) *ServiceMockUpdateProductsExpectation {
-	if mmUpdateProducts.mock.funcUpdateProducts != nil {
-		mmUpdateProducts.mock.t.Fatalf("ServiceMock.UpdateProducts mock is already set by Set")
+func (mmPutProduct *mServiceMockPutProduct) When(product models.Product, source models.ChangeSource) *ServiceMockPutProductExpectation {
+	if mmPutProduct.mock.funcPutProduct != nil {
+		mmPutProduct.mock.t.Fatalf("ServiceMock.PutProduct mock is already set by Set")
 	}
 
-	expectation := &ServiceMockUpdateProductsExpectation{
-		mock:   mmUpdateProducts.mock,
-		params: &ServiceMockUpdateProductsParams{sellerId, productUpdates},
+	expectation := &ServiceMockPutProductExpectation{
+		mock:   mmPutProduct.mock,
+		params: &ServiceMockPutProductParams{product, source},
 	}
-	mmUpdateProducts.expectations = append(mmUpdateProducts.expectations, expectation)
+	mmPutProduct.expectations = append(mmPutProduct.expectations, expectation)
 	return expectation
 }
 
-// Then sets up Service.UpdateProducts return parameters for the expectation previously defined by the When method
-func (e *ServiceMockUpdateProductsExpectation) Then(u1 service.UpdateResults, err error) *ServiceMock {
-	e.results = &ServiceMockUpdateProductsResults{u1, err}
+// Then sets up Service.PutProduct return parameters for the expectation previously defined by the When method
+func (e *ServiceMockPutProductExpectation) Then(created bool, err error) *ServiceMock {
+	e.results = &ServiceMockPutProductResults{created, err}
 	return e.mock
 }
 
-// UpdateProducts implements Service
-func (mmUpdateProducts *ServiceMock) UpdateProducts(sellerId uint64, productUpdates []models.ProductUpdate) (u1 service.UpdateResults, err error) {
-	mm_atomic.AddUint64(&mmUpdateProducts.beforeUpdateProductsCounter, 1)
-	defer mm_atomic.AddUint64(&mmUpdateProducts.afterUpdateProductsCounter, 1)
+// PutProduct implements Service
+func (mmPutProduct *ServiceMock) PutProduct(product models.Product, source models.ChangeSource) (created bool, err error) {
+	mm_atomic.AddUint64(&mmPutProduct.beforePutProductCounter, 1)
+	defer mm_atomic.AddUint64(&mmPutProduct.afterPutProductCounter, 1)
 
-	if mmUpdateProducts.inspectFuncUpdateProducts != nil {
-		mmUpdateProducts.inspectFuncUpdateProducts(sellerId, productUpdates)
+	if mmPutProduct.inspectFuncPutProduct != nil {
+		mmPutProduct.inspectFuncPutProduct(product, source)
 	}
 
-	mm_params := &ServiceMockUpdateProductsParams{sellerId, productUpdates}
+	mm_params := &ServiceMockPutProductParams{product, source}
 
 	// Record call args
-	mmUpdateProducts.UpdateProductsMock.mutex.Lock()
-	mmUpdateProducts.UpdateProductsMock.callArgs = append(mmUpdateProducts.UpdateProductsMock.callArgs, mm_params)
-	mmUpdateProducts.UpdateProductsMock.mutex.Unlock()
+	mmPutProduct.PutProductMock.mutex.Lock()
+	mmPutProduct.PutProductMock.callArgs = append(mmPutProduct.PutProductMock.callArgs, mm_params)
+	mmPutProduct.PutProductMock.mutex.Unlock()
 
-	for _, e := range mmUpdateProducts.UpdateProductsMock.expectations {
+	for _, e := range mmPutProduct.PutProductMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.u1, e.results.err
+			return e.results.created, e.results.err
 		}
 	}
 
-	if mmUpdateProducts.UpdateProductsMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmUpdateProducts.UpdateProductsMock.defaultExpectation.Counter, 1)
-		mm_want := mmUpdateProducts.UpdateProductsMock.defaultExpectation.params
-		mm_got := ServiceMockUpdateProductsParams{sellerId, productUpdates}
+	if mmPutProduct.PutProductMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmPutProduct.PutProductMock.defaultExpectation.Counter, 1)
+		mm_want := mmPutProduct.PutProductMock.defaultExpectation.params
+		mm_got := ServiceMockPutProductParams{product, source}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmUpdateProducts.t.Errorf("ServiceMock.UpdateProducts got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmPutProduct.t.Errorf("ServiceMock.PutProduct got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmUpdateProducts.UpdateProductsMock.defaultExpectation.results
+		mm_results := mmPutProduct.PutProductMock.defaultExpectation.results
 		if mm_results == nil {
-			mmUpdateProducts.t.Fatal("No results are set for the ServiceMock.UpdateProducts")
+			mmPutProduct.t.Fatal("No results are set for the ServiceMock.PutProduct")
 		}
-		return (*mm_results).u1, (*mm_results).err
+		return (*mm_results).created, (*mm_results).err
 	}
-	if mmUpdateProducts.funcUpdateProducts != nil {
-		return mmUpdateProducts.funcUpdateProducts(sellerId, productUpdates)
+	if mmPutProduct.funcPutProduct != nil {
+		return mmPutProduct.funcPutProduct(product, source)
 	}
-	mmUpdateProducts.t.Fatalf("Unexpected call to ServiceMock.UpdateProducts. %v %v", sellerId, productUpdates)
+	mmPutProduct.t.Fatalf("Unexpected call to ServiceMock.PutProduct. %v %v", product, source)
 	return
 }
 
-// UpdateProductsAfterCounter returns a count of finished ServiceMock.UpdateProducts invocations
-func (mmUpdateProducts *ServiceMock) UpdateProductsAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmUpdateProducts.afterUpdateProductsCounter)
+// PutProductAfterCounter returns a count of finished ServiceMock.PutProduct invocations
+func (mmPutProduct *ServiceMock) PutProductAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPutProduct.afterPutProductCounter)
 }
 
-// UpdateProductsBeforeCounter returns a count of ServiceMock.UpdateProducts invocations
-func (mmUpdateProducts *ServiceMock) UpdateProductsBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmUpdateProducts.beforeUpdateProductsCounter)
+// PutProductBeforeCounter returns a count of ServiceMock.PutProduct invocations
+func (mmPutProduct *ServiceMock) PutProductBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPutProduct.beforePutProductCounter)
 }
 
-// Calls returns a list of arguments used in each call to ServiceMock.UpdateProducts.
+// Calls returns a list of arguments used in each call to ServiceMock.PutProduct.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmUpdateProducts *mServiceMockUpdateProducts) Calls() []*ServiceMockUpdateProductsParams {
-	mmUpdateProducts.mutex.RLock()
+func (mmPutProduct *mServiceMockPutProduct) Calls() []*ServiceMockPutProductParams {
+	mmPutProduct.mutex.RLock()
 
-	argCopy := make([]*ServiceMockUpdateProductsParams, len(mmUpdateProducts.callArgs))
-	copy(argCopy, mmUpdateProducts.callArgs)
+	argCopy := make([]*ServiceMockPutProductParams, len(mmPutProduct.callArgs))
+	copy(argCopy, mmPutProduct.callArgs)
 
-	mmUpdateProducts.mutex.RUnlock()
+	mmPutProduct.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockUpdateProductsDone returns true if the count of the UpdateProducts invocations corresponds
+// MinimockPutProductDone returns true if the count of the PutProduct invocations corresponds
 // the number of defined expectations
-func (m *ServiceMock) MinimockUpdateProductsDone() bool {
-	for _, e := range m.UpdateProductsMock.expectations {
+func (m *ServiceMock) MinimockPutProductDone() bool {
+	for _, e := range m.PutProductMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.UpdateProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterUpdateProductsCounter) < 1 {
+	if m.PutProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPutProductCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcUpdateProducts != nil && mm_atomic.LoadUint64(&m.afterUpdateProductsCounter) < 1 {
+	if m.funcPutProduct != nil && mm_atomic.LoadUint64(&m.afterPutProductCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockUpdateProductsInspect logs each unmet expectation
-func (m *ServiceMock) MinimockUpdateProductsInspect() {
-	for _, e := range m.UpdateProductsMock.expectations {
+// MinimockPutProductInspect logs each unmet expectation
+func (m *ServiceMock) MinimockPutProductInspect() {
+	for _, e := range m.PutProductMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to ServiceMock.UpdateProducts with params: %#v", *e.params)
+			m.t.Errorf("Expected call to ServiceMock.PutProduct with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.UpdateProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterUpdateProductsCounter) < 1 {
-		if m.UpdateProductsMock.defaultExpectation.params == nil {
-			m.t.Error("Expected call to ServiceMock.UpdateProducts")
+	if m.PutProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPutProductCounter) < 1 {
+		if m.PutProductMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.PutProduct")
 		} else {
-			m.t.Errorf("Expected call to ServiceMock.UpdateProducts with params: %#v", *m.UpdateProductsMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to ServiceMock.PutProduct with params: %#v", *m.PutProductMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcUpdateProducts != nil && mm_atomic.LoadUint64(&m.afterUpdateProductsCounter) < 1 {
-		m.t.Error("Expected call to ServiceMock.UpdateProducts")
+	if m.funcPutProduct != nil && mm_atomic.LoadUint64(&m.afterPutProductCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.PutProduct")
 	}
 }
 
 // MinimockFinish checks that all mocked methods have been called the expected number of times
 func (m *ServiceMock) MinimockFinish() {
 	if !m.minimockDone() {
+		m.MinimockDeleteProductInspect()
+
+		m.MinimockExportProductsInspect()
+
+		m.MinimockPatchProductInspect()
+
+		m.MinimockProductInspect()
+
+		m.MinimockProductHistoryInspect()
+
 		m.MinimockProductsByFilterInspect()
 
-		m.MinimockUpdateProductsInspect()
+		m.MinimockPutProductInspect()
 		m.t.FailNow()
 	}
 }
@@ -509,6 +1651,11 @@ func (m *ServiceMock) MinimockWait(timeout mm_time.Duration) {
 func (m *ServiceMock) minimockDone() bool {
 	done := true
 	return done &&
+		m.MinimockDeleteProductDone() &&
+		m.MinimockExportProductsDone() &&
+		m.MinimockPatchProductDone() &&
+		m.MinimockProductDone() &&
+		m.MinimockProductHistoryDone() &&
 		m.MinimockProductsByFilterDone() &&
-		m.MinimockUpdateProductsDone()
+		m.MinimockPutProductDone()
 }
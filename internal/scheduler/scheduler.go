@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+	defaultMinInterval  = 15
+	defaultBatchSize    = 100
+	defaultLockKey      = 7301
+)
+
+var (
+	ErrFeedNotFound = errors.New("feed not found")
+	ErrRepoFailed   = errors.New("repo err")
+
+	ErrIntervalTooShort = errors.New("feed interval too short")
+)
+
+type Repository interface {
+	CreateFeed(feed models.Feed) (models.Feed, error)
+	Feeds(sellerId uint64) ([]models.Feed, error)
+	DeleteFeed(sellerId uint64, feedId uint64) error
+	DueFeeds(limit uint64) ([]models.Feed, error)
+	SaveFeedRun(feedId uint64, run models.FeedRun) error
+}
+
+// Importer ставит загрузку таблицы фида в общую очередь задач
+type Importer interface {
+	// SealSource шифрует учётные данные источника так же, как для задач; возвращает ссылку для хранения
+	SealSource(tableURL string, auth models.TableAuth) (string, []byte, error)
+	// EnqueueSealed ставит задачу с уже зашифрованными учётными данными job.Auth
+	EnqueueSealed(job models.Job) (models.Job, error)
+}
+
+type URLChecker interface {
+	// CheckURL проверяет, что ссылку можно скачивать; ошибка объясняет причину
+	CheckURL(url string) error
+}
+
+// Lock - блокировка лидера, общая для всех реплик
+type Lock interface {
+	// Acquire берёт блокировку или проверяет, что она всё ещё у нас; false - лидер другая реплика
+	Acquire() (bool, error)
+	Release() error
+}
+
+// Scheduler хранит фиды продавцов и по расписанию ставит загрузки их таблиц в очередь Importer:
+// скачивание, разбор и запись в каталог (Service.BeginImport) - те же, что у загрузки по ссылке.
+// Фиды запускает только одна реплика - та, что держит Lock.
+type Scheduler struct {
+	repo Repository
+	im   Importer
+	uc   URLChecker
+	lock Lock
+
+	pollInterval time.Duration
+	// минуты
+	minInterval uint64
+	batchSize   uint64
+}
+
+func NewScheduler(cfg config.Config, repo Repository, im Importer, uc URLChecker, lock Lock) *Scheduler {
+	s := Scheduler{
+		repo:         repo,
+		im:           im,
+		uc:           uc,
+		lock:         lock,
+		pollInterval: time.Duration(cfg.Scheduler.PollInterval) * time.Second,
+		minInterval:  cfg.Scheduler.MinIntervalMinutes,
+		batchSize:    cfg.Scheduler.BatchSize,
+	}
+
+	if s.pollInterval <= 0 {
+		s.pollInterval = defaultPollInterval
+	}
+	if s.minInterval == 0 {
+		s.minInterval = defaultMinInterval
+	}
+	if s.batchSize == 0 {
+		s.batchSize = defaultBatchSize
+	}
+
+	return &s
+}
+
+// LockKey - ключ блокировки лидера из конфига
+func LockKey(cfg config.Config) int64 {
+	if cfg.Scheduler.LockKey == 0 {
+		return defaultLockKey
+	}
+
+	return cfg.Scheduler.LockKey
+}
+
+// CreateFeed проверяет ссылку так же, как загрузка таблицы, и сохраняет фид; первый запуск - при ближайшем обходе.
+// Ссылка, запрещённая политикой gateway, возвращается ошибкой gateway.ErrForbiddenURL с причиной.
+// Учётные данные источника (в том числе логин, пароль и параметры из ссылки) хранятся в фиде зашифрованными;
+// без ключа шифрования они не принимаются (importer.ErrCredentialsDisabled).
+func (s *Scheduler) CreateFeed(feed models.Feed, auth models.TableAuth) (models.Feed, error) {
+	if feed.IntervalMinutes < s.minInterval {
+		return models.Feed{}, fmt.Errorf("%w: minimum is %d minutes", ErrIntervalTooShort, s.minInterval)
+	}
+
+	if err := s.uc.CheckURL(feed.TableURL); err != nil {
+		log.Println(err)
+		return models.Feed{}, err
+	}
+
+	tableURL, sealed, err := s.im.SealSource(feed.TableURL, auth)
+	if err != nil {
+		return models.Feed{}, err
+	}
+	feed.TableURL, feed.Auth = tableURL, sealed
+
+	createdFeed, err := s.repo.CreateFeed(feed)
+	if err != nil {
+		log.Println(err)
+		return models.Feed{}, ErrRepoFailed
+	}
+
+	return createdFeed, nil
+}
+
+func (s *Scheduler) Feeds(sellerId uint64) ([]models.Feed, error) {
+	feeds, err := s.repo.Feeds(sellerId)
+	if err != nil {
+		log.Println(err)
+		return nil, ErrRepoFailed
+	}
+
+	return feeds, nil
+}
+
+// DeleteFeed удаляет фид продавца; уже поставленная задача фида выполнится
+func (s *Scheduler) DeleteFeed(sellerId uint64, feedId uint64) error {
+	err := s.repo.DeleteFeed(sellerId, feedId)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return ErrFeedNotFound
+
+	case err != nil:
+		log.Println(err)
+		return ErrRepoFailed
+	}
+
+	return nil
+}
+
+// Run обходит фиды каждые pollInterval и блокируется до отмены контекста. Пока блокировку держит
+// другая реплика, фиды не запускаются; если лидер упал, блокировку берёт следующая реплика.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	defer func() {
+		if err := s.lock.Release(); err != nil {
+			log.Printf("failed to release scheduler lock: %v", err)
+		}
+	}()
+
+	leader := false
+	for {
+		locked, err := s.lock.Acquire()
+		if err != nil {
+			log.Printf("failed to acquire scheduler lock: %v", err)
+		}
+		if locked != leader {
+			leader = locked
+			log.Printf("scheduler leadership: %t", leader)
+		}
+
+		if leader {
+			s.runDue()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue ставит в очередь загрузки фидов, которым пора запускаться
+func (s *Scheduler) runDue() {
+	feeds, err := s.repo.DueFeeds(s.batchSize)
+	if err != nil {
+		log.Printf("failed to get due feeds: %v", err)
+		return
+	}
+
+	for _, feed := range feeds {
+		s.runFeed(feed)
+	}
+}
+
+// runFeed ставит задачу загрузки фида и назначает следующий запуск. Если итог не сохранился,
+// фид запустится снова при следующем обходе; неизменившуюся таблицу Importer повторно не запишет.
+func (s *Scheduler) runFeed(feed models.Feed) {
+	run := models.FeedRun{Status: models.JobQueued}
+
+	job, err := s.im.EnqueueSealed(models.Job{SellerId: feed.SellerId, TableURL: feed.TableURL, SyncMode: feed.SyncMode, Auth: feed.Auth})
+	if err != nil {
+		log.Printf("failed to run feed #%d: %v", feed.Id, err)
+		run = models.FeedRun{Status: models.JobFailed, Error: err.Error()}
+	} else {
+		run.JobId = &job.Id
+	}
+
+	if err := s.repo.SaveFeedRun(feed.Id, run); err != nil {
+		log.Printf("failed to save run of feed #%d: %v", feed.Id, err)
+	}
+}
@@ -0,0 +1,72 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"github.com/hablof/merchant-experience/internal/config"
+)
+
+const keySize = 32
+
+var (
+	ErrNoKey     = errors.New("no encryption key configured")
+	ErrBadKey    = errors.New("encryption key must be 32 bytes in base64")
+	ErrCorrupted = errors.New("failed to decrypt secret")
+)
+
+// Box шифрует секреты для хранения в базе: AES-256-GCM, случайный nonce хранится перед шифротекстом
+type Box struct {
+	aead cipher.AEAD
+}
+
+// NewBox берёт ключ из security.credentials-key; без ключа возвращает ErrNoKey
+func NewBox(cfg config.Config) (*Box, error) {
+	if cfg.Security.CredentialsKey == "" {
+		return nil, ErrNoKey
+	}
+
+	key, err := base64.StdEncoding.DecodeString(cfg.Security.CredentialsKey)
+	if err != nil || len(key) != keySize {
+		return nil, ErrBadKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Box{aead: aead}, nil
+}
+
+func (b *Box) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return b.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open возвращает ErrCorrupted, если секрет повреждён или зашифрован другим ключом
+func (b *Box) Open(sealed []byte) ([]byte, error) {
+	nonceSize := b.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrCorrupted
+	}
+
+	plaintext, err := b.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,72 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func keyConfig(key []byte) config.Config {
+	return config.Config{Security: config.Security{CredentialsKey: base64.StdEncoding.EncodeToString(key)}}
+}
+
+func TestNewBox(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		wantErr error
+	}{
+		{name: "no key", cfg: config.Config{}, wantErr: ErrNoKey},
+		{name: "not base64", cfg: config.Config{Security: config.Security{CredentialsKey: "не base64"}}, wantErr: ErrBadKey},
+		{name: "short key", cfg: keyConfig(bytes.Repeat([]byte{1}, 16)), wantErr: ErrBadKey},
+		{name: "valid key", cfg: keyConfig(bytes.Repeat([]byte{1}, 32)), wantErr: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box, err := NewBox(tt.cfg)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantErr == nil, box != nil)
+		})
+	}
+}
+
+func TestBox_SealOpen(t *testing.T) {
+	box, err := NewBox(keyConfig(bytes.Repeat([]byte{1}, 32)))
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	otherBox, err := NewBox(keyConfig(bytes.Repeat([]byte{2}, 32)))
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	secret := []byte(`{"bearerToken":"s3cr3t"}`)
+	sealed, err := box.Seal(secret)
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	assert.NotContains(t, string(sealed), "s3cr3t")
+
+	// nonce случайный: одинаковые секреты шифруются по-разному
+	sealedAgain, _ := box.Seal(secret)
+	assert.NotEqual(t, sealed, sealedAgain)
+
+	opened, err := box.Open(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, opened)
+
+	_, err = otherBox.Open(sealed)
+	assert.Equal(t, ErrCorrupted, err, "other key")
+
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = box.Open(tampered)
+	assert.Equal(t, ErrCorrupted, err, "tampered")
+
+	_, err = box.Open([]byte{1, 2, 3})
+	assert.Equal(t, ErrCorrupted, err, "too short")
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+)
+
+// guard - защита каталога от сломанных выгрузок продавца (config.Guard)
+type guard struct {
+	maxDeletePercent   float64
+	priceChangePercent float64
+	maxRepricedPercent float64
+}
+
+func newGuard(cfg config.Guard) guard {
+	return guard{
+		maxDeletePercent:   cfg.MaxDeletePercent,
+		priceChangePercent: cfg.PriceChangePercent,
+		maxRepricedPercent: cfg.MaxRepricedPercent,
+	}
+}
+
+// GuardReport перечисляет офферы, из-за которых загрузка остановлена защитой каталога
+type GuardReport struct {
+	// размер каталога продавца до загрузки; доли считаются от него
+	CatalogSize uint64 `json:"catalogSize"`
+	// офферы, которые загрузка удалила бы, если доля удалений превышена
+	Deleted []uint64 `json:"deleted,omitempty"`
+	// офферы с резким изменением цены, если доля таких офферов превышена
+	Repriced []PriceChange `json:"repriced,omitempty"`
+}
+
+type PriceChange struct {
+	OfferId  uint64 `json:"offerId"`
+	OldPrice uint64 `json:"oldPrice"`
+	NewPrice uint64 `json:"newPrice"`
+}
+
+// enabled: задан хотя бы один порог
+func (g guard) enabled() bool {
+	return g.maxDeletePercent > 0 || g.checkPrices()
+}
+
+// checkPrices нужны сохранённые цены обновляемых товаров
+func (g guard) checkPrices() bool {
+	return g.priceChangePercent > 0 && g.maxRepricedPercent > 0
+}
+
+// check возвращает отчёт, если загрузка удаляет или резко переоценивает слишком большую долю каталога; иначе nil.
+// deleted - существующие офферы, которые загрузка удалит; repriced - резкие изменения цены из repricing.
+func (g guard) check(catalogSize uint64, deleted []uint64, repriced []PriceChange) *GuardReport {
+	if catalogSize == 0 {
+		return nil
+	}
+
+	report := GuardReport{CatalogSize: catalogSize}
+	if g.maxDeletePercent > 0 && percentOf(uint64(len(deleted)), catalogSize) > g.maxDeletePercent {
+		report.Deleted = deleted
+	}
+
+	if g.checkPrices() && percentOf(uint64(len(repriced)), catalogSize) > g.maxRepricedPercent {
+		report.Repriced = repriced
+	}
+
+	if len(report.Deleted) == 0 && len(report.Repriced) == 0 {
+		return nil
+	}
+
+	return &report
+}
+
+// repricing возвращает обновления, меняющие цену сильнее чем на priceChangePercent процентов;
+// stored - сохранённые состояния обновляемых товаров
+func (g guard) repricing(toUpd []models.Product, stored map[uint64]models.Product) []PriceChange {
+	if !g.checkPrices() {
+		return nil
+	}
+
+	repriced := make([]PriceChange, 0)
+	for _, product := range toUpd {
+		storedProduct, ok := stored[product.OfferId]
+		if !ok || storedProduct.Price == 0 {
+			continue
+		}
+
+		diff := float64(product.Price) - float64(storedProduct.Price)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff/float64(storedProduct.Price)*100 > g.priceChangePercent {
+			repriced = append(repriced, PriceChange{OfferId: product.OfferId, OldPrice: storedProduct.Price, NewPrice: product.Price})
+		}
+	}
+
+	return repriced
+}
+
+func percentOf(n uint64, total uint64) float64 {
+	return float64(n) / float64(total) * 100
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard_check(t *testing.T) {
+	g := newGuard(config.Guard{MaxDeletePercent: 20, PriceChangePercent: 50, MaxRepricedPercent: 10})
+	stored := map[uint64]models.Product{
+		1: {OfferId: 1, Price: 100},
+		2: {OfferId: 2, Price: 0},
+	}
+
+	testCases := []struct {
+		name        string
+		catalogSize uint64
+		deleted     []uint64
+		toUpd       []models.Product
+		want        *GuardReport
+	}{
+		{
+			name:        "удаление в пределах доли",
+			catalogSize: 10,
+			deleted:     []uint64{1, 2},
+		},
+		{
+			name:        "удаление сверх доли",
+			catalogSize: 10,
+			deleted:     []uint64{1, 2, 3},
+			want:        &GuardReport{CatalogSize: 10, Deleted: []uint64{1, 2, 3}},
+		},
+		{
+			name:        "пустой каталог не защищается",
+			catalogSize: 0,
+			deleted:     []uint64{1},
+		},
+		{
+			name:        "резкое изменение цены сверх доли",
+			catalogSize: 5,
+			toUpd:       []models.Product{{OfferId: 1, Price: 0}},
+			want:        &GuardReport{CatalogSize: 5, Repriced: []PriceChange{{OfferId: 1, OldPrice: 100, NewPrice: 0}}},
+		},
+		{
+			name:        "резкое изменение цены в пределах доли",
+			catalogSize: 20,
+			toUpd:       []models.Product{{OfferId: 1, Price: 0}},
+		},
+		{
+			name:        "умеренное изменение цены и товар без сохранённой цены",
+			catalogSize: 5,
+			toUpd:       []models.Product{{OfferId: 1, Price: 150}, {OfferId: 2, Price: 1000}, {OfferId: 3, Price: 1}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, g.check(tc.catalogSize, tc.deleted, g.repricing(tc.toUpd, stored)))
+		})
+	}
+
+	// без настроек защита выключена
+	off := newGuard(config.Guard{})
+	assert.Nil(t, off.check(1, []uint64{1}, off.repricing([]models.Product{{OfferId: 1, Price: 0}}, stored)))
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"github.com/hablof/merchant-experience/internal/models"
+)
+
+// ImportTx - транзакция загрузки таблицы в каталог продавца. Строки таблицы копятся в ней пачками (Stage)
+// и пишутся в каталог одним Apply; до Apply каталог не меняется.
+type ImportTx interface {
+	// toKeep - офферы, которые есть в таблице, но не пишутся; режим replace их не удаляет
+	Stage(toUpsert []models.Product, toDelete []uint64, toKeep []uint64) error
+	// число офферов продавца в каталоге
+	CatalogSize() (uint64, error)
+	// товары продавца, которых нет среди отложенных строк
+	MissingProducts() ([]models.Product, error)
+	// пишет отложенные строки в каталог и фиксирует транзакцию; replace - удалить офферы, которых нет в таблице
+	Apply(replace bool, source models.ChangeSource) (deleted uint64, purged uint64, err error)
+	Rollback() error
+}
+
+// Import - загрузка одной таблицы в каталог продавца: таблица проверяется пачками,
+// а в каталог попадает целиком или не попадает вовсе
+type Import interface {
+	// Stage проверяет пачку строк таблицы и откладывает её запись; защиту каталога проверяет Commit.
+	// presentOfferIDs - офферы из строк, не прошедших разбор: режим replace их не удаляет.
+	Stage(productUpdates []models.ProductUpdate, presentOfferIDs []uint64) (UpdateResults, error)
+	// Commit проверяет защиту каталога по всей таблице и, если она не сработала, пишет все отложенные пачки
+	// в каталог одной транзакцией; в предпросмотре только считает изменения
+	Commit() (UpdateResults, error)
+	// Rollback отменяет незавершённую загрузку; после Commit ничего не делает
+	Rollback()
+}
+
+type tableImport struct {
+	s        *Service
+	tx       ImportTx
+	sellerId uint64
+	opts     UpdateOptions
+	rules    rules
+
+	// размер каталога до загрузки - от него считает доли защита каталога
+	catalogSize uint64
+	// существующие офферы, удаляемые строками таблицы, и резкие изменения цены: защита проверяет их по всей таблице в Commit
+	deleted  []uint64
+	repriced []PriceChange
+	// в транзакции есть отложенные строки
+	staged bool
+	// транзакция закрыта
+	done bool
+}
+
+// BeginImport начинает загрузку таблицы в каталог продавца sellerId; загрузку завершает Commit или Rollback
+func (s *Service) BeginImport(sellerId uint64, opts UpdateOptions) (Import, error) {
+	tx, err := s.repo.BeginImport(sellerId)
+	if err != nil {
+		log.Println(err)
+		return nil, ErrRepoFailed
+	}
+
+	imp := &tableImport{
+		s:        s,
+		tx:       tx,
+		sellerId: sellerId,
+		opts:     opts,
+		rules:    s.rules.forSeller(sellerId),
+	}
+
+	if imp.checkGuard() {
+		imp.catalogSize, err = tx.CatalogSize()
+		if err != nil {
+			log.Println(err)
+			imp.Rollback()
+			return nil, ErrRepoFailed
+		}
+	}
+
+	return imp, nil
+}
+
+// checkGuard: загрузка не подтверждена продавцом и защита каталога включена
+func (imp *tableImport) checkGuard() bool {
+	return !imp.opts.Confirmed && imp.s.guard.enabled()
+}
+
+func (imp *tableImport) Stage(productUpdates []models.ProductUpdate, presentOfferIDs []uint64) (UpdateResults, error) {
+
+	// в режиме replace пачка может состоять только из строк с ошибками
+	if len(productUpdates) == 0 && len(presentOfferIDs) == 0 {
+		return UpdateResults{}, errors.New("empty request")
+	}
+
+	// сохранённые товары пачки делят её на добавления и обновления; их цены нужны правилам и защите каталога
+	stored := make(map[uint64]models.Product)
+	if len(productUpdates) > 0 {
+		offerIDs := make([]uint64, 0, len(productUpdates))
+		for _, upd := range productUpdates {
+			offerIDs = append(offerIDs, upd.Product.OfferId)
+		}
+
+		storedProducts, err := imp.s.repo.SellerProductsByIDs(imp.sellerId, offerIDs)
+		if err != nil {
+			log.Println(err)
+			return UpdateResults{}, ErrRepoFailed
+		}
+
+		for _, product := range storedProducts {
+			stored[product.OfferId] = product
+		}
+	}
+
+	toAdd := make([]models.Product, 0)
+	toUpd := make([]models.Product, 0)
+	toDel := make([]models.Product, 0)
+
+	for _, upd := range productUpdates {
+		_, exists := stored[upd.Product.OfferId]
+		switch {
+		case !upd.Available:
+			toDel = append(toDel, upd.Product)
+
+		case exists:
+			toUpd = append(toUpd, upd.Product)
+
+		default:
+			toAdd = append(toAdd, upd.Product)
+		}
+	}
+
+	validToAdd := make([]models.Product, 0, len(toAdd))
+	validToUpd := make([]models.Product, 0, len(toUpd))
+	issues := make([]models.ImportIssue, 0)
+	// офферы таблицы, которые не пишутся: в режиме replace их нельзя удалять
+	var toKeep []uint64
+	replace := imp.opts.Mode == models.SyncModeReplace
+
+	// проблемы товаров из таблицы адресуются строкой
+	rows := make(map[uint64]uint64, len(productUpdates))
+	for _, upd := range productUpdates {
+		rows[upd.Product.OfferId] = upd.Row
+	}
+
+	for _, product := range toAdd {
+		productIssues, ok := validate(imp.rules, product, nil, rows)
+		issues = append(issues, productIssues...)
+		switch {
+		case ok:
+			validToAdd = append(validToAdd, product)
+		case replace:
+			toKeep = append(toKeep, product.OfferId)
+		}
+	}
+	for _, product := range toUpd {
+		storedProduct := stored[product.OfferId]
+
+		productIssues, ok := validate(imp.rules, product, &storedProduct, rows)
+		issues = append(issues, productIssues...)
+		switch {
+		case ok:
+			validToUpd = append(validToUpd, product)
+		case replace:
+			toKeep = append(toKeep, product.OfferId)
+		}
+	}
+	if replace {
+		toKeep = append(toKeep, presentOfferIDs...)
+	}
+
+	// удаление несуществующего оффера каталог не уменьшает
+	toDelIDs := make([]uint64, 0, len(toDel))
+	deleted := make([]uint64, 0, len(toDel))
+	for _, product := range toDel {
+		toDelIDs = append(toDelIDs, product.OfferId)
+		if _, ok := stored[product.OfferId]; ok {
+			deleted = append(deleted, product.OfferId)
+		}
+	}
+
+	results := UpdateResults{
+		Added:   uint64(len(validToAdd)),
+		Updated: uint64(len(validToUpd)),
+		Issues:  issues,
+		DryRun:  imp.opts.DryRun,
+	}
+
+	if imp.checkGuard() {
+		imp.deleted = append(imp.deleted, deleted...)
+		imp.repriced = append(imp.repriced, imp.s.guard.repricing(validToUpd, stored)...)
+	}
+
+	if imp.opts.DryRun {
+		results.Deleted = uint64(len(deleted))
+		results.Diff = diffProducts(imp.sellerId, validToAdd, validToUpd, toDel, stored)
+	}
+
+	// предпросмотру откладывать строки нужно только для поиска офферов, которых нет в таблице
+	if imp.opts.DryRun && !replace {
+		return results, nil
+	}
+
+	toUpsert := make([]models.Product, 0, len(validToAdd)+len(validToUpd))
+	toUpsert = append(toUpsert, validToAdd...)
+	toUpsert = append(toUpsert, validToUpd...)
+	if len(toUpsert)+len(toDelIDs)+len(toKeep) == 0 {
+		return results, nil
+	}
+
+	if err := imp.tx.Stage(toUpsert, toDelIDs, toKeep); err != nil {
+		log.Println(err)
+		return UpdateResults{}, ErrRepoFailed
+	}
+	imp.staged = true
+
+	return results, nil
+}
+
+func (imp *tableImport) Commit() (UpdateResults, error) {
+	imp.done = true
+	replace := imp.opts.Mode == models.SyncModeReplace
+
+	// офферы, которых нет в таблице: режим replace их удалит
+	var missing []models.Product
+	if replace && imp.staged && (imp.opts.DryRun || imp.checkGuard()) {
+		var err error
+		missing, err = imp.tx.MissingProducts()
+		if err != nil {
+			log.Println(err)
+			imp.rollback()
+			return UpdateResults{}, ErrRepoFailed
+		}
+	}
+
+	// защита проверяется один раз на итогах всей таблицы: удаления строками вместе с удалением отсутствующих офферов
+	var guardReport *GuardReport
+	if imp.checkGuard() {
+		deleted := make([]uint64, 0, len(imp.deleted)+len(missing))
+		deleted = append(deleted, imp.deleted...)
+		for _, product := range missing {
+			deleted = append(deleted, product.OfferId)
+		}
+
+		guardReport = imp.s.guard.check(imp.catalogSize, deleted, imp.repriced)
+	}
+
+	// предпросмотр показывает, что защита сработает, но ничего не останавливает
+	if guardReport != nil && !imp.opts.DryRun {
+		imp.rollback()
+		return UpdateResults{Guard: guardReport}, ErrGuardTripped
+	}
+
+	if imp.opts.DryRun {
+		imp.rollback()
+
+		ur := UpdateResults{Purged: uint64(len(missing)), DryRun: true, Guard: guardReport}
+		for idx := range missing {
+			ur.Diff = append(ur.Diff, ProductDiff{OfferId: missing[idx].OfferId, Action: DiffActionPurge, Old: &missing[idx]})
+		}
+
+		return ur, nil
+	}
+
+	// пустая таблица не удаляет каталог и в режиме replace
+	if !imp.staged {
+		imp.rollback()
+		return UpdateResults{}, nil
+	}
+
+	deleted, purged, err := imp.tx.Apply(replace, imp.opts.Source)
+	if err != nil {
+		log.Println(err)
+		return UpdateResults{}, ErrRepoFailed
+	}
+
+	return UpdateResults{Deleted: deleted, Purged: purged}, nil
+}
+
+func (imp *tableImport) Rollback() {
+	if imp.done {
+		return
+	}
+
+	imp.done = true
+	imp.rollback()
+}
+
+func (imp *tableImport) rollback() {
+	if err := imp.tx.Rollback(); err != nil {
+		log.Println(err)
+	}
+}
+
+// diffProducts сравнивает классифицированные товары пачки с сохранёнными.
+// Обновления, которые ничего не меняют, в diff не попадают;
+// удаление несуществующего оффера не считается.
+func diffProducts(sellerId uint64, toAdd, toUpd, toDel []models.Product, stored map[uint64]models.Product) []ProductDiff {
+	diff := make([]ProductDiff, 0, len(toAdd)+len(toUpd)+len(toDel))
+	for _, product := range toAdd {
+		newProduct := product
+		newProduct.SellerId = sellerId
+		diff = append(diff, ProductDiff{OfferId: product.OfferId, Action: DiffActionAdd, New: &newProduct})
+	}
+
+	for _, product := range toUpd {
+		newProduct := product
+		newProduct.SellerId = sellerId
+
+		oldProduct, ok := stored[product.OfferId]
+		if ok && oldProduct == newProduct {
+			continue
+		}
+
+		pd := ProductDiff{OfferId: product.OfferId, Action: DiffActionUpdate, New: &newProduct}
+		if ok {
+			pd.Old = &oldProduct
+		}
+		diff = append(diff, pd)
+	}
+
+	for _, product := range toDel {
+		oldProduct, ok := stored[product.OfferId]
+		if !ok {
+			continue
+		}
+
+		diff = append(diff, ProductDiff{OfferId: product.OfferId, Action: DiffActionDelete, Old: &oldProduct})
+	}
+
+	return diff
+}
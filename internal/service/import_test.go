@@ -0,0 +1,715 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// importResults - результаты загрузки таблицы одной пачкой
+type importResults struct {
+	stage     UpdateResults
+	stageErr  error
+	commit    UpdateResults
+	commitErr error
+}
+
+// importTable загружает таблицу одной пачкой так же, как импортёр: при ошибке пачки загрузка отменяется
+func importTable(t *testing.T, s *Service, sellerId uint64, productUpdates []models.ProductUpdate, presentOfferIDs []uint64, opts UpdateOptions) importResults {
+	imp, err := s.BeginImport(sellerId, opts)
+	if !assert.NoError(t, err, "begin import") {
+		return importResults{}
+	}
+	defer imp.Rollback()
+
+	res := importResults{}
+	res.stage, res.stageErr = imp.Stage(productUpdates, presentOfferIDs)
+	if res.stageErr != nil {
+		return res
+	}
+
+	res.commit, res.commitErr = imp.Commit()
+	return res
+}
+
+func TestImport(t *testing.T) {
+	testCases := []struct {
+		name           string
+		sellerId       uint64
+		productUpdates []models.ProductUpdate
+
+		repoBehavior func(rMock *RepositoryMock, txMock *ImportTxMock)
+
+		want importResults
+	}{
+		{
+			name:           "пустой запрос",
+			sellerId:       0,
+			productUpdates: []models.ProductUpdate{},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				txMock.RollbackMock.Return(nil)
+			},
+			want: importResults{stageErr: errors.New("empty request")},
+		},
+		{
+			name:     "валидный запрос: только добавление",
+			sellerId: 1,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "test1", Price: 100, Quantity: 5}, Available: true},
+				{Product: models.Product{OfferId: 2, Name: "test2", Price: 1000, Quantity: 50}, Available: true},
+				{Product: models.Product{OfferId: 3, Name: "test3", Price: 10, Quantity: 20}, Available: true},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1, 2, 3}).Return([]models.Product{}, nil)
+				txMock.StageMock.Expect(
+					[]models.Product{
+						{OfferId: 1, Name: "test1", Price: 100, Quantity: 5},
+						{OfferId: 2, Name: "test2", Price: 1000, Quantity: 50},
+						{OfferId: 3, Name: "test3", Price: 10, Quantity: 20},
+					},
+					[]uint64{},
+					nil,
+				).Return(nil)
+				txMock.ApplyMock.Expect(false, models.ChangeSource{}).Return(0, 0, nil)
+			},
+			want: importResults{
+				stage: UpdateResults{Added: 3, Issues: []models.ImportIssue{}},
+			},
+		},
+		{
+			name:     "валидный запрос: только обновление",
+			sellerId: 1,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "test11", Price: 100, Quantity: 5}, Available: true},
+				{Product: models.Product{OfferId: 2, Name: "test22", Price: 1000, Quantity: 50}, Available: true},
+				{Product: models.Product{OfferId: 3, Name: "test33", Price: 10, Quantity: 20}, Available: true},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1, 2, 3}).Return([]models.Product{
+					{SellerId: 1, OfferId: 1, Name: "test1", Price: 100, Quantity: 5},
+					{SellerId: 1, OfferId: 2, Name: "test2", Price: 1000, Quantity: 50},
+					{SellerId: 1, OfferId: 3, Name: "test3", Price: 10, Quantity: 20},
+				}, nil)
+				txMock.StageMock.Expect(
+					[]models.Product{
+						{OfferId: 1, Name: "test11", Price: 100, Quantity: 5},
+						{OfferId: 2, Name: "test22", Price: 1000, Quantity: 50},
+						{OfferId: 3, Name: "test33", Price: 10, Quantity: 20},
+					},
+					[]uint64{},
+					nil,
+				).Return(nil)
+				txMock.ApplyMock.Expect(false, models.ChangeSource{}).Return(0, 0, nil)
+			},
+			want: importResults{
+				stage: UpdateResults{Updated: 3, Issues: []models.ImportIssue{}},
+			},
+		},
+		{
+			name:     "валидный запрос: только удаление",
+			sellerId: 1,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "test11", Price: 100, Quantity: 5}, Available: false},
+				{Product: models.Product{OfferId: 2, Name: "test22", Price: 1000, Quantity: 50}, Available: false},
+				{Product: models.Product{OfferId: 3, Name: "test33", Price: 10, Quantity: 20}, Available: false},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1, 2, 3}).Return([]models.Product{
+					{SellerId: 1, OfferId: 1, Name: "test11", Price: 100, Quantity: 5},
+					{SellerId: 1, OfferId: 2, Name: "test22", Price: 1000, Quantity: 50},
+					{SellerId: 1, OfferId: 3, Name: "test33", Price: 10, Quantity: 20},
+				}, nil)
+				txMock.StageMock.Expect([]models.Product{}, []uint64{1, 2, 3}, nil).Return(nil)
+				txMock.ApplyMock.Expect(false, models.ChangeSource{}).Return(3, 0, nil)
+			},
+			want: importResults{
+				stage:  UpdateResults{Issues: []models.ImportIssue{}},
+				commit: UpdateResults{Deleted: 3},
+			},
+		},
+		{
+			name:     "валидный запрос: два добавления, два удаления, два обновления",
+			sellerId: 42,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "первый кандидат на удаление", Price: 15, Quantity: 15}, Available: false},
+				{Product: models.Product{OfferId: 2, Name: "первый кандидат на добавление", Price: 125, Quantity: 10}, Available: true},
+				{Product: models.Product{OfferId: 3, Name: "первый кандидат на обновление", Price: 4990, Quantity: 1}, Available: true},
+				{Product: models.Product{OfferId: 4, Name: "второй кандидат на удаление", Price: 200, Quantity: 5}, Available: false},
+				{Product: models.Product{OfferId: 5, Name: "второй кандидат на добавление", Price: 125, Quantity: 10}, Available: true},
+				{Product: models.Product{OfferId: 6, Name: "второй кандидат на обновление", Price: 15000, Quantity: 60}, Available: true},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(42, []uint64{1, 2, 3, 4, 5, 6}).Return([]models.Product{
+					{SellerId: 42, OfferId: 1, Name: "первый кандидат на удаление", Price: 15, Quantity: 15},
+					{SellerId: 42, OfferId: 3, Name: "первый кандидат на обновление", Price: 5000, Quantity: 1},
+					{SellerId: 42, OfferId: 4, Name: "второй кандидат на удаление", Price: 200, Quantity: 5},
+					{SellerId: 42, OfferId: 6, Name: "второй кандидат на обновление", Price: 15000, Quantity: 50},
+				}, nil)
+				txMock.StageMock.Expect(
+					[]models.Product{
+						{OfferId: 2, Name: "первый кандидат на добавление", Price: 125, Quantity: 10},
+						{OfferId: 5, Name: "второй кандидат на добавление", Price: 125, Quantity: 10},
+						{OfferId: 3, Name: "первый кандидат на обновление", Price: 4990, Quantity: 1},
+						{OfferId: 6, Name: "второй кандидат на обновление", Price: 15000, Quantity: 60},
+					},
+					[]uint64{1, 4},
+					nil,
+				).Return(nil)
+				txMock.ApplyMock.Expect(false, models.ChangeSource{}).Return(2, 0, nil)
+			},
+			want: importResults{
+				stage:  UpdateResults{Added: 2, Updated: 2, Issues: []models.ImportIssue{}},
+				commit: UpdateResults{Deleted: 2},
+			},
+		},
+		{
+			name:     "добавляем и обновляем только длинные (невалидные) названия",
+			sellerId: 70,
+			productUpdates: []models.ProductUpdate{
+				{
+					Product:   models.Product{OfferId: 15, Name: "ну очень очень очень очень очень очень очень очень очень очень очень очень очень очень очень длинное название"},
+					Available: true,
+				},
+				{
+					Product:   models.Product{OfferId: 16, Name: "ну очень очень очень очень очень очень очень очень очень очень очень очень очень очень очень длинное название"},
+					Available: true,
+					Row:       3,
+				},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(70, []uint64{15, 16}).Return([]models.Product{{SellerId: 70, OfferId: 15, Name: "short"}}, nil)
+				// писать нечего: Stage и Apply не вызываются
+				txMock.RollbackMock.Return(nil)
+			},
+			want: importResults{
+				stage: UpdateResults{
+					Issues: []models.ImportIssue{
+						{Row: 3, OfferId: 16, Field: "name", Code: models.CodeTooLongName, Severity: models.SeverityError, Message: models.MsgTooLongName},
+						{OfferId: 15, Field: "name", Code: models.CodeTooLongName, Severity: models.SeverityError, Message: models.MsgTooLongName},
+					},
+				},
+			},
+		},
+		{
+			name:     "ошибка записи: каталог не меняется",
+			sellerId: 1,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "test1", Price: 100, Quantity: 5}, Available: true},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1}).Return([]models.Product{}, nil)
+				txMock.StageMock.Expect([]models.Product{{OfferId: 1, Name: "test1", Price: 100, Quantity: 5}}, []uint64{}, nil).Return(nil)
+				txMock.ApplyMock.Expect(false, models.ChangeSource{}).Return(0, 0, errors.New("some error"))
+			},
+			want: importResults{
+				stage:     UpdateResults{Added: 1, Issues: []models.ImportIssue{}},
+				commitErr: ErrRepoFailed,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rMock := NewRepositoryMock(mc)
+			txMock := NewImportTxMock(mc)
+			rMock.BeginImportMock.Expect(tc.sellerId).Return(txMock, nil)
+			tc.repoBehavior(rMock, txMock)
+
+			s := Service{
+				repo: rMock,
+			}
+			got := importTable(t, &s, tc.sellerId, tc.productUpdates, nil, UpdateOptions{})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestImport_BeginFailed(t *testing.T) {
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	rMock := NewRepositoryMock(mc)
+	rMock.BeginImportMock.Expect(1).Return(nil, errors.New("some error"))
+
+	s := Service{
+		repo: rMock,
+	}
+	imp, err := s.BeginImport(1, UpdateOptions{})
+	assert.Nil(t, imp)
+	assert.Equal(t, ErrRepoFailed, err)
+}
+
+func TestImport_DryRun(t *testing.T) {
+	testCases := []struct {
+		name           string
+		sellerId       uint64
+		productUpdates []models.ProductUpdate
+
+		repoBehavior func(rMock *RepositoryMock, txMock *ImportTxMock)
+
+		want importResults
+	}{
+		{
+			name:     "только добавление",
+			sellerId: 1,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "test1", Price: 100, Quantity: 5}, Available: true},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1}).Return([]models.Product{}, nil)
+			},
+			want: importResults{
+				stage: UpdateResults{
+					Added:  1,
+					Issues: []models.ImportIssue{},
+					DryRun: true,
+					Diff: []ProductDiff{
+						{OfferId: 1, Action: DiffActionAdd, New: &models.Product{SellerId: 1, OfferId: 1, Name: "test1", Price: 100, Quantity: 5}},
+					},
+				},
+				commit: UpdateResults{DryRun: true},
+			},
+		},
+		{
+			name:     "добавление, обновление, удаление",
+			sellerId: 2,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "new", Price: 10, Quantity: 1}, Available: true},
+				{Product: models.Product{OfferId: 2, Name: "changed", Price: 0, Quantity: 2}, Available: true},
+				{Product: models.Product{OfferId: 3, Name: "same", Price: 30, Quantity: 3}, Available: true},
+				{Product: models.Product{OfferId: 4, Name: "gone", Price: 40, Quantity: 4}, Available: false},
+				{Product: models.Product{OfferId: 5, Name: "never existed", Price: 50, Quantity: 5}, Available: false},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(2, []uint64{1, 2, 3, 4, 5}).Return([]models.Product{
+					{SellerId: 2, OfferId: 2, Name: "changed", Price: 20, Quantity: 2},
+					{SellerId: 2, OfferId: 3, Name: "same", Price: 30, Quantity: 3},
+					{SellerId: 2, OfferId: 4, Name: "gone", Price: 40, Quantity: 4},
+				}, nil)
+			},
+			want: importResults{
+				stage: UpdateResults{
+					Added:   1,
+					Updated: 2,
+					Deleted: 1,
+					Issues:  []models.ImportIssue{},
+					DryRun:  true,
+					Diff: []ProductDiff{
+						{OfferId: 1, Action: DiffActionAdd, New: &models.Product{SellerId: 2, OfferId: 1, Name: "new", Price: 10, Quantity: 1}},
+						{
+							OfferId: 2,
+							Action:  DiffActionUpdate,
+							Old:     &models.Product{SellerId: 2, OfferId: 2, Name: "changed", Price: 20, Quantity: 2},
+							New:     &models.Product{SellerId: 2, OfferId: 2, Name: "changed", Price: 0, Quantity: 2},
+						},
+						{OfferId: 4, Action: DiffActionDelete, Old: &models.Product{SellerId: 2, OfferId: 4, Name: "gone", Price: 40, Quantity: 4}},
+					},
+				},
+				commit: UpdateResults{DryRun: true},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rMock := NewRepositoryMock(mc)
+			txMock := NewImportTxMock(mc)
+			rMock.BeginImportMock.Expect(tc.sellerId).Return(txMock, nil)
+			// в режиме merge предпросмотру нечего откладывать: StageMock и ApplyMock не настроены
+			txMock.RollbackMock.Return(nil)
+			tc.repoBehavior(rMock, txMock)
+
+			s := Service{
+				repo: rMock,
+			}
+			got := importTable(t, &s, tc.sellerId, tc.productUpdates, nil, UpdateOptions{DryRun: true})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestImport_Replace(t *testing.T) {
+	testCases := []struct {
+		name            string
+		productUpdates  []models.ProductUpdate
+		presentOfferIDs []uint64
+		opts            UpdateOptions
+
+		repoBehavior func(rMock *RepositoryMock, txMock *ImportTxMock)
+
+		want importResults
+	}{
+		{
+			name: "отсутствующие в таблице офферы удаляются",
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 2, Name: "kept", Price: 20, Quantity: 2}, Available: true},
+				{Product: models.Product{OfferId: 3, Name: "gone", Price: 30, Quantity: 3}, Available: false},
+			},
+			presentOfferIDs: []uint64{4}, // строка с ошибкой разбора
+			opts: UpdateOptions{
+				Source: models.ChangeSource{Kind: models.ChangeKindImport, JobId: 7},
+				Mode:   models.SyncModeReplace,
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{2, 3}).Return([]models.Product{
+					{SellerId: 1, OfferId: 2, Name: "kept", Price: 20, Quantity: 2},
+					{SellerId: 1, OfferId: 3, Name: "gone", Price: 30, Quantity: 3},
+				}, nil)
+				txMock.StageMock.Expect([]models.Product{{OfferId: 2, Name: "kept", Price: 20, Quantity: 2}}, []uint64{3}, []uint64{4}).Return(nil)
+				txMock.ApplyMock.Expect(true, models.ChangeSource{Kind: models.ChangeKindImport, JobId: 7}).Return(1, 2, nil)
+			},
+			want: importResults{
+				stage:  UpdateResults{Updated: 1, Issues: []models.ImportIssue{}},
+				commit: UpdateResults{Deleted: 1, Purged: 2},
+			},
+		},
+		{
+			name: "оффер с ошибкой проверки не удаляется",
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "ну очень очень очень очень очень очень очень очень очень очень очень очень очень очень очень длинное название", Price: 10, Quantity: 1}, Available: true, Row: 2},
+			},
+			opts: UpdateOptions{Mode: models.SyncModeReplace},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1}).Return([]models.Product{{SellerId: 1, OfferId: 1, Name: "name", Price: 10, Quantity: 1}}, nil)
+				txMock.StageMock.Expect([]models.Product{}, []uint64{}, []uint64{1}).Return(nil)
+				txMock.ApplyMock.Expect(true, models.ChangeSource{}).Return(0, 0, nil)
+			},
+			want: importResults{
+				stage: UpdateResults{Issues: []models.ImportIssue{
+					{Row: 2, OfferId: 1, Field: "name", Code: models.CodeTooLongName, Severity: models.SeverityError, Message: models.MsgTooLongName},
+				}},
+			},
+		},
+		{
+			name: "dry-run: удаляемые офферы попадают в diff",
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "new", Price: 10, Quantity: 1}, Available: true},
+			},
+			opts: UpdateOptions{DryRun: true, Mode: models.SyncModeReplace},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1}).Return([]models.Product{}, nil)
+				txMock.StageMock.Expect([]models.Product{{OfferId: 1, Name: "new", Price: 10, Quantity: 1}}, []uint64{}, nil).Return(nil)
+				txMock.MissingProductsMock.Return([]models.Product{{SellerId: 1, OfferId: 2, Name: "old", Price: 20, Quantity: 2}}, nil)
+				txMock.RollbackMock.Return(nil)
+			},
+			want: importResults{
+				stage: UpdateResults{
+					Added:  1,
+					Issues: []models.ImportIssue{},
+					DryRun: true,
+					Diff: []ProductDiff{
+						{OfferId: 1, Action: DiffActionAdd, New: &models.Product{SellerId: 1, OfferId: 1, Name: "new", Price: 10, Quantity: 1}},
+					},
+				},
+				commit: UpdateResults{
+					Purged: 1,
+					DryRun: true,
+					Diff: []ProductDiff{
+						{OfferId: 2, Action: DiffActionPurge, Old: &models.Product{SellerId: 1, OfferId: 2, Name: "old", Price: 20, Quantity: 2}},
+					},
+				},
+			},
+		},
+		{
+			name:            "пачка только из строк с ошибками",
+			productUpdates:  []models.ProductUpdate{},
+			presentOfferIDs: []uint64{1, 3},
+			opts:            UpdateOptions{Mode: models.SyncModeReplace},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				txMock.StageMock.Expect([]models.Product{}, []uint64{}, []uint64{1, 3}).Return(nil)
+				txMock.ApplyMock.Expect(true, models.ChangeSource{}).Return(0, 1, nil)
+			},
+			want: importResults{
+				stage:  UpdateResults{Issues: []models.ImportIssue{}},
+				commit: UpdateResults{Purged: 1},
+			},
+		},
+		{
+			name:           "пустая таблица не удаляет офферы продавца",
+			productUpdates: []models.ProductUpdate{},
+			opts:           UpdateOptions{Mode: models.SyncModeReplace},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				txMock.RollbackMock.Return(nil)
+			},
+			want: importResults{stageErr: errors.New("empty request")},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rMock := NewRepositoryMock(mc)
+			txMock := NewImportTxMock(mc)
+			rMock.BeginImportMock.Expect(1).Return(txMock, nil)
+			tc.repoBehavior(rMock, txMock)
+
+			s := Service{
+				repo: rMock,
+			}
+			got := importTable(t, &s, 1, tc.productUpdates, tc.presentOfferIDs, tc.opts)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestImport_Rules(t *testing.T) {
+	validation := config.Validation{
+		Default: config.Rules{MinPrice: 1, MaxPriceChangePercent: 50},
+		Sellers: map[uint64]config.Rules{
+			// у продавца 2 свои правила: изменение цены не ограничено
+			2: {MinPrice: 1},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		sellerId       uint64
+		productUpdates []models.ProductUpdate
+
+		repoBehavior func(rMock *RepositoryMock, txMock *ImportTxMock)
+
+		want importResults
+	}{
+		{
+			name:     "нарушения правил не прерывают загрузку",
+			sellerId: 1,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "free", Price: 0, Quantity: 1}, Available: true, Row: 2},
+				{Product: models.Product{OfferId: 2, Name: "half", Price: 50, Quantity: 1}, Available: true, Row: 3},
+				{Product: models.Product{OfferId: 3, Name: "drop", Price: 10, Quantity: 1}, Available: true, Row: 4},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1, 2, 3}).Return([]models.Product{
+					{SellerId: 1, OfferId: 2, Name: "half", Price: 100, Quantity: 1},
+					{SellerId: 1, OfferId: 3, Name: "drop", Price: 100, Quantity: 1},
+				}, nil)
+				txMock.StageMock.Expect([]models.Product{{OfferId: 2, Name: "half", Price: 50, Quantity: 1}}, []uint64{}, nil).Return(nil)
+				txMock.ApplyMock.Expect(false, models.ChangeSource{}).Return(0, 0, nil)
+			},
+			want: importResults{
+				stage: UpdateResults{
+					Updated: 1,
+					Issues: []models.ImportIssue{
+						{Row: 2, OfferId: 1, Field: "price", Code: models.CodePriceTooLow, Severity: models.SeverityError, Message: "price 0 is less than 1"},
+						{Row: 4, OfferId: 3, Field: "price", Code: models.CodePriceChangeTooHigh, Severity: models.SeverityError, Message: "price changes from 100 to 10 (90.0%), more than 50%"},
+					},
+				},
+			},
+		},
+		{
+			name:     "правила продавца заменяют общие",
+			sellerId: 2,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 3, Name: "drop", Price: 10, Quantity: 1}, Available: true, Row: 2},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(2, []uint64{3}).Return([]models.Product{{SellerId: 2, OfferId: 3, Name: "drop", Price: 100, Quantity: 1}}, nil)
+				txMock.StageMock.Expect([]models.Product{{OfferId: 3, Name: "drop", Price: 10, Quantity: 1}}, []uint64{}, nil).Return(nil)
+				txMock.ApplyMock.Expect(false, models.ChangeSource{}).Return(0, 0, nil)
+			},
+			want: importResults{
+				stage: UpdateResults{Updated: 1, Issues: []models.ImportIssue{}},
+			},
+		},
+		{
+			name:     "ошибка чтения сохранённых товаров",
+			sellerId: 1,
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 3, Name: "drop", Price: 10, Quantity: 1}, Available: true, Row: 2},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{3}).Return(nil, errors.New("some error"))
+				txMock.RollbackMock.Return(nil)
+			},
+			want: importResults{stageErr: errors.New("repo err")},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rMock := NewRepositoryMock(mc)
+			txMock := NewImportTxMock(mc)
+			rMock.BeginImportMock.Expect(tc.sellerId).Return(txMock, nil)
+			tc.repoBehavior(rMock, txMock)
+
+			s := NewService(config.Config{Validation: validation}, rMock)
+			got := importTable(t, s, tc.sellerId, tc.productUpdates, nil, UpdateOptions{})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestImport_Guard(t *testing.T) {
+	guardCfg := config.Guard{MaxDeletePercent: 50, PriceChangePercent: 50, MaxRepricedPercent: 30}
+
+	testCases := []struct {
+		name           string
+		productUpdates []models.ProductUpdate
+		opts           UpdateOptions
+
+		repoBehavior func(rMock *RepositoryMock, txMock *ImportTxMock)
+
+		want importResults
+	}{
+		{
+			// строка удаляет 1 оффер из 4, replace - ещё 2: по отдельности в пределах доли, вместе - нет
+			name: "удаления строками и отсутствующие офферы считаются вместе",
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "kept", Price: 10, Quantity: 1}, Available: true},
+				{Product: models.Product{OfferId: 2, Name: "gone", Price: 20, Quantity: 1}, Available: false},
+				{Product: models.Product{OfferId: 9, Name: "never existed", Price: 20, Quantity: 1}, Available: false},
+			},
+			opts: UpdateOptions{Mode: models.SyncModeReplace},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				txMock.CatalogSizeMock.Return(4, nil)
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1, 2, 9}).Return([]models.Product{
+					{SellerId: 1, OfferId: 1, Name: "kept", Price: 10, Quantity: 1},
+					{SellerId: 1, OfferId: 2, Name: "gone", Price: 20, Quantity: 1},
+				}, nil)
+				txMock.StageMock.Expect([]models.Product{{OfferId: 1, Name: "kept", Price: 10, Quantity: 1}}, []uint64{2, 9}, nil).Return(nil)
+				txMock.MissingProductsMock.Return([]models.Product{{SellerId: 1, OfferId: 3}, {SellerId: 1, OfferId: 4}}, nil)
+				// ApplyMock не настроен: запись уронит тест
+				txMock.RollbackMock.Return(nil)
+			},
+			want: importResults{
+				stage:     UpdateResults{Updated: 1, Issues: []models.ImportIssue{}},
+				commit:    UpdateResults{Guard: &GuardReport{CatalogSize: 4, Deleted: []uint64{2, 3, 4}}},
+				commitErr: ErrGuardTripped,
+			},
+		},
+		{
+			name: "резкое изменение цен у большой доли каталога останавливается",
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "zero", Price: 0, Quantity: 1}, Available: true},
+				{Product: models.Product{OfferId: 2, Name: "double", Price: 300, Quantity: 1}, Available: true},
+				{Product: models.Product{OfferId: 3, Name: "slight", Price: 110, Quantity: 1}, Available: true},
+			},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				txMock.CatalogSizeMock.Return(3, nil)
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1, 2, 3}).Return([]models.Product{
+					{SellerId: 1, OfferId: 1, Name: "zero", Price: 100, Quantity: 1},
+					{SellerId: 1, OfferId: 2, Name: "double", Price: 100, Quantity: 1},
+					{SellerId: 1, OfferId: 3, Name: "slight", Price: 100, Quantity: 1},
+				}, nil)
+				txMock.StageMock.Expect([]models.Product{
+					{OfferId: 1, Name: "zero", Price: 0, Quantity: 1},
+					{OfferId: 2, Name: "double", Price: 300, Quantity: 1},
+					{OfferId: 3, Name: "slight", Price: 110, Quantity: 1},
+				}, []uint64{}, nil).Return(nil)
+				txMock.RollbackMock.Return(nil)
+			},
+			want: importResults{
+				stage: UpdateResults{Updated: 3, Issues: []models.ImportIssue{}},
+				commit: UpdateResults{
+					Guard: &GuardReport{CatalogSize: 3, Repriced: []PriceChange{
+						{OfferId: 1, OldPrice: 100, NewPrice: 0},
+						{OfferId: 2, OldPrice: 100, NewPrice: 300},
+					}},
+				},
+				commitErr: ErrGuardTripped,
+			},
+		},
+		{
+			name: "подтверждённая загрузка записывается",
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "zero", Price: 0, Quantity: 1}, Available: true},
+				{Product: models.Product{OfferId: 2, Name: "gone", Price: 20, Quantity: 1}, Available: false},
+			},
+			opts: UpdateOptions{Confirmed: true},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{1, 2}).Return([]models.Product{
+					{SellerId: 1, OfferId: 1, Name: "zero", Price: 100, Quantity: 1},
+					{SellerId: 1, OfferId: 2, Name: "gone", Price: 20, Quantity: 1},
+				}, nil)
+				txMock.StageMock.Expect([]models.Product{{OfferId: 1, Name: "zero", Price: 0, Quantity: 1}}, []uint64{2}, nil).Return(nil)
+				txMock.ApplyMock.Expect(false, models.ChangeSource{}).Return(1, 0, nil)
+			},
+			want: importResults{
+				stage:  UpdateResults{Updated: 1, Issues: []models.ImportIssue{}},
+				commit: UpdateResults{Deleted: 1},
+			},
+		},
+		{
+			name: "dry-run показывает срабатывание защиты без ошибки",
+			productUpdates: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 2, Name: "gone", Price: 20, Quantity: 1}, Available: false},
+			},
+			opts: UpdateOptions{DryRun: true},
+			repoBehavior: func(rMock *RepositoryMock, txMock *ImportTxMock) {
+				txMock.CatalogSizeMock.Return(1, nil)
+				rMock.SellerProductsByIDsMock.Expect(1, []uint64{2}).
+					Return([]models.Product{{SellerId: 1, OfferId: 2, Name: "gone", Price: 20, Quantity: 1}}, nil)
+				txMock.RollbackMock.Return(nil)
+			},
+			want: importResults{
+				stage: UpdateResults{
+					Deleted: 1,
+					Issues:  []models.ImportIssue{},
+					DryRun:  true,
+					Diff: []ProductDiff{
+						{OfferId: 2, Action: DiffActionDelete, Old: &models.Product{SellerId: 1, OfferId: 2, Name: "gone", Price: 20, Quantity: 1}},
+					},
+				},
+				commit: UpdateResults{DryRun: true, Guard: &GuardReport{CatalogSize: 1, Deleted: []uint64{2}}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rMock := NewRepositoryMock(mc)
+			txMock := NewImportTxMock(mc)
+			rMock.BeginImportMock.Expect(1).Return(txMock, nil)
+			tc.repoBehavior(rMock, txMock)
+
+			s := NewService(config.Config{Guard: guardCfg}, rMock)
+			got := importTable(t, s, 1, tc.productUpdates, nil, tc.opts)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestImport_GuardWholeTable(t *testing.T) {
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	rMock := NewRepositoryMock(mc)
+	txMock := NewImportTxMock(mc)
+	rMock.BeginImportMock.Expect(1).Return(txMock, nil)
+	txMock.CatalogSizeMock.Return(4, nil)
+	rMock.SellerProductsByIDsMock.When(1, []uint64{1}).Then([]models.Product{{SellerId: 1, OfferId: 1, Price: 10}}, nil)
+	rMock.SellerProductsByIDsMock.When(1, []uint64{2}).Then([]models.Product{{SellerId: 1, OfferId: 2, Price: 20}}, nil)
+	txMock.StageMock.Set(func(toUpsert []models.Product, toDelete []uint64, toKeep []uint64) error { return nil })
+	// ApplyMock не настроен: ни одна пачка не должна попасть в каталог
+	txMock.RollbackMock.Return(nil)
+
+	s := NewService(config.Config{Guard: config.Guard{MaxDeletePercent: 30}}, rMock)
+	imp, err := s.BeginImport(1, UpdateOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer imp.Rollback()
+
+	// каждая пачка удаляет четверть каталога - в пределах доли, вся таблица - половину
+	for _, offerId := range []uint64{1, 2} {
+		_, err := imp.Stage([]models.ProductUpdate{{Product: models.Product{OfferId: offerId}, Available: false}}, nil)
+		assert.NoError(t, err)
+	}
+
+	ur, err := imp.Commit()
+	assert.Equal(t, ErrGuardTripped, err)
+	assert.Equal(t, UpdateResults{Guard: &GuardReport{CatalogSize: 4, Deleted: []uint64{1, 2}}}, ur)
+}
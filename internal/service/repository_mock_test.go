@@ -17,260 +17,1399 @@ import (
 type RepositoryMock struct {
 	t minimock.Tester
 
-	funcManageProducts          func(sellerId uint64, productsToAdd []models.Product, productsToDelete []models.Product, productsToUpdate []models.Product) (u1 uint64, err error)
-	inspectFuncManageProducts   func(sellerId uint64, productsToAdd []models.Product, productsToDelete []models.Product, productsToUpdate []models.Product)
-	afterManageProductsCounter  uint64
-	beforeManageProductsCounter uint64
-	ManageProductsMock          mRepositoryMockManageProducts
+	funcBeginImport          func(sellerId uint64) (i1 ImportTx, err error)
+	inspectFuncBeginImport   func(sellerId uint64)
+	afterBeginImportCounter  uint64
+	beforeBeginImportCounter uint64
+	BeginImportMock          mRepositoryMockBeginImport
+
+	funcDeleteProduct          func(sellerId uint64, offerId uint64, source models.ChangeSource) (err error)
+	inspectFuncDeleteProduct   func(sellerId uint64, offerId uint64, source models.ChangeSource)
+	afterDeleteProductCounter  uint64
+	beforeDeleteProductCounter uint64
+	DeleteProductMock          mRepositoryMockDeleteProduct
+
+	funcExportProducts          func(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) (err error)
+	inspectFuncExportProducts   func(sellerId uint64, pageSize uint64, handle func(products []models.Product) error)
+	afterExportProductsCounter  uint64
+	beforeExportProductsCounter uint64
+	ExportProductsMock          mRepositoryMockExportProducts
+
+	funcPatchProduct          func(sellerId uint64, offerId uint64, patch ProductPatch, source models.ChangeSource) (p1 models.Product, err error)
+	inspectFuncPatchProduct   func(sellerId uint64, offerId uint64, patch ProductPatch, source models.ChangeSource)
+	afterPatchProductCounter  uint64
+	beforePatchProductCounter uint64
+	PatchProductMock          mRepositoryMockPatchProduct
+
+	funcProduct          func(sellerId uint64, offerId uint64) (p1 models.Product, err error)
+	inspectFuncProduct   func(sellerId uint64, offerId uint64)
+	afterProductCounter  uint64
+	beforeProductCounter uint64
+	ProductMock          mRepositoryMockProduct
+
+	funcProductHistory          func(sellerId uint64, offerId uint64, limit uint64) (pa1 []models.ProductChange, err error)
+	inspectFuncProductHistory   func(sellerId uint64, offerId uint64, limit uint64)
+	afterProductHistoryCounter  uint64
+	beforeProductHistoryCounter uint64
+	ProductHistoryMock          mRepositoryMockProductHistory
+
+	funcProductsByFilter          func(filter RequestFilter) (p1 ProductsPage, err error)
+	inspectFuncProductsByFilter   func(filter RequestFilter)
+	afterProductsByFilterCounter  uint64
+	beforeProductsByFilterCounter uint64
+	ProductsByFilterMock          mRepositoryMockProductsByFilter
+
+	funcPutProduct          func(product models.Product, source models.ChangeSource) (created bool, err error)
+	inspectFuncPutProduct   func(product models.Product, source models.ChangeSource)
+	afterPutProductCounter  uint64
+	beforePutProductCounter uint64
+	PutProductMock          mRepositoryMockPutProduct
+
+	funcSellerProductsByIDs          func(sellerId uint64, offerIDs []uint64) (pa1 []models.Product, err error)
+	inspectFuncSellerProductsByIDs   func(sellerId uint64, offerIDs []uint64)
+	afterSellerProductsByIDsCounter  uint64
+	beforeSellerProductsByIDsCounter uint64
+	SellerProductsByIDsMock          mRepositoryMockSellerProductsByIDs
+}
+
+// NewRepositoryMock returns a mock for Repository
+func NewRepositoryMock(t minimock.Tester) *RepositoryMock {
+	m := &RepositoryMock{t: t}
+	if controller, ok := t.(minimock.MockController); ok {
+		controller.RegisterMocker(m)
+	}
+
+	m.BeginImportMock = mRepositoryMockBeginImport{mock: m}
+	m.BeginImportMock.callArgs = []*RepositoryMockBeginImportParams{}
+
+	m.DeleteProductMock = mRepositoryMockDeleteProduct{mock: m}
+	m.DeleteProductMock.callArgs = []*RepositoryMockDeleteProductParams{}
+
+	m.ExportProductsMock = mRepositoryMockExportProducts{mock: m}
+	m.ExportProductsMock.callArgs = []*RepositoryMockExportProductsParams{}
+
+	m.PatchProductMock = mRepositoryMockPatchProduct{mock: m}
+	m.PatchProductMock.callArgs = []*RepositoryMockPatchProductParams{}
+
+	m.ProductMock = mRepositoryMockProduct{mock: m}
+	m.ProductMock.callArgs = []*RepositoryMockProductParams{}
+
+	m.ProductHistoryMock = mRepositoryMockProductHistory{mock: m}
+	m.ProductHistoryMock.callArgs = []*RepositoryMockProductHistoryParams{}
+
+	m.ProductsByFilterMock = mRepositoryMockProductsByFilter{mock: m}
+	m.ProductsByFilterMock.callArgs = []*RepositoryMockProductsByFilterParams{}
+
+	m.PutProductMock = mRepositoryMockPutProduct{mock: m}
+	m.PutProductMock.callArgs = []*RepositoryMockPutProductParams{}
+
+	m.SellerProductsByIDsMock = mRepositoryMockSellerProductsByIDs{mock: m}
+	m.SellerProductsByIDsMock.callArgs = []*RepositoryMockSellerProductsByIDsParams{}
+
+	return m
+}
+
+type mRepositoryMockBeginImport struct {
+	mock               *RepositoryMock
+	defaultExpectation *RepositoryMockBeginImportExpectation
+	expectations       []*RepositoryMockBeginImportExpectation
+
+	callArgs []*RepositoryMockBeginImportParams
+	mutex    sync.RWMutex
+}
+
+// RepositoryMockBeginImportExpectation specifies expectation struct of the Repository.BeginImport
+type RepositoryMockBeginImportExpectation struct {
+	mock    *RepositoryMock
+	params  *RepositoryMockBeginImportParams
+	results *RepositoryMockBeginImportResults
+	Counter uint64
+}
+
+// RepositoryMockBeginImportParams contains parameters of the Repository.BeginImport
+type RepositoryMockBeginImportParams struct {
+	sellerId uint64
+}
+
+// RepositoryMockBeginImportResults contains results of the Repository.BeginImport
+type RepositoryMockBeginImportResults struct {
+	i1  ImportTx
+	err error
+}
+
+// Expect sets up expected params for Repository.BeginImport
+func (mmBeginImport *mRepositoryMockBeginImport) Expect(sellerId uint64) *mRepositoryMockBeginImport {
+	if mmBeginImport.mock.funcBeginImport != nil {
+		mmBeginImport.mock.t.Fatalf("RepositoryMock.BeginImport mock is already set by Set")
+	}
+
+	if mmBeginImport.defaultExpectation == nil {
+		mmBeginImport.defaultExpectation = &RepositoryMockBeginImportExpectation{}
+	}
+
+	mmBeginImport.defaultExpectation.params = &RepositoryMockBeginImportParams{sellerId}
+	for _, e := range mmBeginImport.expectations {
+		if minimock.Equal(e.params, mmBeginImport.defaultExpectation.params) {
+			mmBeginImport.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmBeginImport.defaultExpectation.params)
+		}
+	}
+
+	return mmBeginImport
+}
+
+// Inspect accepts an inspector function that has same arguments as the Repository.BeginImport
+func (mmBeginImport *mRepositoryMockBeginImport) Inspect(f func(sellerId uint64)) *mRepositoryMockBeginImport {
+	if mmBeginImport.mock.inspectFuncBeginImport != nil {
+		mmBeginImport.mock.t.Fatalf("Inspect function is already set for RepositoryMock.BeginImport")
+	}
+
+	mmBeginImport.mock.inspectFuncBeginImport = f
+
+	return mmBeginImport
+}
+
+// Return sets up results that will be returned by Repository.BeginImport
+func (mmBeginImport *mRepositoryMockBeginImport) Return(i1 ImportTx, err error) *RepositoryMock {
+	if mmBeginImport.mock.funcBeginImport != nil {
+		mmBeginImport.mock.t.Fatalf("RepositoryMock.BeginImport mock is already set by Set")
+	}
+
+	if mmBeginImport.defaultExpectation == nil {
+		mmBeginImport.defaultExpectation = &RepositoryMockBeginImportExpectation{mock: mmBeginImport.mock}
+	}
+	mmBeginImport.defaultExpectation.results = &RepositoryMockBeginImportResults{i1, err}
+	return mmBeginImport.mock
+}
+
+// Set uses given function f to mock the Repository.BeginImport method
+func (mmBeginImport *mRepositoryMockBeginImport) Set(f func(sellerId uint64) (i1 ImportTx, err error)) *RepositoryMock {
+	if mmBeginImport.defaultExpectation != nil {
+		mmBeginImport.mock.t.Fatalf("Default expectation is already set for the Repository.BeginImport method")
+	}
+
+	if len(mmBeginImport.expectations) > 0 {
+		mmBeginImport.mock.t.Fatalf("Some expectations are already set for the Repository.BeginImport method")
+	}
+
+	mmBeginImport.mock.funcBeginImport = f
+	return mmBeginImport.mock
+}
+
+// When sets expectation for the Repository.BeginImport which will trigger the result defined by the following
+// Then helper
+func (mmBeginImport *mRepositoryMockBeginImport) When(sellerId uint64) *RepositoryMockBeginImportExpectation {
+	if mmBeginImport.mock.funcBeginImport != nil {
+		mmBeginImport.mock.t.Fatalf("RepositoryMock.BeginImport mock is already set by Set")
+	}
+
+	expectation := &RepositoryMockBeginImportExpectation{
+		mock:   mmBeginImport.mock,
+		params: &RepositoryMockBeginImportParams{sellerId},
+	}
+	mmBeginImport.expectations = append(mmBeginImport.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Repository.BeginImport return parameters for the expectation previously defined by the When method
+func (e *RepositoryMockBeginImportExpectation) Then(i1 ImportTx, err error) *RepositoryMock {
+	e.results = &RepositoryMockBeginImportResults{i1, err}
+	return e.mock
+}
+
+// BeginImport implements Repository
+func (mmBeginImport *RepositoryMock) BeginImport(sellerId uint64) (i1 ImportTx, err error) {
+	mm_atomic.AddUint64(&mmBeginImport.beforeBeginImportCounter, 1)
+	defer mm_atomic.AddUint64(&mmBeginImport.afterBeginImportCounter, 1)
+
+	if mmBeginImport.inspectFuncBeginImport != nil {
+		mmBeginImport.inspectFuncBeginImport(sellerId)
+	}
+
+	mm_params := &RepositoryMockBeginImportParams{sellerId}
+
+	// Record call args
+	mmBeginImport.BeginImportMock.mutex.Lock()
+	mmBeginImport.BeginImportMock.callArgs = append(mmBeginImport.BeginImportMock.callArgs, mm_params)
+	mmBeginImport.BeginImportMock.mutex.Unlock()
+
+	for _, e := range mmBeginImport.BeginImportMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.i1, e.results.err
+		}
+	}
+
+	if mmBeginImport.BeginImportMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmBeginImport.BeginImportMock.defaultExpectation.Counter, 1)
+		mm_want := mmBeginImport.BeginImportMock.defaultExpectation.params
+		mm_got := RepositoryMockBeginImportParams{sellerId}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmBeginImport.t.Errorf("RepositoryMock.BeginImport got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmBeginImport.BeginImportMock.defaultExpectation.results
+		if mm_results == nil {
+			mmBeginImport.t.Fatal("No results are set for the RepositoryMock.BeginImport")
+		}
+		return (*mm_results).i1, (*mm_results).err
+	}
+	if mmBeginImport.funcBeginImport != nil {
+		return mmBeginImport.funcBeginImport(sellerId)
+	}
+	mmBeginImport.t.Fatalf("Unexpected call to RepositoryMock.BeginImport. %v", sellerId)
+	return
+}
+
+// BeginImportAfterCounter returns a count of finished RepositoryMock.BeginImport invocations
+func (mmBeginImport *RepositoryMock) BeginImportAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmBeginImport.afterBeginImportCounter)
+}
+
+// BeginImportBeforeCounter returns a count of RepositoryMock.BeginImport invocations
+func (mmBeginImport *RepositoryMock) BeginImportBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmBeginImport.beforeBeginImportCounter)
+}
+
+// Calls returns a list of arguments used in each call to RepositoryMock.BeginImport.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmBeginImport *mRepositoryMockBeginImport) Calls() []*RepositoryMockBeginImportParams {
+	mmBeginImport.mutex.RLock()
+
+	argCopy := make([]*RepositoryMockBeginImportParams, len(mmBeginImport.callArgs))
+	copy(argCopy, mmBeginImport.callArgs)
+
+	mmBeginImport.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockBeginImportDone returns true if the count of the BeginImport invocations corresponds
+// the number of defined expectations
+func (m *RepositoryMock) MinimockBeginImportDone() bool {
+	for _, e := range m.BeginImportMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.BeginImportMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterBeginImportCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcBeginImport != nil && mm_atomic.LoadUint64(&m.afterBeginImportCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockBeginImportInspect logs each unmet expectation
+func (m *RepositoryMock) MinimockBeginImportInspect() {
+	for _, e := range m.BeginImportMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to RepositoryMock.BeginImport with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.BeginImportMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterBeginImportCounter) < 1 {
+		if m.BeginImportMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to RepositoryMock.BeginImport")
+		} else {
+			m.t.Errorf("Expected call to RepositoryMock.BeginImport with params: %#v", *m.BeginImportMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcBeginImport != nil && mm_atomic.LoadUint64(&m.afterBeginImportCounter) < 1 {
+		m.t.Error("Expected call to RepositoryMock.BeginImport")
+	}
+}
+
+type mRepositoryMockDeleteProduct struct {
+	mock               *RepositoryMock
+	defaultExpectation *RepositoryMockDeleteProductExpectation
+	expectations       []*RepositoryMockDeleteProductExpectation
+
+	callArgs []*RepositoryMockDeleteProductParams
+	mutex    sync.RWMutex
+}
+
+// RepositoryMockDeleteProductExpectation specifies expectation struct of the Repository.DeleteProduct
+type RepositoryMockDeleteProductExpectation struct {
+	mock    *RepositoryMock
+	params  *RepositoryMockDeleteProductParams
+	results *RepositoryMockDeleteProductResults
+	Counter uint64
+}
+
+// RepositoryMockDeleteProductParams contains parameters of the Repository.DeleteProduct
+type RepositoryMockDeleteProductParams struct {
+	sellerId uint64
+	offerId  uint64
+	source   models.ChangeSource
+}
+
+// RepositoryMockDeleteProductResults contains results of the Repository.DeleteProduct
+type RepositoryMockDeleteProductResults struct {
+	err error
+}
+
+// Expect sets up expected params for Repository.DeleteProduct
+func (mmDeleteProduct *mRepositoryMockDeleteProduct) Expect(sellerId uint64, offerId uint64, source models.ChangeSource) *mRepositoryMockDeleteProduct {
+	if mmDeleteProduct.mock.funcDeleteProduct != nil {
+		mmDeleteProduct.mock.t.Fatalf("RepositoryMock.DeleteProduct mock is already set by Set")
+	}
+
+	if mmDeleteProduct.defaultExpectation == nil {
+		mmDeleteProduct.defaultExpectation = &RepositoryMockDeleteProductExpectation{}
+	}
+
+	mmDeleteProduct.defaultExpectation.params = &RepositoryMockDeleteProductParams{sellerId, offerId, source}
+	for _, e := range mmDeleteProduct.expectations {
+		if minimock.Equal(e.params, mmDeleteProduct.defaultExpectation.params) {
+			mmDeleteProduct.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmDeleteProduct.defaultExpectation.params)
+		}
+	}
+
+	return mmDeleteProduct
+}
+
+// Inspect accepts an inspector function that has same arguments as the Repository.DeleteProduct
+func (mmDeleteProduct *mRepositoryMockDeleteProduct) Inspect(f func(sellerId uint64, offerId uint64, source models.ChangeSource)) *mRepositoryMockDeleteProduct {
+	if mmDeleteProduct.mock.inspectFuncDeleteProduct != nil {
+		mmDeleteProduct.mock.t.Fatalf("Inspect function is already set for RepositoryMock.DeleteProduct")
+	}
+
+	mmDeleteProduct.mock.inspectFuncDeleteProduct = f
+
+	return mmDeleteProduct
+}
+
+// Return sets up results that will be returned by Repository.DeleteProduct
+func (mmDeleteProduct *mRepositoryMockDeleteProduct) Return(err error) *RepositoryMock {
+	if mmDeleteProduct.mock.funcDeleteProduct != nil {
+		mmDeleteProduct.mock.t.Fatalf("RepositoryMock.DeleteProduct mock is already set by Set")
+	}
+
+	if mmDeleteProduct.defaultExpectation == nil {
+		mmDeleteProduct.defaultExpectation = &RepositoryMockDeleteProductExpectation{mock: mmDeleteProduct.mock}
+	}
+	mmDeleteProduct.defaultExpectation.results = &RepositoryMockDeleteProductResults{err}
+	return mmDeleteProduct.mock
+}
+
+// Set uses given function f to mock the Repository.DeleteProduct method
+func (mmDeleteProduct *mRepositoryMockDeleteProduct) Set(f func(sellerId uint64, offerId uint64, source models.ChangeSource) (err error)) *RepositoryMock {
+	if mmDeleteProduct.defaultExpectation != nil {
+		mmDeleteProduct.mock.t.Fatalf("Default expectation is already set for the Repository.DeleteProduct method")
+	}
+
+	if len(mmDeleteProduct.expectations) > 0 {
+		mmDeleteProduct.mock.t.Fatalf("Some expectations are already set for the Repository.DeleteProduct method")
+	}
+
+	mmDeleteProduct.mock.funcDeleteProduct = f
+	return mmDeleteProduct.mock
+}
+
+// When sets expectation for the Repository.DeleteProduct which will trigger the result defined by the following
+// Then helper
+func (mmDeleteProduct *mRepositoryMockDeleteProduct) When(sellerId uint64, offerId uint64, source models.ChangeSource) *RepositoryMockDeleteProductExpectation {
+	if mmDeleteProduct.mock.funcDeleteProduct != nil {
+		mmDeleteProduct.mock.t.Fatalf("RepositoryMock.DeleteProduct mock is already set by Set")
+	}
+
+	expectation := &RepositoryMockDeleteProductExpectation{
+		mock:   mmDeleteProduct.mock,
+		params: &RepositoryMockDeleteProductParams{sellerId, offerId, source},
+	}
+	mmDeleteProduct.expectations = append(mmDeleteProduct.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Repository.DeleteProduct return parameters for the expectation previously defined by the When method
+func (e *RepositoryMockDeleteProductExpectation) Then(err error) *RepositoryMock {
+	e.results = &RepositoryMockDeleteProductResults{err}
+	return e.mock
+}
+
+// DeleteProduct implements Repository
+func (mmDeleteProduct *RepositoryMock) DeleteProduct(sellerId uint64, offerId uint64, source models.ChangeSource) (err error) {
+	mm_atomic.AddUint64(&mmDeleteProduct.beforeDeleteProductCounter, 1)
+	defer mm_atomic.AddUint64(&mmDeleteProduct.afterDeleteProductCounter, 1)
+
+	if mmDeleteProduct.inspectFuncDeleteProduct != nil {
+		mmDeleteProduct.inspectFuncDeleteProduct(sellerId, offerId, source)
+	}
+
+	mm_params := &RepositoryMockDeleteProductParams{sellerId, offerId, source}
+
+	// Record call args
+	mmDeleteProduct.DeleteProductMock.mutex.Lock()
+	mmDeleteProduct.DeleteProductMock.callArgs = append(mmDeleteProduct.DeleteProductMock.callArgs, mm_params)
+	mmDeleteProduct.DeleteProductMock.mutex.Unlock()
+
+	for _, e := range mmDeleteProduct.DeleteProductMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmDeleteProduct.DeleteProductMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmDeleteProduct.DeleteProductMock.defaultExpectation.Counter, 1)
+		mm_want := mmDeleteProduct.DeleteProductMock.defaultExpectation.params
+		mm_got := RepositoryMockDeleteProductParams{sellerId, offerId, source}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmDeleteProduct.t.Errorf("RepositoryMock.DeleteProduct got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmDeleteProduct.DeleteProductMock.defaultExpectation.results
+		if mm_results == nil {
+			mmDeleteProduct.t.Fatal("No results are set for the RepositoryMock.DeleteProduct")
+		}
+		return (*mm_results).err
+	}
+	if mmDeleteProduct.funcDeleteProduct != nil {
+		return mmDeleteProduct.funcDeleteProduct(sellerId, offerId, source)
+	}
+	mmDeleteProduct.t.Fatalf("Unexpected call to RepositoryMock.DeleteProduct. %v %v %v", sellerId, offerId, source)
+	return
+}
+
+// DeleteProductAfterCounter returns a count of finished RepositoryMock.DeleteProduct invocations
+func (mmDeleteProduct *RepositoryMock) DeleteProductAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDeleteProduct.afterDeleteProductCounter)
+}
+
+// DeleteProductBeforeCounter returns a count of RepositoryMock.DeleteProduct invocations
+func (mmDeleteProduct *RepositoryMock) DeleteProductBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDeleteProduct.beforeDeleteProductCounter)
+}
+
+// Calls returns a list of arguments used in each call to RepositoryMock.DeleteProduct.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmDeleteProduct *mRepositoryMockDeleteProduct) Calls() []*RepositoryMockDeleteProductParams {
+	mmDeleteProduct.mutex.RLock()
+
+	argCopy := make([]*RepositoryMockDeleteProductParams, len(mmDeleteProduct.callArgs))
+	copy(argCopy, mmDeleteProduct.callArgs)
+
+	mmDeleteProduct.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockDeleteProductDone returns true if the count of the DeleteProduct invocations corresponds
+// the number of defined expectations
+func (m *RepositoryMock) MinimockDeleteProductDone() bool {
+	for _, e := range m.DeleteProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.DeleteProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterDeleteProductCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcDeleteProduct != nil && mm_atomic.LoadUint64(&m.afterDeleteProductCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockDeleteProductInspect logs each unmet expectation
+func (m *RepositoryMock) MinimockDeleteProductInspect() {
+	for _, e := range m.DeleteProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to RepositoryMock.DeleteProduct with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.DeleteProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterDeleteProductCounter) < 1 {
+		if m.DeleteProductMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to RepositoryMock.DeleteProduct")
+		} else {
+			m.t.Errorf("Expected call to RepositoryMock.DeleteProduct with params: %#v", *m.DeleteProductMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcDeleteProduct != nil && mm_atomic.LoadUint64(&m.afterDeleteProductCounter) < 1 {
+		m.t.Error("Expected call to RepositoryMock.DeleteProduct")
+	}
+}
+
+type mRepositoryMockExportProducts struct {
+	mock               *RepositoryMock
+	defaultExpectation *RepositoryMockExportProductsExpectation
+	expectations       []*RepositoryMockExportProductsExpectation
+
+	callArgs []*RepositoryMockExportProductsParams
+	mutex    sync.RWMutex
+}
+
+// RepositoryMockExportProductsExpectation specifies expectation struct of the Repository.ExportProducts
+type RepositoryMockExportProductsExpectation struct {
+	mock    *RepositoryMock
+	params  *RepositoryMockExportProductsParams
+	results *RepositoryMockExportProductsResults
+	Counter uint64
+}
+
+// RepositoryMockExportProductsParams contains parameters of the Repository.ExportProducts
+type RepositoryMockExportProductsParams struct {
+	sellerId uint64
+	pageSize uint64
+	handle   func(products []models.Product) error
+}
+
+// RepositoryMockExportProductsResults contains results of the Repository.ExportProducts
+type RepositoryMockExportProductsResults struct {
+	err error
+}
+
+// Expect sets up expected params for Repository.ExportProducts
+func (mmExportProducts *mRepositoryMockExportProducts) Expect(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) *mRepositoryMockExportProducts {
+	if mmExportProducts.mock.funcExportProducts != nil {
+		mmExportProducts.mock.t.Fatalf("RepositoryMock.ExportProducts mock is already set by Set")
+	}
+
+	if mmExportProducts.defaultExpectation == nil {
+		mmExportProducts.defaultExpectation = &RepositoryMockExportProductsExpectation{}
+	}
+
+	mmExportProducts.defaultExpectation.params = &RepositoryMockExportProductsParams{sellerId, pageSize, handle}
+	for _, e := range mmExportProducts.expectations {
+		if minimock.Equal(e.params, mmExportProducts.defaultExpectation.params) {
+			mmExportProducts.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmExportProducts.defaultExpectation.params)
+		}
+	}
+
+	return mmExportProducts
+}
+
+// Inspect accepts an inspector function that has same arguments as the Repository.ExportProducts
+func (mmExportProducts *mRepositoryMockExportProducts) Inspect(f func(sellerId uint64, pageSize uint64, handle func(products []models.Product) error)) *mRepositoryMockExportProducts {
+	if mmExportProducts.mock.inspectFuncExportProducts != nil {
+		mmExportProducts.mock.t.Fatalf("Inspect function is already set for RepositoryMock.ExportProducts")
+	}
+
+	mmExportProducts.mock.inspectFuncExportProducts = f
+
+	return mmExportProducts
+}
+
+// Return sets up results that will be returned by Repository.ExportProducts
+func (mmExportProducts *mRepositoryMockExportProducts) Return(err error) *RepositoryMock {
+	if mmExportProducts.mock.funcExportProducts != nil {
+		mmExportProducts.mock.t.Fatalf("RepositoryMock.ExportProducts mock is already set by Set")
+	}
+
+	if mmExportProducts.defaultExpectation == nil {
+		mmExportProducts.defaultExpectation = &RepositoryMockExportProductsExpectation{mock: mmExportProducts.mock}
+	}
+	mmExportProducts.defaultExpectation.results = &RepositoryMockExportProductsResults{err}
+	return mmExportProducts.mock
+}
+
+// Set uses given function f to mock the Repository.ExportProducts method
+func (mmExportProducts *mRepositoryMockExportProducts) Set(f func(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) (err error)) *RepositoryMock {
+	if mmExportProducts.defaultExpectation != nil {
+		mmExportProducts.mock.t.Fatalf("Default expectation is already set for the Repository.ExportProducts method")
+	}
+
+	if len(mmExportProducts.expectations) > 0 {
+		mmExportProducts.mock.t.Fatalf("Some expectations are already set for the Repository.ExportProducts method")
+	}
+
+	mmExportProducts.mock.funcExportProducts = f
+	return mmExportProducts.mock
+}
+
+// When sets expectation for the Repository.ExportProducts which will trigger the result defined by the following
+// Then helper
+func (mmExportProducts *mRepositoryMockExportProducts) When(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) *RepositoryMockExportProductsExpectation {
+	if mmExportProducts.mock.funcExportProducts != nil {
+		mmExportProducts.mock.t.Fatalf("RepositoryMock.ExportProducts mock is already set by Set")
+	}
+
+	expectation := &RepositoryMockExportProductsExpectation{
+		mock:   mmExportProducts.mock,
+		params: &RepositoryMockExportProductsParams{sellerId, pageSize, handle},
+	}
+	mmExportProducts.expectations = append(mmExportProducts.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Repository.ExportProducts return parameters for the expectation previously defined by the When method
+func (e *RepositoryMockExportProductsExpectation) Then(err error) *RepositoryMock {
+	e.results = &RepositoryMockExportProductsResults{err}
+	return e.mock
+}
+
+// ExportProducts implements Repository
+func (mmExportProducts *RepositoryMock) ExportProducts(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) (err error) {
+	mm_atomic.AddUint64(&mmExportProducts.beforeExportProductsCounter, 1)
+	defer mm_atomic.AddUint64(&mmExportProducts.afterExportProductsCounter, 1)
+
+	if mmExportProducts.inspectFuncExportProducts != nil {
+		mmExportProducts.inspectFuncExportProducts(sellerId, pageSize, handle)
+	}
+
+	mm_params := &RepositoryMockExportProductsParams{sellerId, pageSize, handle}
+
+	// Record call args
+	mmExportProducts.ExportProductsMock.mutex.Lock()
+	mmExportProducts.ExportProductsMock.callArgs = append(mmExportProducts.ExportProductsMock.callArgs, mm_params)
+	mmExportProducts.ExportProductsMock.mutex.Unlock()
+
+	for _, e := range mmExportProducts.ExportProductsMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmExportProducts.ExportProductsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmExportProducts.ExportProductsMock.defaultExpectation.Counter, 1)
+		mm_want := mmExportProducts.ExportProductsMock.defaultExpectation.params
+		mm_got := RepositoryMockExportProductsParams{sellerId, pageSize, handle}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmExportProducts.t.Errorf("RepositoryMock.ExportProducts got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmExportProducts.ExportProductsMock.defaultExpectation.results
+		if mm_results == nil {
+			mmExportProducts.t.Fatal("No results are set for the RepositoryMock.ExportProducts")
+		}
+		return (*mm_results).err
+	}
+	if mmExportProducts.funcExportProducts != nil {
+		return mmExportProducts.funcExportProducts(sellerId, pageSize, handle)
+	}
+	mmExportProducts.t.Fatalf("Unexpected call to RepositoryMock.ExportProducts. %v %v %v", sellerId, pageSize, handle)
+	return
+}
+
+// ExportProductsAfterCounter returns a count of finished RepositoryMock.ExportProducts invocations
+func (mmExportProducts *RepositoryMock) ExportProductsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmExportProducts.afterExportProductsCounter)
+}
+
+// ExportProductsBeforeCounter returns a count of RepositoryMock.ExportProducts invocations
+func (mmExportProducts *RepositoryMock) ExportProductsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmExportProducts.beforeExportProductsCounter)
+}
+
+// Calls returns a list of arguments used in each call to RepositoryMock.ExportProducts.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmExportProducts *mRepositoryMockExportProducts) Calls() []*RepositoryMockExportProductsParams {
+	mmExportProducts.mutex.RLock()
+
+	argCopy := make([]*RepositoryMockExportProductsParams, len(mmExportProducts.callArgs))
+	copy(argCopy, mmExportProducts.callArgs)
+
+	mmExportProducts.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockExportProductsDone returns true if the count of the ExportProducts invocations corresponds
+// the number of defined expectations
+func (m *RepositoryMock) MinimockExportProductsDone() bool {
+	for _, e := range m.ExportProductsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ExportProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterExportProductsCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcExportProducts != nil && mm_atomic.LoadUint64(&m.afterExportProductsCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockExportProductsInspect logs each unmet expectation
+func (m *RepositoryMock) MinimockExportProductsInspect() {
+	for _, e := range m.ExportProductsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to RepositoryMock.ExportProducts with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ExportProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterExportProductsCounter) < 1 {
+		if m.ExportProductsMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to RepositoryMock.ExportProducts")
+		} else {
+			m.t.Errorf("Expected call to RepositoryMock.ExportProducts with params: %#v", *m.ExportProductsMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcExportProducts != nil && mm_atomic.LoadUint64(&m.afterExportProductsCounter) < 1 {
+		m.t.Error("Expected call to RepositoryMock.ExportProducts")
+	}
+}
+
+type mRepositoryMockPatchProduct struct {
+	mock               *RepositoryMock
+	defaultExpectation *RepositoryMockPatchProductExpectation
+	expectations       []*RepositoryMockPatchProductExpectation
+
+	callArgs []*RepositoryMockPatchProductParams
+	mutex    sync.RWMutex
+}
+
+// RepositoryMockPatchProductExpectation specifies expectation struct of the Repository.PatchProduct
+type RepositoryMockPatchProductExpectation struct {
+	mock    *RepositoryMock
+	params  *RepositoryMockPatchProductParams
+	results *RepositoryMockPatchProductResults
+	Counter uint64
+}
+
+// RepositoryMockPatchProductParams contains parameters of the Repository.PatchProduct
+type RepositoryMockPatchProductParams struct {
+	sellerId uint64
+	offerId  uint64
+	patch    ProductPatch
+	source   models.ChangeSource
+}
+
+// RepositoryMockPatchProductResults contains results of the Repository.PatchProduct
+type RepositoryMockPatchProductResults struct {
+	p1  models.Product
+	err error
+}
+
+// Expect sets up expected params for Repository.PatchProduct
+func (mmPatchProduct *mRepositoryMockPatchProduct) Expect(sellerId uint64, offerId uint64, patch ProductPatch, source models.ChangeSource) *mRepositoryMockPatchProduct {
+	if mmPatchProduct.mock.funcPatchProduct != nil {
+		mmPatchProduct.mock.t.Fatalf("RepositoryMock.PatchProduct mock is already set by Set")
+	}
+
+	if mmPatchProduct.defaultExpectation == nil {
+		mmPatchProduct.defaultExpectation = &RepositoryMockPatchProductExpectation{}
+	}
+
+	mmPatchProduct.defaultExpectation.params = &RepositoryMockPatchProductParams{sellerId, offerId, patch, source}
+	for _, e := range mmPatchProduct.expectations {
+		if minimock.Equal(e.params, mmPatchProduct.defaultExpectation.params) {
+			mmPatchProduct.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmPatchProduct.defaultExpectation.params)
+		}
+	}
+
+	return mmPatchProduct
+}
+
+// Inspect accepts an inspector function that has same arguments as the Repository.PatchProduct
+func (mmPatchProduct *mRepositoryMockPatchProduct) Inspect(f func(sellerId uint64, offerId uint64, patch ProductPatch, source models.ChangeSource)) *mRepositoryMockPatchProduct {
+	if mmPatchProduct.mock.inspectFuncPatchProduct != nil {
+		mmPatchProduct.mock.t.Fatalf("Inspect function is already set for RepositoryMock.PatchProduct")
+	}
+
+	mmPatchProduct.mock.inspectFuncPatchProduct = f
+
+	return mmPatchProduct
+}
+
+// Return sets up results that will be returned by Repository.PatchProduct
+func (mmPatchProduct *mRepositoryMockPatchProduct) Return(p1 models.Product, err error) *RepositoryMock {
+	if mmPatchProduct.mock.funcPatchProduct != nil {
+		mmPatchProduct.mock.t.Fatalf("RepositoryMock.PatchProduct mock is already set by Set")
+	}
+
+	if mmPatchProduct.defaultExpectation == nil {
+		mmPatchProduct.defaultExpectation = &RepositoryMockPatchProductExpectation{mock: mmPatchProduct.mock}
+	}
+	mmPatchProduct.defaultExpectation.results = &RepositoryMockPatchProductResults{p1, err}
+	return mmPatchProduct.mock
+}
+
+// Set uses given function f to mock the Repository.PatchProduct method
+func (mmPatchProduct *mRepositoryMockPatchProduct) Set(f func(sellerId uint64, offerId uint64, patch ProductPatch, source models.ChangeSource) (p1 models.Product, err error)) *RepositoryMock {
+	if mmPatchProduct.defaultExpectation != nil {
+		mmPatchProduct.mock.t.Fatalf("Default expectation is already set for the Repository.PatchProduct method")
+	}
+
+	if len(mmPatchProduct.expectations) > 0 {
+		mmPatchProduct.mock.t.Fatalf("Some expectations are already set for the Repository.PatchProduct method")
+	}
+
+	mmPatchProduct.mock.funcPatchProduct = f
+	return mmPatchProduct.mock
+}
+
+// When sets expectation for the Repository.PatchProduct which will trigger the result defined by the following
+// Then helper
+func (mmPatchProduct *mRepositoryMockPatchProduct) When(sellerId uint64, offerId uint64, patch ProductPatch, source models.ChangeSource) *RepositoryMockPatchProductExpectation {
+	if mmPatchProduct.mock.funcPatchProduct != nil {
+		mmPatchProduct.mock.t.Fatalf("RepositoryMock.PatchProduct mock is already set by Set")
+	}
+
+	expectation := &RepositoryMockPatchProductExpectation{
+		mock:   mmPatchProduct.mock,
+		params: &RepositoryMockPatchProductParams{sellerId, offerId, patch, source},
+	}
+	mmPatchProduct.expectations = append(mmPatchProduct.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Repository.PatchProduct return parameters for the expectation previously defined by the When method
+func (e *RepositoryMockPatchProductExpectation) Then(p1 models.Product, err error) *RepositoryMock {
+	e.results = &RepositoryMockPatchProductResults{p1, err}
+	return e.mock
+}
+
+// PatchProduct implements Repository
+func (mmPatchProduct *RepositoryMock) PatchProduct(sellerId uint64, offerId uint64, patch ProductPatch, source models.ChangeSource) (p1 models.Product, err error) {
+	mm_atomic.AddUint64(&mmPatchProduct.beforePatchProductCounter, 1)
+	defer mm_atomic.AddUint64(&mmPatchProduct.afterPatchProductCounter, 1)
+
+	if mmPatchProduct.inspectFuncPatchProduct != nil {
+		mmPatchProduct.inspectFuncPatchProduct(sellerId, offerId, patch, source)
+	}
+
+	mm_params := &RepositoryMockPatchProductParams{sellerId, offerId, patch, source}
+
+	// Record call args
+	mmPatchProduct.PatchProductMock.mutex.Lock()
+	mmPatchProduct.PatchProductMock.callArgs = append(mmPatchProduct.PatchProductMock.callArgs, mm_params)
+	mmPatchProduct.PatchProductMock.mutex.Unlock()
+
+	for _, e := range mmPatchProduct.PatchProductMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.p1, e.results.err
+		}
+	}
+
+	if mmPatchProduct.PatchProductMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmPatchProduct.PatchProductMock.defaultExpectation.Counter, 1)
+		mm_want := mmPatchProduct.PatchProductMock.defaultExpectation.params
+		mm_got := RepositoryMockPatchProductParams{sellerId, offerId, patch, source}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmPatchProduct.t.Errorf("RepositoryMock.PatchProduct got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmPatchProduct.PatchProductMock.defaultExpectation.results
+		if mm_results == nil {
+			mmPatchProduct.t.Fatal("No results are set for the RepositoryMock.PatchProduct")
+		}
+		return (*mm_results).p1, (*mm_results).err
+	}
+	if mmPatchProduct.funcPatchProduct != nil {
+		return mmPatchProduct.funcPatchProduct(sellerId, offerId, patch, source)
+	}
+	mmPatchProduct.t.Fatalf("Unexpected call to RepositoryMock.PatchProduct. %v %v %v %v", sellerId, offerId, patch, source)
+	return
+}
+
+// PatchProductAfterCounter returns a count of finished RepositoryMock.PatchProduct invocations
+func (mmPatchProduct *RepositoryMock) PatchProductAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPatchProduct.afterPatchProductCounter)
+}
+
+// PatchProductBeforeCounter returns a count of RepositoryMock.PatchProduct invocations
+func (mmPatchProduct *RepositoryMock) PatchProductBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPatchProduct.beforePatchProductCounter)
+}
+
+// Calls returns a list of arguments used in each call to RepositoryMock.PatchProduct.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmPatchProduct *mRepositoryMockPatchProduct) Calls() []*RepositoryMockPatchProductParams {
+	mmPatchProduct.mutex.RLock()
+
+	argCopy := make([]*RepositoryMockPatchProductParams, len(mmPatchProduct.callArgs))
+	copy(argCopy, mmPatchProduct.callArgs)
+
+	mmPatchProduct.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockPatchProductDone returns true if the count of the PatchProduct invocations corresponds
+// the number of defined expectations
+func (m *RepositoryMock) MinimockPatchProductDone() bool {
+	for _, e := range m.PatchProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
 
-	funcProductsByFilter          func(filter RequestFilter) (pa1 []models.Product, err error)
-	inspectFuncProductsByFilter   func(filter RequestFilter)
-	afterProductsByFilterCounter  uint64
-	beforeProductsByFilterCounter uint64
-	ProductsByFilterMock          mRepositoryMockProductsByFilter
+	// if default expectation was set then invocations count should be greater than zero
+	if m.PatchProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPatchProductCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcPatchProduct != nil && mm_atomic.LoadUint64(&m.afterPatchProductCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockPatchProductInspect logs each unmet expectation
+func (m *RepositoryMock) MinimockPatchProductInspect() {
+	for _, e := range m.PatchProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to RepositoryMock.PatchProduct with params: %#v", *e.params)
+		}
+	}
 
-	funcSellerProductIDs          func(sellerId uint64) (ua1 []uint64, err error)
-	inspectFuncSellerProductIDs   func(sellerId uint64)
-	afterSellerProductIDsCounter  uint64
-	beforeSellerProductIDsCounter uint64
-	SellerProductIDsMock          mRepositoryMockSellerProductIDs
+	// if default expectation was set then invocations count should be greater than zero
+	if m.PatchProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPatchProductCounter) < 1 {
+		if m.PatchProductMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to RepositoryMock.PatchProduct")
+		} else {
+			m.t.Errorf("Expected call to RepositoryMock.PatchProduct with params: %#v", *m.PatchProductMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcPatchProduct != nil && mm_atomic.LoadUint64(&m.afterPatchProductCounter) < 1 {
+		m.t.Error("Expected call to RepositoryMock.PatchProduct")
+	}
 }
 
-// NewRepositoryMock returns a mock for Repository
-func NewRepositoryMock(t minimock.Tester) *RepositoryMock {
-	m := &RepositoryMock{t: t}
-	if controller, ok := t.(minimock.MockController); ok {
-		controller.RegisterMocker(m)
+type mRepositoryMockProduct struct {
+	mock               *RepositoryMock
+	defaultExpectation *RepositoryMockProductExpectation
+	expectations       []*RepositoryMockProductExpectation
+
+	callArgs []*RepositoryMockProductParams
+	mutex    sync.RWMutex
+}
+
+// RepositoryMockProductExpectation specifies expectation struct of the Repository.Product
+type RepositoryMockProductExpectation struct {
+	mock    *RepositoryMock
+	params  *RepositoryMockProductParams
+	results *RepositoryMockProductResults
+	Counter uint64
+}
+
+// RepositoryMockProductParams contains parameters of the Repository.Product
+type RepositoryMockProductParams struct {
+	sellerId uint64
+	offerId  uint64
+}
+
+// RepositoryMockProductResults contains results of the Repository.Product
+type RepositoryMockProductResults struct {
+	p1  models.Product
+	err error
+}
+
+// Expect sets up expected params for Repository.Product
+func (mmProduct *mRepositoryMockProduct) Expect(sellerId uint64, offerId uint64) *mRepositoryMockProduct {
+	if mmProduct.mock.funcProduct != nil {
+		mmProduct.mock.t.Fatalf("RepositoryMock.Product mock is already set by Set")
 	}
 
-	m.ManageProductsMock = mRepositoryMockManageProducts{mock: m}
-	m.ManageProductsMock.callArgs = []*RepositoryMockManageProductsParams{}
+	if mmProduct.defaultExpectation == nil {
+		mmProduct.defaultExpectation = &RepositoryMockProductExpectation{}
+	}
 
-	m.ProductsByFilterMock = mRepositoryMockProductsByFilter{mock: m}
-	m.ProductsByFilterMock.callArgs = []*RepositoryMockProductsByFilterParams{}
+	mmProduct.defaultExpectation.params = &RepositoryMockProductParams{sellerId, offerId}
+	for _, e := range mmProduct.expectations {
+		if minimock.Equal(e.params, mmProduct.defaultExpectation.params) {
+			mmProduct.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmProduct.defaultExpectation.params)
+		}
+	}
 
-	m.SellerProductIDsMock = mRepositoryMockSellerProductIDs{mock: m}
-	m.SellerProductIDsMock.callArgs = []*RepositoryMockSellerProductIDsParams{}
+	return mmProduct
+}
 
-	return m
+// Inspect accepts an inspector function that has same arguments as the Repository.Product
+func (mmProduct *mRepositoryMockProduct) Inspect(f func(sellerId uint64, offerId uint64)) *mRepositoryMockProduct {
+	if mmProduct.mock.inspectFuncProduct != nil {
+		mmProduct.mock.t.Fatalf("Inspect function is already set for RepositoryMock.Product")
+	}
+
+	mmProduct.mock.inspectFuncProduct = f
+
+	return mmProduct
+}
+
+// Return sets up results that will be returned by Repository.Product
+func (mmProduct *mRepositoryMockProduct) Return(p1 models.Product, err error) *RepositoryMock {
+	if mmProduct.mock.funcProduct != nil {
+		mmProduct.mock.t.Fatalf("RepositoryMock.Product mock is already set by Set")
+	}
+
+	if mmProduct.defaultExpectation == nil {
+		mmProduct.defaultExpectation = &RepositoryMockProductExpectation{mock: mmProduct.mock}
+	}
+	mmProduct.defaultExpectation.results = &RepositoryMockProductResults{p1, err}
+	return mmProduct.mock
+}
+
+// Set uses given function f to mock the Repository.Product method
+func (mmProduct *mRepositoryMockProduct) Set(f func(sellerId uint64, offerId uint64) (p1 models.Product, err error)) *RepositoryMock {
+	if mmProduct.defaultExpectation != nil {
+		mmProduct.mock.t.Fatalf("Default expectation is already set for the Repository.Product method")
+	}
+
+	if len(mmProduct.expectations) > 0 {
+		mmProduct.mock.t.Fatalf("Some expectations are already set for the Repository.Product method")
+	}
+
+	mmProduct.mock.funcProduct = f
+	return mmProduct.mock
+}
+
+// When sets expectation for the Repository.Product which will trigger the result defined by the following
+// Then helper
+func (mmProduct *mRepositoryMockProduct) When(sellerId uint64, offerId uint64) *RepositoryMockProductExpectation {
+	if mmProduct.mock.funcProduct != nil {
+		mmProduct.mock.t.Fatalf("RepositoryMock.Product mock is already set by Set")
+	}
+
+	expectation := &RepositoryMockProductExpectation{
+		mock:   mmProduct.mock,
+		params: &RepositoryMockProductParams{sellerId, offerId},
+	}
+	mmProduct.expectations = append(mmProduct.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Repository.Product return parameters for the expectation previously defined by the When method
+func (e *RepositoryMockProductExpectation) Then(p1 models.Product, err error) *RepositoryMock {
+	e.results = &RepositoryMockProductResults{p1, err}
+	return e.mock
+}
+
+// Product implements Repository
+func (mmProduct *RepositoryMock) Product(sellerId uint64, offerId uint64) (p1 models.Product, err error) {
+	mm_atomic.AddUint64(&mmProduct.beforeProductCounter, 1)
+	defer mm_atomic.AddUint64(&mmProduct.afterProductCounter, 1)
+
+	if mmProduct.inspectFuncProduct != nil {
+		mmProduct.inspectFuncProduct(sellerId, offerId)
+	}
+
+	mm_params := &RepositoryMockProductParams{sellerId, offerId}
+
+	// Record call args
+	mmProduct.ProductMock.mutex.Lock()
+	mmProduct.ProductMock.callArgs = append(mmProduct.ProductMock.callArgs, mm_params)
+	mmProduct.ProductMock.mutex.Unlock()
+
+	for _, e := range mmProduct.ProductMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.p1, e.results.err
+		}
+	}
+
+	if mmProduct.ProductMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmProduct.ProductMock.defaultExpectation.Counter, 1)
+		mm_want := mmProduct.ProductMock.defaultExpectation.params
+		mm_got := RepositoryMockProductParams{sellerId, offerId}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmProduct.t.Errorf("RepositoryMock.Product got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmProduct.ProductMock.defaultExpectation.results
+		if mm_results == nil {
+			mmProduct.t.Fatal("No results are set for the RepositoryMock.Product")
+		}
+		return (*mm_results).p1, (*mm_results).err
+	}
+	if mmProduct.funcProduct != nil {
+		return mmProduct.funcProduct(sellerId, offerId)
+	}
+	mmProduct.t.Fatalf("Unexpected call to RepositoryMock.Product. %v %v", sellerId, offerId)
+	return
+}
+
+// ProductAfterCounter returns a count of finished RepositoryMock.Product invocations
+func (mmProduct *RepositoryMock) ProductAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProduct.afterProductCounter)
+}
+
+// ProductBeforeCounter returns a count of RepositoryMock.Product invocations
+func (mmProduct *RepositoryMock) ProductBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProduct.beforeProductCounter)
+}
+
+// Calls returns a list of arguments used in each call to RepositoryMock.Product.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmProduct *mRepositoryMockProduct) Calls() []*RepositoryMockProductParams {
+	mmProduct.mutex.RLock()
+
+	argCopy := make([]*RepositoryMockProductParams, len(mmProduct.callArgs))
+	copy(argCopy, mmProduct.callArgs)
+
+	mmProduct.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockProductDone returns true if the count of the Product invocations corresponds
+// the number of defined expectations
+func (m *RepositoryMock) MinimockProductDone() bool {
+	for _, e := range m.ProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcProduct != nil && mm_atomic.LoadUint64(&m.afterProductCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockProductInspect logs each unmet expectation
+func (m *RepositoryMock) MinimockProductInspect() {
+	for _, e := range m.ProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to RepositoryMock.Product with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductCounter) < 1 {
+		if m.ProductMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to RepositoryMock.Product")
+		} else {
+			m.t.Errorf("Expected call to RepositoryMock.Product with params: %#v", *m.ProductMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcProduct != nil && mm_atomic.LoadUint64(&m.afterProductCounter) < 1 {
+		m.t.Error("Expected call to RepositoryMock.Product")
+	}
 }
 
-type mRepositoryMockManageProducts struct {
+type mRepositoryMockProductHistory struct {
 	mock               *RepositoryMock
-	defaultExpectation *RepositoryMockManageProductsExpectation
-	expectations       []*RepositoryMockManageProductsExpectation
+	defaultExpectation *RepositoryMockProductHistoryExpectation
+	expectations       []*RepositoryMockProductHistoryExpectation
 
-	callArgs []*RepositoryMockManageProductsParams
+	callArgs []*RepositoryMockProductHistoryParams
 	mutex    sync.RWMutex
 }
 
-// RepositoryMockManageProductsExpectation specifies expectation struct of the Repository.ManageProducts
-type RepositoryMockManageProductsExpectation struct {
+// RepositoryMockProductHistoryExpectation specifies expectation struct of the Repository.ProductHistory
+type RepositoryMockProductHistoryExpectation struct {
 	mock    *RepositoryMock
-	params  *RepositoryMockManageProductsParams
-	results *RepositoryMockManageProductsResults
+	params  *RepositoryMockProductHistoryParams
+	results *RepositoryMockProductHistoryResults
 	Counter uint64
 }
 
-// RepositoryMockManageProductsParams contains parameters of the Repository.ManageProducts
-type RepositoryMockManageProductsParams struct {
-	sellerId         uint64
-	productsToAdd    []models.Product
-	productsToDelete []models.Product
-	productsToUpdate []models.Product
+// RepositoryMockProductHistoryParams contains parameters of the Repository.ProductHistory
+type RepositoryMockProductHistoryParams struct {
+	sellerId uint64
+	offerId  uint64
+	limit    uint64
 }
 
-// RepositoryMockManageProductsResults contains results of the Repository.ManageProducts
-type RepositoryMockManageProductsResults struct {
-	u1  uint64
+// RepositoryMockProductHistoryResults contains results of the Repository.ProductHistory
+type RepositoryMockProductHistoryResults struct {
+	pa1 []models.ProductChange
 	err error
 }
 
-// Expect sets up expected params for Repository.ManageProducts
-func (mmManageProducts *mRepositoryMockManageProducts) Expect(sellerId uint64, productsToAdd []models.Product, productsToDelete []models.Product, productsToUpdate []models.Product) *mRepositoryMockManageProducts {
-	if mmManageProducts.mock.funcManageProducts != nil {
-		mmManageProducts.mock.t.Fatalf("RepositoryMock.ManageProducts mock is already set by Set")
+// Expect sets up expected params for Repository.ProductHistory
+func (mmProductHistory *mRepositoryMockProductHistory) Expect(sellerId uint64, offerId uint64, limit uint64) *mRepositoryMockProductHistory {
+	if mmProductHistory.mock.funcProductHistory != nil {
+		mmProductHistory.mock.t.Fatalf("RepositoryMock.ProductHistory mock is already set by Set")
 	}
 
-	if mmManageProducts.defaultExpectation == nil {
-		mmManageProducts.defaultExpectation = &RepositoryMockManageProductsExpectation{}
+	if mmProductHistory.defaultExpectation == nil {
+		mmProductHistory.defaultExpectation = &RepositoryMockProductHistoryExpectation{}
 	}
 
-	mmManageProducts.defaultExpectation.params = &RepositoryMockManageProductsParams{sellerId, productsToAdd, productsToDelete, productsToUpdate}
-	for _, e := range mmManageProducts.expectations {
-		if minimock.Equal(e.params, mmManageProducts.defaultExpectation.params) {
-			mmManageProducts.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmManageProducts.defaultExpectation.params)
+	mmProductHistory.defaultExpectation.params = &RepositoryMockProductHistoryParams{sellerId, offerId, limit}
+	for _, e := range mmProductHistory.expectations {
+		if minimock.Equal(e.params, mmProductHistory.defaultExpectation.params) {
+			mmProductHistory.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmProductHistory.defaultExpectation.params)
 		}
 	}
 
-	return mmManageProducts
+	return mmProductHistory
 }
 
-// Inspect accepts an inspector function that has same arguments as the Repository.ManageProducts
-func (mmManageProducts *mRepositoryMockManageProducts) Inspect(f func(sellerId uint64, productsToAdd []models.Product, productsToDelete []models.Product, productsToUpdate []models.Product)) *mRepositoryMockManageProducts {
-	if mmManageProducts.mock.inspectFuncManageProducts != nil {
-		mmManageProducts.mock.t.Fatalf("Inspect function is already set for RepositoryMock.ManageProducts")
+// Inspect accepts an inspector function that has same arguments as the Repository.ProductHistory
+func (mmProductHistory *mRepositoryMockProductHistory) Inspect(f func(sellerId uint64, offerId uint64, limit uint64)) *mRepositoryMockProductHistory {
+	if mmProductHistory.mock.inspectFuncProductHistory != nil {
+		mmProductHistory.mock.t.Fatalf("Inspect function is already set for RepositoryMock.ProductHistory")
 	}
 
-	mmManageProducts.mock.inspectFuncManageProducts = f
+	mmProductHistory.mock.inspectFuncProductHistory = f
 
-	return mmManageProducts
+	return mmProductHistory
 }
 
-// Return sets up results that will be returned by Repository.ManageProducts
-func (mmManageProducts *mRepositoryMockManageProducts) Return(u1 uint64, err error) *RepositoryMock {
-	if mmManageProducts.mock.funcManageProducts != nil {
-		mmManageProducts.mock.t.Fatalf("RepositoryMock.ManageProducts mock is already set by Set")
+// Return sets up results that will be returned by Repository.ProductHistory
+func (mmProductHistory *mRepositoryMockProductHistory) Return(pa1 []models.ProductChange, err error) *RepositoryMock {
+	if mmProductHistory.mock.funcProductHistory != nil {
+		mmProductHistory.mock.t.Fatalf("RepositoryMock.ProductHistory mock is already set by Set")
 	}
 
-	if mmManageProducts.defaultExpectation == nil {
-		mmManageProducts.defaultExpectation = &RepositoryMockManageProductsExpectation{mock: mmManageProducts.mock}
+	if mmProductHistory.defaultExpectation == nil {
+		mmProductHistory.defaultExpectation = &RepositoryMockProductHistoryExpectation{mock: mmProductHistory.mock}
 	}
-	mmManageProducts.defaultExpectation.results = &RepositoryMockManageProductsResults{u1, err}
-	return mmManageProducts.mock
+	mmProductHistory.defaultExpectation.results = &RepositoryMockProductHistoryResults{pa1, err}
+	return mmProductHistory.mock
 }
 
-// Set uses given function f to mock the Repository.ManageProducts method
-func (mmManageProducts *mRepositoryMockManageProducts) Set(f func(sellerId uint64, productsToAdd []models.Product, productsToDelete []models.Product, productsToUpdate []models.Product) (u1 uint64, err error)) *RepositoryMock {
-	if mmManageProducts.defaultExpectation != nil {
-		mmManageProducts.mock.t.Fatalf("Default expectation is already set for the Repository.ManageProducts method")
+// Set uses given function f to mock the Repository.ProductHistory method
+func (mmProductHistory *mRepositoryMockProductHistory) Set(f func(sellerId uint64, offerId uint64, limit uint64) (pa1 []models.ProductChange, err error)) *RepositoryMock {
+	if mmProductHistory.defaultExpectation != nil {
+		mmProductHistory.mock.t.Fatalf("Default expectation is already set for the Repository.ProductHistory method")
 	}
 
-	if len(mmManageProducts.expectations) > 0 {
-		mmManageProducts.mock.t.Fatalf("Some expectations are already set for the Repository.ManageProducts method")
+	if len(mmProductHistory.expectations) > 0 {
+		mmProductHistory.mock.t.Fatalf("Some expectations are already set for the Repository.ProductHistory method")
 	}
 
-	mmManageProducts.mock.funcManageProducts = f
-	return mmManageProducts.mock
+	mmProductHistory.mock.funcProductHistory = f
+	return mmProductHistory.mock
 }
 
-// When sets expectation for the Repository.ManageProducts which will trigger the result defined by the following
+// When sets expectation for the Repository.ProductHistory which will trigger the result defined by the following
 // Then helper
-func (mmManageProducts *mRepositoryMockManageProducts) When(sellerId uint64, productsToAdd []models.Product, productsToDelete []models.Product, productsToUpdate []models.Product) *RepositoryMockManageProductsExpectation {
-	if mmManageProducts.mock.funcManageProducts != nil {
-		mmManageProducts.mock.t.Fatalf("RepositoryMock.ManageProducts mock is already set by Set")
+func (mmProductHistory *mRepositoryMockProductHistory) When(sellerId uint64, offerId uint64, limit uint64) *RepositoryMockProductHistoryExpectation {
+	if mmProductHistory.mock.funcProductHistory != nil {
+		mmProductHistory.mock.t.Fatalf("RepositoryMock.ProductHistory mock is already set by Set")
 	}
 
-	expectation := &RepositoryMockManageProductsExpectation{
-		mock:   mmManageProducts.mock,
-		params: &RepositoryMockManageProductsParams{sellerId, productsToAdd, productsToDelete, productsToUpdate},
+	expectation := &RepositoryMockProductHistoryExpectation{
+		mock:   mmProductHistory.mock,
+		params: &RepositoryMockProductHistoryParams{sellerId, offerId, limit},
 	}
-	mmManageProducts.expectations = append(mmManageProducts.expectations, expectation)
+	mmProductHistory.expectations = append(mmProductHistory.expectations, expectation)
 	return expectation
 }
 
-// Then sets up Repository.ManageProducts return parameters for the expectation previously defined by the When method
-func (e *RepositoryMockManageProductsExpectation) Then(u1 uint64, err error) *RepositoryMock {
-	e.results = &RepositoryMockManageProductsResults{u1, err}
+// Then sets up Repository.ProductHistory return parameters for the expectation previously defined by the When method
+func (e *RepositoryMockProductHistoryExpectation) Then(pa1 []models.ProductChange, err error) *RepositoryMock {
+	e.results = &RepositoryMockProductHistoryResults{pa1, err}
 	return e.mock
 }
 
-// ManageProducts implements Repository
-func (mmManageProducts *RepositoryMock) ManageProducts(sellerId uint64, productsToAdd []models.Product, productsToDelete []models.Product, productsToUpdate []models.Product) (u1 uint64, err error) {
-	mm_atomic.AddUint64(&mmManageProducts.beforeManageProductsCounter, 1)
-	defer mm_atomic.AddUint64(&mmManageProducts.afterManageProductsCounter, 1)
+// ProductHistory implements Repository
+func (mmProductHistory *RepositoryMock) ProductHistory(sellerId uint64, offerId uint64, limit uint64) (pa1 []models.ProductChange, err error) {
+	mm_atomic.AddUint64(&mmProductHistory.beforeProductHistoryCounter, 1)
+	defer mm_atomic.AddUint64(&mmProductHistory.afterProductHistoryCounter, 1)
 
-	if mmManageProducts.inspectFuncManageProducts != nil {
-		mmManageProducts.inspectFuncManageProducts(sellerId, productsToAdd, productsToDelete, productsToUpdate)
+	if mmProductHistory.inspectFuncProductHistory != nil {
+		mmProductHistory.inspectFuncProductHistory(sellerId, offerId, limit)
 	}
 
-	mm_params := &RepositoryMockManageProductsParams{sellerId, productsToAdd, productsToDelete, productsToUpdate}
+	mm_params := &RepositoryMockProductHistoryParams{sellerId, offerId, limit}
 
 	// Record call args
-	mmManageProducts.ManageProductsMock.mutex.Lock()
-	mmManageProducts.ManageProductsMock.callArgs = append(mmManageProducts.ManageProductsMock.callArgs, mm_params)
-	mmManageProducts.ManageProductsMock.mutex.Unlock()
+	mmProductHistory.ProductHistoryMock.mutex.Lock()
+	mmProductHistory.ProductHistoryMock.callArgs = append(mmProductHistory.ProductHistoryMock.callArgs, mm_params)
+	mmProductHistory.ProductHistoryMock.mutex.Unlock()
 
-	for _, e := range mmManageProducts.ManageProductsMock.expectations {
+	for _, e := range mmProductHistory.ProductHistoryMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.u1, e.results.err
+			return e.results.pa1, e.results.err
 		}
 	}
 
-	if mmManageProducts.ManageProductsMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmManageProducts.ManageProductsMock.defaultExpectation.Counter, 1)
-		mm_want := mmManageProducts.ManageProductsMock.defaultExpectation.params
-		mm_got := RepositoryMockManageProductsParams{sellerId, productsToAdd, productsToDelete, productsToUpdate}
+	if mmProductHistory.ProductHistoryMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmProductHistory.ProductHistoryMock.defaultExpectation.Counter, 1)
+		mm_want := mmProductHistory.ProductHistoryMock.defaultExpectation.params
+		mm_got := RepositoryMockProductHistoryParams{sellerId, offerId, limit}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmManageProducts.t.Errorf("RepositoryMock.ManageProducts got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmProductHistory.t.Errorf("RepositoryMock.ProductHistory got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmManageProducts.ManageProductsMock.defaultExpectation.results
+		mm_results := mmProductHistory.ProductHistoryMock.defaultExpectation.results
 		if mm_results == nil {
-			mmManageProducts.t.Fatal("No results are set for the RepositoryMock.ManageProducts")
+			mmProductHistory.t.Fatal("No results are set for the RepositoryMock.ProductHistory")
 		}
-		return (*mm_results).u1, (*mm_results).err
+		return (*mm_results).pa1, (*mm_results).err
 	}
-	if mmManageProducts.funcManageProducts != nil {
-		return mmManageProducts.funcManageProducts(sellerId, productsToAdd, productsToDelete, productsToUpdate)
+	if mmProductHistory.funcProductHistory != nil {
+		return mmProductHistory.funcProductHistory(sellerId, offerId, limit)
 	}
-	mmManageProducts.t.Fatalf("Unexpected call to RepositoryMock.ManageProducts. %v %v %v %v", sellerId, productsToAdd, productsToDelete, productsToUpdate)
+	mmProductHistory.t.Fatalf("Unexpected call to RepositoryMock.ProductHistory. %v %v %v", sellerId, offerId, limit)
 	return
 }
 
-// ManageProductsAfterCounter returns a count of finished RepositoryMock.ManageProducts invocations
-func (mmManageProducts *RepositoryMock) ManageProductsAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmManageProducts.afterManageProductsCounter)
+// ProductHistoryAfterCounter returns a count of finished RepositoryMock.ProductHistory invocations
+func (mmProductHistory *RepositoryMock) ProductHistoryAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProductHistory.afterProductHistoryCounter)
 }
 
-// ManageProductsBeforeCounter returns a count of RepositoryMock.ManageProducts invocations
-func (mmManageProducts *RepositoryMock) ManageProductsBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmManageProducts.beforeManageProductsCounter)
+// ProductHistoryBeforeCounter returns a count of RepositoryMock.ProductHistory invocations
+func (mmProductHistory *RepositoryMock) ProductHistoryBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProductHistory.beforeProductHistoryCounter)
 }
 
-// Calls returns a list of arguments used in each call to RepositoryMock.ManageProducts.
+// Calls returns a list of arguments used in each call to RepositoryMock.ProductHistory.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmManageProducts *mRepositoryMockManageProducts) Calls() []*RepositoryMockManageProductsParams {
-	mmManageProducts.mutex.RLock()
+func (mmProductHistory *mRepositoryMockProductHistory) Calls() []*RepositoryMockProductHistoryParams {
+	mmProductHistory.mutex.RLock()
 
-	argCopy := make([]*RepositoryMockManageProductsParams, len(mmManageProducts.callArgs))
-	copy(argCopy, mmManageProducts.callArgs)
+	argCopy := make([]*RepositoryMockProductHistoryParams, len(mmProductHistory.callArgs))
+	copy(argCopy, mmProductHistory.callArgs)
 
-	mmManageProducts.mutex.RUnlock()
+	mmProductHistory.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockManageProductsDone returns true if the count of the ManageProducts invocations corresponds
+// MinimockProductHistoryDone returns true if the count of the ProductHistory invocations corresponds
 // the number of defined expectations
-func (m *RepositoryMock) MinimockManageProductsDone() bool {
-	for _, e := range m.ManageProductsMock.expectations {
+func (m *RepositoryMock) MinimockProductHistoryDone() bool {
+	for _, e := range m.ProductHistoryMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.ManageProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterManageProductsCounter) < 1 {
+	if m.ProductHistoryMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductHistoryCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcManageProducts != nil && mm_atomic.LoadUint64(&m.afterManageProductsCounter) < 1 {
+	if m.funcProductHistory != nil && mm_atomic.LoadUint64(&m.afterProductHistoryCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockManageProductsInspect logs each unmet expectation
-func (m *RepositoryMock) MinimockManageProductsInspect() {
-	for _, e := range m.ManageProductsMock.expectations {
+// MinimockProductHistoryInspect logs each unmet expectation
+func (m *RepositoryMock) MinimockProductHistoryInspect() {
+	for _, e := range m.ProductHistoryMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to RepositoryMock.ManageProducts with params: %#v", *e.params)
+			m.t.Errorf("Expected call to RepositoryMock.ProductHistory with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.ManageProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterManageProductsCounter) < 1 {
-		if m.ManageProductsMock.defaultExpectation.params == nil {
-			m.t.Error("Expected call to RepositoryMock.ManageProducts")
+	if m.ProductHistoryMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductHistoryCounter) < 1 {
+		if m.ProductHistoryMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to RepositoryMock.ProductHistory")
 		} else {
-			m.t.Errorf("Expected call to RepositoryMock.ManageProducts with params: %#v", *m.ManageProductsMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to RepositoryMock.ProductHistory with params: %#v", *m.ProductHistoryMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcManageProducts != nil && mm_atomic.LoadUint64(&m.afterManageProductsCounter) < 1 {
-		m.t.Error("Expected call to RepositoryMock.ManageProducts")
+	if m.funcProductHistory != nil && mm_atomic.LoadUint64(&m.afterProductHistoryCounter) < 1 {
+		m.t.Error("Expected call to RepositoryMock.ProductHistory")
 	}
 }
 
@@ -298,7 +1437,7 @@ type RepositoryMockProductsByFilterParams struct {
 
 // RepositoryMockProductsByFilterResults contains results of the Repository.ProductsByFilter
 type RepositoryMockProductsByFilterResults struct {
-	pa1 []models.Product
+	p1  ProductsPage
 	err error
 }
 
@@ -334,7 +1473,7 @@ func (mmProductsByFilter *mRepositoryMockProductsByFilter) Inspect(f func(filter
 }
 
 // Return sets up results that will be returned by Repository.ProductsByFilter
-func (mmProductsByFilter *mRepositoryMockProductsByFilter) Return(pa1 []models.Product, err error) *RepositoryMock {
+func (mmProductsByFilter *mRepositoryMockProductsByFilter) Return(p1 ProductsPage, err error) *RepositoryMock {
 	if mmProductsByFilter.mock.funcProductsByFilter != nil {
 		mmProductsByFilter.mock.t.Fatalf("RepositoryMock.ProductsByFilter mock is already set by Set")
 	}
@@ -342,12 +1481,12 @@ func (mmProductsByFilter *mRepositoryMockProductsByFilter) Return(pa1 []models.P
 	if mmProductsByFilter.defaultExpectation == nil {
 		mmProductsByFilter.defaultExpectation = &RepositoryMockProductsByFilterExpectation{mock: mmProductsByFilter.mock}
 	}
-	mmProductsByFilter.defaultExpectation.results = &RepositoryMockProductsByFilterResults{pa1, err}
+	mmProductsByFilter.defaultExpectation.results = &RepositoryMockProductsByFilterResults{p1, err}
 	return mmProductsByFilter.mock
 }
 
 // Set uses given function f to mock the Repository.ProductsByFilter method
-func (mmProductsByFilter *mRepositoryMockProductsByFilter) Set(f func(filter RequestFilter) (pa1 []models.Product, err error)) *RepositoryMock {
+func (mmProductsByFilter *mRepositoryMockProductsByFilter) Set(f func(filter RequestFilter) (p1 ProductsPage, err error)) *RepositoryMock {
 	if mmProductsByFilter.defaultExpectation != nil {
 		mmProductsByFilter.mock.t.Fatalf("Default expectation is already set for the Repository.ProductsByFilter method")
 	}
@@ -376,13 +1515,13 @@ func (mmProductsByFilter *mRepositoryMockProductsByFilter) When(filter RequestFi
 }
 
 // Then sets up Repository.ProductsByFilter return parameters for the expectation previously defined by the When method
-func (e *RepositoryMockProductsByFilterExpectation) Then(pa1 []models.Product, err error) *RepositoryMock {
-	e.results = &RepositoryMockProductsByFilterResults{pa1, err}
+func (e *RepositoryMockProductsByFilterExpectation) Then(p1 ProductsPage, err error) *RepositoryMock {
+	e.results = &RepositoryMockProductsByFilterResults{p1, err}
 	return e.mock
 }
 
 // ProductsByFilter implements Repository
-func (mmProductsByFilter *RepositoryMock) ProductsByFilter(filter RequestFilter) (pa1 []models.Product, err error) {
+func (mmProductsByFilter *RepositoryMock) ProductsByFilter(filter RequestFilter) (p1 ProductsPage, err error) {
 	mm_atomic.AddUint64(&mmProductsByFilter.beforeProductsByFilterCounter, 1)
 	defer mm_atomic.AddUint64(&mmProductsByFilter.afterProductsByFilterCounter, 1)
 
@@ -400,7 +1539,7 @@ func (mmProductsByFilter *RepositoryMock) ProductsByFilter(filter RequestFilter)
 	for _, e := range mmProductsByFilter.ProductsByFilterMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.pa1, e.results.err
+			return e.results.p1, e.results.err
 		}
 	}
 
@@ -416,7 +1555,7 @@ func (mmProductsByFilter *RepositoryMock) ProductsByFilter(filter RequestFilter)
 		if mm_results == nil {
 			mmProductsByFilter.t.Fatal("No results are set for the RepositoryMock.ProductsByFilter")
 		}
-		return (*mm_results).pa1, (*mm_results).err
+		return (*mm_results).p1, (*mm_results).err
 	}
 	if mmProductsByFilter.funcProductsByFilter != nil {
 		return mmProductsByFilter.funcProductsByFilter(filter)
@@ -490,230 +1629,460 @@ func (m *RepositoryMock) MinimockProductsByFilterInspect() {
 	}
 }
 
-type mRepositoryMockSellerProductIDs struct {
+type mRepositoryMockPutProduct struct {
+	mock               *RepositoryMock
+	defaultExpectation *RepositoryMockPutProductExpectation
+	expectations       []*RepositoryMockPutProductExpectation
+
+	callArgs []*RepositoryMockPutProductParams
+	mutex    sync.RWMutex
+}
+
+// RepositoryMockPutProductExpectation specifies expectation struct of the Repository.PutProduct
+type RepositoryMockPutProductExpectation struct {
+	mock    *RepositoryMock
+	params  *RepositoryMockPutProductParams
+	results *RepositoryMockPutProductResults
+	Counter uint64
+}
+
+// RepositoryMockPutProductParams contains parameters of the Repository.PutProduct
+type RepositoryMockPutProductParams struct {
+	product models.Product
+	source  models.ChangeSource
+}
+
+// RepositoryMockPutProductResults contains results of the Repository.PutProduct
+type RepositoryMockPutProductResults struct {
+	created bool
+	err     error
+}
+
+// Expect sets up expected params for Repository.PutProduct
+func (mmPutProduct *mRepositoryMockPutProduct) Expect(product models.Product, source models.ChangeSource) *mRepositoryMockPutProduct {
+	if mmPutProduct.mock.funcPutProduct != nil {
+		mmPutProduct.mock.t.Fatalf("RepositoryMock.PutProduct mock is already set by Set")
+	}
+
+	if mmPutProduct.defaultExpectation == nil {
+		mmPutProduct.defaultExpectation = &RepositoryMockPutProductExpectation{}
+	}
+
+	mmPutProduct.defaultExpectation.params = &RepositoryMockPutProductParams{product, source}
+	for _, e := range mmPutProduct.expectations {
+		if minimock.Equal(e.params, mmPutProduct.defaultExpectation.params) {
+			mmPutProduct.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmPutProduct.defaultExpectation.params)
+		}
+	}
+
+	return mmPutProduct
+}
+
+// Inspect accepts an inspector function that has same arguments as the Repository.PutProduct
+func (mmPutProduct *mRepositoryMockPutProduct) Inspect(f func(product models.Product, source models.ChangeSource)) *mRepositoryMockPutProduct {
+	if mmPutProduct.mock.inspectFuncPutProduct != nil {
+		mmPutProduct.mock.t.Fatalf("Inspect function is already set for RepositoryMock.PutProduct")
+	}
+
+	mmPutProduct.mock.inspectFuncPutProduct = f
+
+	return mmPutProduct
+}
+
+// Return sets up results that will be returned by Repository.PutProduct
+func (mmPutProduct *mRepositoryMockPutProduct) Return(created bool, err error) *RepositoryMock {
+	if mmPutProduct.mock.funcPutProduct != nil {
+		mmPutProduct.mock.t.Fatalf("RepositoryMock.PutProduct mock is already set by Set")
+	}
+
+	if mmPutProduct.defaultExpectation == nil {
+		mmPutProduct.defaultExpectation = &RepositoryMockPutProductExpectation{mock: mmPutProduct.mock}
+	}
+	mmPutProduct.defaultExpectation.results = &RepositoryMockPutProductResults{created, err}
+	return mmPutProduct.mock
+}
+
+// Set uses given function f to mock the Repository.PutProduct method
+func (mmPutProduct *mRepositoryMockPutProduct) Set(f func(product models.Product, source models.ChangeSource) (created bool, err error)) *RepositoryMock {
+	if mmPutProduct.defaultExpectation != nil {
+		mmPutProduct.mock.t.Fatalf("Default expectation is already set for the Repository.PutProduct method")
+	}
+
+	if len(mmPutProduct.expectations) > 0 {
+		mmPutProduct.mock.t.Fatalf("Some expectations are already set for the Repository.PutProduct method")
+	}
+
+	mmPutProduct.mock.funcPutProduct = f
+	return mmPutProduct.mock
+}
+
+// When sets expectation for the Repository.PutProduct which will trigger the result defined by the following
+// Then helper
+func (mmPutProduct *mRepositoryMockPutProduct) When(product models.Product, source models.ChangeSource) *RepositoryMockPutProductExpectation {
+	if mmPutProduct.mock.funcPutProduct != nil {
+		mmPutProduct.mock.t.Fatalf("RepositoryMock.PutProduct mock is already set by Set")
+	}
+
+	expectation := &RepositoryMockPutProductExpectation{
+		mock:   mmPutProduct.mock,
+		params: &RepositoryMockPutProductParams{product, source},
+	}
+	mmPutProduct.expectations = append(mmPutProduct.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Repository.PutProduct return parameters for the expectation previously defined by the When method
+func (e *RepositoryMockPutProductExpectation) Then(created bool, err error) *RepositoryMock {
+	e.results = &RepositoryMockPutProductResults{created, err}
+	return e.mock
+}
+
+// PutProduct implements Repository
+func (mmPutProduct *RepositoryMock) PutProduct(product models.Product, source models.ChangeSource) (created bool, err error) {
+	mm_atomic.AddUint64(&mmPutProduct.beforePutProductCounter, 1)
+	defer mm_atomic.AddUint64(&mmPutProduct.afterPutProductCounter, 1)
+
+	if mmPutProduct.inspectFuncPutProduct != nil {
+		mmPutProduct.inspectFuncPutProduct(product, source)
+	}
+
+	mm_params := &RepositoryMockPutProductParams{product, source}
+
+	// Record call args
+	mmPutProduct.PutProductMock.mutex.Lock()
+	mmPutProduct.PutProductMock.callArgs = append(mmPutProduct.PutProductMock.callArgs, mm_params)
+	mmPutProduct.PutProductMock.mutex.Unlock()
+
+	for _, e := range mmPutProduct.PutProductMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.created, e.results.err
+		}
+	}
+
+	if mmPutProduct.PutProductMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmPutProduct.PutProductMock.defaultExpectation.Counter, 1)
+		mm_want := mmPutProduct.PutProductMock.defaultExpectation.params
+		mm_got := RepositoryMockPutProductParams{product, source}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmPutProduct.t.Errorf("RepositoryMock.PutProduct got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmPutProduct.PutProductMock.defaultExpectation.results
+		if mm_results == nil {
+			mmPutProduct.t.Fatal("No results are set for the RepositoryMock.PutProduct")
+		}
+		return (*mm_results).created, (*mm_results).err
+	}
+	if mmPutProduct.funcPutProduct != nil {
+		return mmPutProduct.funcPutProduct(product, source)
+	}
+	mmPutProduct.t.Fatalf("Unexpected call to RepositoryMock.PutProduct. %v %v", product, source)
+	return
+}
+
+// PutProductAfterCounter returns a count of finished RepositoryMock.PutProduct invocations
+func (mmPutProduct *RepositoryMock) PutProductAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPutProduct.afterPutProductCounter)
+}
+
+// PutProductBeforeCounter returns a count of RepositoryMock.PutProduct invocations
+func (mmPutProduct *RepositoryMock) PutProductBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPutProduct.beforePutProductCounter)
+}
+
+// Calls returns a list of arguments used in each call to RepositoryMock.PutProduct.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmPutProduct *mRepositoryMockPutProduct) Calls() []*RepositoryMockPutProductParams {
+	mmPutProduct.mutex.RLock()
+
+	argCopy := make([]*RepositoryMockPutProductParams, len(mmPutProduct.callArgs))
+	copy(argCopy, mmPutProduct.callArgs)
+
+	mmPutProduct.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockPutProductDone returns true if the count of the PutProduct invocations corresponds
+// the number of defined expectations
+func (m *RepositoryMock) MinimockPutProductDone() bool {
+	for _, e := range m.PutProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.PutProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPutProductCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcPutProduct != nil && mm_atomic.LoadUint64(&m.afterPutProductCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockPutProductInspect logs each unmet expectation
+func (m *RepositoryMock) MinimockPutProductInspect() {
+	for _, e := range m.PutProductMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to RepositoryMock.PutProduct with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.PutProductMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPutProductCounter) < 1 {
+		if m.PutProductMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to RepositoryMock.PutProduct")
+		} else {
+			m.t.Errorf("Expected call to RepositoryMock.PutProduct with params: %#v", *m.PutProductMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcPutProduct != nil && mm_atomic.LoadUint64(&m.afterPutProductCounter) < 1 {
+		m.t.Error("Expected call to RepositoryMock.PutProduct")
+	}
+}
+
+type mRepositoryMockSellerProductsByIDs struct {
 	mock               *RepositoryMock
-	defaultExpectation *RepositoryMockSellerProductIDsExpectation
-	expectations       []*RepositoryMockSellerProductIDsExpectation
+	defaultExpectation *RepositoryMockSellerProductsByIDsExpectation
+	expectations       []*RepositoryMockSellerProductsByIDsExpectation
 
-	callArgs []*RepositoryMockSellerProductIDsParams
+	callArgs []*RepositoryMockSellerProductsByIDsParams
 	mutex    sync.RWMutex
 }
 
-// RepositoryMockSellerProductIDsExpectation specifies expectation struct of the Repository.SellerProductIDs
-type RepositoryMockSellerProductIDsExpectation struct {
+// RepositoryMockSellerProductsByIDsExpectation specifies expectation struct of the Repository.SellerProductsByIDs
+type RepositoryMockSellerProductsByIDsExpectation struct {
 	mock    *RepositoryMock
-	params  *RepositoryMockSellerProductIDsParams
-	results *RepositoryMockSellerProductIDsResults
+	params  *RepositoryMockSellerProductsByIDsParams
+	results *RepositoryMockSellerProductsByIDsResults
 	Counter uint64
 }
 
-// RepositoryMockSellerProductIDsParams contains parameters of the Repository.SellerProductIDs
-type RepositoryMockSellerProductIDsParams struct {
+// RepositoryMockSellerProductsByIDsParams contains parameters of the Repository.SellerProductsByIDs
+type RepositoryMockSellerProductsByIDsParams struct {
 	sellerId uint64
+	offerIDs []uint64
 }
 
-// RepositoryMockSellerProductIDsResults contains results of the Repository.SellerProductIDs
-type RepositoryMockSellerProductIDsResults struct {
-	ua1 []uint64
+// RepositoryMockSellerProductsByIDsResults contains results of the Repository.SellerProductsByIDs
+type RepositoryMockSellerProductsByIDsResults struct {
+	pa1 []models.Product
 	err error
 }
 
-// Expect sets up expected params for Repository.SellerProductIDs
-func (mmSellerProductIDs *mRepositoryMockSellerProductIDs) Expect(sellerId uint64) *mRepositoryMockSellerProductIDs {
-	if mmSellerProductIDs.mock.funcSellerProductIDs != nil {
-		mmSellerProductIDs.mock.t.Fatalf("RepositoryMock.SellerProductIDs mock is already set by Set")
+// Expect sets up expected params for Repository.SellerProductsByIDs
+func (mmSellerProductsByIDs *mRepositoryMockSellerProductsByIDs) Expect(sellerId uint64, offerIDs []uint64) *mRepositoryMockSellerProductsByIDs {
+	if mmSellerProductsByIDs.mock.funcSellerProductsByIDs != nil {
+		mmSellerProductsByIDs.mock.t.Fatalf("RepositoryMock.SellerProductsByIDs mock is already set by Set")
 	}
 
-	if mmSellerProductIDs.defaultExpectation == nil {
-		mmSellerProductIDs.defaultExpectation = &RepositoryMockSellerProductIDsExpectation{}
+	if mmSellerProductsByIDs.defaultExpectation == nil {
+		mmSellerProductsByIDs.defaultExpectation = &RepositoryMockSellerProductsByIDsExpectation{}
 	}
 
-	mmSellerProductIDs.defaultExpectation.params = &RepositoryMockSellerProductIDsParams{sellerId}
-	for _, e := range mmSellerProductIDs.expectations {
-		if minimock.Equal(e.params, mmSellerProductIDs.defaultExpectation.params) {
-			mmSellerProductIDs.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmSellerProductIDs.defaultExpectation.params)
+	mmSellerProductsByIDs.defaultExpectation.params = &RepositoryMockSellerProductsByIDsParams{sellerId, offerIDs}
+	for _, e := range mmSellerProductsByIDs.expectations {
+		if minimock.Equal(e.params, mmSellerProductsByIDs.defaultExpectation.params) {
+			mmSellerProductsByIDs.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmSellerProductsByIDs.defaultExpectation.params)
 		}
 	}
 
-	return mmSellerProductIDs
+	return mmSellerProductsByIDs
 }
 
-// Inspect accepts an inspector function that has same arguments as the Repository.SellerProductIDs
-func (mmSellerProductIDs *mRepositoryMockSellerProductIDs) Inspect(f func(sellerId uint64)) *mRepositoryMockSellerProductIDs {
-	if mmSellerProductIDs.mock.inspectFuncSellerProductIDs != nil {
-		mmSellerProductIDs.mock.t.Fatalf("Inspect function is already set for RepositoryMock.SellerProductIDs")
+// Inspect accepts an inspector function that has same arguments as the Repository.SellerProductsByIDs
+func (mmSellerProductsByIDs *mRepositoryMockSellerProductsByIDs) Inspect(f func(sellerId uint64, offerIDs []uint64)) *mRepositoryMockSellerProductsByIDs {
+	if mmSellerProductsByIDs.mock.inspectFuncSellerProductsByIDs != nil {
+		mmSellerProductsByIDs.mock.t.Fatalf("Inspect function is already set for RepositoryMock.SellerProductsByIDs")
 	}
 
-	mmSellerProductIDs.mock.inspectFuncSellerProductIDs = f
+	mmSellerProductsByIDs.mock.inspectFuncSellerProductsByIDs = f
 
-	return mmSellerProductIDs
+	return mmSellerProductsByIDs
 }
 
-// Return sets up results that will be returned by Repository.SellerProductIDs
-func (mmSellerProductIDs *mRepositoryMockSellerProductIDs) Return(ua1 []uint64, err error) *RepositoryMock {
-	if mmSellerProductIDs.mock.funcSellerProductIDs != nil {
-		mmSellerProductIDs.mock.t.Fatalf("RepositoryMock.SellerProductIDs mock is already set by Set")
+// Return sets up results that will be returned by Repository.SellerProductsByIDs
+func (mmSellerProductsByIDs *mRepositoryMockSellerProductsByIDs) Return(pa1 []models.Product, err error) *RepositoryMock {
+	if mmSellerProductsByIDs.mock.funcSellerProductsByIDs != nil {
+		mmSellerProductsByIDs.mock.t.Fatalf("RepositoryMock.SellerProductsByIDs mock is already set by Set")
 	}
 
-	if mmSellerProductIDs.defaultExpectation == nil {
-		mmSellerProductIDs.defaultExpectation = &RepositoryMockSellerProductIDsExpectation{mock: mmSellerProductIDs.mock}
+	if mmSellerProductsByIDs.defaultExpectation == nil {
+		mmSellerProductsByIDs.defaultExpectation = &RepositoryMockSellerProductsByIDsExpectation{mock: mmSellerProductsByIDs.mock}
 	}
-	mmSellerProductIDs.defaultExpectation.results = &RepositoryMockSellerProductIDsResults{ua1, err}
-	return mmSellerProductIDs.mock
+	mmSellerProductsByIDs.defaultExpectation.results = &RepositoryMockSellerProductsByIDsResults{pa1, err}
+	return mmSellerProductsByIDs.mock
 }
 
-// Set uses given function f to mock the Repository.SellerProductIDs method
-func (mmSellerProductIDs *mRepositoryMockSellerProductIDs) Set(f func(sellerId uint64) (ua1 []uint64, err error)) *RepositoryMock {
-	if mmSellerProductIDs.defaultExpectation != nil {
-		mmSellerProductIDs.mock.t.Fatalf("Default expectation is already set for the Repository.SellerProductIDs method")
+// Set uses given function f to mock the Repository.SellerProductsByIDs method
+func (mmSellerProductsByIDs *mRepositoryMockSellerProductsByIDs) Set(f func(sellerId uint64, offerIDs []uint64) (pa1 []models.Product, err error)) *RepositoryMock {
+	if mmSellerProductsByIDs.defaultExpectation != nil {
+		mmSellerProductsByIDs.mock.t.Fatalf("Default expectation is already set for the Repository.SellerProductsByIDs method")
 	}
 
-	if len(mmSellerProductIDs.expectations) > 0 {
-		mmSellerProductIDs.mock.t.Fatalf("Some expectations are already set for the Repository.SellerProductIDs method")
+	if len(mmSellerProductsByIDs.expectations) > 0 {
+		mmSellerProductsByIDs.mock.t.Fatalf("Some expectations are already set for the Repository.SellerProductsByIDs method")
 	}
 
-	mmSellerProductIDs.mock.funcSellerProductIDs = f
-	return mmSellerProductIDs.mock
+	mmSellerProductsByIDs.mock.funcSellerProductsByIDs = f
+	return mmSellerProductsByIDs.mock
 }
 
-// When sets expectation for the Repository.SellerProductIDs which will trigger the result defined by the following
+// When sets expectation for the Repository.SellerProductsByIDs which will trigger the result defined by the following
 // Then helper
-func (mmSellerProductIDs *mRepositoryMockSellerProductIDs) When(sellerId uint64) *RepositoryMockSellerProductIDsExpectation {
-	if mmSellerProductIDs.mock.funcSellerProductIDs != nil {
-		mmSellerProductIDs.mock.t.Fatalf("RepositoryMock.SellerProductIDs mock is already set by Set")
+func (mmSellerProductsByIDs *mRepositoryMockSellerProductsByIDs) When(sellerId uint64, offerIDs []uint64) *RepositoryMockSellerProductsByIDsExpectation {
+	if mmSellerProductsByIDs.mock.funcSellerProductsByIDs != nil {
+		mmSellerProductsByIDs.mock.t.Fatalf("RepositoryMock.SellerProductsByIDs mock is already set by Set")
 	}
 
-	expectation := &RepositoryMockSellerProductIDsExpectation{
-		mock:   mmSellerProductIDs.mock,
-		params: &RepositoryMockSellerProductIDsParams{sellerId},
+	expectation := &RepositoryMockSellerProductsByIDsExpectation{
+		mock:   mmSellerProductsByIDs.mock,
+		params: &RepositoryMockSellerProductsByIDsParams{sellerId, offerIDs},
 	}
-	mmSellerProductIDs.expectations = append(mmSellerProductIDs.expectations, expectation)
+	mmSellerProductsByIDs.expectations = append(mmSellerProductsByIDs.expectations, expectation)
 	return expectation
 }
 
-// Then sets up Repository.SellerProductIDs return parameters for the expectation previously defined by the When method
-func (e *RepositoryMockSellerProductIDsExpectation) Then(ua1 []uint64, err error) *RepositoryMock {
-	e.results = &RepositoryMockSellerProductIDsResults{ua1, err}
+// Then sets up Repository.SellerProductsByIDs return parameters for the expectation previously defined by the When method
+func (e *RepositoryMockSellerProductsByIDsExpectation) Then(pa1 []models.Product, err error) *RepositoryMock {
+	e.results = &RepositoryMockSellerProductsByIDsResults{pa1, err}
 	return e.mock
 }
 
-// SellerProductIDs implements Repository
-func (mmSellerProductIDs *RepositoryMock) SellerProductIDs(sellerId uint64) (ua1 []uint64, err error) {
-	mm_atomic.AddUint64(&mmSellerProductIDs.beforeSellerProductIDsCounter, 1)
-	defer mm_atomic.AddUint64(&mmSellerProductIDs.afterSellerProductIDsCounter, 1)
+// SellerProductsByIDs implements Repository
+func (mmSellerProductsByIDs *RepositoryMock) SellerProductsByIDs(sellerId uint64, offerIDs []uint64) (pa1 []models.Product, err error) {
+	mm_atomic.AddUint64(&mmSellerProductsByIDs.beforeSellerProductsByIDsCounter, 1)
+	defer mm_atomic.AddUint64(&mmSellerProductsByIDs.afterSellerProductsByIDsCounter, 1)
 
-	if mmSellerProductIDs.inspectFuncSellerProductIDs != nil {
-		mmSellerProductIDs.inspectFuncSellerProductIDs(sellerId)
+	if mmSellerProductsByIDs.inspectFuncSellerProductsByIDs != nil {
+		mmSellerProductsByIDs.inspectFuncSellerProductsByIDs(sellerId, offerIDs)
 	}
 
-	mm_params := &RepositoryMockSellerProductIDsParams{sellerId}
+	mm_params := &RepositoryMockSellerProductsByIDsParams{sellerId, offerIDs}
 
 	// Record call args
-	mmSellerProductIDs.SellerProductIDsMock.mutex.Lock()
-	mmSellerProductIDs.SellerProductIDsMock.callArgs = append(mmSellerProductIDs.SellerProductIDsMock.callArgs, mm_params)
-	mmSellerProductIDs.SellerProductIDsMock.mutex.Unlock()
+	mmSellerProductsByIDs.SellerProductsByIDsMock.mutex.Lock()
+	mmSellerProductsByIDs.SellerProductsByIDsMock.callArgs = append(mmSellerProductsByIDs.SellerProductsByIDsMock.callArgs, mm_params)
+	mmSellerProductsByIDs.SellerProductsByIDsMock.mutex.Unlock()
 
-	for _, e := range mmSellerProductIDs.SellerProductIDsMock.expectations {
+	for _, e := range mmSellerProductsByIDs.SellerProductsByIDsMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.ua1, e.results.err
+			return e.results.pa1, e.results.err
 		}
 	}
 
-	if mmSellerProductIDs.SellerProductIDsMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmSellerProductIDs.SellerProductIDsMock.defaultExpectation.Counter, 1)
-		mm_want := mmSellerProductIDs.SellerProductIDsMock.defaultExpectation.params
-		mm_got := RepositoryMockSellerProductIDsParams{sellerId}
+	if mmSellerProductsByIDs.SellerProductsByIDsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmSellerProductsByIDs.SellerProductsByIDsMock.defaultExpectation.Counter, 1)
+		mm_want := mmSellerProductsByIDs.SellerProductsByIDsMock.defaultExpectation.params
+		mm_got := RepositoryMockSellerProductsByIDsParams{sellerId, offerIDs}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmSellerProductIDs.t.Errorf("RepositoryMock.SellerProductIDs got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmSellerProductsByIDs.t.Errorf("RepositoryMock.SellerProductsByIDs got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmSellerProductIDs.SellerProductIDsMock.defaultExpectation.results
+		mm_results := mmSellerProductsByIDs.SellerProductsByIDsMock.defaultExpectation.results
 		if mm_results == nil {
-			mmSellerProductIDs.t.Fatal("No results are set for the RepositoryMock.SellerProductIDs")
+			mmSellerProductsByIDs.t.Fatal("No results are set for the RepositoryMock.SellerProductsByIDs")
 		}
-		return (*mm_results).ua1, (*mm_results).err
+		return (*mm_results).pa1, (*mm_results).err
 	}
-	if mmSellerProductIDs.funcSellerProductIDs != nil {
-		return mmSellerProductIDs.funcSellerProductIDs(sellerId)
+	if mmSellerProductsByIDs.funcSellerProductsByIDs != nil {
+		return mmSellerProductsByIDs.funcSellerProductsByIDs(sellerId, offerIDs)
 	}
-	mmSellerProductIDs.t.Fatalf("Unexpected call to RepositoryMock.SellerProductIDs. %v", sellerId)
+	mmSellerProductsByIDs.t.Fatalf("Unexpected call to RepositoryMock.SellerProductsByIDs. %v %v", sellerId, offerIDs)
 	return
 }
 
-// SellerProductIDsAfterCounter returns a count of finished RepositoryMock.SellerProductIDs invocations
-func (mmSellerProductIDs *RepositoryMock) SellerProductIDsAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmSellerProductIDs.afterSellerProductIDsCounter)
+// SellerProductsByIDsAfterCounter returns a count of finished RepositoryMock.SellerProductsByIDs invocations
+func (mmSellerProductsByIDs *RepositoryMock) SellerProductsByIDsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmSellerProductsByIDs.afterSellerProductsByIDsCounter)
 }
 
-// SellerProductIDsBeforeCounter returns a count of RepositoryMock.SellerProductIDs invocations
-func (mmSellerProductIDs *RepositoryMock) SellerProductIDsBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmSellerProductIDs.beforeSellerProductIDsCounter)
+// SellerProductsByIDsBeforeCounter returns a count of RepositoryMock.SellerProductsByIDs invocations
+func (mmSellerProductsByIDs *RepositoryMock) SellerProductsByIDsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmSellerProductsByIDs.beforeSellerProductsByIDsCounter)
 }
 
-// Calls returns a list of arguments used in each call to RepositoryMock.SellerProductIDs.
+// Calls returns a list of arguments used in each call to RepositoryMock.SellerProductsByIDs.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmSellerProductIDs *mRepositoryMockSellerProductIDs) Calls() []*RepositoryMockSellerProductIDsParams {
-	mmSellerProductIDs.mutex.RLock()
+func (mmSellerProductsByIDs *mRepositoryMockSellerProductsByIDs) Calls() []*RepositoryMockSellerProductsByIDsParams {
+	mmSellerProductsByIDs.mutex.RLock()
 
-	argCopy := make([]*RepositoryMockSellerProductIDsParams, len(mmSellerProductIDs.callArgs))
-	copy(argCopy, mmSellerProductIDs.callArgs)
+	argCopy := make([]*RepositoryMockSellerProductsByIDsParams, len(mmSellerProductsByIDs.callArgs))
+	copy(argCopy, mmSellerProductsByIDs.callArgs)
 
-	mmSellerProductIDs.mutex.RUnlock()
+	mmSellerProductsByIDs.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockSellerProductIDsDone returns true if the count of the SellerProductIDs invocations corresponds
+// MinimockSellerProductsByIDsDone returns true if the count of the SellerProductsByIDs invocations corresponds
 // the number of defined expectations
-func (m *RepositoryMock) MinimockSellerProductIDsDone() bool {
-	for _, e := range m.SellerProductIDsMock.expectations {
+func (m *RepositoryMock) MinimockSellerProductsByIDsDone() bool {
+	for _, e := range m.SellerProductsByIDsMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.SellerProductIDsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterSellerProductIDsCounter) < 1 {
+	if m.SellerProductsByIDsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterSellerProductsByIDsCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcSellerProductIDs != nil && mm_atomic.LoadUint64(&m.afterSellerProductIDsCounter) < 1 {
+	if m.funcSellerProductsByIDs != nil && mm_atomic.LoadUint64(&m.afterSellerProductsByIDsCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockSellerProductIDsInspect logs each unmet expectation
-func (m *RepositoryMock) MinimockSellerProductIDsInspect() {
-	for _, e := range m.SellerProductIDsMock.expectations {
+// MinimockSellerProductsByIDsInspect logs each unmet expectation
+func (m *RepositoryMock) MinimockSellerProductsByIDsInspect() {
+	for _, e := range m.SellerProductsByIDsMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to RepositoryMock.SellerProductIDs with params: %#v", *e.params)
+			m.t.Errorf("Expected call to RepositoryMock.SellerProductsByIDs with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.SellerProductIDsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterSellerProductIDsCounter) < 1 {
-		if m.SellerProductIDsMock.defaultExpectation.params == nil {
-			m.t.Error("Expected call to RepositoryMock.SellerProductIDs")
+	if m.SellerProductsByIDsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterSellerProductsByIDsCounter) < 1 {
+		if m.SellerProductsByIDsMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to RepositoryMock.SellerProductsByIDs")
 		} else {
-			m.t.Errorf("Expected call to RepositoryMock.SellerProductIDs with params: %#v", *m.SellerProductIDsMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to RepositoryMock.SellerProductsByIDs with params: %#v", *m.SellerProductsByIDsMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcSellerProductIDs != nil && mm_atomic.LoadUint64(&m.afterSellerProductIDsCounter) < 1 {
-		m.t.Error("Expected call to RepositoryMock.SellerProductIDs")
+	if m.funcSellerProductsByIDs != nil && mm_atomic.LoadUint64(&m.afterSellerProductsByIDsCounter) < 1 {
+		m.t.Error("Expected call to RepositoryMock.SellerProductsByIDs")
 	}
 }
 
 // MinimockFinish checks that all mocked methods have been called the expected number of times
 func (m *RepositoryMock) MinimockFinish() {
 	if !m.minimockDone() {
-		m.MinimockManageProductsInspect()
+		m.MinimockBeginImportInspect()
+
+		m.MinimockDeleteProductInspect()
+
+		m.MinimockExportProductsInspect()
+
+		m.MinimockPatchProductInspect()
+
+		m.MinimockProductInspect()
+
+		m.MinimockProductHistoryInspect()
 
 		m.MinimockProductsByFilterInspect()
 
-		m.MinimockSellerProductIDsInspect()
+		m.MinimockPutProductInspect()
+
+		m.MinimockSellerProductsByIDsInspect()
 		m.t.FailNow()
 	}
 }
@@ -737,7 +2106,13 @@ func (m *RepositoryMock) MinimockWait(timeout mm_time.Duration) {
 func (m *RepositoryMock) minimockDone() bool {
 	done := true
 	return done &&
-		m.MinimockManageProductsDone() &&
+		m.MinimockBeginImportDone() &&
+		m.MinimockDeleteProductDone() &&
+		m.MinimockExportProductsDone() &&
+		m.MinimockPatchProductDone() &&
+		m.MinimockProductDone() &&
+		m.MinimockProductHistoryDone() &&
 		m.MinimockProductsByFilterDone() &&
-		m.MinimockSellerProductIDsDone()
+		m.MinimockPutProductDone() &&
+		m.MinimockSellerProductsByIDsDone()
 }
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+)
+
+// ruleSets - правила проверки товаров из config.yml: общие и отдельных продавцов
+type ruleSets struct {
+	def     rules
+	sellers map[uint64]rules
+}
+
+// rules - config.Rules, подготовленные к проверке
+type rules struct {
+	minPrice              uint64
+	maxPrice              uint64
+	maxQuantity           uint64
+	forbiddenWords        map[string]struct{}
+	requireName           bool
+	maxPriceChangePercent float64
+}
+
+func newRuleSets(cfg config.Validation) ruleSets {
+	rs := ruleSets{
+		def:     newRules(cfg.Default),
+		sellers: make(map[uint64]rules, len(cfg.Sellers)),
+	}
+	for sellerId, sellerRules := range cfg.Sellers {
+		rs.sellers[sellerId] = newRules(sellerRules)
+	}
+
+	return rs
+}
+
+func newRules(cfg config.Rules) rules {
+	r := rules{
+		minPrice:              cfg.MinPrice,
+		maxPrice:              cfg.MaxPrice,
+		maxQuantity:           cfg.MaxQuantity,
+		forbiddenWords:        make(map[string]struct{}, len(cfg.ForbiddenWords)),
+		requireName:           cfg.RequireName,
+		maxPriceChangePercent: cfg.MaxPriceChangePercent,
+	}
+	for _, word := range cfg.ForbiddenWords {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			r.forbiddenWords[word] = struct{}{}
+		}
+	}
+
+	return r
+}
+
+func (rs ruleSets) forSeller(sellerId uint64) rules {
+	if r, ok := rs.sellers[sellerId]; ok {
+		return r
+	}
+
+	return rs.def
+}
+
+// check проверяет товар целиком, кроме изменения цены
+func (r rules) check(product models.Product) []models.ImportIssue {
+	issues := r.checkName(product)
+	issues = append(issues, r.checkPrice(product)...)
+	issues = append(issues, r.checkQuantity(product)...)
+
+	return issues
+}
+
+func (r rules) checkName(product models.Product) []models.ImportIssue {
+	var issues []models.ImportIssue
+	if r.requireName && strings.TrimSpace(product.Name) == "" {
+		issues = append(issues, ruleIssue(product, "name", models.CodeEmptyName, "name is required"))
+	}
+
+	if len(r.forbiddenWords) == 0 {
+		return issues
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(product.Name), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	for _, word := range words {
+		if _, ok := r.forbiddenWords[word]; ok {
+			issues = append(issues, ruleIssue(product, "name", models.CodeForbiddenWord, fmt.Sprintf("name contains forbidden word %q", word)))
+			break
+		}
+	}
+
+	return issues
+}
+
+func (r rules) checkPrice(product models.Product) []models.ImportIssue {
+	switch {
+	case product.Price < r.minPrice:
+		return []models.ImportIssue{ruleIssue(product, "price", models.CodePriceTooLow, fmt.Sprintf("price %d is less than %d", product.Price, r.minPrice))}
+
+	case r.maxPrice > 0 && product.Price > r.maxPrice:
+		return []models.ImportIssue{ruleIssue(product, "price", models.CodePriceTooHigh, fmt.Sprintf("price %d is greater than %d", product.Price, r.maxPrice))}
+	}
+
+	return nil
+}
+
+func (r rules) checkQuantity(product models.Product) []models.ImportIssue {
+	if r.maxQuantity > 0 && product.Quantity > r.maxQuantity {
+		return []models.ImportIssue{ruleIssue(product, "quantity", models.CodeQuantityTooHigh, fmt.Sprintf("quantity %d is greater than %d", product.Quantity, r.maxQuantity))}
+	}
+
+	return nil
+}
+
+// checkPriceChange сравнивает новую цену с сохранённой; у товара с нулевой ценой изменение не ограничено
+func (r rules) checkPriceChange(product models.Product, stored models.Product) []models.ImportIssue {
+	if r.maxPriceChangePercent <= 0 || stored.Price == 0 {
+		return nil
+	}
+
+	diff := float64(product.Price) - float64(stored.Price)
+	if diff < 0 {
+		diff = -diff
+	}
+
+	changePercent := diff / float64(stored.Price) * 100
+	if changePercent > r.maxPriceChangePercent {
+		msg := fmt.Sprintf("price changes from %d to %d (%.1f%%), more than %g%%", stored.Price, product.Price, changePercent, r.maxPriceChangePercent)
+		return []models.ImportIssue{ruleIssue(product, "price", models.CodePriceChangeTooHigh, msg)}
+	}
+
+	return nil
+}
+
+func ruleIssue(product models.Product, field string, code models.IssueCode, msg string) models.ImportIssue {
+	return models.ImportIssue{
+		OfferId:  product.OfferId,
+		Field:    field,
+		Code:     code,
+		Severity: models.SeverityError,
+		Message:  msg,
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules_check(t *testing.T) {
+	r := newRules(config.Rules{
+		MinPrice:       10,
+		MaxPrice:       1000,
+		MaxQuantity:    50,
+		ForbiddenWords: []string{" Реплика ", "fake"},
+		RequireName:    true,
+	})
+
+	testCases := []struct {
+		name    string
+		product models.Product
+		want    []models.ImportIssue
+	}{
+		{
+			name:    "товар проходит все правила",
+			product: models.Product{OfferId: 1, Name: "Кроссовки", Price: 100, Quantity: 5},
+			want:    nil,
+		},
+		{
+			name:    "пустое название",
+			product: models.Product{OfferId: 1, Name: "  ", Price: 100, Quantity: 5},
+			want: []models.ImportIssue{
+				{OfferId: 1, Field: "name", Code: models.CodeEmptyName, Severity: models.SeverityError, Message: "name is required"},
+			},
+		},
+		{
+			name:    "запрещённое слово без учёта регистра и пунктуации",
+			product: models.Product{OfferId: 1, Name: "Кроссовки (РЕПЛИКА)", Price: 100, Quantity: 5},
+			want: []models.ImportIssue{
+				{OfferId: 1, Field: "name", Code: models.CodeForbiddenWord, Severity: models.SeverityError, Message: `name contains forbidden word "реплика"`},
+			},
+		},
+		{
+			name:    "запрещённое слово внутри другого слова не считается",
+			product: models.Product{OfferId: 1, Name: "fakel", Price: 100, Quantity: 5},
+			want:    nil,
+		},
+		{
+			name:    "цена ниже минимальной и количество выше максимального",
+			product: models.Product{OfferId: 1, Name: "Кроссовки", Price: 0, Quantity: 51},
+			want: []models.ImportIssue{
+				{OfferId: 1, Field: "price", Code: models.CodePriceTooLow, Severity: models.SeverityError, Message: "price 0 is less than 10"},
+				{OfferId: 1, Field: "quantity", Code: models.CodeQuantityTooHigh, Severity: models.SeverityError, Message: "quantity 51 is greater than 50"},
+			},
+		},
+		{
+			name:    "цена выше максимальной",
+			product: models.Product{OfferId: 1, Name: "Кроссовки", Price: 1001, Quantity: 5},
+			want: []models.ImportIssue{
+				{OfferId: 1, Field: "price", Code: models.CodePriceTooHigh, Severity: models.SeverityError, Message: "price 1001 is greater than 1000"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, r.check(tc.product))
+		})
+	}
+
+	// без правил проверять нечего
+	assert.Empty(t, newRules(config.Rules{}).check(models.Product{OfferId: 1}))
+}
+
+func TestRules_checkPriceChange(t *testing.T) {
+	r := newRules(config.Rules{MaxPriceChangePercent: 50})
+
+	testCases := []struct {
+		name     string
+		newPrice uint64
+		stored   uint64
+		wantCode models.IssueCode
+	}{
+		{name: "рост в пределах", newPrice: 150, stored: 100},
+		{name: "падение в пределах", newPrice: 50, stored: 100},
+		{name: "рост сверх лимита", newPrice: 151, stored: 100, wantCode: models.CodePriceChangeTooHigh},
+		{name: "обнуление цены", newPrice: 0, stored: 100, wantCode: models.CodePriceChangeTooHigh},
+		{name: "у сохранённого товара нулевая цена", newPrice: 1000, stored: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			issues := r.checkPriceChange(models.Product{OfferId: 1, Price: tc.newPrice}, models.Product{OfferId: 1, Price: tc.stored})
+			if tc.wantCode == "" {
+				assert.Empty(t, issues)
+				return
+			}
+
+			if assert.Len(t, issues, 1) {
+				assert.Equal(t, tc.wantCode, issues[0].Code)
+				assert.Equal(t, "price", issues[0].Field)
+			}
+		})
+	}
+
+	assert.Empty(t, newRules(config.Rules{}).checkPriceChange(models.Product{Price: 0}, models.Product{Price: 100}))
+}
+
+func TestRuleSets_forSeller(t *testing.T) {
+	rs := newRuleSets(config.Validation{
+		Default: config.Rules{MaxPrice: 1000},
+		Sellers: map[uint64]config.Rules{
+			42: {MinPrice: 10},
+		},
+	})
+
+	// правила продавца целиком заменяют общие
+	assert.Equal(t, uint64(0), rs.forSeller(42).maxPrice)
+	assert.Equal(t, uint64(10), rs.forSeller(42).minPrice)
+	assert.Equal(t, uint64(1000), rs.forSeller(7).maxPrice)
+}
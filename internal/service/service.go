@@ -3,39 +3,65 @@ package service
 import (
 	"errors"
 	"log"
-	"sort"
+	"strconv"
 
+	"github.com/hablof/merchant-experience/internal/config"
 	"github.com/hablof/merchant-experience/internal/models"
 )
 
 type Service struct {
-	repo Repository
+	repo  Repository
+	rules ruleSets
+	guard guard
 }
 
-func NewService(r Repository) *Service {
+func NewService(cfg config.Config, r Repository) *Service {
 	s := Service{
-		repo: r,
+		repo:  r,
+		rules: newRuleSets(cfg.Validation),
+		guard: newGuard(cfg.Guard),
 	}
 	return &s
 }
 
 type Repository interface {
 	// SellerProducts(sellerId uint64) ([]models.Product, error)
-	SellerProductIDs(sellerId uint64) ([]uint64, error)
+
+	// товары продавца с указанными offer_id (для предпросмотра изменений)
+	SellerProductsByIDs(sellerId uint64, offerIDs []uint64) ([]models.Product, error)
 
 	// AddProducts(sellerId uint64, products []models.Product) error
 
-	// единый метод для обеспечения транзакционности внутри репо
-	ManageProducts(
-		sellerId uint64,
-		productsToAdd []models.Product,
-		productsToDelete []models.Product,
-		productsToUpdate []models.Product,
-	) (uint64, error)
+	// транзакция загрузки таблицы: пачки копятся в ней и пишутся в каталог разом
+	BeginImport(sellerId uint64) (ImportTx, error)
+
+	// последние изменения оффера, новые первыми
+	ProductHistory(sellerId uint64, offerId uint64, limit uint64) ([]models.ProductChange, error)
+
+	// страница товаров: не больше filter.Limit, NextCursor - ключ сортировки последнего товара, если за ней есть ещё товары
+	ProductsByFilter(filter RequestFilter) (ProductsPage, error)
+
+	// весь каталог продавца страницами по pageSize в порядке offer_id, одним снимком; ошибка handle возвращается как есть
+	ExportProducts(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) error
 
-	ProductsByFilter(filter RequestFilter) ([]models.Product, error)
+	// операции над одним оффером; отсутствие оффера - models.ErrNotFound
+	Product(sellerId uint64, offerId uint64) (models.Product, error)
+	PutProduct(product models.Product, source models.ChangeSource) (created bool, err error)
+	PatchProduct(sellerId uint64, offerId uint64, patch ProductPatch, source models.ChangeSource) (models.Product, error)
+	DeleteProduct(sellerId uint64, offerId uint64, source models.ChangeSource) error
 }
 
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrRepoFailed      = errors.New("repo err")
+
+	// в курсоре нет значения колонки сортировки или оно не разбирается
+	ErrBadCursor = errors.New("bad cursor")
+
+	// загрузка удаляет или резко переоценивает слишком большую долю каталога; UpdateResults.Guard перечисляет офферы
+	ErrGuardTripped = errors.New("guard tripped")
+)
+
 // type ManageProductsError struct {
 // 	Errors error
 // }
@@ -44,113 +70,372 @@ type RequestFilter struct {
 	SellerIDs []uint64
 	OfferIDs  []uint64
 	Substring string
+	// пустое значение равносильно SearchModeSubstring
+	SearchMode SearchMode
+
+	// границы включительно; nil - без ограничения
+	PriceMin    *uint64
+	PriceMax    *uint64
+	QuantityMin *uint64
+	QuantityMax *uint64
+	// true - quantity > 0, false - quantity = 0, nil - без фильтра
+	InStock *bool
+
+	// 0 - DefaultLimit; больше MaxLimit не отдаётся
+	Limit uint64
+	// товары после указанного (keyset пагинация); nil - с начала
+	After *Cursor
+	// пустое значение - по seller_id, offer_id
+	SortBy   SortField
+	SortDesc bool
+}
+
+type SearchMode string
+
+const (
+	// регистронезависимый поиск подстроки
+	SearchModeSubstring SearchMode = "substring"
+	// полнотекстовый поиск с русской морфологией; без явной сортировки результаты упорядочены по релевантности
+	SearchModeFulltext SearchMode = "fulltext"
+)
+
+type SortField string
+
+const (
+	SortByOfferId  SortField = "offer_id"
+	SortByName     SortField = "name"
+	SortByPrice    SortField = "price"
+	SortByQuantity SortField = "quantity"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Cursor - ключ сортировки последнего товара страницы. Следующая страница начинается сразу после него,
+// даже если сам товар уже удалён или изменён.
+type Cursor struct {
+	SellerId uint64
+	OfferId  uint64
+	// значение колонки сортировки (name, price, quantity или релевантность поиска) в текстовом виде;
+	// при сортировке только по айдишникам пусто
+	Value string
+}
+
+type ProductsPage struct {
+	Products []models.Product
+	// nil - страница последняя
+	NextCursor *Cursor
+}
+
+// ByRelevance: полнотекстовый поиск без явной сортировки упорядочен по релевантности
+func (f RequestFilter) ByRelevance() bool {
+	return f.Substring != "" && f.SearchMode == SearchModeFulltext && f.SortBy == ""
+}
+
+// AfterValue разбирает значение колонки сортировки из курсора f.After;
+// nil - курсора нет или ключ сортировки состоит только из айдишников
+func (f RequestFilter) AfterValue() (interface{}, error) {
+	if f.After == nil {
+		return nil, nil
+	}
+
+	if f.ByRelevance() {
+		rank, err := strconv.ParseFloat(f.After.Value, 32)
+		if err != nil {
+			return nil, ErrBadCursor
+		}
+
+		return float32(rank), nil
+	}
+
+	switch f.SortBy {
+	case SortByName:
+		return f.After.Value, nil
+
+	case SortByPrice, SortByQuantity:
+		value, err := strconv.ParseUint(f.After.Value, 10, 64)
+		if err != nil {
+			return nil, ErrBadCursor
+		}
+
+		return value, nil
+	}
+
+	return nil, nil
+}
+
+// ProductPatch - частичное изменение оффера; nil - поле не меняется
+type ProductPatch struct {
+	Name     *string
+	Price    *uint64
+	Quantity *uint64
+}
+
+type UpdateOptions struct {
+	// только посчитать изменения, не записывая их в каталог
+	DryRun bool
+
+	// что вызвало изменения - сохраняется в истории товаров
+	Source models.ChangeSource
+
+	// пустое значение равносильно models.SyncModeMerge
+	Mode models.SyncMode
+
+	// загрузка подтверждена продавцом и выполняется без защиты каталога
+	Confirmed bool
+}
+
+const (
+	DiffActionAdd    = "add"
+	DiffActionUpdate = "update"
+	DiffActionDelete = "delete"
+	DiffActionPurge  = "purge"
+)
+
+// ProductDiff описывает, что произойдёт с оффером при загрузке таблицы
+type ProductDiff struct {
+	OfferId uint64          `json:"offerId"`
+	Action  string          `json:"action"`
+	Old     *models.Product `json:"old,omitempty"`
+	New     *models.Product `json:"new,omitempty"`
 }
 
 type UpdateResults struct {
-	Added   uint64  `json:"added"`
-	Updated uint64  `json:"updated"`
-	Deleted uint64  `json:"deleted"`
-	Errors  []error `json:"errors"`
+	Added   uint64               `json:"added"`
+	Updated uint64               `json:"updated"`
+	Deleted uint64               `json:"deleted"`
+	Purged  uint64               `json:"purged,omitempty"`
+	Issues  []models.ImportIssue `json:"issues"`
+	DryRun  bool                 `json:"dryRun,omitempty"`
+	Diff    []ProductDiff        `json:"diff,omitempty"`
+	// офферы, из-за которых сработала защита каталога
+	Guard *GuardReport `json:"guard,omitempty"`
+	// таблица не изменилась с последней загрузки, разбор и запись пропущены
+	NoChanges bool `json:"noChanges,omitempty"`
 }
 
-func (s *Service) UpdateProducts(sellerId uint64, productUpdates []models.ProductUpdate) (UpdateResults, error) {
+// validate проверяет товар по логике домена и правилам продавца; ok - товар можно записывать.
+// stored - сохранённое состояние обновляемого товара, nil - изменение цены не проверяется.
+// Проблемам проставляется номер строки таблицы, из которой пришёл товар.
+func validate(r rules, product models.Product, stored *models.Product, rows map[uint64]uint64) (issues []models.ImportIssue, ok bool) {
+	var issue models.ImportIssue
+	if err := product.Validate(); errors.As(err, &issue) {
+		issues = append(issues, issue)
+	}
+
+	issues = append(issues, r.check(product)...)
+	if stored != nil {
+		issues = append(issues, r.checkPriceChange(product, *stored)...)
+	}
 
-	if len(productUpdates) == 0 {
-		return UpdateResults{}, errors.New("empty request")
+	for idx := range issues {
+		issues[idx].Row = rows[product.OfferId]
 	}
 
-	sellerProductIDs, err := s.repo.SellerProductIDs(sellerId)
+	return issues, !models.HasErrors(issues)
+}
+
+func (s *Service) ProductsByFilter(filter RequestFilter) (ProductsPage, error) {
+
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = DefaultLimit
+	case filter.Limit > MaxLimit:
+		filter.Limit = MaxLimit
+	}
+
+	if _, err := filter.AfterValue(); err != nil {
+		return ProductsPage{}, err
+	}
+
+	// filter.Substring = strings.TrimSpace(filter.Substring)
+	page, err := s.repo.ProductsByFilter(filter)
 	if err != nil {
 		log.Println(err)
-		return UpdateResults{}, errors.New("repo err")
+		return ProductsPage{}, errors.New("repo err")
+	}
+
+	return page, nil
+}
+
+// ProductHistory не содержит логики домена: приводит limit к диапазону и прячет ошибку репозитория
+func (s *Service) ProductHistory(sellerId uint64, offerId uint64, limit uint64) ([]models.ProductChange, error) {
+	switch {
+	case limit == 0:
+		limit = DefaultLimit
+	case limit > MaxLimit:
+		limit = MaxLimit
 	}
 
-	if !sort.SliceIsSorted(sellerProductIDs, func(i, j int) bool { return sellerProductIDs[i] < sellerProductIDs[j] }) {
-		sort.Slice(sellerProductIDs, func(i, j int) bool { return sellerProductIDs[i] < sellerProductIDs[j] })
+	changes, err := s.repo.ProductHistory(sellerId, offerId, limit)
+	if err != nil {
+		log.Println(err)
+		return nil, errors.New("repo err")
 	}
-	// validatedUpdates := make([]models.ProductUpdate, 0, len(productUpdates))
-	toAdd := make([]models.Product, 0)
-	toUpd := make([]models.Product, 0)
-	toDel := make([]models.Product, 0)
 
-	for _, upd := range productUpdates {
-		switch {
-		case !upd.Available:
-			toDel = append(toDel, upd.Product)
+	return changes, nil
+}
 
-		case contains(sellerProductIDs, upd.Product.OfferId):
-			toUpd = append(toUpd, upd.Product)
+// Product возвращает один оффер продавца
+func (s *Service) Product(sellerId uint64, offerId uint64) (models.Product, error) {
+	product, err := s.repo.Product(sellerId, offerId)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return models.Product{}, ErrProductNotFound
 
-		default:
-			toAdd = append(toAdd, upd.Product)
-		}
+	case err != nil:
+		log.Println(err)
+		return models.Product{}, ErrRepoFailed
 	}
 
-	validToAdd := make([]models.Product, 0, len(toAdd))
-	validToUpd := make([]models.Product, 0, len(toUpd))
-	validToDel := make([]models.Product, 0, len(toDel))
-	validationErrs := make([]error, 0)
+	return product, nil
+}
 
-	for _, product := range toAdd {
-		if err := product.Validate(); err != nil {
-			validationErrs = append(validationErrs, err)
-		} else {
-			validToAdd = append(validToAdd, product)
-		}
+// PutProduct создаёт или целиком заменяет оффер; невалидный товар возвращает models.ImportIssue
+func (s *Service) PutProduct(product models.Product, source models.ChangeSource) (created bool, err error) {
+	if err := product.Validate(); err != nil {
+		return false, err
 	}
-	for _, product := range toUpd {
-		if err := product.Validate(); err != nil {
-			validationErrs = append(validationErrs, err)
-		} else {
-			validToUpd = append(validToUpd, product)
-		}
+
+	sellerRules := s.rules.forSeller(product.SellerId)
+	if issues := sellerRules.check(product); models.HasErrors(issues) {
+		return false, firstError(issues)
 	}
-	validToDel = append(validToDel, toDel...) // не знаю как на тестах положительно сравнить одинаково наполненные слайсы с разной capacity
 
-	if len(validToAdd) == 0 && len(validToDel) == 0 && len(validToUpd) == 0 {
-		ur := UpdateResults{}
-		ur.Errors = append(ur.Errors, validationErrs...)
-		return ur, nil
+	issues, err := s.checkStoredPrice(sellerRules, product)
+	switch {
+	case errors.Is(err, ErrProductNotFound):
+		// новый оффер: изменение цены не ограничено
+
+	case err != nil:
+		return false, err
+
+	case models.HasErrors(issues):
+		return false, firstError(issues)
 	}
 
-	actualDeleted, err := s.repo.ManageProducts(sellerId, validToAdd, validToDel, validToUpd)
+	created, err = s.repo.PutProduct(product, source)
 	if err != nil {
 		log.Println(err)
-		return UpdateResults{}, errors.New("repo err")
+		return false, ErrRepoFailed
 	}
 
-	totalErrors := make([]error, 0, len(validationErrs))
-	totalErrors = append(totalErrors, validationErrs...)
+	return created, nil
+}
+
+// PatchProduct меняет переданные поля оффера и возвращает его новое состояние
+func (s *Service) PatchProduct(sellerId uint64, offerId uint64, patch ProductPatch, source models.ChangeSource) (models.Product, error) {
+	if patch.Name == nil && patch.Price == nil && patch.Quantity == nil {
+		return s.Product(sellerId, offerId)
+	}
+
+	// проверяются только переданные поля
+	sellerRules := s.rules.forSeller(sellerId)
+	patched := models.Product{SellerId: sellerId, OfferId: offerId}
+	var issues []models.ImportIssue
+	if patch.Name != nil {
+		patched.Name = *patch.Name
+		if err := patched.Validate(); err != nil {
+			return models.Product{}, err
+		}
+		issues = append(issues, sellerRules.checkName(patched)...)
+	}
+	if patch.Price != nil {
+		patched.Price = *patch.Price
+		issues = append(issues, sellerRules.checkPrice(patched)...)
 
-	return UpdateResults{
-		Added:   uint64(len(validToAdd)),
-		Updated: uint64(len(validToUpd)),
-		Deleted: actualDeleted,
-		Errors:  totalErrors,
-	}, nil
+		priceIssues, err := s.checkStoredPrice(sellerRules, patched)
+		if err != nil {
+			return models.Product{}, err
+		}
+		issues = append(issues, priceIssues...)
+	}
+	if patch.Quantity != nil {
+		patched.Quantity = *patch.Quantity
+		issues = append(issues, sellerRules.checkQuantity(patched)...)
+	}
+	if models.HasErrors(issues) {
+		return models.Product{}, firstError(issues)
+	}
+
+	product, err := s.repo.PatchProduct(sellerId, offerId, patch, source)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return models.Product{}, ErrProductNotFound
+
+	case err != nil:
+		log.Println(err)
+		return models.Product{}, ErrRepoFailed
+	}
+
+	return product, nil
+}
+
+// checkStoredPrice сравнивает цену оффера с сохранённой по max-price-change-percent, как при загрузке таблицы;
+// без правила сохранённый оффер не читается. Оффера нет - ErrProductNotFound.
+func (s *Service) checkStoredPrice(r rules, product models.Product) ([]models.ImportIssue, error) {
+	if r.maxPriceChangePercent <= 0 {
+		return nil, nil
+	}
+
+	stored, err := s.repo.Product(product.SellerId, product.OfferId)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return nil, ErrProductNotFound
+
+	case err != nil:
+		log.Println(err)
+		return nil, ErrRepoFailed
+	}
+
+	return r.checkPriceChange(product, stored), nil
 }
 
-func contains(slice []uint64, elem uint64) bool {
-	if len(slice) == 0 {
-		return false
+// firstError - первая ошибка среди проблем; запросы к одному офферу отвечают одной проблемой
+func firstError(issues []models.ImportIssue) models.ImportIssue {
+	for _, issue := range issues {
+		if issue.Severity == models.SeverityError {
+			return issue
+		}
 	}
 
-	i := sort.Search(len(slice), func(i int) bool { return slice[i] >= elem })
-	if i >= len(slice) || i < 0 {
-		return false
+	return models.ImportIssue{}
+}
+
+func (s *Service) DeleteProduct(sellerId uint64, offerId uint64, source models.ChangeSource) error {
+	err := s.repo.DeleteProduct(sellerId, offerId, source)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return ErrProductNotFound
+
+	case err != nil:
+		log.Println(err)
+		return ErrRepoFailed
 	}
 
-	return slice[i] == elem
+	return nil
 }
 
-func (s *Service) ProductsByFilter(filter RequestFilter) ([]models.Product, error) {
+// ExportProducts отдаёт в handle весь каталог продавца страницами по MaxLimit товаров в порядке offer_id.
+// Репозиторий читает все страницы одним снимком каталога, поэтому изменения во время выгрузки в неё не попадают.
+func (s *Service) ExportProducts(sellerId uint64, handle func(products []models.Product) error) error {
+	// ошибку handle отдаём как есть, ошибку репозитория наружу не пропускаем
+	var handleErr error
+	err := s.repo.ExportProducts(sellerId, MaxLimit, func(products []models.Product) error {
+		handleErr = handle(products)
+		return handleErr
+	})
 
-	// filter.Substring = strings.TrimSpace(filter.Substring)
-	products, err := s.repo.ProductsByFilter(filter)
-	if err != nil {
+	switch {
+	case handleErr != nil:
+		return handleErr
+
+	case err != nil:
 		log.Println(err)
-		return nil, errors.New("repo err")
+		return ErrRepoFailed
 	}
 
-	return products, nil
+	return nil
 }
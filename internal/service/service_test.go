@@ -2,525 +2,586 @@ package service
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/gojuno/minimock/v3"
+	"github.com/hablof/merchant-experience/internal/config"
 	"github.com/hablof/merchant-experience/internal/models"
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_contains(t *testing.T) {
+func TestProductsByFilter(t *testing.T) {
+	products := []models.Product{
+		{SellerId: 1, OfferId: 1, Name: "name1", Price: 1, Quantity: 1},
+		{SellerId: 1, OfferId: 2, Name: "name2", Price: 2, Quantity: 2},
+	}
+	page := ProductsPage{Products: products, NextCursor: &Cursor{SellerId: 1, OfferId: 2, Value: "2"}}
+
+	testCases := []struct {
+		name         string
+		filter       RequestFilter
+		repoBehavior func(rMock *RepositoryMock)
+		shouldReturn ProductsPage
+		returnsError error
+	}{
+		{
+			name:   "лимит по умолчанию",
+			filter: RequestFilter{SellerIDs: []uint64{1, 2}},
+			repoBehavior: func(rMock *RepositoryMock) {
+				rMock.ProductsByFilterMock.Expect(RequestFilter{SellerIDs: []uint64{1, 2}, Limit: DefaultLimit}).Return(ProductsPage{Products: products}, nil)
+			},
+			shouldReturn: ProductsPage{Products: products},
+		},
+		{
+			name:   "лимит больше максимального, курсор по цене",
+			filter: RequestFilter{Limit: MaxLimit + 1, SortBy: SortByPrice, After: &Cursor{SellerId: 1, OfferId: 1, Value: "1"}},
+			repoBehavior: func(rMock *RepositoryMock) {
+				rMock.ProductsByFilterMock.
+					Expect(RequestFilter{Limit: MaxLimit, SortBy: SortByPrice, After: &Cursor{SellerId: 1, OfferId: 1, Value: "1"}}).
+					Return(page, nil)
+			},
+			shouldReturn: page,
+		},
+		{
+			name:         "в курсоре нет цены",
+			filter:       RequestFilter{SortBy: SortByPrice, After: &Cursor{SellerId: 1, OfferId: 1}},
+			repoBehavior: func(rMock *RepositoryMock) {},
+			returnsError: ErrBadCursor,
+		},
+		{
+			name:         "в курсоре нет релевантности",
+			filter:       RequestFilter{Substring: "яблоки", SearchMode: SearchModeFulltext, After: &Cursor{SellerId: 1, OfferId: 1, Value: "яблоки"}},
+			repoBehavior: func(rMock *RepositoryMock) {},
+			returnsError: ErrBadCursor,
+		},
+		{
+			name:   "ошибка репозитория",
+			filter: RequestFilter{},
+			repoBehavior: func(rMock *RepositoryMock) {
+				rMock.ProductsByFilterMock.Expect(RequestFilter{Limit: DefaultLimit}).Return(ProductsPage{}, errors.New("some error"))
+			},
+			shouldReturn: ProductsPage{},
+			returnsError: errors.New("repo err"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rMock := NewRepositoryMock(mc)
+			tc.repoBehavior(rMock)
 
-	tests := []struct {
-		name  string
-		slice []uint64
-		elem  uint64
-		want  bool
+			s := Service{
+				repo: rMock,
+			}
+			actualResult, actualErr := s.ProductsByFilter(tc.filter)
+			assert.Equal(t, tc.returnsError, actualErr)
+			assert.Equal(t, tc.shouldReturn, actualResult)
+		})
+	}
+}
+
+func TestRequestFilter_AfterValue(t *testing.T) {
+	testCases := []struct {
+		name    string
+		filter  RequestFilter
+		want    interface{}
+		wantErr error
 	}{
 		{
-			name:  "первый элемент",
-			slice: []uint64{0, 1, 2, 3, 4, 5},
-			elem:  0,
-			want:  true,
+			name:   "без курсора",
+			filter: RequestFilter{SortBy: SortByPrice},
 		},
 		{
-			name:  "последний элемент",
-			slice: []uint64{0, 1, 2, 3, 4, 5, 6},
-			elem:  6,
-			want:  true,
+			name:   "ключ из айдишников",
+			filter: RequestFilter{SortBy: SortByOfferId, After: &Cursor{SellerId: 1, OfferId: 2, Value: "ignored"}},
 		},
 		{
-			name:  "центральный элемент",
-			slice: []uint64{0, 1, 2, 3, 4, 5, 6},
-			elem:  3,
-			want:  true,
+			name:   "название",
+			filter: RequestFilter{SortBy: SortByName, After: &Cursor{SellerId: 1, OfferId: 2, Value: "Яблоко"}},
+			want:   "Яблоко",
 		},
 		{
-			name:  "отсутствующий элемент в середине",
-			slice: []uint64{0, 1, 2, 4, 5, 6},
-			elem:  3,
-			want:  false,
+			name:   "количество",
+			filter: RequestFilter{SortBy: SortByQuantity, After: &Cursor{SellerId: 1, OfferId: 2, Value: "15"}},
+			want:   uint64(15),
 		},
 		{
-			name:  "отсутствующий элемент больше большего",
-			slice: []uint64{0, 1, 2, 3, 4, 5, 6},
-			elem:  7,
-			want:  false,
+			name:    "некорректная цена",
+			filter:  RequestFilter{SortBy: SortByPrice, After: &Cursor{SellerId: 1, OfferId: 2, Value: "-1"}},
+			wantErr: ErrBadCursor,
 		},
 		{
-			name:  "отсутствующий элемент меньше меньшего",
-			slice: []uint64{1, 2, 3, 4, 5, 6},
-			elem:  0,
-			want:  false,
+			name:   "релевантность",
+			filter: RequestFilter{Substring: "яблоки", SearchMode: SearchModeFulltext, After: &Cursor{SellerId: 1, OfferId: 2, Value: "0.0607927"}},
+			want:   float32(0.0607927),
 		},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := contains(tt.slice, tt.elem)
-			assert.Equal(t, tt.want, got)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.filter.AfterValue()
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
 		})
 	}
 }
 
-func TestUpdateProducts(t *testing.T) {
+func TestProductHistory(t *testing.T) {
+	changes := []models.ProductChange{
+		{
+			Id: 2, SellerId: 1, OfferId: 1, Action: models.ChangeUpdate, Source: models.ChangeKindImport, JobId: 7,
+			Old: &models.Product{SellerId: 1, OfferId: 1, Name: "name1", Price: 10, Quantity: 1},
+			New: &models.Product{SellerId: 1, OfferId: 1, Name: "name1", Price: 20, Quantity: 1},
+		},
+		{
+			Id: 1, SellerId: 1, OfferId: 1, Action: models.ChangeInsert, Source: models.ChangeKindImport, JobId: 6,
+			New: &models.Product{SellerId: 1, OfferId: 1, Name: "name1", Price: 10, Quantity: 1},
+		},
+	}
+
 	testCases := []struct {
-		name           string
-		sellerId       uint64
-		productUpdates []models.ProductUpdate
-
-		mSellerProductIDs_Expects    uint64
-		mSellerProductIDs_Returns    []uint64
-		mSellerProductIDs_ReturnsErr error
-		mSellerProductIDs_Behavior   func(rMock *RepositoryMock, expectedInput uint64, returns []uint64, returnsErr error)
-
-		mManageProducts_ExpectedToAdd  []models.Product
-		mManageProducts_ExpectedToUpd  []models.Product
-		mManageProducts_ExpectedToDel  []models.Product
-		mManageProducts_ReturnsDeleted uint64
-		mManageProducts_ReturnsErr     error
-		mManageProducts_Behavior       func(rMock *RepositoryMock, expSellerId uint64, expToAdd []models.Product, expToUpd []models.Product, expToDel []models.Product, returns uint64, returnsErr error)
-
-		shouldReturn UpdateResults
+		name         string
+		limit        uint64
+		repoLimit    uint64
+		repoReturns  []models.ProductChange
+		repoErr      error
+		shouldReturn []models.ProductChange
 		returnsError error
 	}{
 		{
-			name:           "пустой запрос",
-			sellerId:       0,
-			productUpdates: []models.ProductUpdate{},
+			name:         "лимит по умолчанию",
+			limit:        0,
+			repoLimit:    DefaultLimit,
+			repoReturns:  changes,
+			shouldReturn: changes,
+		},
+		{
+			name:         "лимит больше максимального",
+			limit:        MaxLimit + 1,
+			repoLimit:    MaxLimit,
+			repoReturns:  changes,
+			shouldReturn: changes,
+		},
+		{
+			name:         "ошибка репозитория",
+			limit:        1,
+			repoLimit:    1,
+			repoErr:      errors.New("some error"),
+			shouldReturn: nil,
+			returnsError: errors.New("repo err"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
 
-			mSellerProductIDs_Behavior: func(rMock *RepositoryMock, expectedInput uint64, returns []uint64, returnsErr error) {},
-			mManageProducts_Behavior: func(rMock *RepositoryMock, expSellerId uint64, expToAdd, expToUpd, expToDel []models.Product, returns uint64, returnsErr error) {
-			},
+			rMock := NewRepositoryMock(mc)
+			rMock.ProductHistoryMock.Expect(1, 1, tc.repoLimit).Return(tc.repoReturns, tc.repoErr)
 
-			returnsError: errors.New("empty request"),
-		},
-		{
-			name:     "валидный запрос: только добавление",
-			sellerId: 1,
-			productUpdates: []models.ProductUpdate{
-				{
-					Product: models.Product{
-						OfferId:  1,
-						Name:     "test1",
-						Price:    100,
-						Quantity: 5,
-					},
-					Available: true,
-				},
-				{
-					Product: models.Product{
-						OfferId:  2,
-						Name:     "test2",
-						Price:    1000,
-						Quantity: 50,
-					},
-					Available: true,
-				},
-				{
-					Product: models.Product{
-						OfferId:  3,
-						Name:     "test3",
-						Price:    10,
-						Quantity: 20,
-					},
-					Available: true,
-				},
-			},
+			s := Service{
+				repo: rMock,
+			}
+			actualResult, actualErr := s.ProductHistory(1, 1, tc.limit)
+			assert.Equal(t, tc.returnsError, actualErr)
+			assert.Equal(t, tc.shouldReturn, actualResult)
+		})
+	}
+}
 
-			mSellerProductIDs_Expects:    1,
-			mSellerProductIDs_Returns:    []uint64{},
-			mSellerProductIDs_ReturnsErr: nil,
-			mSellerProductIDs_Behavior: func(rMock *RepositoryMock, expectedInput uint64, returns []uint64, returnsErr error) {
-				rMock.SellerProductIDsMock.Expect(expectedInput).Return(returns, returnsErr)
-			},
+func TestProduct(t *testing.T) {
+	product := models.Product{SellerId: 1, OfferId: 2, Name: "name2", Price: 20, Quantity: 2}
 
-			mManageProducts_ExpectedToAdd: []models.Product{
-				{
-					OfferId:  1,
-					Name:     "test1",
-					Price:    100,
-					Quantity: 5,
-				},
-				{
-					OfferId:  2,
-					Name:     "test2",
-					Price:    1000,
-					Quantity: 50,
-				},
-				{
-					OfferId:  3,
-					Name:     "test3",
-					Price:    10,
-					Quantity: 20,
-				},
-			},
-			mManageProducts_ExpectedToUpd:  []models.Product{},
-			mManageProducts_ExpectedToDel:  []models.Product{},
-			mManageProducts_ReturnsDeleted: uint64(0),
-			mManageProducts_ReturnsErr:     nil,
-			mManageProducts_Behavior: func(rMock *RepositoryMock, expSellerId uint64, expectedToAdd, expectedToUpd, expectedToDel []models.Product, returns uint64, returnsErr error) {
-				rMock.ManageProductsMock.Expect(1, expectedToAdd, expectedToDel, expectedToUpd).Return(returns, returnsErr)
-			},
+	testCases := []struct {
+		name         string
+		repoReturns  models.Product
+		repoErr      error
+		shouldReturn models.Product
+		returnsError error
+	}{
+		{
+			name:         "оффер найден",
+			repoReturns:  product,
+			shouldReturn: product,
+		},
+		{
+			name:         "оффера нет",
+			repoErr:      models.ErrNotFound,
+			returnsError: ErrProductNotFound,
+		},
+		{
+			name:         "ошибка репозитория",
+			repoErr:      errors.New("some error"),
+			returnsError: ErrRepoFailed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
 
-			shouldReturn: UpdateResults{
-				Added:   3,
-				Updated: 0,
-				Deleted: 0,
-				Errors:  []error{},
-			},
-			returnsError: nil,
-		},
-		{
-			name:     "валидный запрос: только обновление",
-			sellerId: 1,
-			productUpdates: []models.ProductUpdate{
-				{
-					Product: models.Product{
-						OfferId:  1,
-						Name:     "test11",
-						Price:    100,
-						Quantity: 5,
-					},
-					Available: true,
-				},
-				{
-					Product: models.Product{
-						OfferId:  2,
-						Name:     "test22",
-						Price:    1000,
-						Quantity: 50,
-					},
-					Available: true,
-				},
-				{
-					Product: models.Product{
-						OfferId:  3,
-						Name:     "test33",
-						Price:    10,
-						Quantity: 20,
-					},
-					Available: true,
-				},
-			},
+			rMock := NewRepositoryMock(mc)
+			rMock.ProductMock.Expect(1, 2).Return(tc.repoReturns, tc.repoErr)
 
-			mSellerProductIDs_Expects:    1,
-			mSellerProductIDs_Returns:    []uint64{1, 2, 3},
-			mSellerProductIDs_ReturnsErr: nil,
-			mSellerProductIDs_Behavior: func(rMock *RepositoryMock, expectedInput uint64, returns []uint64, returnsErr error) {
-				rMock.SellerProductIDsMock.Expect(expectedInput).Return(returns, returnsErr)
-			},
+			s := Service{
+				repo: rMock,
+			}
+			actualResult, actualErr := s.Product(1, 2)
+			assert.Equal(t, tc.returnsError, actualErr)
+			assert.Equal(t, tc.shouldReturn, actualResult)
+		})
+	}
+}
 
-			mManageProducts_ExpectedToAdd: []models.Product{},
-			mManageProducts_ExpectedToUpd: []models.Product{
-				{
-					OfferId:  1,
-					Name:     "test11",
-					Price:    100,
-					Quantity: 5,
-				},
-				{
-					OfferId:  2,
-					Name:     "test22",
-					Price:    1000,
-					Quantity: 50,
-				},
-				{
-					OfferId:  3,
-					Name:     "test33",
-					Price:    10,
-					Quantity: 20,
-				},
+func TestPutProduct(t *testing.T) {
+	source := models.ChangeSource{Kind: models.ChangeKindAPI, RequestId: "req-1"}
+	product := models.Product{SellerId: 1, OfferId: 2, Name: "name2", Price: 20, Quantity: 2}
+
+	testCases := []struct {
+		name         string
+		product      models.Product
+		rules        config.Validation
+		repoBehavior func(m *RepositoryMock)
+		shouldReturn bool
+		returnsError error
+	}{
+		{
+			name:    "новый оффер",
+			product: product,
+			repoBehavior: func(m *RepositoryMock) {
+				m.PutProductMock.Expect(product, source).Return(true, nil)
 			},
-			mManageProducts_ExpectedToDel:  []models.Product{},
-			mManageProducts_ReturnsDeleted: uint64(0),
-			mManageProducts_ReturnsErr:     nil,
-
-			shouldReturn: UpdateResults{
-				Added:   0,
-				Updated: 3,
-				Deleted: 0,
-				Errors:  []error{},
+			shouldReturn: true,
+		},
+		{
+			name:    "замена существующего",
+			product: product,
+			repoBehavior: func(m *RepositoryMock) {
+				m.PutProductMock.Expect(product, source).Return(false, nil)
 			},
-			returnsError: nil,
-			mManageProducts_Behavior: func(rMock *RepositoryMock, expSellerId uint64, expToAdd, expToUpd, expToDel []models.Product, returns uint64, returnsErr error) {
-				rMock.ManageProductsMock.Expect(expSellerId, expToAdd, expToDel, expToUpd).Return(returns, returnsErr)
+			shouldReturn: false,
+		},
+		{
+			name:         "невалидное название",
+			product:      models.Product{SellerId: 1, OfferId: 2, Name: strings.Repeat("a", 101)},
+			repoBehavior: func(m *RepositoryMock) {},
+			returnsError: models.ImportIssue{OfferId: 2, Field: "name", Code: models.CodeTooLongName, Severity: models.SeverityError, Message: models.MsgTooLongName},
+		},
+		{
+			name:         "нарушено правило продавца",
+			product:      product,
+			rules:        config.Validation{Sellers: map[uint64]config.Rules{1: {MaxQuantity: 1}}},
+			repoBehavior: func(m *RepositoryMock) {},
+			returnsError: models.ImportIssue{OfferId: 2, Field: "quantity", Code: models.CodeQuantityTooHigh, Severity: models.SeverityError, Message: "quantity 2 is greater than 1"},
+		},
+		{
+			name:    "цена меняется сильнее max-price-change-percent",
+			product: product,
+			rules:   config.Validation{Default: config.Rules{MaxPriceChangePercent: 50}},
+			repoBehavior: func(m *RepositoryMock) {
+				m.ProductMock.Expect(1, 2).Return(models.Product{SellerId: 1, OfferId: 2, Name: "name2", Price: 100, Quantity: 2}, nil)
 			},
+			returnsError: models.ImportIssue{OfferId: 2, Field: "price", Code: models.CodePriceChangeTooHigh, Severity: models.SeverityError, Message: "price changes from 100 to 20 (80.0%), more than 50%"},
 		},
 		{
-			name:     "валидный запрос: только удаление",
-			sellerId: 1,
-			productUpdates: []models.ProductUpdate{
-				{
-					Product: models.Product{
-						OfferId:  1,
-						Name:     "test11",
-						Price:    100,
-						Quantity: 5,
-					},
-					Available: false,
-				},
-				{
-					Product: models.Product{
-						OfferId:  2,
-						Name:     "test22",
-						Price:    1000,
-						Quantity: 50,
-					},
-					Available: false,
-				},
-				{
-					Product: models.Product{
-						OfferId:  3,
-						Name:     "test33",
-						Price:    10,
-						Quantity: 20,
-					},
-					Available: false,
-				},
+			name:    "цена в пределах max-price-change-percent",
+			product: product,
+			rules:   config.Validation{Default: config.Rules{MaxPriceChangePercent: 50}},
+			repoBehavior: func(m *RepositoryMock) {
+				m.ProductMock.Expect(1, 2).Return(models.Product{SellerId: 1, OfferId: 2, Name: "name2", Price: 30, Quantity: 2}, nil)
+				m.PutProductMock.Expect(product, source).Return(false, nil)
 			},
-
-			mSellerProductIDs_Returns:    []uint64{1, 2, 3},
-			mSellerProductIDs_ReturnsErr: nil,
-			mSellerProductIDs_Expects:    1,
-			mSellerProductIDs_Behavior: func(rMock *RepositoryMock, expectedInput uint64, returns []uint64, returnsErr error) {
-				rMock.SellerProductIDsMock.Expect(expectedInput).Return(returns, returnsErr)
+			shouldReturn: false,
+		},
+		{
+			name:    "новый оффер не проверяется по max-price-change-percent",
+			product: product,
+			rules:   config.Validation{Default: config.Rules{MaxPriceChangePercent: 50}},
+			repoBehavior: func(m *RepositoryMock) {
+				m.ProductMock.Expect(1, 2).Return(models.Product{}, models.ErrNotFound)
+				m.PutProductMock.Expect(product, source).Return(true, nil)
 			},
-
-			mManageProducts_ExpectedToAdd: []models.Product{},
-			mManageProducts_ExpectedToUpd: []models.Product{},
-			mManageProducts_ExpectedToDel: []models.Product{
-				{
-					OfferId:  1,
-					Name:     "test11",
-					Price:    100,
-					Quantity: 5,
-				},
-				{
-					OfferId:  2,
-					Name:     "test22",
-					Price:    1000,
-					Quantity: 50,
-				},
-				{
-					OfferId:  3,
-					Name:     "test33",
-					Price:    10,
-					Quantity: 20,
-				},
+			shouldReturn: true,
+		},
+		{
+			name:    "ошибка чтения сохранённого оффера",
+			product: product,
+			rules:   config.Validation{Default: config.Rules{MaxPriceChangePercent: 50}},
+			repoBehavior: func(m *RepositoryMock) {
+				m.ProductMock.Expect(1, 2).Return(models.Product{}, errors.New("some error"))
 			},
-			mManageProducts_ReturnsDeleted: uint64(3),
-			mManageProducts_ReturnsErr:     nil,
-			mManageProducts_Behavior: func(rMock *RepositoryMock, expSellerId uint64, expToAdd, expToUpd, expToDel []models.Product, returns uint64, returnsErr error) {
-				rMock.ManageProductsMock.Expect(expSellerId, expToAdd, expToDel, expToUpd).Return(returns, returnsErr)
+			returnsError: ErrRepoFailed,
+		},
+		{
+			name:    "ошибка репозитория",
+			product: product,
+			repoBehavior: func(m *RepositoryMock) {
+				m.PutProductMock.Expect(product, source).Return(false, errors.New("some error"))
 			},
+			returnsError: ErrRepoFailed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
 
-			shouldReturn: UpdateResults{
-				Added:   0,
-				Updated: 0,
-				Deleted: 3,
-				Errors:  []error{},
-			},
-			returnsError: nil,
-		},
-		{
-			name:     "валидный запрос: два добавления, два удаления, два обновления",
-			sellerId: 42,
-			productUpdates: []models.ProductUpdate{
-				{
-					Product: models.Product{
-						OfferId:  1,
-						Name:     "первый кандидат на удаление",
-						Price:    15,
-						Quantity: 15,
-					},
-					Available: false,
-				},
-				{
-					Product: models.Product{
-						OfferId:  2,
-						Name:     "первый кандидат на добавление",
-						Price:    125,
-						Quantity: 10,
-					},
-					Available: true,
-				},
-				{
-					Product: models.Product{
-						OfferId:  3,
-						Name:     "первый кандидат на обновление",
-						Price:    4990,
-						Quantity: 1,
-					},
-					Available: true,
-				},
-				{
-					Product: models.Product{
-						OfferId:  4,
-						Name:     "второй кандидат на удаление",
-						Price:    200,
-						Quantity: 5,
-					},
-					Available: false,
-				},
-				{
-					Product: models.Product{
-						OfferId:  5,
-						Name:     "второй кандидат на добавление",
-						Price:    125,
-						Quantity: 10,
-					},
-					Available: true,
-				},
-				{
-					Product: models.Product{
-						OfferId:  6,
-						Name:     "второй кандидат на обновление",
-						Price:    15000,
-						Quantity: 60,
-					},
-					Available: true,
-				},
-			},
+			rMock := NewRepositoryMock(mc)
+			tc.repoBehavior(rMock)
 
-			mSellerProductIDs_Expects:    42,
-			mSellerProductIDs_Returns:    []uint64{1, 3, 4, 6},
-			mSellerProductIDs_ReturnsErr: nil,
-			mSellerProductIDs_Behavior: func(rMock *RepositoryMock, expectedInput uint64, returns []uint64, returnsErr error) {
-				rMock.SellerProductIDsMock.Expect(expectedInput).Return(returns, returnsErr)
-			},
+			s := Service{
+				repo:  rMock,
+				rules: newRuleSets(tc.rules),
+			}
+			actualResult, actualErr := s.PutProduct(tc.product, source)
+			assert.Equal(t, tc.returnsError, actualErr)
+			assert.Equal(t, tc.shouldReturn, actualResult)
+		})
+	}
+}
 
-			mManageProducts_ExpectedToAdd: []models.Product{
-				{
-					OfferId:  2,
-					Name:     "первый кандидат на добавление",
-					Price:    125,
-					Quantity: 10,
-				},
-				{
-					OfferId:  5,
-					Name:     "второй кандидат на добавление",
-					Price:    125,
-					Quantity: 10,
-				},
+func TestPatchProduct(t *testing.T) {
+	source := models.ChangeSource{Kind: models.ChangeKindAPI, RequestId: "req-1"}
+	product := models.Product{SellerId: 1, OfferId: 2, Name: "name2", Price: 25, Quantity: 2}
+	price := uint64(25)
+	quantity := uint64(2)
+	longName := strings.Repeat("a", 101)
+
+	testCases := []struct {
+		name         string
+		patch        ProductPatch
+		rules        config.Validation
+		repoBehavior func(m *RepositoryMock)
+		shouldReturn models.Product
+		returnsError error
+	}{
+		{
+			name:  "меняем цену",
+			patch: ProductPatch{Price: &price},
+			repoBehavior: func(m *RepositoryMock) {
+				m.PatchProductMock.Expect(1, 2, ProductPatch{Price: &price}, source).Return(product, nil)
 			},
-			mManageProducts_ExpectedToUpd: []models.Product{
-				{
-					OfferId:  3,
-					Name:     "первый кандидат на обновление",
-					Price:    4990,
-					Quantity: 1,
-				},
-				{
-					OfferId:  6,
-					Name:     "второй кандидат на обновление",
-					Price:    15000,
-					Quantity: 60,
-				},
+			shouldReturn: product,
+		},
+		{
+			name:  "пустой patch возвращает оффер без изменений",
+			patch: ProductPatch{},
+			repoBehavior: func(m *RepositoryMock) {
+				m.ProductMock.Expect(1, 2).Return(product, nil)
 			},
-			mManageProducts_ExpectedToDel: []models.Product{
-				{
-					OfferId:  1,
-					Name:     "первый кандидат на удаление",
-					Price:    15,
-					Quantity: 15,
-				},
-				{
-					OfferId:  4,
-					Name:     "второй кандидат на удаление",
-					Price:    200,
-					Quantity: 5,
-				},
+			shouldReturn: product,
+		},
+		{
+			name:         "невалидное название",
+			patch:        ProductPatch{Name: &longName},
+			repoBehavior: func(m *RepositoryMock) {},
+			returnsError: models.ImportIssue{OfferId: 2, Field: "name", Code: models.CodeTooLongName, Severity: models.SeverityError, Message: models.MsgTooLongName},
+		},
+		{
+			name:         "цена нарушает общее правило",
+			patch:        ProductPatch{Price: &price},
+			rules:        config.Validation{Default: config.Rules{MaxPrice: 20}},
+			repoBehavior: func(m *RepositoryMock) {},
+			returnsError: models.ImportIssue{OfferId: 2, Field: "price", Code: models.CodePriceTooHigh, Severity: models.SeverityError, Message: "price 25 is greater than 20"},
+		},
+		{
+			name:  "правило для непереданного поля не проверяется",
+			patch: ProductPatch{Price: &price},
+			rules: config.Validation{Default: config.Rules{RequireName: true}},
+			repoBehavior: func(m *RepositoryMock) {
+				m.PatchProductMock.Expect(1, 2, ProductPatch{Price: &price}, source).Return(product, nil)
 			},
-			mManageProducts_ReturnsDeleted: uint64(2),
-			mManageProducts_ReturnsErr:     nil,
-			mManageProducts_Behavior: func(rMock *RepositoryMock, expSellerId uint64, expToAdd []models.Product, expToUpd []models.Product, expToDel []models.Product, returns uint64, returnsErr error) {
-				rMock.ManageProductsMock.Expect(expSellerId, expToAdd, expToDel, expToUpd).Return(returns, returnsErr)
+			shouldReturn: product,
+		},
+		{
+			name:  "цена меняется сильнее max-price-change-percent",
+			patch: ProductPatch{Price: &price},
+			rules: config.Validation{Sellers: map[uint64]config.Rules{1: {MaxPriceChangePercent: 10}}},
+			repoBehavior: func(m *RepositoryMock) {
+				m.ProductMock.Expect(1, 2).Return(models.Product{SellerId: 1, OfferId: 2, Name: "name2", Price: 20, Quantity: 2}, nil)
 			},
-			shouldReturn: UpdateResults{
-				Added:   2,
-				Updated: 2,
-				Deleted: 2,
-				Errors:  []error{},
+			returnsError: models.ImportIssue{OfferId: 2, Field: "price", Code: models.CodePriceChangeTooHigh, Severity: models.SeverityError, Message: "price changes from 20 to 25 (25.0%), more than 10%"},
+		},
+		{
+			name:  "цена в пределах max-price-change-percent",
+			patch: ProductPatch{Price: &price},
+			rules: config.Validation{Sellers: map[uint64]config.Rules{1: {MaxPriceChangePercent: 30}}},
+			repoBehavior: func(m *RepositoryMock) {
+				m.ProductMock.Expect(1, 2).Return(models.Product{SellerId: 1, OfferId: 2, Name: "name2", Price: 20, Quantity: 2}, nil)
+				m.PatchProductMock.Expect(1, 2, ProductPatch{Price: &price}, source).Return(product, nil)
 			},
-			returnsError: nil,
-		},
-		{
-			name:     "добавляем и обновляем только длинные (невалидные) названия",
-			sellerId: 70,
-			productUpdates: []models.ProductUpdate{
-				{
-					Product: models.Product{
-						OfferId:  15,
-						Name:     "ну очень очень очень очень очень очень очень очень очень очень очень очень очень очень очень длинное название",
-						Price:    0,
-						Quantity: 0,
-					},
-					Available: true,
-				},
-				{
-					Product: models.Product{
-						OfferId:  16,
-						Name:     "ну очень очень очень очень очень очень очень очень очень очень очень очень очень очень очень длинное название",
-						Price:    0,
-						Quantity: 0,
-					},
-					Available: true,
-				},
+			shouldReturn: product,
+		},
+		{
+			name:  "patch без цены не читает сохранённый оффер",
+			patch: ProductPatch{Quantity: &quantity},
+			rules: config.Validation{Default: config.Rules{MaxPriceChangePercent: 10}},
+			repoBehavior: func(m *RepositoryMock) {
+				m.PatchProductMock.Expect(1, 2, ProductPatch{Quantity: &quantity}, source).Return(product, nil)
 			},
-			mSellerProductIDs_Expects:    70,
-			mSellerProductIDs_Returns:    []uint64{15},
-			mSellerProductIDs_ReturnsErr: nil,
-			mSellerProductIDs_Behavior: func(rMock *RepositoryMock, expectedInput uint64, returns []uint64, returnsErr error) {
-				rMock.SellerProductIDsMock.Expect(expectedInput).Return(returns, returnsErr)
+			shouldReturn: product,
+		},
+		{
+			name:  "оффера нет при проверке изменения цены",
+			patch: ProductPatch{Price: &price},
+			rules: config.Validation{Default: config.Rules{MaxPriceChangePercent: 10}},
+			repoBehavior: func(m *RepositoryMock) {
+				m.ProductMock.Expect(1, 2).Return(models.Product{}, models.ErrNotFound)
 			},
-			mManageProducts_ExpectedToAdd:  []models.Product{},
-			mManageProducts_ExpectedToUpd:  []models.Product{},
-			mManageProducts_ExpectedToDel:  []models.Product{},
-			mManageProducts_ReturnsDeleted: uint64(0),
-			mManageProducts_ReturnsErr:     nil,
-			mManageProducts_Behavior: func(rMock *RepositoryMock, expSellerId uint64, expToAdd []models.Product, expToUpd []models.Product, expToDel []models.Product, returns uint64, returnsErr error) {
+			returnsError: ErrProductNotFound,
+		},
+		{
+			name:  "оффера нет",
+			patch: ProductPatch{Price: &price},
+			repoBehavior: func(m *RepositoryMock) {
+				m.PatchProductMock.Expect(1, 2, ProductPatch{Price: &price}, source).Return(models.Product{}, models.ErrNotFound)
 			},
-			shouldReturn: UpdateResults{
-				Added:   0,
-				Updated: 0,
-				Deleted: 0,
-				Errors: []error{
-					models.ErrProductValidation{
-						OfferId: 16,
-						Field:   "name",
-						ErrMsg:  models.MsgTooLongName,
-					},
-					models.ErrProductValidation{
-						OfferId: 15,
-						Field:   "name",
-						ErrMsg:  models.MsgTooLongName,
-					},
-				},
+			returnsError: ErrProductNotFound,
+		},
+		{
+			name:  "ошибка репозитория",
+			patch: ProductPatch{Price: &price},
+			repoBehavior: func(m *RepositoryMock) {
+				m.PatchProductMock.Expect(1, 2, ProductPatch{Price: &price}, source).Return(models.Product{}, errors.New("some error"))
 			},
-			returnsError: nil,
+			returnsError: ErrRepoFailed,
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mc := minimock.NewController(t)
+			defer mc.Finish()
+
 			rMock := NewRepositoryMock(mc)
-			tc.mSellerProductIDs_Behavior(rMock, tc.mSellerProductIDs_Expects, tc.mSellerProductIDs_Returns, tc.mSellerProductIDs_ReturnsErr)
-			tc.mManageProducts_Behavior(rMock, tc.sellerId, tc.mManageProducts_ExpectedToAdd, tc.mManageProducts_ExpectedToUpd, tc.mManageProducts_ExpectedToDel, tc.mManageProducts_ReturnsDeleted, tc.mManageProducts_ReturnsErr)
+			tc.repoBehavior(rMock)
+
+			s := Service{
+				repo:  rMock,
+				rules: newRuleSets(tc.rules),
+			}
+			actualResult, actualErr := s.PatchProduct(1, 2, tc.patch, source)
+			assert.Equal(t, tc.returnsError, actualErr)
+			assert.Equal(t, tc.shouldReturn, actualResult)
+		})
+	}
+}
+
+func TestDeleteProduct(t *testing.T) {
+	source := models.ChangeSource{Kind: models.ChangeKindAPI, RequestId: "req-1"}
+
+	testCases := []struct {
+		name         string
+		repoErr      error
+		returnsError error
+	}{
+		{
+			name: "оффер удалён",
+		},
+		{
+			name:         "оффера нет",
+			repoErr:      models.ErrNotFound,
+			returnsError: ErrProductNotFound,
+		},
+		{
+			name:         "ошибка репозитория",
+			repoErr:      errors.New("some error"),
+			returnsError: ErrRepoFailed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			rMock := NewRepositoryMock(mc)
+			rMock.DeleteProductMock.Expect(1, 2, source).Return(tc.repoErr)
+
 			s := Service{
 				repo: rMock,
 			}
-			actualResult, actualErr := s.UpdateProducts(tc.sellerId, tc.productUpdates)
-			assert.Equal(t, tc.shouldReturn.Added, actualResult.Added, "")
-			assert.Equal(t, tc.shouldReturn.Deleted, actualResult.Deleted, "")
-			assert.Equal(t, tc.shouldReturn.Updated, actualResult.Updated, "")
-			if assert.Equal(t, len(actualResult.Errors), len(tc.shouldReturn.Errors)) {
-				for i, elem := range actualResult.Errors {
-					assert.Equal(t, tc.shouldReturn.Errors[i], elem)
+			assert.Equal(t, tc.returnsError, s.DeleteProduct(1, 2, source))
+		})
+	}
+}
+
+func TestExportProducts(t *testing.T) {
+	page1 := []models.Product{{SellerId: 42, OfferId: 1}, {SellerId: 42, OfferId: 2}}
+	page2 := []models.Product{{SellerId: 42, OfferId: 3}}
+
+	// exportPages - репозиторий, отдающий страницы одну за другой
+	exportPages := func(pages ...[]models.Product) func(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) error {
+		return func(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) error {
+			for _, page := range pages {
+				if err := handle(page); err != nil {
+					return err
 				}
 			}
 
-			// assert.ElementsMatch(t, tc.mManageProducts_Returns.Errors, actualResult.Errors)
-			assert.Equal(t, actualErr, tc.returnsError)
-		})
+			return nil
+		}
 	}
+
+	t.Run("каталог выгружается страницами", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		rMock := NewRepositoryMock(mc)
+		rMock.ExportProductsMock.Inspect(func(sellerId uint64, pageSize uint64, handle func(products []models.Product) error) {
+			assert.Equal(t, uint64(42), sellerId)
+			assert.Equal(t, uint64(MaxLimit), pageSize)
+		}).Set(exportPages(page1, page2))
+
+		s := Service{
+			repo: rMock,
+		}
+
+		var got []models.Product
+		err := s.ExportProducts(42, func(products []models.Product) error {
+			got = append(got, products...)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, append(page1[:2:2], page2...), got)
+	})
+
+	t.Run("ошибка handle возвращается как есть", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		rMock := NewRepositoryMock(mc)
+		rMock.ExportProductsMock.Set(exportPages(page1, page2))
+
+		s := Service{
+			repo: rMock,
+		}
+
+		handleErr := errors.New("write failed")
+		calls := 0
+		err := s.ExportProducts(42, func(products []models.Product) error {
+			calls++
+			return handleErr
+		})
+		assert.Equal(t, handleErr, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("ошибка репозитория", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		rMock := NewRepositoryMock(mc)
+		rMock.ExportProductsMock.Return(errors.New("some error"))
+
+		s := Service{
+			repo: rMock,
+		}
+
+		err := s.ExportProducts(42, func(products []models.Product) error {
+			t.Fatal("handle must not be called")
+			return nil
+		})
+		assert.Equal(t, ErrRepoFailed, err)
+	})
 }
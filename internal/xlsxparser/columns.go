@@ -0,0 +1,127 @@
+package xlsxparser
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hablof/merchant-experience/internal/config"
+)
+
+// канонические имена колонок; они же используются в поле Field ошибок разбора
+const (
+	colOfferId   = "offer_id"
+	colName      = "name"
+	colPrice     = "price"
+	colQuantity  = "quantity"
+	colAvailable = "available"
+)
+
+// порядок колонок в таблице без заголовка
+var defaultColumnOrder = []string{colOfferId, colName, colPrice, colQuantity, colAvailable}
+
+// available необязательна: без неё все офферы считаются доступными
+var requiredColumns = []string{colOfferId, colName, colPrice, colQuantity}
+
+type ErrMissingColumns struct {
+	Columns []string
+}
+
+func (e ErrMissingColumns) Error() string {
+	return fmt.Sprintf("missing required column(s): %s", strings.Join(e.Columns, ", "))
+}
+
+// layout - номера колонок по каноническим именам
+type layout struct {
+	columns map[string]int
+	// первая непустая строка - заголовок
+	hasHeader bool
+}
+
+// cell безопасно достаёт значение колонки: excelize обрезает пустые ячейки в конце строки
+func (l layout) cell(row []string, column string) (string, bool) {
+	idx, ok := l.columns[column]
+	if !ok {
+		return "", false
+	}
+
+	if idx >= len(row) {
+		return "", true
+	}
+
+	return row[idx], true
+}
+
+// columnAliases строит словарь "нормализованное название -> каноническое имя колонки".
+// Канонические имена распознаются всегда, синонимы берутся из конфига.
+func columnAliases(cfg config.Config) map[string]string {
+	aliases := make(map[string]string, len(defaultColumnOrder))
+	for _, column := range defaultColumnOrder {
+		aliases[column] = column
+	}
+
+	for column, columnAliases := range cfg.Parser.Aliases {
+		if !isKnownColumn(column) {
+			log.Printf("unknown column %q in parser aliases", column)
+			continue
+		}
+
+		for _, alias := range columnAliases {
+			aliases[normalizeHeader(alias)] = column
+		}
+	}
+
+	return aliases
+}
+
+func isKnownColumn(column string) bool {
+	for _, known := range defaultColumnOrder {
+		if column == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeHeader(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// detectLayout считает первую строку заголовком, если в ней есть хотя бы одно известное название колонки.
+// Лишние колонки игнорируются, порядок колонок не важен. Без заголовка используется defaultColumnOrder.
+func detectLayout(firstRow []string, aliases map[string]string) (layout, error) {
+	columns := make(map[string]int, len(defaultColumnOrder))
+	for idx, cell := range firstRow {
+		column, ok := aliases[normalizeHeader(cell)]
+		if !ok {
+			continue
+		}
+
+		// при повторе названия берём первую колонку
+		if _, ok := columns[column]; !ok {
+			columns[column] = idx
+		}
+	}
+
+	if len(columns) == 0 {
+		for idx, column := range defaultColumnOrder {
+			columns[column] = idx
+		}
+
+		return layout{columns: columns, hasHeader: false}, nil
+	}
+
+	missing := make([]string, 0)
+	for _, column := range requiredColumns {
+		if _, ok := columns[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+
+	if len(missing) > 0 {
+		return layout{}, ErrMissingColumns{Columns: missing}
+	}
+
+	return layout{columns: columns, hasHeader: true}, nil
+}
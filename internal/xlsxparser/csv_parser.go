@@ -0,0 +1,159 @@
+package xlsxparser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// по началу файла определяются кодировка и разделитель
+const sniffSize = 64 << 10
+
+// CSVParser разбирает CSV/TSV выгрузки (1С, Google Sheets).
+// Разделитель (запятая, точка с запятой, табуляция) и кодировка (UTF-8, UTF-8 BOM, Windows-1251) определяются автоматически.
+type CSVParser struct {
+	aliases map[string]string
+}
+
+func NewCSVParser(cfg config.Config) CSVParser {
+	return CSVParser{
+		aliases: columnAliases(cfg),
+	}
+}
+
+// StreamProducts читает файл дважды (см. streamRows), поэтому r должен поддерживать Seek.
+// Листов в CSV нет: с непустым sheet возвращает ErrSheetNotFound.
+func (p CSVParser) StreamProducts(r io.ReadSeeker, sheet string, batchSize int, handle BatchHandler) error {
+	if sheet != "" {
+		return ErrSheetNotFound
+	}
+
+	return streamRows(csvOpener(r), p.aliases, batchSize, handle)
+}
+
+// SheetNames: листов в CSV нет
+func (p CSVParser) SheetNames(r io.ReadSeeker) ([]string, error) {
+	return nil, ErrSheetNotFound
+}
+
+func csvOpener(r io.ReadSeeker) func() (rowIterator, error) {
+	return func() (rowIterator, error) {
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		br := bufio.NewReaderSize(r, sniffSize)
+		sample, err := br.Peek(sniffSize)
+		truncated := err == nil
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		if bytes.HasPrefix(sample, utf8BOM) {
+			if _, err := br.Discard(len(utf8BOM)); err != nil {
+				return nil, err
+			}
+			sample = sample[len(utf8BOM):]
+		}
+
+		var src io.Reader = br
+		if !validUTF8(sample, truncated) {
+			src = transform.NewReader(br, charmap.Windows1251.NewDecoder())
+
+			sample, err = charmap.Windows1251.NewDecoder().Bytes(sample)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cr := csv.NewReader(src)
+		cr.Comma = detectDelimiter(sample)
+		cr.FieldsPerRecord = -1 // лишние и недостающие ячейки обрабатывает layout
+		cr.LazyQuotes = true
+
+		return &csvRows{r: cr}, nil
+	}
+}
+
+// validUTF8 проверяет начало файла; если оно обрезано, последний символ может быть неполным
+func validUTF8(sample []byte, truncated bool) bool {
+	if utf8.Valid(sample) {
+		return true
+	}
+
+	if !truncated {
+		return false
+	}
+
+	for cut := 1; cut < utf8.UTFMax && cut < len(sample); cut++ {
+		if utf8.Valid(sample[:len(sample)-cut]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// detectDelimiter выбирает самый частый из возможных разделителей в первой строке
+func detectDelimiter(b []byte) rune {
+	firstLine := b
+	if idx := bytes.IndexByte(b, '\n'); idx >= 0 {
+		firstLine = b[:idx]
+	}
+
+	delimiter, maxCount := ',', 0
+	for _, candidate := range []rune{',', ';', '\t'} {
+		count := bytes.Count(firstLine, []byte(string(candidate)))
+		if count > maxCount {
+			delimiter, maxCount = candidate, count
+		}
+	}
+
+	return delimiter
+}
+
+// csvRows приводит csv.Reader к rowIterator
+type csvRows struct {
+	r         *csv.Reader
+	row       []string
+	rowNumber uint64
+	err       error
+}
+
+func (c *csvRows) Next() bool {
+	row, err := c.r.Read()
+	if errors.Is(err, io.EOF) {
+		return false
+	}
+	if err != nil {
+		c.err = err
+		return false
+	}
+
+	// csv.Reader пропускает пустые строки, поэтому номер берём у самого ридера
+	line, _ := c.r.FieldPos(0)
+	c.row, c.rowNumber = row, uint64(line)
+
+	return true
+}
+
+func (c *csvRows) Row() ([]string, uint64, error) {
+	return c.row, c.rowNumber, nil
+}
+
+func (c *csvRows) Err() error {
+	return c.err
+}
+
+func (c *csvRows) Close() error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package xlsxparser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVparser(t *testing.T) {
+	cfg := config.Config{
+		Parser: config.Parser{
+			Aliases: map[string][]string{
+				"offer_id": {"артикул"},
+				"name":     {"наименование"},
+				"price":    {"цена"},
+				"quantity": {"количество"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testname   string
+		fileName   string
+		want       []models.ProductUpdate
+		wantIssues []models.ImportIssue
+		wantErr    error
+	}{
+		{
+			testname: "utf-8, запятая, заголовок, ошибка в строке",
+			fileName: "example.csv",
+			want: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true, Row: 2},
+				{Product: models.Product{OfferId: 2, Name: "body, big", Price: 20, Quantity: 0}, Available: true, Row: 3},
+				{Product: models.Product{OfferId: 4, Name: "arm", Price: 40, Quantity: 4}, Available: false, Row: 5},
+			},
+			wantIssues: []models.ImportIssue{
+				{
+					Row:      4,
+					OfferId:  3,
+					Field:    "price",
+					Code:     models.CodeInvalidNumber,
+					Severity: models.SeverityError,
+					Message:  `must be a non-negative integer, got "-5"`,
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			testname: "windows-1251, точка с запятой, синонимы",
+			fileName: "example_cp1251.csv",
+			want: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "Колесо", Price: 100, Quantity: 5}, Available: true, Row: 2},
+				{Product: models.Product{OfferId: 2, Name: "Ветка", Price: 200, Quantity: 0}, Available: true, Row: 3},
+			},
+			wantIssues: nil,
+			wantErr:    nil,
+		},
+		{
+			testname: "utf-8 BOM, табуляция, без заголовка",
+			fileName: "example_bom.tsv",
+			want: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "Кросовок", Price: 10, Quantity: 1}, Available: true, Row: 1},
+				{Product: models.Product{OfferId: 2, Name: "big melon", Price: 2, Quantity: 2}, Available: false, Row: 2},
+			},
+			wantIssues: nil,
+			wantErr:    nil,
+		},
+		{
+			testname:   "duplicates",
+			fileName:   "example_duplicates.csv",
+			want:       nil,
+			wantIssues: nil,
+			wantErr:    ErrHasDuplicates,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.testname, func(t *testing.T) {
+			filename := filepath.Join("test", tt.fileName)
+			f, err := os.Open(filename)
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			p := NewCSVParser(cfg)
+
+			parsedProducts, parseErrs, err := streamAll(p, f)
+			assert.Equal(t, tt.wantErr, err, "method errors")
+			assert.Equal(t, tt.wantIssues, parseErrs, "parse errors")
+			assert.Equal(t, tt.want, parsedProducts, "parsed products")
+		})
+	}
+}
+
+func TestCSVparser_Empty(t *testing.T) {
+	p := NewCSVParser(config.Config{})
+
+	parsedProducts, parseErrs, err := streamAll(p, bytes.NewReader([]byte(" \r\n")))
+	assert.Equal(t, ErrEmptySheet, err)
+	assert.Nil(t, parseErrs)
+	assert.Nil(t, parsedProducts)
+}
+
+func TestCSVparser_Sheet(t *testing.T) {
+	p := NewCSVParser(config.Config{})
+
+	called := false
+	err := p.StreamProducts(bytes.NewReader([]byte("1,head,10,1\n")), "Лист1", 10, func(productUpdates []models.ProductUpdate, issues []models.ImportIssue, last bool) error {
+		called = true
+		return nil
+	})
+	assert.Equal(t, ErrSheetNotFound, err)
+	assert.False(t, called, "в CSV листов нет")
+}
+
+func TestCSVparser_SheetNames(t *testing.T) {
+	names, err := NewCSVParser(config.Config{}).SheetNames(bytes.NewReader([]byte("1,head,10,1\n")))
+	assert.Equal(t, ErrSheetNotFound, err)
+	assert.Nil(t, names)
+}
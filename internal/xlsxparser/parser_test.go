@@ -1,113 +1,138 @@
 package xlsxparser
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strconv"
 	"testing"
 
+	"github.com/hablof/merchant-experience/internal/config"
 	"github.com/hablof/merchant-experience/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
 )
 
+// streamParser - общий интерфейс xlsx и csv парсеров для тестов
+type streamParser interface {
+	StreamProducts(r io.ReadSeeker, sheet string, batchSize int, handle BatchHandler) error
+}
+
+// streamAll собирает все пачки первого листа, как их получает importer
+func streamAll(p streamParser, r io.ReadSeeker) ([]models.ProductUpdate, []models.ImportIssue, error) {
+	productUpdates := make([]models.ProductUpdate, 0)
+	var issues []models.ImportIssue
+	err := p.StreamProducts(r, "", 1024, func(batch []models.ProductUpdate, batchIssues []models.ImportIssue, last bool) error {
+		productUpdates = append(productUpdates, batch...)
+		issues = append(issues, batchIssues...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return productUpdates, issues, nil
+}
+
 func TestXLSXparser(t *testing.T) {
 	testCases := []struct {
-		testname        string
-		fileName        string
-		want            []models.ProductUpdate
-		wantProductErrs []error
-		wantErr         error
+		testname   string
+		fileName   string
+		want       []models.ProductUpdate
+		wantIssues []models.ImportIssue
+		wantErr    error
 	}{
 		{
-			testname:        "empty file",
-			fileName:        "example_empty.xlsx",
-			want:            nil,
-			wantProductErrs: nil,
-			wantErr:         ErrEmptySheet,
+			testname:   "empty file",
+			fileName:   "example_empty.xlsx",
+			want:       nil,
+			wantIssues: nil,
+			wantErr:    ErrEmptySheet,
 		},
 		{
-			testname:        "duplicates",
-			fileName:        "example_duplicates.xlsx",
-			want:            nil,
-			wantProductErrs: nil,
-			wantErr:         ErrHasDuplicates,
+			testname:   "duplicates",
+			fileName:   "example_duplicates.xlsx",
+			want:       nil,
+			wantIssues: nil,
+			wantErr:    ErrHasDuplicates,
 		},
 		{
-			testname:        "",
-			fileName:        "example_with_invalid_offer_id_col.xlsx",
-			want:            nil,
-			wantProductErrs: nil,
-			wantErr:         ErrInvalidIDs,
+			testname:   "",
+			fileName:   "example_with_invalid_offer_id_col.xlsx",
+			want:       nil,
+			wantIssues: nil,
+			wantErr:    ErrInvalidIDs,
 		},
 		{
 			testname: "file with errors",
 			fileName: "example_with_errors.xlsx",
 			want: []models.ProductUpdate{
-				{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true},
-				{Product: models.Product{OfferId: 9, Name: "name2_1", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 10, Name: "big boss", Price: 3, Quantity: 321}, Available: true},
-				{Product: models.Product{OfferId: 11, Name: "Ветка", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 12, Name: "big spoon", Price: 1, Quantity: 166}, Available: true},
-				{Product: models.Product{OfferId: 13, Name: "name3_1", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 14, Name: "Биткоинт", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 15, Name: "big TV", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 16, Name: "body", Price: 2, Quantity: 2}, Available: true},
-				{Product: models.Product{OfferId: 17, Name: "submarine", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 18, Name: "subwoofer", Price: 2, Quantity: 2}, Available: true},
-				{Product: models.Product{OfferId: 19, Name: "name15_10", Price: 71, Quantity: 10}, Available: true},
-				{Product: models.Product{OfferId: 20, Name: "subtitles", Price: 1, Quantity: 1}, Available: true},
+				{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true, Row: 1},
+				{Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true, Row: 2},
+				{Product: models.Product{OfferId: 9, Name: "name2_1", Price: 1, Quantity: 1}, Available: true, Row: 9},
+				{Product: models.Product{OfferId: 10, Name: "big boss", Price: 3, Quantity: 321}, Available: true, Row: 10},
+				{Product: models.Product{OfferId: 11, Name: "Ветка", Price: 1, Quantity: 1}, Available: true, Row: 11},
+				{Product: models.Product{OfferId: 12, Name: "big spoon", Price: 1, Quantity: 166}, Available: true, Row: 12},
+				{Product: models.Product{OfferId: 13, Name: "name3_1", Price: 1, Quantity: 1}, Available: true, Row: 13},
+				{Product: models.Product{OfferId: 14, Name: "Биткоинт", Price: 1, Quantity: 1}, Available: true, Row: 14},
+				{Product: models.Product{OfferId: 15, Name: "big TV", Price: 1, Quantity: 1}, Available: true, Row: 15},
+				{Product: models.Product{OfferId: 16, Name: "body", Price: 2, Quantity: 2}, Available: true, Row: 16},
+				{Product: models.Product{OfferId: 17, Name: "submarine", Price: 1, Quantity: 1}, Available: true, Row: 17},
+				{Product: models.Product{OfferId: 18, Name: "subwoofer", Price: 2, Quantity: 2}, Available: true, Row: 18},
+				{Product: models.Product{OfferId: 19, Name: "name15_10", Price: 71, Quantity: 10}, Available: true, Row: 19},
+				{Product: models.Product{OfferId: 20, Name: "subtitles", Price: 1, Quantity: 1}, Available: true, Row: 20},
 			},
-			wantProductErrs: []error{
-				ErrProductParsing{
-					Row:    3,
-					Field:  "name",
-					ErrMsg: models.MsgTooLongName,
+			wantIssues: []models.ImportIssue{
+				{
+					Row:      3,
+					OfferId:  3,
+					Field:    "name",
+					Code:     models.CodeTooLongName,
+					Severity: models.SeverityError,
+					Message:  models.MsgTooLongName,
 				},
-				ErrProductParsing{
-					Row:   4,
-					Field: "price",
-					ErrMsg: (&strconv.NumError{
-						Func: "ParseUint",
-						Num:  "0-40",
-						Err:  strconv.ErrSyntax,
-					}).Error(),
+				{
+					Row:      4,
+					OfferId:  4,
+					Field:    "price",
+					Code:     models.CodeInvalidNumber,
+					Severity: models.SeverityError,
+					Message:  `must be a non-negative integer, got "0-40"`,
 				},
-				ErrProductParsing{
-					Row:   5,
-					Field: "price",
-					ErrMsg: (&strconv.NumError{
-						Func: "ParseUint",
-						Num:  "-666",
-						Err:  strconv.ErrSyntax,
-					}).Error(),
+				{
+					Row:      5,
+					OfferId:  5,
+					Field:    "price",
+					Code:     models.CodeInvalidNumber,
+					Severity: models.SeverityError,
+					Message:  `must be a non-negative integer, got "-666"`,
 				},
-				ErrProductParsing{
-					Row:   6,
-					Field: "quantity",
-					ErrMsg: (&strconv.NumError{
-						Func: "ParseUint",
-						Num:  "0-40",
-						Err:  strconv.ErrSyntax,
-					}).Error(),
+				{
+					Row:      6,
+					OfferId:  6,
+					Field:    "quantity",
+					Code:     models.CodeInvalidNumber,
+					Severity: models.SeverityError,
+					Message:  `must be a non-negative integer, got "0-40"`,
 				},
-				ErrProductParsing{
-					Row:   7,
-					Field: "quantity",
-					ErrMsg: (&strconv.NumError{
-						Func: "ParseUint",
-						Num:  "-666",
-						Err:  strconv.ErrSyntax,
-					}).Error(),
+				{
+					Row:      7,
+					OfferId:  7,
+					Field:    "quantity",
+					Code:     models.CodeInvalidNumber,
+					Severity: models.SeverityError,
+					Message:  `must be a non-negative integer, got "-666"`,
 				},
-				ErrProductParsing{
-					Row:   8,
-					Field: "available",
-					ErrMsg: (&strconv.NumError{
-						Func: "ParseBool",
-						Num:  "абра-кадабра",
-						Err:  strconv.ErrSyntax,
-					}).Error(),
+				{
+					Row:      8,
+					OfferId:  8,
+					Field:    "available",
+					Code:     models.CodeInvalidBool,
+					Severity: models.SeverityError,
+					Message:  `must be true or false, got "абра-кадабра"`,
 				},
 			},
 			wantErr: nil,
@@ -116,29 +141,29 @@ func TestXLSXparser(t *testing.T) {
 			testname: "20 rows",
 			fileName: "example1.xlsx",
 			want: []models.ProductUpdate{
-				{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true},
-				{Product: models.Product{OfferId: 3, Name: "name1_3", Price: 30, Quantity: 3}, Available: true},
-				{Product: models.Product{OfferId: 4, Name: "big changus", Price: 40, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 5, Name: "Колесо", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 6, Name: "Кросовок", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 7, Name: "big melon", Price: 2, Quantity: 2}, Available: false},
-				{Product: models.Product{OfferId: 8, Name: "head", Price: 1, Quantity: 1}, Available: false},
-				{Product: models.Product{OfferId: 9, Name: "name2_1", Price: 1, Quantity: 1}, Available: false},
-				{Product: models.Product{OfferId: 10, Name: "big boss", Price: 3, Quantity: 321}, Available: false},
-				{Product: models.Product{OfferId: 11, Name: "Ветка", Price: 1, Quantity: 1}, Available: false},
-				{Product: models.Product{OfferId: 12, Name: "big spoon", Price: 1, Quantity: 166}, Available: true},
-				{Product: models.Product{OfferId: 13, Name: "name3_1", Price: 1, Quantity: 1}, Available: false},
-				{Product: models.Product{OfferId: 14, Name: "Биткоинт", Price: 1, Quantity: 1}, Available: false},
-				{Product: models.Product{OfferId: 15, Name: "big TV", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 16, Name: "body", Price: 2, Quantity: 2}, Available: false},
-				{Product: models.Product{OfferId: 17, Name: "submarine", Price: 1, Quantity: 1}, Available: true},
-				{Product: models.Product{OfferId: 18, Name: "subwoofer", Price: 2, Quantity: 2}, Available: true},
-				{Product: models.Product{OfferId: 19, Name: "name15_10", Price: 71, Quantity: 10}, Available: true},
-				{Product: models.Product{OfferId: 20, Name: "subtitles", Price: 1, Quantity: 1}, Available: true},
+				{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true, Row: 1},
+				{Product: models.Product{OfferId: 2, Name: "body", Price: 20, Quantity: 0}, Available: true, Row: 2},
+				{Product: models.Product{OfferId: 3, Name: "name1_3", Price: 30, Quantity: 3}, Available: true, Row: 3},
+				{Product: models.Product{OfferId: 4, Name: "big changus", Price: 40, Quantity: 1}, Available: true, Row: 4},
+				{Product: models.Product{OfferId: 5, Name: "Колесо", Price: 1, Quantity: 1}, Available: true, Row: 5},
+				{Product: models.Product{OfferId: 6, Name: "Кросовок", Price: 1, Quantity: 1}, Available: true, Row: 6},
+				{Product: models.Product{OfferId: 7, Name: "big melon", Price: 2, Quantity: 2}, Available: false, Row: 7},
+				{Product: models.Product{OfferId: 8, Name: "head", Price: 1, Quantity: 1}, Available: false, Row: 8},
+				{Product: models.Product{OfferId: 9, Name: "name2_1", Price: 1, Quantity: 1}, Available: false, Row: 9},
+				{Product: models.Product{OfferId: 10, Name: "big boss", Price: 3, Quantity: 321}, Available: false, Row: 10},
+				{Product: models.Product{OfferId: 11, Name: "Ветка", Price: 1, Quantity: 1}, Available: false, Row: 11},
+				{Product: models.Product{OfferId: 12, Name: "big spoon", Price: 1, Quantity: 166}, Available: true, Row: 12},
+				{Product: models.Product{OfferId: 13, Name: "name3_1", Price: 1, Quantity: 1}, Available: false, Row: 13},
+				{Product: models.Product{OfferId: 14, Name: "Биткоинт", Price: 1, Quantity: 1}, Available: false, Row: 14},
+				{Product: models.Product{OfferId: 15, Name: "big TV", Price: 1, Quantity: 1}, Available: true, Row: 15},
+				{Product: models.Product{OfferId: 16, Name: "body", Price: 2, Quantity: 2}, Available: false, Row: 16},
+				{Product: models.Product{OfferId: 17, Name: "submarine", Price: 1, Quantity: 1}, Available: true, Row: 17},
+				{Product: models.Product{OfferId: 18, Name: "subwoofer", Price: 2, Quantity: 2}, Available: true, Row: 18},
+				{Product: models.Product{OfferId: 19, Name: "name15_10", Price: 71, Quantity: 10}, Available: true, Row: 19},
+				{Product: models.Product{OfferId: 20, Name: "subtitles", Price: 1, Quantity: 1}, Available: true, Row: 20},
 			},
-			wantProductErrs: nil,
-			wantErr:         nil,
+			wantIssues: nil,
+			wantErr:    nil,
 		},
 	}
 	for _, tt := range testCases {
@@ -148,12 +173,331 @@ func TestXLSXparser(t *testing.T) {
 			if err != nil {
 				assert.FailNow(t, err.Error())
 			}
-			p := NewParser()
+			p := NewParser(config.Config{})
 
-			parsedProducts, parseErrs, err := p.ParseProducts(f)
+			parsedProducts, parseErrs, err := streamAll(p, f)
 			assert.Equal(t, tt.wantErr, err, "method errors")
-			assert.Equal(t, tt.wantProductErrs, parseErrs, "parse errors")
+			assert.Equal(t, tt.wantIssues, parseErrs, "parse errors")
 			assert.Equal(t, tt.want, parsedProducts, "parsed products")
 		})
 	}
 }
+
+func TestXLSXparser_Header(t *testing.T) {
+	cfg := config.Config{
+		Parser: config.Parser{
+			Aliases: map[string][]string{
+				"offer_id": {"артикул", "sku"},
+				"name":     {"Наименование"},
+				"price":    {"цена"},
+				"quantity": {"количество", "остаток"},
+				"unknown":  {"комментарий"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testname   string
+		fileName   string
+		want       []models.ProductUpdate
+		wantIssues []models.ImportIssue
+		wantErr    error
+	}{
+		{
+			testname: "синонимы, другой порядок колонок, лишняя колонка, без available",
+			fileName: "example_with_header.xlsx",
+			want: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "head", Price: 100, Quantity: 5}, Available: true, Row: 2},
+				{Product: models.Product{OfferId: 2, Name: "body", Price: 200, Quantity: 0}, Available: true, Row: 3},
+			},
+			wantIssues: []models.ImportIssue{
+				{
+					Row:      4,
+					OfferId:  3,
+					Field:    "price",
+					Code:     models.CodeInvalidNumber,
+					Severity: models.SeverityError,
+					Message:  `must be a non-negative integer, got "сто"`,
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			testname: "канонические названия и колонка available",
+			fileName: "example_with_header_available.xlsx",
+			want: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "head", Price: 100, Quantity: 5}, Available: true, Row: 2},
+				{Product: models.Product{OfferId: 2, Name: "body", Price: 200, Quantity: 0}, Available: false, Row: 3},
+			},
+			wantIssues: nil,
+			wantErr:    nil,
+		},
+		{
+			testname:   "нет обязательных колонок",
+			fileName:   "example_missing_columns.xlsx",
+			want:       nil,
+			wantIssues: nil,
+			wantErr:    ErrMissingColumns{Columns: []string{"price", "quantity"}},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.testname, func(t *testing.T) {
+			filename := filepath.Join("test", tt.fileName)
+			f, err := os.Open(filename)
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			p := NewParser(cfg)
+
+			parsedProducts, parseErrs, err := streamAll(p, f)
+			assert.Equal(t, tt.wantErr, err, "method errors")
+			assert.Equal(t, tt.wantIssues, parseErrs, "parse errors")
+			assert.Equal(t, tt.want, parsedProducts, "parsed products")
+		})
+	}
+}
+
+func TestXLSXparser_StreamProducts(t *testing.T) {
+	type batch struct {
+		size int
+		errs int
+		last bool
+	}
+
+	testCases := []struct {
+		testname    string
+		fileName    string
+		batchSize   int
+		handlerErr  error
+		wantBatches []batch
+		wantErr     error
+	}{
+		{
+			testname:    "20 строк пачками по 7",
+			fileName:    "example1.xlsx",
+			batchSize:   7,
+			wantBatches: []batch{{size: 7}, {size: 7}, {size: 6, last: true}},
+			wantErr:     nil,
+		},
+		{
+			testname:    "размер пачки кратен числу строк",
+			fileName:    "example1.xlsx",
+			batchSize:   10,
+			wantBatches: []batch{{size: 10}, {size: 10, last: true}},
+			wantErr:     nil,
+		},
+		{
+			testname:    "ошибки строк попадают в свою пачку",
+			fileName:    "example_with_errors.xlsx",
+			batchSize:   5,
+			wantBatches: []batch{{size: 2, errs: 3}, {size: 2, errs: 3}, {size: 5}, {size: 5, last: true}},
+			wantErr:     nil,
+		},
+		{
+			testname:    "дубликаты находятся до первой пачки",
+			fileName:    "example_duplicates.xlsx",
+			batchSize:   3,
+			wantBatches: nil,
+			wantErr:     ErrHasDuplicates,
+		},
+		{
+			testname:    "ошибка обработчика прерывает разбор",
+			fileName:    "example1.xlsx",
+			batchSize:   7,
+			handlerErr:  errors.New("handler err"),
+			wantBatches: []batch{{size: 7}},
+			wantErr:     errors.New("handler err"),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.testname, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("test", tt.fileName))
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			defer f.Close()
+			p := NewParser(config.Config{})
+
+			var gotBatches []batch
+			err = p.StreamProducts(f, "", tt.batchSize, func(productUpdates []models.ProductUpdate, issues []models.ImportIssue, last bool) error {
+				gotBatches = append(gotBatches, batch{size: len(productUpdates), errs: len(issues), last: last})
+				return tt.handlerErr
+			})
+			assert.Equal(t, tt.wantErr, err, "method errors")
+			assert.Equal(t, tt.wantBatches, gotBatches, "batches")
+		})
+	}
+}
+
+// countingRows считает прочитанные первым проходом строки
+type countingRows struct {
+	rowIterator
+	read *int
+}
+
+func (c countingRows) Next() bool {
+	*c.read++
+	return c.rowIterator.Next()
+}
+
+func TestScan_StopsAtFirstDuplicate(t *testing.T) {
+	table := &bytes.Buffer{}
+	table.WriteString("offer_id,name,price,quantity\n1,head,10,1\n1,head,10,1\n")
+	for id := 2; id < 10000; id++ {
+		fmt.Fprintf(table, "%d,body,10,1\n", id)
+	}
+
+	read := 0
+	open := func() (rowIterator, error) {
+		it, err := csvOpener(bytes.NewReader(table.Bytes()))()
+		if err != nil {
+			return nil, err
+		}
+
+		return countingRows{rowIterator: it, read: &read}, nil
+	}
+
+	_, _, err := scan(open, columnAliases(config.Config{}))
+	assert.Equal(t, ErrHasDuplicates, err)
+	assert.Equal(t, 3, read, "scan stops at the repeated row")
+}
+
+// multiSheetBook - книга с листами продавцов для тестов; первый лист пустой, как титульный лист выгрузки
+func multiSheetBook(t *testing.T) []byte {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheets := map[string][][]interface{}{
+		"Магазин 1": {
+			{"offer_id", "name", "price", "quantity"},
+			{1, "head", 10, 1},
+			{2, "body", "сто", 2},
+		},
+		"Магазин 2": {
+			{"offer_id", "name", "price", "quantity"},
+			{7, "tail", 70, 7},
+		},
+	}
+	for _, name := range []string{"Магазин 1", "Магазин 2"} {
+		if _, err := f.NewSheet(name); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		for idx, row := range sheets[name] {
+			cell, _ := excelize.CoordinatesToCellName(1, idx+1)
+			if err := f.SetSheetRow(name, cell, &row); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := f.WriteTo(buf); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	return buf.Bytes()
+}
+
+func TestXLSXparser_SheetNames(t *testing.T) {
+	r := bytes.NewReader(multiSheetBook(t))
+	// книгу уже читали: SheetNames читает её с начала
+	if _, err := r.Seek(0, io.SeekEnd); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	names, err := NewParser(config.Config{}).SheetNames(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Магазин 1", "Магазин 2"}, names)
+}
+
+func TestXLSXparser_StreamProducts_Sheet(t *testing.T) {
+	book := multiSheetBook(t)
+
+	testCases := []struct {
+		testname   string
+		sheet      string
+		want       []models.ProductUpdate
+		wantIssues []models.ImportIssue
+		wantErr    error
+	}{
+		{
+			testname:   "первый лист по умолчанию",
+			sheet:      "",
+			wantErr:    ErrEmptySheet,
+			want:       nil,
+			wantIssues: nil,
+		},
+		{
+			testname: "лист по имени",
+			sheet:    "Магазин 1",
+			want: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 1, Name: "head", Price: 10, Quantity: 1}, Available: true, Row: 2},
+			},
+			wantIssues: []models.ImportIssue{
+				{Row: 3, OfferId: 2, Field: "price", Code: models.CodeInvalidNumber, Severity: models.SeverityError, Message: `must be a non-negative integer, got "сто"`},
+			},
+		},
+		{
+			testname: "другой лист",
+			sheet:    "Магазин 2",
+			want: []models.ProductUpdate{
+				{Product: models.Product{OfferId: 7, Name: "tail", Price: 70, Quantity: 7}, Available: true, Row: 2},
+			},
+		},
+		{
+			testname: "нет такого листа",
+			sheet:    "Магазин 3",
+			wantErr:  ErrSheetNotFound,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.testname, func(t *testing.T) {
+			var (
+				gotUpdates []models.ProductUpdate
+				gotIssues  []models.ImportIssue
+			)
+			err := NewParser(config.Config{}).StreamProducts(bytes.NewReader(book), tt.sheet, 10, func(productUpdates []models.ProductUpdate, issues []models.ImportIssue, last bool) error {
+				gotUpdates = append(gotUpdates, productUpdates...)
+				gotIssues = append(gotIssues, issues...)
+				return nil
+			})
+			assert.Equal(t, tt.wantErr, err, "method errors")
+			assert.Equal(t, tt.want, gotUpdates, "parsed products")
+			assert.Equal(t, tt.wantIssues, gotIssues, "parse errors")
+		})
+	}
+}
+
+func TestNewParser_UnzipLimits(t *testing.T) {
+	testCases := []struct {
+		testname string
+		cfg      config.Parser
+		wantXML  int64
+		wantZip  int64
+	}{
+		{
+			testname: "по умолчанию",
+			wantXML:  4 << 20,
+			wantZip:  1024 << 20,
+		},
+		{
+			testname: "из конфига",
+			cfg:      config.Parser{UnzipXMLSizeLimitMB: 8, UnzipSizeLimitMB: 256},
+			wantXML:  8 << 20,
+			wantZip:  256 << 20,
+		},
+		{
+			testname: "общий лимит не меньше лимита листа",
+			cfg:      config.Parser{UnzipXMLSizeLimitMB: 64, UnzipSizeLimitMB: 16},
+			wantXML:  64 << 20,
+			wantZip:  64 << 20,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.testname, func(t *testing.T) {
+			p := NewParser(config.Config{Parser: tt.cfg})
+			assert.Equal(t, tt.wantXML, p.opts.UnzipXMLSizeLimit, "xml limit")
+			assert.Equal(t, tt.wantZip, p.opts.UnzipSizeLimit, "unzip limit")
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package xlsxparser
+
+import (
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	reportSheetName   = "Sheet1"
+	reportErrorColumn = "error"
+	// заливка ячеек с ошибками и с предупреждениями
+	reportErrorColor   = "#FFC7CE"
+	reportWarningColor = "#FFEB9C"
+)
+
+// WriteErrorReport пишет в w xlsx-копию таблицы с колонкой error и подсвеченными ячейками,
+// в которых нашлись проблемы разбора и валидации. Колонка error парсером игнорируется,
+// поэтому исправленный отчёт можно загрузить вместо исходной таблицы.
+// Проблемы с ImportIssue.Sheet попадают на одноимённый лист отчёта, без листа - на первый лист книги.
+func (p Parser) WriteErrorReport(r io.ReadSeeker, issues []models.ImportIssue, w io.Writer) error {
+	// таблица обычно уже прочитана при разборе
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		log.Println(err)
+		return ErrFailedToRead
+	}
+
+	f, err := openBook(r, p.opts)
+	if err != nil {
+		return err
+	}
+	defer closeFile(f)
+
+	bySheet := make(map[string][]models.ImportIssue)
+	for _, issue := range issues {
+		bySheet[issue.Sheet] = append(bySheet[issue.Sheet], issue)
+	}
+
+	report, err := newReportBook()
+	if err != nil {
+		return err
+	}
+	defer closeFile(report.f)
+
+	if sheetIssues, ok := bySheet[""]; ok {
+		open, err := sheetOpener(f, "")
+		if err != nil {
+			return err
+		}
+		if err := report.addSheet(reportSheetName, open, p.aliases, sheetIssues); err != nil {
+			return err
+		}
+	}
+
+	// листы отчёта идут в порядке книги
+	for _, sheet := range f.GetSheetList() {
+		sheetIssues, ok := bySheet[sheet]
+		if !ok {
+			continue
+		}
+
+		open, err := sheetOpener(f, sheet)
+		if err != nil {
+			return err
+		}
+		if err := report.addSheet(sheet, open, p.aliases, sheetIssues); err != nil {
+			return err
+		}
+	}
+
+	return report.writeTo(w)
+}
+
+// WriteErrorReport - см. Parser.WriteErrorReport; отчёт по CSV тоже в формате xlsx
+func (p CSVParser) WriteErrorReport(r io.ReadSeeker, issues []models.ImportIssue, w io.Writer) error {
+	report, err := newReportBook()
+	if err != nil {
+		return err
+	}
+	defer closeFile(report.f)
+
+	if err := report.addSheet(reportSheetName, csvOpener(r), p.aliases, issues); err != nil {
+		return err
+	}
+
+	return report.writeTo(w)
+}
+
+// reportBook - книга отчёта об ошибках; листы добавляются по одному
+type reportBook struct {
+	f            *excelize.File
+	errorStyle   int
+	warningStyle int
+	sheets       int
+}
+
+func newReportBook() (*reportBook, error) {
+	f := excelize.NewFile()
+
+	errorStyle, err := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{reportErrorColor}},
+	})
+	if err != nil {
+		closeFile(f)
+		return nil, err
+	}
+
+	warningStyle, err := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{reportWarningColor}},
+	})
+	if err != nil {
+		closeFile(f)
+		return nil, err
+	}
+
+	return &reportBook{f: f, errorStyle: errorStyle, warningStyle: warningStyle}, nil
+}
+
+// addSheet копирует лист таблицы в отчёт и дописывает проблемы его строк
+func (rb *reportBook) addSheet(name string, open func() (rowIterator, error), aliases map[string]string, issues []models.ImportIssue) error {
+	// проблемы обычно знают номер строки; товары, пришедшие не из таблицы, - только offer_id
+	byRow := make(map[uint64][]models.ImportIssue)
+	byOffer := make(map[uint64][]models.ImportIssue)
+	for _, issue := range issues {
+		if issue.Row > 0 {
+			byRow[issue.Row] = append(byRow[issue.Row], issue)
+		} else {
+			byOffer[issue.OfferId] = append(byOffer[issue.OfferId], issue)
+		}
+	}
+
+	l, width, err := reportLayout(open, aliases)
+	if err != nil {
+		return err
+	}
+
+	// новая книга создаётся с одним пустым листом - первый лист отчёта занимает его место
+	if rb.sheets == 0 {
+		if err := rb.f.SetSheetName(reportSheetName, name); err != nil {
+			return err
+		}
+	} else if _, err := rb.f.NewSheet(name); err != nil {
+		return err
+	}
+	rb.sheets++
+
+	sw, err := rb.f.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+
+	it, err := open()
+	if err != nil {
+		log.Println(err)
+		return ErrFailedToRead
+	}
+	defer closeIterator(it)
+
+	headerSeen := false
+	for it.Next() {
+		row, rowNumber, err := it.Row()
+		if err != nil {
+			log.Println(err)
+			return ErrFailedToRead
+		}
+
+		if isEmptyRow(row) {
+			continue
+		}
+
+		cells := make([]interface{}, width+1)
+		for idx, value := range row {
+			cells[idx] = excelize.Cell{Value: value}
+		}
+
+		if !headerSeen && l.hasHeader {
+			headerSeen = true
+			cells[width] = excelize.Cell{Value: reportErrorColumn}
+		} else {
+			headerSeen = true
+
+			rowIssues := byRow[rowNumber]
+			if offerIdCell, ok := l.cell(row, colOfferId); ok {
+				if offerId, err := strconv.ParseUint(offerIdCell, 10, 64); err == nil {
+					rowIssues = append(rowIssues, byOffer[offerId]...)
+				}
+			}
+
+			msgs := make([]string, 0, len(rowIssues))
+			for _, issue := range rowIssues {
+				msg := issue.Field + ": " + issue.Message
+				style := rb.errorStyle
+				if issue.Severity == models.SeverityWarning {
+					msg += " (warning)"
+					style = rb.warningStyle
+				}
+				msgs = append(msgs, msg)
+
+				// предупреждение не перекрашивает ячейку с ошибкой
+				if idx, ok := l.columns[issue.Field]; ok {
+					if prev, ok := cells[idx].(excelize.Cell); ok && prev.StyleID == rb.errorStyle {
+						continue
+					}
+					value, _ := l.cell(row, issue.Field)
+					cells[idx] = excelize.Cell{Value: value, StyleID: style}
+				}
+			}
+
+			if len(msgs) > 0 {
+				cells[width] = excelize.Cell{Value: strings.Join(msgs, "; ")}
+			}
+		}
+
+		// номера строк совпадают с исходной таблицей, чтобы Row из ошибок указывал на ту же строку
+		cell, err := excelize.CoordinatesToCellName(1, int(rowNumber))
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(cell, cells); err != nil {
+			return err
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		log.Println(err)
+		return ErrFailedToRead
+	}
+
+	return sw.Flush()
+}
+
+func (rb *reportBook) writeTo(w io.Writer) error {
+	if _, err := rb.f.WriteTo(w); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+// reportLayout - первый проход: расположение колонок и ширина таблицы; колонка error ставится после самой длинной строки
+func reportLayout(open func() (rowIterator, error), aliases map[string]string) (layout, int, error) {
+	it, err := open()
+	if err != nil {
+		log.Println(err)
+		return layout{}, 0, ErrFailedToRead
+	}
+	defer closeIterator(it)
+
+	var (
+		l          layout
+		headerSeen bool
+		width      = len(defaultColumnOrder)
+	)
+	for it.Next() {
+		row, _, err := it.Row()
+		if err != nil {
+			log.Println(err)
+			return layout{}, 0, ErrFailedToRead
+		}
+
+		if isEmptyRow(row) {
+			continue
+		}
+
+		if !headerSeen {
+			headerSeen = true
+
+			l, err = detectLayout(row, aliases)
+			if err != nil {
+				return layout{}, 0, err
+			}
+		}
+
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		log.Println(err)
+		return layout{}, 0, ErrFailedToRead
+	}
+
+	return l, width, nil
+}
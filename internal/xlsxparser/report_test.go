@@ -0,0 +1,176 @@
+package xlsxparser
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+// reportParser - общий интерфейс xlsx и csv парсеров для тестов отчёта
+type reportParser interface {
+	streamParser
+	WriteErrorReport(r io.ReadSeeker, issues []models.ImportIssue, w io.Writer) error
+}
+
+func TestWriteErrorReport(t *testing.T) {
+	cfg := config.Config{
+		Parser: config.Parser{
+			Aliases: map[string][]string{
+				"offer_id": {"артикул"},
+				"name":     {"наименование"},
+				"price":    {"цена"},
+				"quantity": {"количество"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testname string
+		fileName string
+		parser   reportParser
+	}{
+		{
+			testname: "xlsx",
+			fileName: "example_with_errors.xlsx",
+			parser:   NewParser(cfg),
+		},
+		{
+			testname: "csv",
+			fileName: "example.csv",
+			parser:   NewCSVParser(cfg),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.testname, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join("test", tc.fileName))
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+
+			productUpdates, issues, err := streamAll(tc.parser, bytes.NewReader(b))
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			if !assert.NotEmpty(t, issues) || !assert.NotEmpty(t, productUpdates) {
+				return
+			}
+
+			// проблема товара не из таблицы знает только offer_id
+			validOffer := productUpdates[0].Product.OfferId
+			allIssues := append(issues, models.ImportIssue{
+				OfferId:  validOffer,
+				Field:    "name",
+				Code:     models.CodeTooLongName,
+				Severity: models.SeverityError,
+				Message:  models.MsgTooLongName,
+			})
+
+			buf := &bytes.Buffer{}
+			if err := tc.parser.WriteErrorReport(bytes.NewReader(b), allIssues, buf); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+
+			f, err := excelize.OpenReader(buf)
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+			defer f.Close()
+
+			rows, err := f.GetRows(reportSheetName)
+			if err != nil {
+				assert.FailNow(t, err.Error())
+			}
+
+			// колонка error - после самой длинной строки
+			errorCol := 0
+			for _, row := range rows {
+				if len(row) > errorCol {
+					errorCol = len(row)
+				}
+			}
+			errorCol--
+
+			for _, issue := range issues {
+				row := rows[issue.Row-1]
+				if assert.Greater(t, len(row), errorCol, "row %d has error column", issue.Row) {
+					assert.Contains(t, row[errorCol], issue.Field+": "+issue.Message)
+				}
+			}
+
+			// строка без ошибок разбора, но с ошибкой валидации
+			found := false
+			for rowIdx, row := range rows {
+				if len(row) > errorCol && row[errorCol] == "name: "+models.MsgTooLongName {
+					found = true
+
+					l, _ := detectLayout(rows[0], columnAliases(cfg))
+					cell, _ := excelize.CoordinatesToCellName(l.columns[colName]+1, rowIdx+1)
+					styleID, err := f.GetCellStyle(reportSheetName, cell)
+					assert.NoError(t, err)
+					assert.NotZero(t, styleID, "invalid cell is highlighted")
+
+					cell, _ = excelize.CoordinatesToCellName(l.columns[colPrice]+1, rowIdx+1)
+					styleID, err = f.GetCellStyle(reportSheetName, cell)
+					assert.NoError(t, err)
+					assert.Zero(t, styleID, "valid cell is not highlighted")
+				}
+			}
+			assert.True(t, found, "issue is reported by offer_id")
+
+			// отчёт (всегда xlsx) можно загрузить обратно: колонка error игнорируется
+			_, reportIssues, err := streamAll(NewParser(cfg), bytes.NewReader(mustWrite(t, f)))
+			assert.NoError(t, err)
+			assert.Len(t, reportIssues, len(issues))
+		})
+	}
+}
+
+func TestWriteErrorReport_Sheets(t *testing.T) {
+	book := multiSheetBook(t)
+	issues := []models.ImportIssue{
+		{Sheet: "Магазин 1", Row: 3, OfferId: 2, Field: "price", Code: models.CodeInvalidNumber, Severity: models.SeverityError, Message: `must be a non-negative integer, got "сто"`},
+		{Sheet: "Магазин 2", Row: 2, OfferId: 7, Field: "name", Code: models.CodeEmptyName, Severity: models.SeverityWarning, Message: "name is required"},
+		// листа нет в книге - проблема в отчёт не попадает
+		{Sheet: "Магазин 3", Row: 2, OfferId: 9, Field: "price", Code: models.CodePriceTooLow, Severity: models.SeverityError, Message: "price 0 is less than 1"},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := NewParser(config.Config{}).WriteErrorReport(bytes.NewReader(book), issues, buf); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	f, err := excelize.OpenReader(buf)
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	defer f.Close()
+
+	// в отчёте только листы с проблемами, в порядке книги
+	assert.Equal(t, []string{"Магазин 1", "Магазин 2"}, f.GetSheetList())
+
+	rows, err := f.GetRows("Магазин 1")
+	assert.NoError(t, err)
+	// колонка error не уже колонок по умолчанию (с available)
+	assert.Equal(t, []string{"offer_id", "name", "price", "quantity", "", "error"}, rows[0])
+	assert.Equal(t, []string{"2", "body", "сто", "2", "", `price: must be a non-negative integer, got "сто"`}, rows[2])
+
+	rows, err = f.GetRows("Магазин 2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"7", "tail", "70", "7", "", "name: name is required (warning)"}, rows[1])
+}
+
+func mustWrite(t *testing.T, f *excelize.File) []byte {
+	buf := &bytes.Buffer{}
+	if _, err := f.WriteTo(buf); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	return buf.Bytes()
+}
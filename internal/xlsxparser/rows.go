@@ -0,0 +1,262 @@
+package xlsxparser
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hablof/merchant-experience/internal/models"
+)
+
+// BatchHandler получает очередную пачку разобранных строк; last - признак последней пачки.
+// Ошибка обработчика прерывает разбор и возвращается наружу.
+// Строки с ошибками в пачку не попадают, их проблемы передаются в issues.
+type BatchHandler func(productUpdates []models.ProductUpdate, issues []models.ImportIssue, last bool) error
+
+// rowIterator - построчное чтение таблицы, общее для xlsx и csv
+type rowIterator interface {
+	Next() bool
+	// rowNumber - номер строки в файле в человеческом счёте
+	Row() (cells []string, rowNumber uint64, err error)
+	Err() error
+	Close() error
+}
+
+// streamRows проходит таблицу дважды, не держа её в памяти целиком.
+// Первый проход определяет колонки, проверяет offer_id и ищет дубликаты, поэтому при ошибке в таблице
+// обработчик не вызывается ни разу. Второй проход разбирает строки и отдаёт их пачками по batchSize.
+func streamRows(open func() (rowIterator, error), aliases map[string]string, batchSize int, handle BatchHandler) error {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	l, dataRows, err := scan(open, aliases)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	it, err := open()
+	if err != nil {
+		log.Println(err)
+		return ErrFailedToRead
+	}
+	defer closeIterator(it)
+
+	var (
+		batch       []models.ProductUpdate
+		batchIssues []models.ImportIssue
+		processed   = 0
+		skipHeader  = l.hasHeader
+	)
+	for it.Next() {
+		row, rowNumber, err := it.Row()
+		if err != nil {
+			log.Println(err)
+			return ErrFailedToRead
+		}
+
+		if isEmptyRow(row) {
+			continue
+		}
+
+		if skipHeader {
+			skipHeader = false
+			continue
+		}
+
+		updateUnit, rowIssues := parseRow(row, rowNumber, l)
+		batchIssues = append(batchIssues, rowIssues...)
+		// строка только с предупреждениями загружается
+		if !models.HasErrors(rowIssues) {
+			batch = append(batch, updateUnit)
+		}
+
+		processed++
+		if processed%batchSize == 0 || processed == dataRows {
+			if err := handle(batch, batchIssues, processed == dataRows); err != nil {
+				return err
+			}
+
+			batch, batchIssues = nil, nil
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		log.Println(err)
+		return ErrFailedToRead
+	}
+
+	return nil
+}
+
+// scan - первый проход: расположение колонок, проверка offer_id и дубликатов, подсчёт строк с данными.
+// Дубликат ищется при чтении каждой строки, проход останавливается на первом повторе.
+// Для этого в памяти держится множество уже прочитанных offer_id: порядка 40 байт на строку листа,
+// то есть память первого прохода растёт с числом строк, а не с размером строк.
+func scan(open func() (rowIterator, error), aliases map[string]string) (layout, int, error) {
+	it, err := open()
+	if err != nil {
+		log.Println(err)
+		return layout{}, 0, ErrFailedToRead
+	}
+	defer closeIterator(it)
+
+	var (
+		l          layout
+		headerSeen bool
+		dataRows   int
+		offerIDs   = make(map[uint64]struct{})
+	)
+	for it.Next() {
+		row, _, err := it.Row()
+		if err != nil {
+			log.Println(err)
+			return layout{}, 0, ErrFailedToRead
+		}
+
+		if isEmptyRow(row) {
+			continue
+		}
+
+		if !headerSeen {
+			headerSeen = true
+
+			l, err = detectLayout(row, aliases)
+			if err != nil {
+				return layout{}, 0, err
+			}
+
+			if l.hasHeader {
+				continue
+			}
+		}
+
+		str, _ := l.cell(row, colOfferId)
+		offerId, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			log.Println(err)
+			return layout{}, 0, ErrInvalidIDs
+		}
+
+		if _, ok := offerIDs[offerId]; ok {
+			log.Println("sheet has offerID duplicates")
+			return layout{}, 0, ErrHasDuplicates
+		}
+
+		offerIDs[offerId] = struct{}{}
+		dataRows++
+	}
+
+	if err := it.Err(); err != nil {
+		log.Println(err)
+		return layout{}, 0, ErrFailedToRead
+	}
+
+	if dataRows == 0 {
+		log.Println("empty sheet")
+		return layout{}, 0, ErrEmptySheet
+	}
+
+	return l, dataRows, nil
+}
+
+// parseRow разбирает строку с данными; проблемы разбора и валидации возвращаются в rowIssues
+func parseRow(row []string, rowNumber uint64, l layout) (updateUnit models.ProductUpdate, rowIssues []models.ImportIssue) {
+	productUnit := models.Product{}
+	// cols (порядок задаёт заголовок, см. detectLayout):
+	// offer_id  - уникальный идентификатор товара в системе продавца
+	// name      - название товара
+	// price     - цена в рублях
+	// quantity  - количество товара на складе продавца
+	// available - true/false, в случае false продавец хочет удалить товар из нашей базы; без колонки - true
+	offerIdCell, _ := l.cell(row, colOfferId)
+	nameCell, _ := l.cell(row, colName)
+	priceCell, _ := l.cell(row, colPrice)
+	quantityCell, _ := l.cell(row, colQuantity)
+	availableCell, hasAvailable := l.cell(row, colAvailable)
+
+	// OfferId нужен, чтобы в режиме replace не удалить оффер, строка которого не прошла разбор
+	issue := func(offerId uint64, field string, code models.IssueCode, msg string) models.ImportIssue {
+		return models.ImportIssue{
+			Row:      rowNumber,
+			OfferId:  offerId,
+			Field:    field,
+			Code:     code,
+			Severity: models.SeverityError,
+			Message:  msg,
+		}
+	}
+
+	// парсим offer_id
+	offerId, err := strconv.ParseUint(offerIdCell, 10, 64)
+	if err != nil {
+		rowIssues = append(rowIssues, issue(0, colOfferId, models.CodeInvalidNumber, invalidValueMsg(models.MsgInvalidNumber, offerIdCell)))
+	}
+
+	// обрезаем пробелы у name
+	name := strings.TrimSpace(nameCell)
+
+	// парсим price
+	price, err := strconv.ParseUint(priceCell, 10, 64)
+	if err != nil {
+		rowIssues = append(rowIssues, issue(offerId, colPrice, models.CodeInvalidNumber, invalidValueMsg(models.MsgInvalidNumber, priceCell)))
+	}
+
+	// парсим quantity
+	quantity, err := strconv.ParseUint(quantityCell, 10, 64)
+	if err != nil {
+		rowIssues = append(rowIssues, issue(offerId, colQuantity, models.CodeInvalidNumber, invalidValueMsg(models.MsgInvalidNumber, quantityCell)))
+	}
+
+	// парсим available
+	available := true
+	if hasAvailable {
+		available, err = strconv.ParseBool(availableCell)
+		if err != nil {
+			rowIssues = append(rowIssues, issue(offerId, colAvailable, models.CodeInvalidBool, invalidValueMsg(models.MsgInvalidBool, availableCell)))
+		}
+	}
+
+	productUnit.OfferId = offerId
+	productUnit.Name = name
+	productUnit.Price = price
+	productUnit.Quantity = quantity
+
+	// валидируем по логике домена
+	var validationIssue models.ImportIssue
+	if err := productUnit.Validate(); errors.As(err, &validationIssue) {
+		validationIssue.Row = rowNumber
+		rowIssues = append(rowIssues, validationIssue)
+	}
+
+	updateUnit.Product = productUnit
+	updateUnit.Available = available
+	updateUnit.Row = rowNumber
+
+	return updateUnit, rowIssues
+}
+
+// invalidValueMsg - сообщение о неразобранной ячейке вместе с её значением
+func invalidValueMsg(msg string, cell string) string {
+	return fmt.Sprintf("%s, got %q", msg, cell)
+}
+
+// isEmptyRow: пустые строки пропускаются (excelize отдаёт их для пропусков между заполненными строками)
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func closeIterator(it rowIterator) {
+	if err := it.Close(); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,90 @@
+package xlsxparser
+
+import (
+	"io"
+	"log"
+	"strconv"
+
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	exportSheetName = "Sheet1"
+	// больше 15 цифр число в xlsx читается округлённым (9.00719925474099E+15), такие значения пишутся текстом
+	maxExactNumber = 999_999_999_999_999
+)
+
+// ProductPages отдаёт товары в handle порциями, пока они не закончатся
+type ProductPages func(handle func(products []models.Product) error) error
+
+// Writer выгружает товары в xlsx с заголовком и порядком колонок defaultColumnOrder,
+// поэтому выгрузку можно поправить и загрузить обратно без изменений
+type Writer struct{}
+
+func NewWriter() Writer {
+	return Writer{}
+}
+
+// WriteProducts собирает книгу целиком и только потом пишет её в w, поэтому первый байт уходит в w,
+// когда прочитаны все товары. Строки листа не копятся в памяти в виде ячеек: excelize.StreamWriter
+// сбрасывает большой лист во временный файл.
+func (Writer) WriteProducts(w io.Writer, pages ProductPages) error {
+	f := excelize.NewFile()
+	defer closeFile(f)
+
+	sw, err := f.NewStreamWriter(exportSheetName)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, 0, len(defaultColumnOrder))
+	for _, column := range defaultColumnOrder {
+		header = append(header, column)
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	rowNumber := 1
+	err = pages(func(products []models.Product) error {
+		for _, p := range products {
+			rowNumber++
+
+			cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+			if err != nil {
+				return err
+			}
+
+			// available всегда true: в базе только доступные офферы
+			if err := sw.SetRow(cell, []interface{}{exportNumber(p.OfferId), p.Name, exportNumber(p.Price), exportNumber(p.Quantity), true}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := f.WriteTo(w); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+// exportNumber: excelize пишет uint64 как знаковое число, а длинные числа теряют точность
+func exportNumber(v uint64) interface{} {
+	if v > maxExactNumber {
+		return strconv.FormatUint(v, 10)
+	}
+
+	return int64(v)
+}
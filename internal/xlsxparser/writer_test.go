@@ -0,0 +1,72 @@
+package xlsxparser
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/hablof/merchant-experience/internal/config"
+	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_WriteProducts(t *testing.T) {
+	pages := [][]models.Product{
+		{
+			{OfferId: 1, Name: "Яблоко красное", Price: 100, Quantity: 5},
+			{OfferId: 2, Name: "  пробелы  ", Price: 0, Quantity: 0},
+		},
+		{
+			{OfferId: math.MaxUint64, Name: "большой offer_id", Price: 1 << 60, Quantity: 1},
+		},
+	}
+	stream := func(handle func(products []models.Product) error) error {
+		for _, page := range pages {
+			if err := handle(page); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	t.Run("выгрузка читается парсером без изменений", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := NewWriter().WriteProducts(buf, stream); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		productUpdates, issues, err := streamAll(NewParser(config.Config{}), bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Empty(t, issues)
+
+		assert.Equal(t, []models.ProductUpdate{
+			{Product: models.Product{OfferId: 1, Name: "Яблоко красное", Price: 100, Quantity: 5}, Available: true, Row: 2},
+			// парсер обрезает пробелы в названии
+			{Product: models.Product{OfferId: 2, Name: "пробелы", Price: 0, Quantity: 0}, Available: true, Row: 3},
+			{Product: models.Product{OfferId: math.MaxUint64, Name: "большой offer_id", Price: 1 << 60, Quantity: 1}, Available: true, Row: 4},
+		}, productUpdates)
+	})
+
+	t.Run("пустой каталог - только заголовок", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := NewWriter().WriteProducts(buf, func(handle func(products []models.Product) error) error { return nil })
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+
+		productUpdates, _, _ := streamAll(NewParser(config.Config{}), bytes.NewReader(buf.Bytes()))
+		assert.Empty(t, productUpdates)
+	})
+
+	t.Run("ошибка источника товаров", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		streamErr := errors.New("repo err")
+		err := NewWriter().WriteProducts(buf, func(handle func(products []models.Product) error) error { return streamErr })
+		assert.Equal(t, streamErr, err)
+		assert.Zero(t, buf.Len(), "nothing is written on error")
+	})
+}
@@ -2,13 +2,10 @@ package xlsxparser
 
 import (
 	"errors"
-	"fmt"
 	"io"
 	"log"
-	"strconv"
-	"strings"
 
-	"github.com/hablof/merchant-experience/internal/models"
+	"github.com/hablof/merchant-experience/internal/config"
 	"github.com/xuri/excelize/v2"
 )
 
@@ -18,198 +15,154 @@ var (
 	ErrHasDuplicates = errors.New("sheet contain offer_id duplicates")
 	ErrInvalidIDs    = errors.New("offer_id column has invalid value(s)")
 	ErrFailedToRead  = errors.New("cannot read document")
+	ErrSheetNotFound = errors.New("sheet not found")
 )
 
-type ErrProductParsing struct {
-	Row    uint64 `json:"row"`
-	Field  string `json:"field"`
-	ErrMsg string `json:"errMsg"`
-}
+const (
+	defaultUnzipXMLSizeLimitMB = 4
+	defaultUnzipSizeLimitMB    = 1024
+)
 
-func (e ErrProductParsing) Error() string {
-	return fmt.Sprintf("product invalid: id=%d, field=%s, err=%s", e.Row, e.Field, e.ErrMsg)
+type Parser struct {
+	aliases map[string]string
+	// ограничения распаковки книги
+	opts excelize.Options
 }
 
-type Parser struct{}
+func NewParser(cfg config.Config) Parser {
+	p := Parser{
+		aliases: columnAliases(cfg),
+		opts: excelize.Options{
+			UnzipXMLSizeLimit: cfg.Parser.UnzipXMLSizeLimitMB << 20,
+			UnzipSizeLimit:    cfg.Parser.UnzipSizeLimitMB << 20,
+		},
+	}
+
+	if p.opts.UnzipXMLSizeLimit <= 0 {
+		p.opts.UnzipXMLSizeLimit = defaultUnzipXMLSizeLimitMB << 20
+	}
+	if p.opts.UnzipSizeLimit <= 0 {
+		p.opts.UnzipSizeLimit = defaultUnzipSizeLimitMB << 20
+	}
+	if p.opts.UnzipSizeLimit < p.opts.UnzipXMLSizeLimit {
+		p.opts.UnzipSizeLimit = p.opts.UnzipXMLSizeLimit
+	}
 
-func NewParser() Parser {
-	return Parser{}
+	return p
 }
 
-// метод не знает ничего про seller_id
-func (Parser) ParseProducts(r io.Reader) (productUpdates []models.ProductUpdate, productErrs []error, methodErr error) {
-	f, err := excelize.OpenReader(r)
+// StreamProducts читает лист sheet (пусто - первый лист) построчно (excelize.Rows) и отдаёт товары в handle пачками по batchSize.
+// Что из книги остаётся в памяти, описано у openBook.
+func (p Parser) StreamProducts(r io.ReadSeeker, sheet string, batchSize int, handle BatchHandler) error {
+	f, err := openBook(r, p.opts)
 	if err != nil {
-		log.Println(err)
-		return nil, nil, ErrFailedToRead
+		return err
 	}
+	defer closeFile(f)
 
-	defer func() {
-		// Close the spreadsheet.
-		if err := f.Close(); err != nil {
-			log.Println(err)
-		}
-	}()
-
-	rows, err := prepare(f)
+	open, err := sheetOpener(f, sheet)
 	if err != nil {
-		log.Println(err)
-		return nil, nil, err
+		return err
 	}
 
-	// основной цикл
-	productUpdates = make([]models.ProductUpdate, 0, len(rows))
-	productErrs = make([]error, 0, len(rows))
-	for rowNumber, row := range rows {
-
-		productUnit := models.Product{}
-		updateUnit := models.ProductUpdate{}
-		isValid := true
-		// cols:
-		// [0] offer_id  - уникальный идентификатор товара в системе продавца
-		// [1] name      - название товара
-		// [2] price     - цена в рублях
-		// [3] quantity  - количество товара на складе продавца
-		// [4] available - true/false, в случае false продавец хочет удалить товар из нашей базы
-
-		// парсим offer_id
-		offerId, err := strconv.ParseUint(row[0], 10, 64)
-		if err != nil {
-			isValid = false
-			e := ErrProductParsing{
-				Row:    uint64(rowNumber + 1), // человеческий счёт
-				Field:  "offer_id",
-				ErrMsg: err.Error(),
-			}
-			productErrs = append(productErrs, e)
-		}
-
-		// обрезаем пробелы у name
-		name := strings.TrimSpace(row[1])
-
-		// парсим price
-		price, err := strconv.ParseUint(row[2], 10, 64)
-		if err != nil {
-			isValid = false
-			e := ErrProductParsing{
-				Row:    uint64(rowNumber + 1), // человеческий счёт
-				Field:  "price",
-				ErrMsg: err.Error(),
-			}
-			productErrs = append(productErrs, e)
-		}
-
-		// парсим quantity
-		quantity, err := strconv.ParseUint(row[3], 10, 64)
-		if err != nil {
-			isValid = false
-			e := ErrProductParsing{
-				Row:    uint64(rowNumber + 1), // человеческий счёт
-				Field:  "quantity",
-				ErrMsg: err.Error(),
-			}
-			productErrs = append(productErrs, e)
-		}
-
-		// парсим available
-		available, err := strconv.ParseBool(row[4])
-		if err != nil {
-			isValid = false
-			e := ErrProductParsing{
-				Row:    uint64(rowNumber + 1), // человеческий счёт
-				Field:  "available",
-				ErrMsg: err.Error(),
-			}
-			productErrs = append(productErrs, e)
-		}
-
-		productUnit.OfferId = offerId
-		productUnit.Name = name
-		productUnit.Price = price
-		productUnit.Quantity = quantity
-
-		// валидируем по логике домена
-		var validationErr models.ErrProductValidation
-		if err := productUnit.Validate(); errors.As(err, &validationErr) {
-			isValid = false
-			e := ErrProductParsing{
-				Row:    uint64(rowNumber + 1), // человеческий счёт
-				Field:  validationErr.Field,
-				ErrMsg: validationErr.ErrMsg,
-			}
-			productErrs = append(productErrs, e)
-		}
-
-		updateUnit.Product = productUnit
-		updateUnit.Available = available
+	return streamRows(open, p.aliases, batchSize, handle)
+}
 
-		if isValid {
-			productUpdates = append(productUpdates, updateUnit)
-		}
+// SheetNames возвращает листы книги в порядке книги
+func (p Parser) SheetNames(r io.ReadSeeker) ([]string, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		log.Println(err)
+		return nil, ErrFailedToRead
 	}
 
-	productUpdates = append(make([]models.ProductUpdate, 0, len(productUpdates)), productUpdates...)
-	productErrs = append(make([]error, 0, len(productErrs)), productErrs...)
-	if len(productErrs) == 0 {
-		productErrs = nil
+	f, err := openBook(r, p.opts)
+	if err != nil {
+		return nil, err
 	}
+	defer closeFile(f)
 
-	return productUpdates, productErrs, nil
+	return f.GetSheetList(), nil
 }
 
-func prepare(f *excelize.File) ([][]string, error) {
-	sheetList := f.GetSheetList()
-	if len(sheetList) == 0 {
-		log.Println("empty document")
-		return nil, ErrEmptyDoc
+// openBook открывает книгу: скачанную или загруженную таблицу - по пути к её временному файлу.
+// Листы и общие строки больше opts.UnzipXMLSizeLimit excelize распаковывает во временные файлы и читает построчно;
+// остальные части книги и сжатый архив (excelize 2.7 читает его целиком даже из файла, он ограничен
+// gateway.max-table-size-mb и server.max-upload-size-mb) остаются в памяти.
+// Таблица общих строк при чтении листа разбирается в память, поэтому растёт с числом разных названий.
+func openBook(r io.Reader, opts excelize.Options) (*excelize.File, error) {
+	var (
+		f   *excelize.File
+		err error
+	)
+	if file, ok := r.(interface{ Name() string }); ok {
+		f, err = excelize.OpenFile(file.Name(), opts)
+	} else {
+		f, err = excelize.OpenReader(r, opts)
 	}
-
-	rows, err := f.GetRows(sheetList[0])
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, ErrFailedToRead
 	}
 
-	if len(rows) == 0 {
-		log.Println("empty sheet")
-		return nil, ErrEmptySheet
+	if len(f.GetSheetList()) == 0 {
+		log.Println("empty document")
+		closeFile(f)
+		return nil, ErrEmptyDoc
 	}
 
-	cols, err := f.GetCols(sheetList[0])
-	if err != nil {
-		log.Println(err)
-		return nil, err
+	return f, nil
+}
+
+// sheetOpener читает лист sheet; пусто - первый лист книги
+func sheetOpener(f *excelize.File, sheet string) (func() (rowIterator, error), error) {
+	if sheet == "" {
+		sheet = f.GetSheetList()[0]
+	} else if idx, err := f.GetSheetIndex(sheet); err != nil || idx < 0 {
+		log.Printf("sheet %q not found", sheet)
+		return nil, ErrSheetNotFound
 	}
 
-	offerIDs := make([]uint64, 0, len(cols[0]))
-	for _, str := range cols[0] {
-		u, err := strconv.ParseUint(str, 10, 64)
+	return func() (rowIterator, error) {
+		rows, err := f.Rows(sheet)
 		if err != nil {
-			log.Println(err)
-			return nil, ErrInvalidIDs // человеческая система счёта
+			return nil, err
 		}
 
-		offerIDs = append(offerIDs, u)
-	}
+		return &xlsxRows{rows: rows}, nil
+	}, nil
+}
 
-	// check for duplicates
-	if hasDuplicates(offerIDs) {
-		log.Println("sheet has offerID duplicates")
-		return nil, ErrHasDuplicates
+func closeFile(f *excelize.File) {
+	// Close the spreadsheet.
+	if err := f.Close(); err != nil {
+		log.Println(err)
 	}
-	return rows, nil
 }
 
-func hasDuplicates[T comparable](slice []T) bool {
-	m := make(map[T]struct{}, len(slice))
+// xlsxRows приводит excelize.Rows к rowIterator
+type xlsxRows struct {
+	rows      *excelize.Rows
+	rowNumber uint64
+}
 
-	for _, elem := range slice {
-		if _, ok := m[elem]; ok {
-			return true
-		} else {
-			m[elem] = struct{}{}
-		}
+func (x *xlsxRows) Next() bool {
+	if !x.rows.Next() {
+		return false
 	}
 
-	return false
+	x.rowNumber++
+	return true
+}
+
+func (x *xlsxRows) Row() ([]string, uint64, error) {
+	cells, err := x.rows.Columns()
+	return cells, x.rowNumber, err
+}
+
+func (x *xlsxRows) Err() error {
+	return x.rows.Error()
+}
+
+func (x *xlsxRows) Close() error {
+	return x.rows.Close()
 }
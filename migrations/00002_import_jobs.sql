@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE import_jobs (
+    id         BIGSERIAL    PRIMARY KEY,
+    seller_id  BIGINT       NOT NULL,
+    table_url  TEXT         NOT NULL,
+    status     VARCHAR(16)  NOT NULL DEFAULT 'queued',
+    results    JSONB        NOT NULL DEFAULT 'null',
+    error      TEXT         NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX import_jobs_status_idx ON import_jobs(status, id);
+
+-- +goose Down
+DROP TABLE import_jobs;
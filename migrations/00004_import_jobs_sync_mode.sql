@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE import_jobs ADD COLUMN sync_mode VARCHAR(16) NOT NULL DEFAULT 'merge';
+
+-- +goose Down
+ALTER TABLE import_jobs DROP COLUMN sync_mode;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- поиск по подстроке (ILIKE)
+CREATE INDEX products_name_trgm_idx ON products USING GIN (name gin_trgm_ops);
+
+-- полнотекстовый поиск; выражение должно совпадать с запросом в репозитории
+CREATE INDEX products_name_fts_idx ON products USING GIN (to_tsvector('russian', name));
+
+-- +goose Down
+DROP INDEX IF EXISTS products_name_fts_idx;
+DROP INDEX IF EXISTS products_name_trgm_idx;
@@ -0,0 +1,63 @@
+-- +goose Up
+CREATE TABLE product_history (
+    id           BIGSERIAL    PRIMARY KEY,
+    seller_id    BIGINT       NOT NULL,
+    offer_id     BIGINT       NOT NULL,
+    action       VARCHAR(16)  NOT NULL,
+    old_name     VARCHAR(100),
+    old_price    BIGINT,
+    old_quantity BIGINT,
+    new_name     VARCHAR(100),
+    new_price    BIGINT,
+    new_quantity BIGINT,
+    source       VARCHAR(16)  NOT NULL DEFAULT '',
+    job_id       BIGINT,
+    request_id   TEXT         NOT NULL DEFAULT '',
+    changed_at   TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX product_history_offer_idx ON product_history(seller_id, offer_id, id);
+CREATE INDEX product_history_job_idx ON product_history(job_id);
+
+-- источник изменения репозиторий передаёт через set_config(..., true) в начале транзакции
+-- +goose StatementBegin
+CREATE FUNCTION record_product_history() RETURNS trigger AS $$
+DECLARE
+    change_source     TEXT   := coalesce(current_setting('app.change_source', true), '');
+    change_job_id     BIGINT := nullif(current_setting('app.change_job_id', true), '')::BIGINT;
+    change_request_id TEXT   := coalesce(current_setting('app.change_request_id', true), '');
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO product_history (seller_id, offer_id, action, new_name, new_price, new_quantity, source, job_id, request_id)
+        VALUES (NEW.seller_id, NEW.offer_id, 'insert', NEW.name, NEW.price, NEW.quantity, change_source, change_job_id, change_request_id);
+
+        RETURN NEW;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO product_history (seller_id, offer_id, action, old_name, old_price, old_quantity, new_name, new_price, new_quantity, source, job_id, request_id)
+        VALUES (NEW.seller_id, NEW.offer_id, 'update', OLD.name, OLD.price, OLD.quantity, NEW.name, NEW.price, NEW.quantity, change_source, change_job_id, change_request_id);
+
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO product_history (seller_id, offer_id, action, old_name, old_price, old_quantity, source, job_id, request_id)
+    VALUES (OLD.seller_id, OLD.offer_id, 'delete', OLD.name, OLD.price, OLD.quantity, change_source, change_job_id, change_request_id);
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER products_history_insert_delete
+    AFTER INSERT OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION record_product_history();
+
+-- upsert без изменений (повторная загрузка той же таблицы) в историю не попадает
+CREATE TRIGGER products_history_update
+    AFTER UPDATE ON products
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE FUNCTION record_product_history();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS products_history_update ON products;
+DROP TRIGGER IF EXISTS products_history_insert_delete ON products;
+DROP FUNCTION IF EXISTS record_product_history();
+DROP TABLE product_history;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE import_jobs ADD COLUMN reverted_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE import_jobs DROP COLUMN reverted_at;
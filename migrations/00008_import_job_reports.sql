@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE import_job_reports (
+    job_id     BIGINT       PRIMARY KEY REFERENCES import_jobs(id) ON DELETE CASCADE,
+    content    BYTEA        NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+ALTER TABLE import_jobs ADD COLUMN has_report BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE import_jobs DROP COLUMN has_report;
+
+DROP TABLE import_job_reports;
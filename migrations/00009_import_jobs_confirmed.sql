@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE import_jobs ADD COLUMN confirmed BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE import_jobs DROP COLUMN confirmed;
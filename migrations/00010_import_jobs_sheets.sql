@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE import_jobs ADD COLUMN sheet TEXT NOT NULL DEFAULT '';
+ALTER TABLE import_jobs ADD COLUMN sheets JSONB;
+
+-- +goose Down
+ALTER TABLE import_jobs DROP COLUMN sheets;
+ALTER TABLE import_jobs DROP COLUMN sheet;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE import_jobs ADD COLUMN auth BYTEA;
+
+-- +goose Down
+ALTER TABLE import_jobs DROP COLUMN auth;
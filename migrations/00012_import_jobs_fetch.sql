@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE import_jobs ADD COLUMN etag TEXT NOT NULL DEFAULT '';
+ALTER TABLE import_jobs ADD COLUMN last_modified TEXT NOT NULL DEFAULT '';
+ALTER TABLE import_jobs ADD COLUMN content_hash TEXT NOT NULL DEFAULT '';
+CREATE INDEX import_jobs_seller_idx ON import_jobs(seller_id, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS import_jobs_seller_idx;
+ALTER TABLE import_jobs DROP COLUMN content_hash;
+ALTER TABLE import_jobs DROP COLUMN last_modified;
+ALTER TABLE import_jobs DROP COLUMN etag;
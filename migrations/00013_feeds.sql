@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE feeds (
+    id               BIGSERIAL    PRIMARY KEY,
+    seller_id        BIGINT       NOT NULL,
+    table_url        TEXT         NOT NULL,
+    interval_minutes BIGINT       NOT NULL,
+    sync_mode        VARCHAR(16)  NOT NULL DEFAULT 'merge',
+    next_run_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    last_run_at      TIMESTAMPTZ,
+    last_job_id      BIGINT       REFERENCES import_jobs(id) ON DELETE SET NULL,
+    last_status      VARCHAR(16)  NOT NULL DEFAULT '',
+    last_error       TEXT         NOT NULL DEFAULT ''
+);
+
+CREATE INDEX feeds_seller_idx ON feeds(seller_id, id);
+CREATE INDEX feeds_next_run_idx ON feeds(next_run_at);
+
+-- +goose Down
+DROP TABLE feeds;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- воркер продлевает аренду задачи, пока выполняет её; задача без продления считается брошенной
+ALTER TABLE import_jobs ADD COLUMN heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- +goose Down
+ALTER TABLE import_jobs DROP COLUMN heartbeat_at;
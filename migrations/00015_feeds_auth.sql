@@ -0,0 +1,6 @@
+-- +goose Up
+-- учётные данные источника фида, зашифрованные так же, как у задач
+ALTER TABLE feeds ADD COLUMN auth BYTEA;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN auth;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- повторная загрузка ссылки проверяет, менялся ли каталог продавца после её последней загрузки
+CREATE INDEX product_history_seller_changed_idx ON product_history(seller_id, changed_at);
+
+-- +goose Down
+DROP INDEX product_history_seller_changed_idx;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- changed_at - время начала транзакции, а транзакции загрузок фиксируются в другом порядке, чем начинаются.
+-- Поэтому повторная загрузка ссылки сравнивает не время, а транзакции: загрузка запоминает снимок (pg_snapshot)
+-- на начало записи в каталог, а изменением после неё считается запись истории транзакции, не видимой в этом снимке.
+-- Без DEFAULT в ADD COLUMN старые строки не переписываются и остаются с NULL.
+ALTER TABLE product_history ADD COLUMN xact_id XID8;
+ALTER TABLE product_history ALTER COLUMN xact_id SET DEFAULT pg_current_xact_id();
+DROP INDEX IF EXISTS product_history_seller_changed_idx;
+CREATE INDEX product_history_seller_xact_idx ON product_history(seller_id, xact_id);
+
+ALTER TABLE import_jobs ADD COLUMN catalog_snapshot PG_SNAPSHOT;
+ALTER TABLE import_jobs ADD COLUMN catalog_xact_id XID8;
+
+-- +goose Down
+ALTER TABLE import_jobs DROP COLUMN catalog_xact_id;
+ALTER TABLE import_jobs DROP COLUMN catalog_snapshot;
+
+DROP INDEX IF EXISTS product_history_seller_xact_idx;
+CREATE INDEX product_history_seller_changed_idx ON product_history(seller_id, changed_at);
+ALTER TABLE product_history DROP COLUMN xact_id;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- номер попытки выполнения: ClaimJob увеличивает его, и воркер меняет задачу, только пока номер совпадает с его попыткой
+ALTER TABLE import_jobs ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE import_jobs DROP COLUMN attempts;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- загруженный файл хранится в базе, а не на диске реплики, которая его приняла: задачу может забрать любая реплика
+CREATE TABLE import_job_uploads (
+    job_id     BIGINT       PRIMARY KEY REFERENCES import_jobs(id) ON DELETE CASCADE,
+    content    BYTEA        NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE import_job_uploads;